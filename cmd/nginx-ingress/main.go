@@ -38,6 +38,7 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	gateway_versioned "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
 )
 
 var (
@@ -73,22 +74,16 @@ var (
 	ingressClass = flag.String("ingress-class", "nginx",
 		`A class of the Ingress controller.
 
-	For Kubernetes >= 1.18, a corresponding IngressClass resource with the name equal to the class must be deployed. Otherwise,
+	A corresponding IngressClass resource with the name equal to the class must be deployed. Otherwise,
 	the Ingress Controller will fail to start.
 	The Ingress controller only processes resources that belong to its class - i.e. have the "ingressClassName" field resource equal to the class.
+	Additionally, if the IngressClass is marked as the default class with the "ingressclass.kubernetes.io/is-default-class" annotation,
+	the Ingress Controller processes the Ingress resources that do not have the class set.
 
-	For Kubernetes < 1.18, the Ingress Controller only processes resources that belong to its class -
-	i.e have the annotation "kubernetes.io/ingress.class" (for Ingress resources)
-	or field "ingressClassName" (for VirtualServer/VirtualServerRoute/TransportServer resources) equal to the class.
-	Additionally, the Ingress Controller processes resources that do not have the class set,
-	which can be disabled by setting the "-use-ingress-class-only" flag
-
-	The Ingress Controller processes all the VirtualServer/VirtualServerRoute/TransportServer resources that do not have the "ingressClassName" field for all versions of kubernetes.`)
+	The Ingress Controller processes all the VirtualServer/VirtualServerRoute/TransportServer resources that do not have the "ingressClassName" field.`)
 
 	useIngressClassOnly = flag.Bool("use-ingress-class-only", false,
-		`For kubernetes versions >= 1.18 this flag will be IGNORED.
-
-	Ignore Ingress resources without the "kubernetes.io/ingress.class" annotation`)
+		`DEPRECATED: this flag is IGNORED. The Ingress Controller processes the Ingress resources without the class only if its IngressClass is the default class.`)
 
 	defaultServerSecret = flag.String("default-server-tls-secret", "",
 		`A Secret with a TLS certificate and key for TLS termination of the default server. Format: <namespace>/<name>.
@@ -146,6 +141,14 @@ var (
 		`The timeout in milliseconds which the Ingress Controller will wait for a successful NGINX reload after a change or at the initial start.
 		The default is 4000 (or 20000 if -enable-app-protect is true). If set to 0, the default value will be used`)
 
+	nginxReloadCoalesceWindow = flag.Int("nginx-reload-coalesce-window", 0,
+		`The time window in milliseconds during which the Ingress Controller coalesces configuration changes into a single NGINX reload.
+		Every change is still tested with 'nginx -t' right away. If set to 0, NGINX is reloaded after every change`)
+
+	nginxReloadCoalesceMaxLatency = flag.Int("nginx-reload-coalesce-max-latency", 0,
+		`The maximum time in milliseconds a configuration change waits for a coalesced NGINX reload.
+		Requires -nginx-reload-coalesce-window. The default is 10 times the value of -nginx-reload-coalesce-window. If set to 0, the default value will be used`)
+
 	wildcardTLSSecret = flag.String("wildcard-tls-secret", "",
 		`A Secret with a TLS certificate and key for TLS termination of every Ingress host for which TLS termination is enabled but the Secret is not specified.
 		Format: <namespace>/<name>. If the argument is not set, for such Ingress hosts NGINX will break any attempt to establish a TLS connection.
@@ -163,6 +166,15 @@ var (
 	enablePreviewPolicies = flag.Bool("enable-preview-policies", false,
 		"Enable preview policies")
 
+	enableBrotli = flag.Bool("enable-brotli", false,
+		"Enable the brotli compression in compression policies. Loads the brotli module of NGINX Plus. Requires -nginx-plus and -enable-preview-policies.")
+
+	geoIP2CountryDatabase = flag.String("geoip2-country-database", "",
+		`Path to a MaxMind GeoIP2 (or GeoLite2) Country database. Enables matching on countries in ipAccessControl policies. Requires -nginx-plus`)
+
+	geoIP2ASNDatabase = flag.String("geoip2-asn-database", "",
+		`Path to a MaxMind GeoIP2 (or GeoLite2) ASN database. Enables matching on autonomous system numbers in ipAccessControl policies. Requires -nginx-plus`)
+
 	enableSnippets = flag.Bool("enable-snippets", false,
 		"Enable custom NGINX configuration snippets in VirtualServer, VirtualServerRoute and TransportServer resources.")
 
@@ -185,6 +197,15 @@ var (
 
 	enableLatencyMetrics = flag.Bool("enable-latency-metrics", false,
 		"Enable collection of latency metrics for upstreams. Requires -enable-prometheus-metrics")
+
+	enableTopologyAwareRouting = flag.Bool("enable-topology-aware-routing", false,
+		`Enable the topology aware routing of the upstreams of VirtualServer and VirtualServerRoute resources. Requires -enable-custom-resources and the NODE_NAME environment variable set to the name of the node of the Ingress Controller pod`)
+
+	enableGatewayAPI = flag.Bool("enable-gateway-api", false,
+		`Enable support for the Gateway API resources (GatewayClass, Gateway, HTTPRoute and TLSRoute). Requires -enable-custom-resources`)
+
+	gatewayControllerName = flag.String("gateway-controller-name", "k8s.nginx.org/nginx-ingress-controller",
+		`The controller name of the GatewayClass resources handled by the Ingress Controller. Requires -enable-gateway-api`)
 )
 
 func main() {
@@ -235,10 +256,30 @@ func main() {
 		glog.Fatal("enable-tls-passthrough flag requires -enable-custom-resources")
 	}
 
+	if *enableGatewayAPI && !*enableCustomResources {
+		glog.Fatal("enable-gateway-api flag requires -enable-custom-resources")
+	}
+
+	if *enableGatewayAPI && *gatewayControllerName == "" {
+		glog.Fatal("gateway-controller-name flag must not be empty")
+	}
+
 	if *appProtect && !*nginxPlus {
 		glog.Fatal("NGINX App Protect support is for NGINX Plus only")
 	}
 
+	if (*geoIP2CountryDatabase != "" || *geoIP2ASNDatabase != "") && !*nginxPlus {
+		glog.Fatal("geoip2-country-database and geoip2-asn-database flags are for NGINX Plus only")
+	}
+
+	if *enableBrotli && !*nginxPlus {
+		glog.Fatal("enable-brotli flag is for NGINX Plus only")
+	}
+
+	if *enableBrotli && !*enablePreviewPolicies {
+		glog.Fatal("enable-brotli flag requires -enable-preview-policies")
+	}
+
 	if *spireAgentAddress != "" && !*nginxPlus {
 		glog.Fatal("spire-agent-address support is for NGINX Plus only")
 	}
@@ -252,6 +293,23 @@ func main() {
 		*enableLatencyMetrics = false
 	}
 
+	nodeName := os.Getenv("NODE_NAME")
+	if *enableTopologyAwareRouting && nodeName == "" {
+		glog.Fatal("enable-topology-aware-routing flag requires the NODE_NAME environment variable")
+	}
+
+	if *nginxReloadCoalesceWindow < 0 {
+		glog.Fatal("nginx-reload-coalesce-window must not be negative")
+	}
+
+	if *nginxReloadCoalesceMaxLatency < 0 {
+		glog.Fatal("nginx-reload-coalesce-max-latency must not be negative")
+	}
+
+	if *nginxReloadCoalesceMaxLatency > 0 && *nginxReloadCoalesceWindow == 0 {
+		glog.Fatal("nginx-reload-coalesce-max-latency flag requires nginx-reload-coalesce-window")
+	}
+
 	if *ingressLink != "" && *externalService != "" {
 		glog.Fatal("ingresslink and external-service cannot both be set")
 	}
@@ -286,25 +344,23 @@ func main() {
 		glog.Fatalf("error retrieving k8s version: %v", err)
 	}
 
-	minK8sVersion := minVersion("1.14.0")
+	// EndpointSlice V1 is only available from k8s >= 1.21
+	minK8sVersion := minVersion("1.21.0")
 	if !k8sVersion.AtLeast(minK8sVersion) {
 		glog.Fatalf("Versions of Kubernetes < %v are not supported, please refer to the documentation for details on supported versions.", minK8sVersion)
 	}
 
-	// Ingress V1 is only available from k8s > 1.18
-	ingressV1Version := minVersion("1.18.0")
-	if k8sVersion.AtLeast(ingressV1Version) {
-		*useIngressClassOnly = true
-		glog.Warningln("The '-use-ingress-class-only' flag will be deprecated and has no effect on versions of kubernetes >= 1.18.0. Processing ONLY resources that have the 'ingressClassName' field in Ingress equal to the class.")
+	if *useIngressClassOnly {
+		glog.Warningln("The '-use-ingress-class-only' flag is DEPRECATED and has no effect. The Ingress resources without the class are processed only if the IngressClass of the Ingress Controller is the default class.")
+	}
 
-		ingressClassRes, err := kubeClient.NetworkingV1beta1().IngressClasses().Get(context.TODO(), *ingressClass, meta_v1.GetOptions{})
-		if err != nil {
-			glog.Fatalf("Error when getting IngressClass %v: %v", *ingressClass, err)
-		}
+	ingressClassRes, err := kubeClient.NetworkingV1().IngressClasses().Get(context.TODO(), *ingressClass, meta_v1.GetOptions{})
+	if err != nil {
+		glog.Fatalf("Error when getting IngressClass %v: %v", *ingressClass, err)
+	}
 
-		if ingressClassRes.Spec.Controller != k8s.IngressControllerName {
-			glog.Fatalf("IngressClass with name %v has an invalid Spec.Controller %v", ingressClassRes.Name, ingressClassRes.Spec.Controller)
-		}
+	if ingressClassRes.Spec.Controller != k8s.IngressControllerName {
+		glog.Fatalf("IngressClass with name %v has an invalid Spec.Controller %v", ingressClassRes.Name, ingressClassRes.Spec.Controller)
 	}
 
 	var dynClient dynamic.Interface
@@ -328,6 +384,14 @@ func main() {
 		}
 	}
 
+	var gatewayClient gateway_versioned.Interface
+	if *enableGatewayAPI {
+		gatewayClient, err = gateway_versioned.NewForConfig(config)
+		if err != nil {
+			glog.Fatalf("Failed to create a gateway client: %v", err)
+		}
+	}
+
 	nginxConfTemplatePath := "nginx.tmpl"
 	nginxIngressTemplatePath := "nginx.ingress.tmpl"
 	nginxVirtualServerTemplatePath := "nginx.virtualserver.tmpl"
@@ -523,7 +587,14 @@ func main() {
 		MainAppProtectLoadModule:       *appProtect,
 		EnableLatencyMetrics:           *enableLatencyMetrics,
 		EnablePreviewPolicies:          *enablePreviewPolicies,
+		EnableBrotli:                   *enableBrotli,
 		SSLRejectHandshake:             sslRejectHandshake,
+		GeoIP2CountryDatabase:          *geoIP2CountryDatabase,
+		GeoIP2ASNDatabase:              *geoIP2ASNDatabase,
+		EnableTopologyAwareRouting:     *enableTopologyAwareRouting,
+		HTTP3Supported:                 nginx.IsHTTP3Supported(nginxVersion),
+		JWTRequireSupported:            nginx.IsJWTRequireSupported(nginxVersion),
+		JWTKeyCacheSupported:           nginx.IsJWTKeyCacheSupported(nginxVersion),
 	}
 
 	ngxConfig := configs.GenerateNginxMainConfig(staticCfgParams, cfgParams)
@@ -566,6 +637,9 @@ func main() {
 	var plusCollector *nginxCollector.NginxPlusCollector
 	var syslogListener metrics.SyslogListener
 	syslogListener = metrics.NewSyslogFakeServer()
+	var upstreamStats *collectors.UpstreamStats
+	var outlierDetectionCollector collectors.OutlierDetectionCollector
+	outlierDetectionCollector = collectors.NewOutlierDetectionFakeCollector()
 	if *enablePrometheusMetrics {
 		upstreamServerVariableLabels := []string{"service", "resource_type", "resource_name", "resource_namespace"}
 		upstreamServerPeerVariableLabelNames := []string{"pod_name"}
@@ -582,6 +656,11 @@ func main() {
 				streamUpstreamServerVariableLabels, streamServerZoneVariableLabels, streamUpstreamServerPeerVariableLabelNames)
 			plusCollector = nginxCollector.NewNginxPlusCollector(plusClient, "nginx_ingress_nginxplus", variableLabelNames, constLabels)
 			go metrics.RunPrometheusListenerForNginxPlus(*prometheusMetricsListenPort, plusCollector, registry)
+
+			outlierDetectionCollector = collectors.NewOutlierDetectionMetricsCollector(constLabels)
+			if err := outlierDetectionCollector.Register(registry); err != nil {
+				glog.Errorf("Error registering Outlier Detection Prometheus metrics: %v", err)
+			}
 		} else {
 			httpClient := getSocketClient("/var/lib/nginx/nginx-status.sock")
 			client, err := metrics.NewNginxMetricsClient(httpClient)
@@ -591,7 +670,8 @@ func main() {
 			go metrics.RunPrometheusListenerForNginx(*prometheusMetricsListenPort, client, registry, constLabels)
 		}
 		if *enableLatencyMetrics {
-			latencyCollector = collectors.NewLatencyMetricsCollector(constLabels, upstreamServerVariableLabels, upstreamServerPeerVariableLabelNames)
+			upstreamStats = collectors.NewUpstreamStats()
+			latencyCollector = collectors.NewLatencyMetricsCollector(constLabels, upstreamServerVariableLabels, upstreamServerPeerVariableLabelNames, upstreamStats)
 			if err := latencyCollector.Register(registry); err != nil {
 				glog.Errorf("Error registering Latency Prometheus metrics: %v", err)
 			}
@@ -600,46 +680,62 @@ func main() {
 		}
 	}
 
+	var reloadCoalescer *nginx.ReloadCoalescer
+	if *nginxReloadCoalesceWindow > 0 {
+		window, maxLatency := parseReloadCoalesceParams(*nginxReloadCoalesceWindow, *nginxReloadCoalesceMaxLatency)
+		reloadCoalescer = nginx.NewReloadCoalescer(nginxManager, window, maxLatency, managerCollector)
+		nginxManager = reloadCoalescer
+	}
+
 	isWildcardEnabled := *wildcardTLSSecret != ""
 	cnf := configs.NewConfigurator(nginxManager, staticCfgParams, cfgParams, templateExecutor,
 		templateExecutorV2, *nginxPlus, isWildcardEnabled, plusCollector, *enablePrometheusMetrics, latencyCollector, *enableLatencyMetrics)
 	controllerNamespace := os.Getenv("POD_NAMESPACE")
 
-	transportServerValidator := cr_validation.NewTransportServerValidator(*enableTLSPassthrough, *enableSnippets)
+	transportServerValidator := cr_validation.NewTransportServerValidator(*enableTLSPassthrough, *enableSnippets, *nginxPlus)
 	virtualServerValidator := cr_validation.NewVirtualServerValidator(*nginxPlus)
 
 	lbcInput := k8s.NewLoadBalancerControllerInput{
-		KubeClient:                   kubeClient,
-		ConfClient:                   confClient,
-		DynClient:                    dynClient,
-		ResyncPeriod:                 30 * time.Second,
-		Namespace:                    *watchNamespace,
-		NginxConfigurator:            cnf,
-		DefaultServerSecret:          *defaultServerSecret,
-		AppProtectEnabled:            *appProtect,
-		IsNginxPlus:                  *nginxPlus,
-		IngressClass:                 *ingressClass,
-		UseIngressClassOnly:          *useIngressClassOnly,
-		ExternalServiceName:          *externalService,
-		IngressLink:                  *ingressLink,
-		ControllerNamespace:          controllerNamespace,
-		ReportIngressStatus:          *reportIngressStatus,
-		IsLeaderElectionEnabled:      *leaderElectionEnabled,
-		LeaderElectionLockName:       *leaderElectionLockName,
-		WildcardTLSSecret:            *wildcardTLSSecret,
-		ConfigMaps:                   *nginxConfigMaps,
-		GlobalConfiguration:          *globalConfiguration,
-		AreCustomResourcesEnabled:    *enableCustomResources,
-		EnablePreviewPolicies:        *enablePreviewPolicies,
-		MetricsCollector:             controllerCollector,
-		GlobalConfigurationValidator: globalConfigurationValidator,
-		TransportServerValidator:     transportServerValidator,
-		VirtualServerValidator:       virtualServerValidator,
-		SpireAgentAddress:            *spireAgentAddress,
-		InternalRoutesEnabled:        *enableInternalRoutes,
-		IsPrometheusEnabled:          *enablePrometheusMetrics,
-		IsLatencyMetricsEnabled:      *enableLatencyMetrics,
-		IsTLSPassthroughEnabled:      *enableTLSPassthrough,
+		KubeClient:                    kubeClient,
+		ConfClient:                    confClient,
+		DynClient:                     dynClient,
+		ResyncPeriod:                  30 * time.Second,
+		Namespace:                     *watchNamespace,
+		NginxConfigurator:             cnf,
+		DefaultServerSecret:           *defaultServerSecret,
+		AppProtectEnabled:             *appProtect,
+		IsNginxPlus:                   *nginxPlus,
+		IngressClass:                  *ingressClass,
+		ExternalServiceName:           *externalService,
+		IngressLink:                   *ingressLink,
+		ControllerNamespace:           controllerNamespace,
+		ReportIngressStatus:           *reportIngressStatus,
+		IsLeaderElectionEnabled:       *leaderElectionEnabled,
+		LeaderElectionLockName:        *leaderElectionLockName,
+		WildcardTLSSecret:             *wildcardTLSSecret,
+		ConfigMaps:                    *nginxConfigMaps,
+		GlobalConfiguration:           *globalConfiguration,
+		AreCustomResourcesEnabled:     *enableCustomResources,
+		EnablePreviewPolicies:         *enablePreviewPolicies,
+		EnableBrotli:                  *enableBrotli,
+		MetricsCollector:              controllerCollector,
+		GlobalConfigurationValidator:  globalConfigurationValidator,
+		TransportServerValidator:      transportServerValidator,
+		VirtualServerValidator:        virtualServerValidator,
+		SpireAgentAddress:             *spireAgentAddress,
+		InternalRoutesEnabled:         *enableInternalRoutes,
+		IsPrometheusEnabled:           *enablePrometheusMetrics,
+		IsLatencyMetricsEnabled:       *enableLatencyMetrics,
+		IsTLSPassthroughEnabled:       *enableTLSPassthrough,
+		ReloadCoalescer:               reloadCoalescer,
+		UpstreamStats:                 upstreamStats,
+		PlusClient:                    plusClient,
+		OutlierDetectionCollector:     outlierDetectionCollector,
+		IsTopologyAwareRoutingEnabled: *enableTopologyAwareRouting,
+		NodeName:                      nodeName,
+		GatewayClient:                 gatewayClient,
+		IsGatewayAPIEnabled:           *enableGatewayAPI,
+		GatewayControllerName:         *gatewayControllerName,
 	}
 
 	lbc := k8s.NewLoadBalancerController(lbcInput)
@@ -838,6 +934,16 @@ func handleTerminationWithAppProtect(lbc *k8s.LoadBalancerController, nginxManag
 	os.Exit(0)
 }
 
+func parseReloadCoalesceParams(window int, maxLatency int) (time.Duration, time.Duration) {
+	const defaultMaxLatencyFactor = 10
+
+	if maxLatency == 0 {
+		maxLatency = window * defaultMaxLatencyFactor
+	}
+
+	return time.Duration(window) * time.Millisecond, time.Duration(maxLatency) * time.Millisecond
+}
+
 func parseReloadTimeout(appProtectEnabled bool, timeout int) time.Duration {
 	const defaultTimeout = 4000 * time.Millisecond
 	const defaultTimeoutAppProtect = 20000 * time.Millisecond
@@ -161,3 +161,33 @@ func TestParseReloadTimeout(t *testing.T) {
 		}
 	}
 }
+
+func TestParseReloadCoalesceParams(t *testing.T) {
+	tests := []struct {
+		window             int
+		maxLatency         int
+		expectedWindow     time.Duration
+		expectedMaxLatency time.Duration
+	}{
+		{
+			window:             100,
+			maxLatency:         0,
+			expectedWindow:     100 * time.Millisecond,
+			expectedMaxLatency: 1000 * time.Millisecond,
+		},
+		{
+			window:             100,
+			maxLatency:         500,
+			expectedWindow:     100 * time.Millisecond,
+			expectedMaxLatency: 500 * time.Millisecond,
+		},
+	}
+
+	for _, test := range tests {
+		window, maxLatency := parseReloadCoalesceParams(test.window, test.maxLatency)
+		if window != test.expectedWindow || maxLatency != test.expectedMaxLatency {
+			t.Errorf("parseReloadCoalesceParams(%v, %v) returned (%v, %v) but expected (%v, %v)", test.window, test.maxLatency,
+				window, maxLatency, test.expectedWindow, test.expectedMaxLatency)
+		}
+	}
+}
@@ -19,4 +19,5 @@ require (
 	k8s.io/code-generator v0.21.0
 	k8s.io/gengo v0.0.0-20210203185629-de9496dff47b // indirect
 	sigs.k8s.io/controller-tools v0.5.0
+	sigs.k8s.io/gateway-api v0.3.0
 )
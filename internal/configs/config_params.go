@@ -17,6 +17,7 @@ type ConfigParams struct {
 	HSTSIncludeSubdomains                  bool
 	HSTSMaxAge                             int64
 	HTTP2                                  bool
+	HTTP3                                  bool
 	Keepalive                              int
 	LBMethod                               string
 	LocationSnippets                       []string
@@ -116,7 +117,14 @@ type StaticConfigParams struct {
 	PodName                        string
 	EnableLatencyMetrics           bool
 	EnablePreviewPolicies          bool
+	EnableBrotli                   bool
 	SSLRejectHandshake             bool
+	GeoIP2CountryDatabase          string
+	GeoIP2ASNDatabase              string
+	EnableTopologyAwareRouting     bool
+	HTTP3Supported                 bool
+	JWTRequireSupported            bool
+	JWTKeyCacheSupported           bool
 }
 
 // GlobalConfigParams holds global configuration parameters. For now, it only holds listeners.
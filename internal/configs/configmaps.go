@@ -92,6 +92,14 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool, hasAppProtect bool) *Con
 		}
 	}
 
+	if HTTP3, exists, err := GetMapKeyAsBool(cfgm.Data, "http3", cfgm); exists {
+		if err != nil {
+			glog.Error(err)
+		} else {
+			cfgParams.HTTP3 = HTTP3
+		}
+	}
+
 	if redirectToHTTPS, exists, err := GetMapKeyAsBool(cfgm.Data, "redirect-to-https", cfgm); exists {
 		if err != nil {
 			glog.Error(err)
@@ -496,6 +504,15 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool, hasAppProtect bool) *Con
 	return cfgParams
 }
 
+// isHTTP3Enabled checks if HTTP/3 is enabled in the ConfigMap and supported by NGINX.
+func isHTTP3Enabled(staticCfgParams *StaticConfigParams, config *ConfigParams) bool {
+	if config.HTTP3 && !staticCfgParams.HTTP3Supported {
+		glog.Warning("The http3 ConfigMap key will be ignored, because NGINX doesn't support HTTP/3")
+		return false
+	}
+	return config.HTTP3
+}
+
 // GenerateNginxMainConfig generates MainConfig.
 func GenerateNginxMainConfig(staticCfgParams *StaticConfigParams, config *ConfigParams) *version1.MainConfig {
 	nginxCfg := &version1.MainConfig{
@@ -506,6 +523,7 @@ func GenerateNginxMainConfig(staticCfgParams *StaticConfigParams, config *Config
 		HealthStatus:                       staticCfgParams.HealthStatus,
 		HealthStatusURI:                    staticCfgParams.HealthStatusURI,
 		HTTP2:                              config.HTTP2,
+		HTTP3:                              isHTTP3Enabled(staticCfgParams, config),
 		HTTPSnippets:                       config.MainHTTPSnippets,
 		KeepaliveRequests:                  config.MainKeepaliveRequests,
 		KeepaliveTimeout:                   config.MainKeepaliveTimeout,
@@ -556,6 +574,9 @@ func GenerateNginxMainConfig(staticCfgParams *StaticConfigParams, config *Config
 		InternalRouteServerName:            staticCfgParams.PodName,
 		LatencyMetrics:                     staticCfgParams.EnableLatencyMetrics,
 		PreviewPolicies:                    staticCfgParams.EnablePreviewPolicies,
+		BrotliLoadModule:                   staticCfgParams.EnableBrotli,
+		GeoIP2CountryDatabase:              staticCfgParams.GeoIP2CountryDatabase,
+		GeoIP2ASNDatabase:                  staticCfgParams.GeoIP2ASNDatabase,
 	}
 	return nginxCfg
 }
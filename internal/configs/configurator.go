@@ -6,6 +6,7 @@ import (
 	"crypto/x509"
 	"encoding/json"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -19,8 +20,9 @@ import (
 
 	"github.com/golang/glog"
 	api_v1 "k8s.io/api/core/v1"
-	networking "k8s.io/api/networking/v1beta1"
+	networking "k8s.io/api/networking/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/nginxinc/kubernetes-ingress/internal/configs/version1"
 	"github.com/nginxinc/kubernetes-ingress/internal/nginx"
@@ -39,6 +41,10 @@ const (
 	appProtectUserSigIndex          = "/etc/nginx/waf/nac-usersigs/index.conf"
 )
 
+// rejectedConfigWarning is reported for a resource, whose config was rejected by NGINX, when the configs
+// of multiple resources are regenerated.
+const rejectedConfigWarning = "The configuration of the resource was rejected by NGINX. The last accepted configuration is used until the resource is updated"
+
 // DefaultServerSecretPath is the full path to the Secret with a TLS cert and a key for the default server.
 const DefaultServerSecretPath = "/etc/nginx/secrets/default"
 
@@ -104,6 +110,9 @@ type Configurator struct {
 	minions                 map[string]map[string]bool
 	virtualServers          map[string]*VirtualServerEx
 	tlsPassthroughPairs     map[string]tlsPassthroughPair
+	rejectedConfigs         map[string]bool
+	cachePaths              map[string]map[string]bool
+	unusedCachePaths        map[string]bool
 	isWildcardEnabled       bool
 	isPlus                  bool
 	labelUpdater            collector.LabelUpdater
@@ -139,6 +148,9 @@ func NewConfigurator(nginxManager nginx.Manager, staticCfgParams *StaticConfigPa
 		templateExecutorV2:      templateExecutorV2,
 		minions:                 make(map[string]map[string]bool),
 		tlsPassthroughPairs:     make(map[string]tlsPassthroughPair),
+		rejectedConfigs:         make(map[string]bool),
+		cachePaths:              make(map[string]map[string]bool),
+		unusedCachePaths:        make(map[string]bool),
 		isPlus:                  isPlus,
 		isWildcardEnabled:       isWildcardEnabled,
 		labelUpdater:            labelUpdater,
@@ -243,19 +255,62 @@ func (cnf *Configurator) deleteIngressMetricsLabels(key string) {
 
 // AddOrUpdateIngress adds or updates NGINX configuration for the Ingress resource.
 func (cnf *Configurator) AddOrUpdateIngress(ingEx *IngressEx) (Warnings, error) {
+	name := objectMetaToFileName(&ingEx.Ingress.ObjectMeta)
+	acceptedIngEx, accepted := cnf.ingresses[name]
+	wasRejected := cnf.retryRejectedConfig(name)
+
 	warnings, err := cnf.addOrUpdateIngress(ingEx)
 	if err != nil {
+		cnf.rejectedConfigs[name] = wasRejected
 		return warnings, fmt.Errorf("Error adding or updating ingress %v/%v: %v", ingEx.Ingress.Namespace, ingEx.Ingress.Name, err)
 	}
 
-	if err := cnf.nginxManager.Reload(nginx.ReloadForOtherUpdate); err != nil {
+	if err := cnf.reload(nginx.ReloadForOtherUpdate); err != nil {
+		if errors.Is(err, nginx.ErrConfigTestFailed) {
+			if accepted {
+				cnf.ingresses[name] = acceptedIngEx
+			} else {
+				delete(cnf.ingresses, name)
+			}
+			cnf.rejectedConfigs[name] = true
+		}
 		return warnings, fmt.Errorf("Error reloading NGINX for %v/%v: %v", ingEx.Ingress.Namespace, ingEx.Ingress.Name, err)
 	}
 
 	return warnings, nil
 }
 
+// retryRejectedConfig makes the Configurator generate the config of the resource even if NGINX rejected
+// the previous config of the resource. It returns true if the previous config was rejected.
+func (cnf *Configurator) retryRejectedConfig(name string) bool {
+	wasRejected := cnf.rejectedConfigs[name]
+	delete(cnf.rejectedConfigs, name)
+
+	return wasRejected
+}
+
+// skipRejectedConfig checks if the config of the resource was rejected by NGINX. NGINX Manager restores
+// the files of a rejected config, so the Configurator keeps the last accepted config of such a resource until
+// the resource is updated. Otherwise, the regeneration of the configs of multiple resources, for example,
+// after a ConfigMap update, would write the rejected config again and fail for all resources.
+func (cnf *Configurator) skipRejectedConfig(name string, obj runtime.Object) (Warnings, bool) {
+	if !cnf.rejectedConfigs[name] {
+		return nil, false
+	}
+
+	glog.V(3).Infof("Skipping the config %v, which was rejected by NGINX", name)
+
+	warnings := newWarnings()
+	warnings.AddWarning(obj, rejectedConfigWarning)
+
+	return warnings, true
+}
+
 func (cnf *Configurator) addOrUpdateIngress(ingEx *IngressEx) (Warnings, error) {
+	if warnings, skip := cnf.skipRejectedConfig(objectMetaToFileName(&ingEx.Ingress.ObjectMeta), ingEx.Ingress); skip {
+		return warnings, nil
+	}
+
 	apResources := cnf.updateApResources(ingEx)
 
 	if jwtKey, exists := ingEx.Ingress.Annotations[JWTKeyAnnotation]; exists {
@@ -284,12 +339,28 @@ func (cnf *Configurator) addOrUpdateIngress(ingEx *IngressEx) (Warnings, error)
 
 // AddOrUpdateMergeableIngress adds or updates NGINX configuration for the Ingress resources with Mergeable Types.
 func (cnf *Configurator) AddOrUpdateMergeableIngress(mergeableIngs *MergeableIngresses) (Warnings, error) {
+	name := objectMetaToFileName(&mergeableIngs.Master.Ingress.ObjectMeta)
+	acceptedMaster, accepted := cnf.ingresses[name]
+	acceptedMinions := cnf.minions[name]
+	wasRejected := cnf.retryRejectedConfig(name)
+
 	warnings, err := cnf.addOrUpdateMergeableIngress(mergeableIngs)
 	if err != nil {
+		cnf.rejectedConfigs[name] = wasRejected
 		return warnings, fmt.Errorf("Error when adding or updating ingress %v/%v: %v", mergeableIngs.Master.Ingress.Namespace, mergeableIngs.Master.Ingress.Name, err)
 	}
 
-	if err := cnf.nginxManager.Reload(nginx.ReloadForOtherUpdate); err != nil {
+	if err := cnf.reload(nginx.ReloadForOtherUpdate); err != nil {
+		if errors.Is(err, nginx.ErrConfigTestFailed) {
+			if accepted {
+				cnf.ingresses[name] = acceptedMaster
+				cnf.minions[name] = acceptedMinions
+			} else {
+				delete(cnf.ingresses, name)
+				delete(cnf.minions, name)
+			}
+			cnf.rejectedConfigs[name] = true
+		}
 		return warnings, fmt.Errorf("Error reloading NGINX for %v/%v: %v", mergeableIngs.Master.Ingress.Namespace, mergeableIngs.Master.Ingress.Name, err)
 	}
 
@@ -297,6 +368,10 @@ func (cnf *Configurator) AddOrUpdateMergeableIngress(mergeableIngs *MergeableIng
 }
 
 func (cnf *Configurator) addOrUpdateMergeableIngress(mergeableIngs *MergeableIngresses) (Warnings, error) {
+	if warnings, skip := cnf.skipRejectedConfig(objectMetaToFileName(&mergeableIngs.Master.Ingress.ObjectMeta), mergeableIngs.Master.Ingress); skip {
+		return warnings, nil
+	}
+
 	masterApResources := cnf.updateApResources(mergeableIngs.Master)
 
 	// LocalSecretStore will not set Path if the secret is not on the filesystem.
@@ -415,28 +490,107 @@ func (cnf *Configurator) deleteVirtualServerMetricsLabels(key string) {
 
 // AddOrUpdateVirtualServer adds or updates NGINX configuration for the VirtualServer resource.
 func (cnf *Configurator) AddOrUpdateVirtualServer(virtualServerEx *VirtualServerEx) (Warnings, error) {
+	name := getFileNameForVirtualServer(virtualServerEx.VirtualServer)
+	acceptedVirtualServerEx, accepted := cnf.virtualServers[name]
+	acceptedCachePaths := cnf.cachePaths[name]
+	wasRejected := cnf.retryRejectedConfig(name)
+
 	warnings, err := cnf.addOrUpdateVirtualServer(virtualServerEx)
 	if err != nil {
+		cnf.rejectedConfigs[name] = wasRejected
 		return warnings, fmt.Errorf("Error adding or updating VirtualServer %v/%v: %v", virtualServerEx.VirtualServer.Namespace, virtualServerEx.VirtualServer.Name, err)
 	}
 
-	if err := cnf.nginxManager.Reload(nginx.ReloadForOtherUpdate); err != nil {
+	if err := cnf.reload(nginx.ReloadForOtherUpdate); err != nil {
+		if errors.Is(err, nginx.ErrConfigTestFailed) {
+			if accepted {
+				cnf.virtualServers[name] = acceptedVirtualServerEx
+			} else {
+				delete(cnf.virtualServers, name)
+			}
+			cnf.setCachePaths(name, acceptedCachePaths)
+			cnf.rejectedConfigs[name] = true
+		}
 		return warnings, fmt.Errorf("Error reloading NGINX for VirtualServer %v/%v: %v", virtualServerEx.VirtualServer.Namespace, virtualServerEx.VirtualServer.Name, err)
 	}
 
 	return warnings, nil
 }
 
+// updateCachePaths updates the paths of the cache zones of the VirtualServer. The paths that are no longer used
+// are deleted after the next successful reload.
+func (cnf *Configurator) updateCachePaths(name string, zones []version2.CacheZone) {
+	paths := make(map[string]bool)
+	for _, z := range zones {
+		paths[z.Path] = true
+	}
+
+	for p := range cnf.cachePaths[name] {
+		if !paths[p] {
+			cnf.unusedCachePaths[p] = true
+		}
+	}
+
+	cnf.setCachePaths(name, paths)
+}
+
+func (cnf *Configurator) setCachePaths(name string, paths map[string]bool) {
+	if len(paths) == 0 {
+		delete(cnf.cachePaths, name)
+		return
+	}
+
+	cnf.cachePaths[name] = paths
+}
+
+func (cnf *Configurator) isCachePathUsed(path string) bool {
+	for _, paths := range cnf.cachePaths {
+		if paths[path] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reload reloads NGINX. If the reload succeeds, it deletes the folders of the cache zones that are no longer used,
+// so that the cached responses of the deleted cache policies and VirtualServers don't pile up on the disk.
+func (cnf *Configurator) reload(isEndpointsUpdate bool) error {
+	if err := cnf.nginxManager.Reload(isEndpointsUpdate); err != nil {
+		// the restored config files might still use the zones
+		cnf.unusedCachePaths = make(map[string]bool)
+		return err
+	}
+
+	// with the coalesced reloads, the running NGINX uses the zones until the pending reload is applied
+	unusedCachePaths := cnf.unusedCachePaths
+	cnf.unusedCachePaths = make(map[string]bool)
+
+	cnf.nginxManager.RunAfterReload(func() {
+		for p := range unusedCachePaths {
+			if !cnf.isCachePathUsed(p) {
+				cnf.nginxManager.DeleteCacheFolder(p)
+			}
+		}
+	})
+
+	return nil
+}
+
 func (cnf *Configurator) addOrUpdateOpenTracingTracerConfig(content string) error {
 	err := cnf.nginxManager.CreateOpenTracingTracerConfig(content)
 	return err
 }
 
 func (cnf *Configurator) addOrUpdateVirtualServer(virtualServerEx *VirtualServerEx) (Warnings, error) {
-	apResources := cnf.updateApResourcesForVs(virtualServerEx)
-
 	name := getFileNameForVirtualServer(virtualServerEx.VirtualServer)
 
+	if warnings, skip := cnf.skipRejectedConfig(name, virtualServerEx.VirtualServer); skip {
+		return warnings, nil
+	}
+
+	apResources := cnf.updateApResourcesForVs(virtualServerEx)
+
 	vsc := newVirtualServerConfigurator(cnf.cfgParams, cnf.isPlus, cnf.IsResolverConfigured(), cnf.staticCfgParams)
 	vsCfg, warnings := vsc.GenerateVirtualServerConfig(virtualServerEx, apResources)
 	content, err := cnf.templateExecutorV2.ExecuteVirtualServerTemplate(&vsCfg)
@@ -446,6 +600,7 @@ func (cnf *Configurator) addOrUpdateVirtualServer(virtualServerEx *VirtualServer
 	cnf.nginxManager.CreateConfig(name, content)
 
 	cnf.virtualServers[name] = virtualServerEx
+	cnf.updateCachePaths(name, vsCfg.CacheZones)
 
 	if (cnf.isPlus && cnf.isPrometheusEnabled) || cnf.isLatencyMetricsEnabled {
 		cnf.updateVirtualServerMetricsLabels(virtualServerEx, vsCfg.Upstreams)
@@ -465,7 +620,7 @@ func (cnf *Configurator) AddOrUpdateVirtualServers(virtualServerExes []*VirtualS
 		allWarnings.Add(warnings)
 	}
 
-	if err := cnf.nginxManager.Reload(nginx.ReloadForOtherUpdate); err != nil {
+	if err := cnf.reload(nginx.ReloadForOtherUpdate); err != nil {
 		return allWarnings, fmt.Errorf("Error when reloading NGINX when updating Policy: %v", err)
 	}
 
@@ -542,12 +697,26 @@ func (cnf *Configurator) deleteTransportServerMetricsLabels(key string) {
 // AddOrUpdateTransportServer adds or updates NGINX configuration for the TransportServer resource.
 // It is a responsibility of the caller to check that the TransportServer references an existing listener.
 func (cnf *Configurator) AddOrUpdateTransportServer(transportServerEx *TransportServerEx) error {
+	name := getFileNameForTransportServer(transportServerEx.TransportServer)
+	key := generateNamespaceNameKey(&transportServerEx.TransportServer.ObjectMeta)
+	acceptedPair, accepted := cnf.tlsPassthroughPairs[key]
+	wasRejected := cnf.retryRejectedConfig(name)
+
 	err := cnf.addOrUpdateTransportServer(transportServerEx)
 	if err != nil {
+		cnf.rejectedConfigs[name] = wasRejected
 		return fmt.Errorf("Error adding or updating TransportServer %v/%v: %v", transportServerEx.TransportServer.Namespace, transportServerEx.TransportServer.Name, err)
 	}
 
-	if err := cnf.nginxManager.Reload(nginx.ReloadForOtherUpdate); err != nil {
+	if err := cnf.reload(nginx.ReloadForOtherUpdate); err != nil {
+		if errors.Is(err, nginx.ErrConfigTestFailed) {
+			if accepted {
+				cnf.tlsPassthroughPairs[key] = acceptedPair
+			} else {
+				delete(cnf.tlsPassthroughPairs, key)
+			}
+			cnf.rejectedConfigs[name] = true
+		}
 		return fmt.Errorf("Error reloading NGINX for TransportServer %v/%v: %v", transportServerEx.TransportServer.Namespace, transportServerEx.TransportServer.Name, err)
 	}
 
@@ -557,6 +726,10 @@ func (cnf *Configurator) AddOrUpdateTransportServer(transportServerEx *Transport
 func (cnf *Configurator) addOrUpdateTransportServer(transportServerEx *TransportServerEx) error {
 	name := getFileNameForTransportServer(transportServerEx.TransportServer)
 
+	if _, skip := cnf.skipRejectedConfig(name, transportServerEx.TransportServer); skip {
+		return nil
+	}
+
 	tsCfg := generateTransportServerConfig(transportServerEx, transportServerEx.ListenerPort, cnf.isPlus)
 
 	content, err := cnf.templateExecutorV2.ExecuteTransportServerTemplate(tsCfg)
@@ -630,6 +803,12 @@ func (cnf *Configurator) addOrUpdateJWKSecret(secret *api_v1.Secret) string {
 	return cnf.nginxManager.CreateSecret(name, data, nginx.JWKSecretFileMode)
 }
 
+func (cnf *Configurator) addOrUpdateHtpasswdSecret(secret *api_v1.Secret) string {
+	name := objectMetaToFileName(&secret.ObjectMeta)
+	data := secret.Data[secrets.HtpasswdKey]
+	return cnf.nginxManager.CreateSecret(name, data, nginx.HtpasswdSecretFileMode)
+}
+
 // AddOrUpdateResources adds or updates configuration for resources.
 func (cnf *Configurator) AddOrUpdateResources(resources ExtendedResources) (Warnings, error) {
 	allWarnings := newWarnings()
@@ -665,7 +844,7 @@ func (cnf *Configurator) AddOrUpdateResources(resources ExtendedResources) (Warn
 		}
 	}
 
-	if err := cnf.nginxManager.Reload(nginx.ReloadForOtherUpdate); err != nil {
+	if err := cnf.reload(nginx.ReloadForOtherUpdate); err != nil {
 		return allWarnings, fmt.Errorf("Error when reloading NGINX when updating resources: %v", err)
 	}
 
@@ -686,7 +865,7 @@ func (cnf *Configurator) AddOrUpdateSpecialTLSSecrets(secret *api_v1.Secret, sec
 		cnf.nginxManager.CreateSecret(secretName, data, nginx.TLSSecretFileMode)
 	}
 
-	if err := cnf.nginxManager.Reload(nginx.ReloadForOtherUpdate); err != nil {
+	if err := cnf.reload(nginx.ReloadForOtherUpdate); err != nil {
 		return fmt.Errorf("Error when reloading NGINX when updating the special Secrets: %v", err)
 	}
 
@@ -720,12 +899,13 @@ func (cnf *Configurator) DeleteIngress(key string) error {
 
 	delete(cnf.ingresses, name)
 	delete(cnf.minions, name)
+	delete(cnf.rejectedConfigs, name)
 
 	if (cnf.isPlus && cnf.isPrometheusEnabled) || cnf.isLatencyMetricsEnabled {
 		cnf.deleteIngressMetricsLabels(key)
 	}
 
-	if err := cnf.nginxManager.Reload(nginx.ReloadForOtherUpdate); err != nil {
+	if err := cnf.reload(nginx.ReloadForOtherUpdate); err != nil {
 		return fmt.Errorf("Error when removing ingress %v: %v", key, err)
 	}
 
@@ -738,11 +918,13 @@ func (cnf *Configurator) DeleteVirtualServer(key string) error {
 	cnf.nginxManager.DeleteConfig(name)
 
 	delete(cnf.virtualServers, name)
+	delete(cnf.rejectedConfigs, name)
+	cnf.updateCachePaths(name, nil)
 	if (cnf.isPlus && cnf.isPrometheusEnabled) || cnf.isLatencyMetricsEnabled {
 		cnf.deleteVirtualServerMetricsLabels(fmt.Sprintf(key))
 	}
 
-	if err := cnf.nginxManager.Reload(nginx.ReloadForOtherUpdate); err != nil {
+	if err := cnf.reload(nginx.ReloadForOtherUpdate); err != nil {
 		return fmt.Errorf("Error when removing VirtualServer %v: %v", key, err)
 	}
 
@@ -760,7 +942,7 @@ func (cnf *Configurator) DeleteTransportServer(key string) error {
 		return fmt.Errorf("Error when removing TransportServer %v: %v", key, err)
 	}
 
-	err = cnf.nginxManager.Reload(nginx.ReloadForOtherUpdate)
+	err = cnf.reload(nginx.ReloadForOtherUpdate)
 	if err != nil {
 		return fmt.Errorf("Error when removing TransportServer %v: %v", key, err)
 	}
@@ -771,6 +953,7 @@ func (cnf *Configurator) DeleteTransportServer(key string) error {
 func (cnf *Configurator) deleteTransportServer(key string) error {
 	name := getFileNameForTransportServerFromKey(key)
 	cnf.nginxManager.DeleteStreamConfig(name)
+	delete(cnf.rejectedConfigs, name)
 
 	// update TLS Passthrough Hosts config in case we have a TLS Passthrough TransportServer
 	if _, exists := cnf.tlsPassthroughPairs[key]; exists {
@@ -807,7 +990,7 @@ func (cnf *Configurator) UpdateEndpoints(ingExes []*IngressEx) error {
 		return nil
 	}
 
-	if err := cnf.nginxManager.Reload(nginx.ReloadForEndpointsUpdate); err != nil {
+	if err := cnf.reload(nginx.ReloadForEndpointsUpdate); err != nil {
 		return fmt.Errorf("Error reloading NGINX when updating endpoints: %v", err)
 	}
 
@@ -841,7 +1024,7 @@ func (cnf *Configurator) UpdateEndpointsMergeableIngress(mergeableIngresses []*M
 		return nil
 	}
 
-	if err := cnf.nginxManager.Reload(nginx.ReloadForEndpointsUpdate); err != nil {
+	if err := cnf.reload(nginx.ReloadForEndpointsUpdate); err != nil {
 		return fmt.Errorf("Error reloading NGINX when updating endpoints for %v: %v", mergeableIngresses, err)
 	}
 
@@ -873,7 +1056,7 @@ func (cnf *Configurator) UpdateEndpointsForVirtualServers(virtualServerExes []*V
 		return nil
 	}
 
-	if err := cnf.nginxManager.Reload(nginx.ReloadForEndpointsUpdate); err != nil {
+	if err := cnf.reload(nginx.ReloadForEndpointsUpdate); err != nil {
 		return fmt.Errorf("Error reloading NGINX when updating endpoints: %v", err)
 	}
 
@@ -920,7 +1103,7 @@ func (cnf *Configurator) UpdateEndpointsForTransportServers(transportServerExes
 		return nil
 	}
 
-	if err := cnf.nginxManager.Reload(nginx.ReloadForEndpointsUpdate); err != nil {
+	if err := cnf.reload(nginx.ReloadForEndpointsUpdate); err != nil {
 		return fmt.Errorf("Error reloading NGINX when updating endpoints: %v", err)
 	}
 
@@ -956,13 +1139,13 @@ func (cnf *Configurator) updatePlusEndpoints(ingEx *IngressEx) error {
 		SlowStart:   ingCfg.SlowStart,
 	}
 
-	if ingEx.Ingress.Spec.Backend != nil {
-		endps, exists := ingEx.Endpoints[ingEx.Ingress.Spec.Backend.ServiceName+ingEx.Ingress.Spec.Backend.ServicePort.String()]
+	if ingEx.Ingress.Spec.DefaultBackend != nil && !isResourceBackend(ingEx.Ingress.Spec.DefaultBackend) {
+		endps, exists := ingEx.Endpoints[GetKeyForIngressBackend(ingEx.Ingress.Spec.DefaultBackend)]
 		if exists {
-			if _, isExternalName := ingEx.ExternalNameSvcs[ingEx.Ingress.Spec.Backend.ServiceName]; isExternalName {
-				glog.V(3).Infof("Service %s is Type ExternalName, skipping NGINX Plus endpoints update via API", ingEx.Ingress.Spec.Backend.ServiceName)
+			if _, isExternalName := ingEx.ExternalNameSvcs[ingEx.Ingress.Spec.DefaultBackend.Service.Name]; isExternalName {
+				glog.V(3).Infof("Service %s is Type ExternalName, skipping NGINX Plus endpoints update via API", ingEx.Ingress.Spec.DefaultBackend.Service.Name)
 			} else {
-				name := getNameForUpstream(ingEx.Ingress, emptyHost, ingEx.Ingress.Spec.Backend)
+				name := getNameForUpstream(ingEx.Ingress, emptyHost, ingEx.Ingress.Spec.DefaultBackend)
 				err := cnf.nginxManager.UpdateServersInPlus(name, endps, cfg)
 				if err != nil {
 					return fmt.Errorf("Couldn't update the endpoints for %v: %v", name, err)
@@ -977,10 +1160,14 @@ func (cnf *Configurator) updatePlusEndpoints(ingEx *IngressEx) error {
 		}
 
 		for _, path := range rule.HTTP.Paths {
-			endps, exists := ingEx.Endpoints[path.Backend.ServiceName+path.Backend.ServicePort.String()]
+			if isResourceBackend(&path.Backend) {
+				continue
+			}
+
+			endps, exists := ingEx.Endpoints[GetKeyForIngressBackend(&path.Backend)]
 			if exists {
-				if _, isExternalName := ingEx.ExternalNameSvcs[path.Backend.ServiceName]; isExternalName {
-					glog.V(3).Infof("Service %s is Type ExternalName, skipping NGINX Plus endpoints update via API", path.Backend.ServiceName)
+				if _, isExternalName := ingEx.ExternalNameSvcs[path.Backend.Service.Name]; isExternalName {
+					glog.V(3).Infof("Service %s is Type ExternalName, skipping NGINX Plus endpoints update via API", path.Backend.Service.Name)
 					continue
 				}
 
@@ -1066,7 +1253,7 @@ func (cnf *Configurator) UpdateConfig(cfgParams *ConfigParams, ingExes []*Ingres
 	}
 
 	cnf.nginxManager.SetOpenTracing(mainCfg.OpenTracingLoadModule)
-	if err := cnf.nginxManager.Reload(nginx.ReloadForOtherUpdate); err != nil {
+	if err := cnf.reload(nginx.ReloadForOtherUpdate); err != nil {
 		return allWarnings, fmt.Errorf("Error when updating config from ConfigMap: %v", err)
 	}
 
@@ -1089,7 +1276,7 @@ func (cnf *Configurator) UpdateTransportServers(updatedTSExes []*TransportServer
 		}
 	}
 
-	if err := cnf.nginxManager.Reload(nginx.ReloadForOtherUpdate); err != nil {
+	if err := cnf.reload(nginx.ReloadForOtherUpdate); err != nil {
 		return fmt.Errorf("Error when updating TransportServers: %v", err)
 	}
 
@@ -1195,7 +1382,7 @@ func (cnf *Configurator) AddOrUpdateSpiffeCerts(svidResponse *workload.X509SVIDs
 	cnf.nginxManager.CreateSecret(spiffeCertFileName, createSpiffeCert(svid.Certificates), spiffeCertsFileMode)
 	cnf.nginxManager.CreateSecret(spiffeBundleFileName, createSpiffeCert(svid.TrustBundle), spiffeCertsFileMode)
 
-	err = cnf.nginxManager.Reload(nginx.ReloadForOtherUpdate)
+	err = cnf.reload(nginx.ReloadForOtherUpdate)
 	if err != nil {
 		return fmt.Errorf("error when reloading NGINX when updating the SPIFFE Certs: %v", err)
 	}
@@ -1311,7 +1498,7 @@ func (cnf *Configurator) AddOrUpdateAppProtectResource(resource *unstructured.Un
 		allWarnings.Add(warnings)
 	}
 
-	if err := cnf.nginxManager.Reload(nginx.ReloadForOtherUpdate); err != nil {
+	if err := cnf.reload(nginx.ReloadForOtherUpdate); err != nil {
 		return allWarnings, fmt.Errorf("Error when reloading NGINX when updating %v: %v", resource.GetKind(), err)
 	}
 
@@ -1352,7 +1539,7 @@ func (cnf *Configurator) DeleteAppProtectPolicy(polNamespaceName string, ingExes
 		allWarnings.Add(warnings)
 	}
 
-	if err := cnf.nginxManager.Reload(nginx.ReloadForOtherUpdate); err != nil {
+	if err := cnf.reload(nginx.ReloadForOtherUpdate); err != nil {
 		return allWarnings, fmt.Errorf("Error when reloading NGINX when removing App Protect Policy: %v", err)
 	}
 
@@ -1392,7 +1579,7 @@ func (cnf *Configurator) DeleteAppProtectLogConf(logConfNamespaceName string, in
 		allWarnings.Add(warnings)
 	}
 
-	if err := cnf.nginxManager.Reload(nginx.ReloadForOtherUpdate); err != nil {
+	if err := cnf.reload(nginx.ReloadForOtherUpdate); err != nil {
 		return allWarnings, fmt.Errorf("Error when reloading NGINX when removing App Protect Log Configuration: %v", err)
 	}
 
@@ -1441,7 +1628,7 @@ func (cnf *Configurator) RefreshAppProtectUserSigs(
 		fmt.Fprintf(&builder, "app_protect_user_defined_signatures %s;\n", fName)
 	}
 	cnf.nginxManager.CreateAppProtectResourceFile(appProtectUserSigIndex, []byte(builder.String()))
-	return allWarnings, cnf.nginxManager.Reload(nginx.ReloadForOtherUpdate)
+	return allWarnings, cnf.reload(nginx.ReloadForOtherUpdate)
 }
 
 // AddInternalRouteConfig adds internal route server to NGINX Configuration and reloads NGINX
@@ -1454,7 +1641,7 @@ func (cnf *Configurator) AddInternalRouteConfig() error {
 		return fmt.Errorf("Error when writing main Config: %v", err)
 	}
 	cnf.nginxManager.CreateMainConfig(mainCfgContent)
-	if err := cnf.nginxManager.Reload(nginx.ReloadForOtherUpdate); err != nil {
+	if err := cnf.reload(nginx.ReloadForOtherUpdate); err != nil {
 		return fmt.Errorf("Error when reloading nginx: %v", err)
 	}
 	return nil
@@ -1467,6 +1654,8 @@ func (cnf *Configurator) AddOrUpdateSecret(secret *api_v1.Secret) string {
 		return cnf.addOrUpdateCASecret(secret)
 	case secrets.SecretTypeJWK:
 		return cnf.addOrUpdateJWKSecret(secret)
+	case secrets.SecretTypeHtpasswd:
+		return cnf.addOrUpdateHtpasswdSecret(secret)
 	case secrets.SecretTypeOIDC:
 		// OIDC ClientSecret is not required on the filesystem, it is written directly to the config file.
 		return ""
@@ -1,16 +1,20 @@
 package configs
 
 import (
+	"fmt"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
-	networking "k8s.io/api/networking/v1beta1"
+	networking "k8s.io/api/networking/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/nginxinc/kubernetes-ingress/internal/configs/version1"
 	"github.com/nginxinc/kubernetes-ingress/internal/configs/version2"
+	"github.com/nginxinc/kubernetes-ingress/internal/metrics/collectors"
 	"github.com/nginxinc/kubernetes-ingress/internal/nginx"
 	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
 	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
@@ -207,6 +211,295 @@ func TestUpdateEndpointsMergeableIngressFailsWithInvalidTemplate(t *testing.T) {
 	}
 }
 
+// rollbackTestManager is a fake Manager, which rejects the configs with an invalid directive
+// and restores the changed configs, like the LocalManager does when the NGINX config test fails.
+type rollbackTestManager struct {
+	*nginx.FakeManager
+	configs map[string]string
+	backups map[string]string
+}
+
+func newRollbackTestManager() *rollbackTestManager {
+	return &rollbackTestManager{
+		FakeManager: nginx.NewFakeManager("/etc/nginx"),
+		configs:     make(map[string]string),
+		backups:     make(map[string]string),
+	}
+}
+
+func (m *rollbackTestManager) CreateConfig(name string, content []byte) {
+	if _, exists := m.backups[name]; !exists {
+		m.backups[name] = m.configs[name]
+	}
+	m.configs[name] = string(content)
+}
+
+func (m *rollbackTestManager) Reload(isEndpointsUpdate bool) error {
+	defer func() {
+		m.backups = make(map[string]string)
+	}()
+
+	for name, content := range m.configs {
+		if strings.Contains(content, "invalid_directive") {
+			for name, backup := range m.backups {
+				m.configs[name] = backup
+			}
+			return fmt.Errorf("%w: unknown directive in %v", nginx.ErrConfigTestFailed, name)
+		}
+	}
+
+	return nil
+}
+
+func TestUpdatesAfterRejectedVirtualServer(t *testing.T) {
+	cnf, err := createTestConfigurator()
+	if err != nil {
+		t.Fatalf("Failed to create a test configurator: %v", err)
+	}
+
+	manager := newRollbackTestManager()
+	cnf.nginxManager = manager
+	cnf.staticCfgParams.EnableSnippets = true
+
+	createVirtualServerEx := func(name string, snippets string) *VirtualServerEx {
+		return &VirtualServerEx{
+			VirtualServer: &conf_v1.VirtualServer{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      name,
+					Namespace: "default",
+				},
+				Spec: conf_v1.VirtualServerSpec{
+					Host:           name + ".example.com",
+					ServerSnippets: snippets,
+				},
+			},
+		}
+	}
+
+	cafe := createVirtualServerEx("cafe", "")
+	invalidCafe := createVirtualServerEx("cafe", "invalid_directive;")
+	tea := createVirtualServerEx("tea", "")
+
+	if _, err := cnf.AddOrUpdateVirtualServer(cafe); err != nil {
+		t.Fatalf("AddOrUpdateVirtualServer() returned unexpected error: %v", err)
+	}
+
+	if _, err := cnf.AddOrUpdateVirtualServer(invalidCafe); err == nil {
+		t.Fatalf("AddOrUpdateVirtualServer() returned no error for the VirtualServer with an invalid config")
+	}
+	if cnf.virtualServers["vs_default_cafe"] != cafe {
+		t.Errorf("AddOrUpdateVirtualServer() didn't restore the last accepted VirtualServer after the config test error")
+	}
+
+	// a regeneration of all configs, for example, after a ConfigMap update, must not write the rejected config again
+	warnings, err := cnf.UpdateConfig(NewDefaultConfigParams(), nil, nil, []*VirtualServerEx{invalidCafe, tea})
+	if err != nil {
+		t.Errorf("UpdateConfig() returned unexpected error: %v", err)
+	}
+	if len(warnings[invalidCafe.VirtualServer]) != 1 {
+		t.Errorf("UpdateConfig() returned warnings %v but expected a warning for the rejected VirtualServer", warnings)
+	}
+
+	if _, err := cnf.AddOrUpdateVirtualServer(tea); err != nil {
+		t.Errorf("AddOrUpdateVirtualServer() returned unexpected error for an unrelated VirtualServer: %v", err)
+	}
+	if strings.Contains(manager.configs["vs_default_cafe"], "invalid_directive") {
+		t.Errorf("The rejected config of the VirtualServer was written again")
+	}
+
+	// an update of the rejected VirtualServer must generate its config
+	fixedCafe := createVirtualServerEx("cafe", "")
+	if _, err := cnf.AddOrUpdateVirtualServer(fixedCafe); err != nil {
+		t.Errorf("AddOrUpdateVirtualServer() returned unexpected error for the fixed VirtualServer: %v", err)
+	}
+	if cnf.virtualServers["vs_default_cafe"] != fixedCafe || cnf.rejectedConfigs["vs_default_cafe"] {
+		t.Errorf("AddOrUpdateVirtualServer() didn't accept the fixed VirtualServer")
+	}
+}
+
+// cacheTestManager is a fake Manager, which remembers the deleted cache folders.
+type cacheTestManager struct {
+	*nginx.FakeManager
+	deletedCacheFolders []string
+}
+
+func (m *cacheTestManager) DeleteCacheFolder(path string) {
+	m.deletedCacheFolders = append(m.deletedCacheFolders, path)
+}
+
+func TestDeleteUnusedCacheFolders(t *testing.T) {
+	cnf, err := createTestConfigurator()
+	if err != nil {
+		t.Fatalf("Failed to create a test configurator: %v", err)
+	}
+
+	manager := &cacheTestManager{
+		FakeManager: nginx.NewFakeManager("/etc/nginx"),
+	}
+	cnf.nginxManager = manager
+
+	createVirtualServerEx := func(name string, policy string) *VirtualServerEx {
+		vsEx := &VirtualServerEx{
+			VirtualServer: &conf_v1.VirtualServer{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      name,
+					Namespace: "default",
+				},
+				Spec: conf_v1.VirtualServerSpec{
+					Host: name + ".example.com",
+					Routes: []conf_v1.Route{
+						{
+							Path: "/",
+							Action: &conf_v1.Action{
+								Return: &conf_v1.ActionReturn{
+									Body: "hello",
+								},
+							},
+						},
+					},
+				},
+			},
+			Policies: map[string]*conf_v1.Policy{
+				"default/cache": {
+					Spec: conf_v1.PolicySpec{
+						Cache: &conf_v1.Cache{
+							ZoneSize: "10m",
+						},
+					},
+				},
+				"default/cache-with-path": {
+					Spec: conf_v1.PolicySpec{
+						Cache: &conf_v1.Cache{
+							ZoneSize: "10m",
+							Path:     "/data/cache",
+						},
+					},
+				},
+			},
+		}
+		if policy != "" {
+			vsEx.VirtualServer.Spec.Policies = []conf_v1.PolicyReference{{Name: policy}}
+		}
+		return vsEx
+	}
+
+	steps := []struct {
+		apply    func() error
+		expected []string
+		msg      string
+	}{
+		{
+			apply: func() error {
+				_, err := cnf.AddOrUpdateVirtualServers([]*VirtualServerEx{createVirtualServerEx("cafe", "cache"), createVirtualServerEx("tea", "cache")})
+				return err
+			},
+			expected: nil,
+			msg:      "add VirtualServers with cache policies",
+		},
+		{
+			apply: func() error {
+				_, err := cnf.AddOrUpdateVirtualServer(createVirtualServerEx("cafe", "cache-with-path"))
+				return err
+			},
+			expected: []string{"/var/cache/nginx/pol_cache_default_cache_default_cafe"},
+			msg:      "change the cache path",
+		},
+		{
+			apply: func() error {
+				_, err := cnf.AddOrUpdateVirtualServer(createVirtualServerEx("cafe", ""))
+				return err
+			},
+			expected: []string{"/data/cache/pol_cache_default_cache-with-path_default_cafe"},
+			msg:      "remove the cache policy",
+		},
+		{
+			apply: func() error {
+				return cnf.DeleteVirtualServer("default/tea")
+			},
+			expected: []string{"/var/cache/nginx/pol_cache_default_cache_default_tea"},
+			msg:      "delete the VirtualServer",
+		},
+	}
+
+	for _, step := range steps {
+		manager.deletedCacheFolders = nil
+
+		if err := step.apply(); err != nil {
+			t.Fatalf("Failed to %s: %v", step.msg, err)
+		}
+		if !reflect.DeepEqual(manager.deletedCacheFolders, step.expected) {
+			t.Errorf("Deleted cache folders %v but expected %v for the case of %s", manager.deletedCacheFolders, step.expected, step.msg)
+		}
+	}
+}
+
+func TestDeleteUnusedCacheFoldersAfterCoalescedReload(t *testing.T) {
+	cnf, err := createTestConfigurator()
+	if err != nil {
+		t.Fatalf("Failed to create a test configurator: %v", err)
+	}
+
+	manager := &cacheTestManager{
+		FakeManager: nginx.NewFakeManager("/etc/nginx"),
+	}
+	reloadCoalescer := nginx.NewReloadCoalescer(manager, time.Hour, time.Hour, collectors.NewManagerFakeCollector())
+	cnf.nginxManager = reloadCoalescer
+
+	vsEx := &VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host:     "cafe.example.com",
+				Policies: []conf_v1.PolicyReference{{Name: "cache"}},
+				Routes: []conf_v1.Route{
+					{
+						Path: "/",
+						Action: &conf_v1.Action{
+							Return: &conf_v1.ActionReturn{
+								Body: "hello",
+							},
+						},
+					},
+				},
+			},
+		},
+		Policies: map[string]*conf_v1.Policy{
+			"default/cache": {
+				Spec: conf_v1.PolicySpec{
+					Cache: &conf_v1.Cache{
+						ZoneSize: "10m",
+					},
+				},
+			},
+		},
+	}
+
+	if _, err := cnf.AddOrUpdateVirtualServer(vsEx); err != nil {
+		t.Fatalf("Failed to add the VirtualServer: %v", err)
+	}
+	if err := reloadCoalescer.ApplyPendingReload(); err != nil {
+		t.Fatalf("ApplyPendingReload() returned unexpected error: %v", err)
+	}
+
+	if err := cnf.DeleteVirtualServer("default/cafe"); err != nil {
+		t.Fatalf("Failed to delete the VirtualServer: %v", err)
+	}
+	if manager.deletedCacheFolders != nil {
+		t.Errorf("Deleted cache folders %v before the pending reload was applied", manager.deletedCacheFolders)
+	}
+
+	if err := reloadCoalescer.ApplyPendingReload(); err != nil {
+		t.Fatalf("ApplyPendingReload() returned unexpected error: %v", err)
+	}
+	expected := []string{"/var/cache/nginx/pol_cache_default_cache_default_cafe"}
+	if !reflect.DeepEqual(manager.deletedCacheFolders, expected) {
+		t.Errorf("Deleted cache folders %v but expected %v after the pending reload was applied", manager.deletedCacheFolders, expected)
+	}
+}
+
 func TestGetVirtualServerConfigFileName(t *testing.T) {
 	vs := conf_v1.VirtualServer{
 		ObjectMeta: meta_v1.ObjectMeta{
@@ -3,12 +3,13 @@ package configs
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/golang/glog"
 	"github.com/nginxinc/kubernetes-ingress/internal/k8s/secrets"
 	api_v1 "k8s.io/api/core/v1"
-	networking "k8s.io/api/networking/v1beta1"
+	networking "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -75,21 +76,27 @@ func generateNginxCfg(ingEx *IngressEx, apResources map[string]string, isMinion
 		grpcServices = make(map[string]bool)
 	}
 
-	if ingEx.Ingress.Spec.Backend != nil {
-		name := getNameForUpstream(ingEx.Ingress, emptyHost, ingEx.Ingress.Spec.Backend)
-		upstream := createUpstream(ingEx, name, ingEx.Ingress.Spec.Backend, spServices[ingEx.Ingress.Spec.Backend.ServiceName], &cfgParams,
+	allWarnings := newWarnings()
+
+	defaultBackend := ingEx.Ingress.Spec.DefaultBackend
+	if defaultBackend != nil && isResourceBackend(defaultBackend) {
+		allWarnings.AddWarning(ingEx.Ingress, "The default backend is ignored: resource backends are not supported")
+		defaultBackend = nil
+	}
+
+	if defaultBackend != nil {
+		name := getNameForUpstream(ingEx.Ingress, emptyHost, defaultBackend)
+		upstream := createUpstream(ingEx, name, defaultBackend, spServices[defaultBackend.Service.Name], &cfgParams,
 			isPlus, isResolverConfigured, staticParams.EnableLatencyMetrics)
 		upstreams[name] = upstream
 
 		if cfgParams.HealthCheckEnabled {
-			if hc, exists := ingEx.HealthChecks[ingEx.Ingress.Spec.Backend.ServiceName+ingEx.Ingress.Spec.Backend.ServicePort.String()]; exists {
+			if hc, exists := ingEx.HealthChecks[GetKeyForIngressBackend(defaultBackend)]; exists {
 				healthChecks[name] = createHealthCheck(hc, name, &cfgParams)
 			}
 		}
 	}
 
-	allWarnings := newWarnings()
-
 	var servers []version1.Server
 
 	for _, rule := range ingEx.Ingress.Spec.Rules {
@@ -113,6 +120,8 @@ func generateNginxCfg(ingEx *IngressEx, apResources map[string]string, isMinion
 			Name:                  serverName,
 			ServerTokens:          cfgParams.ServerTokens,
 			HTTP2:                 cfgParams.HTTP2,
+			HTTP3:                 cfgParams.HTTP3 && staticParams.HTTP3Supported,
+			AltSvc:                generateAltSvc(cfgParams.HTTP3 && staticParams.HTTP3Supported, cfgParams.SSLPorts),
 			RedirectToHTTPS:       cfgParams.RedirectToHTTPS,
 			SSLRedirect:           cfgParams.SSLRedirect,
 			ProxyProtocol:         cfgParams.ProxyProtocol,
@@ -157,10 +166,16 @@ func generateNginxCfg(ingEx *IngressEx, apResources map[string]string, isMinion
 
 		rootLocation := false
 
+		exactLocationPaths := getExactIngressLocationPaths(httpIngressRuleValue.Paths)
+		locationPaths := make(map[string]bool)
+
 		grpcOnly := true
 		if len(grpcServices) > 0 {
 			for _, path := range httpIngressRuleValue.Paths {
-				if _, exists := grpcServices[path.Backend.ServiceName]; !exists {
+				if isResourceBackend(&path.Backend) {
+					continue
+				}
+				if _, exists := grpcServices[path.Backend.Service.Name]; !exists {
 					grpcOnly = false
 					break
 				}
@@ -175,57 +190,77 @@ func generateNginxCfg(ingEx *IngressEx, apResources map[string]string, isMinion
 				continue
 			}
 
+			if isResourceBackend(&path.Backend) {
+				allWarnings.AddWarningf(ingEx.Ingress, "The path %s of the host %s is ignored: resource backends are not supported", pathOrDefault(path.Path), rule.Host)
+				continue
+			}
+
 			upsName := getNameForUpstream(ingEx.Ingress, rule.Host, &path.Backend)
 
 			if cfgParams.HealthCheckEnabled {
-				if hc, exists := ingEx.HealthChecks[path.Backend.ServiceName+path.Backend.ServicePort.String()]; exists {
+				if hc, exists := ingEx.HealthChecks[GetKeyForIngressBackend(&path.Backend)]; exists {
 					healthChecks[upsName] = createHealthCheck(hc, upsName, &cfgParams)
 				}
 			}
 
 			if _, exists := upstreams[upsName]; !exists {
-				upstream := createUpstream(ingEx, upsName, &path.Backend, spServices[path.Backend.ServiceName], &cfgParams, isPlus, isResolverConfigured, staticParams.EnableLatencyMetrics)
+				upstream := createUpstream(ingEx, upsName, &path.Backend, spServices[path.Backend.Service.Name], &cfgParams, isPlus, isResolverConfigured, staticParams.EnableLatencyMetrics)
 				upstreams[upsName] = upstream
 			}
 
-			ssl := isSSLEnabled(sslServices[path.Backend.ServiceName], cfgParams, staticParams)
-			proxySSLName := generateProxySSLName(path.Backend.ServiceName, ingEx.Ingress.Namespace)
-			loc := createLocation(pathOrDefault(path.Path), upstreams[upsName], &cfgParams, wsServices[path.Backend.ServiceName], rewrites[path.Backend.ServiceName],
-				ssl, grpcServices[path.Backend.ServiceName], proxySSLName, path.PathType, path.Backend.ServiceName)
+			ssl := isSSLEnabled(sslServices[path.Backend.Service.Name], cfgParams, staticParams)
+			proxySSLName := generateProxySSLName(path.Backend.Service.Name, ingEx.Ingress.Namespace)
 
+			var jwtAuth *version1.JWTAuth
 			if isMinion && cfgParams.JWTKey != "" {
-				jwtAuth, redirectLoc, warnings := generateJWTConfig(ingEx.Ingress, ingEx.SecretRefs, &cfgParams, getNameForRedirectLocation(ingEx.Ingress))
-				loc.JWTAuth = jwtAuth
+				var redirectLoc *version1.JWTRedirectLocation
+				var warnings Warnings
+				jwtAuth, redirectLoc, warnings = generateJWTConfig(ingEx.Ingress, ingEx.SecretRefs, &cfgParams, getNameForRedirectLocation(ingEx.Ingress))
 				if redirectLoc != nil {
 					server.JWTRedirectLocations = append(server.JWTRedirectLocations, *redirectLoc)
 				}
 				allWarnings.Add(warnings)
 			}
 
-			locations = append(locations, loc)
+			for _, locPath := range generateIngressLocationPaths(pathOrDefault(path.Path), path.PathType) {
+				// an Exact path takes precedence over the exact location of a Prefix path
+				if !isExactIngressPath(path) && exactLocationPaths[locPath] {
+					continue
+				}
+				if locationPaths[locPath] {
+					glog.Warningf("Ingress %s/%s: the location %q is already generated for another path of the host %s", ingEx.Ingress.Namespace, ingEx.Ingress.Name, locPath, rule.Host)
+					continue
+				}
+				locationPaths[locPath] = true
+
+				loc := createLocation(locPath, upstreams[upsName], &cfgParams, wsServices[path.Backend.Service.Name], rewrites[path.Backend.Service.Name],
+					ssl, grpcServices[path.Backend.Service.Name], proxySSLName, path.Backend.Service.Name)
+				loc.JWTAuth = jwtAuth
+
+				locations = append(locations, loc)
 
-			if loc.Path == "/" {
-				rootLocation = true
+				if loc.Path == "/" {
+					rootLocation = true
+				}
 			}
 		}
 
-		if !rootLocation && ingEx.Ingress.Spec.Backend != nil {
-			upsName := getNameForUpstream(ingEx.Ingress, emptyHost, ingEx.Ingress.Spec.Backend)
-			ssl := isSSLEnabled(sslServices[ingEx.Ingress.Spec.Backend.ServiceName], cfgParams, staticParams)
-			proxySSLName := generateProxySSLName(ingEx.Ingress.Spec.Backend.ServiceName, ingEx.Ingress.Namespace)
-			pathtype := networking.PathTypePrefix
+		if !rootLocation && defaultBackend != nil {
+			upsName := getNameForUpstream(ingEx.Ingress, emptyHost, defaultBackend)
+			ssl := isSSLEnabled(sslServices[defaultBackend.Service.Name], cfgParams, staticParams)
+			proxySSLName := generateProxySSLName(defaultBackend.Service.Name, ingEx.Ingress.Namespace)
 
-			loc := createLocation(pathOrDefault("/"), upstreams[upsName], &cfgParams, wsServices[ingEx.Ingress.Spec.Backend.ServiceName], rewrites[ingEx.Ingress.Spec.Backend.ServiceName],
-				ssl, grpcServices[ingEx.Ingress.Spec.Backend.ServiceName], proxySSLName, &pathtype, ingEx.Ingress.Spec.Backend.ServiceName)
+			loc := createLocation("/", upstreams[upsName], &cfgParams, wsServices[defaultBackend.Service.Name], rewrites[defaultBackend.Service.Name],
+				ssl, grpcServices[defaultBackend.Service.Name], proxySSLName, defaultBackend.Service.Name)
 			locations = append(locations, loc)
 
 			if cfgParams.HealthCheckEnabled {
-				if hc, exists := ingEx.HealthChecks[ingEx.Ingress.Spec.Backend.ServiceName+ingEx.Ingress.Spec.Backend.ServicePort.String()]; exists {
+				if hc, exists := ingEx.HealthChecks[GetKeyForIngressBackend(defaultBackend)]; exists {
 					healthChecks[upsName] = createHealthCheck(hc, upsName, &cfgParams)
 				}
 			}
 
-			if _, exists := grpcServices[ingEx.Ingress.Spec.Backend.ServiceName]; !exists {
+			if _, exists := grpcServices[defaultBackend.Service.Name]; !exists {
 				grpcOnly = false
 			}
 		}
@@ -345,20 +380,53 @@ func addSSLConfig(server *version1.Server, owner runtime.Object, host string, in
 	return warnings
 }
 
-func generateIngressPath(path string, pathType *networking.PathType) string {
+// generateIngressLocationPaths generates the paths of the NGINX locations for a path of an Ingress:
+// - An Exact path matches the request path exactly.
+// - A Prefix path matches the request path element by element, so "/foo" matches "/foo" and "/foo/bar", but not
+// "/foobar". It is generated as an exact location for the path and a prefix location for the elements below it.
+// A trailing slash of a Prefix path is ignored.
+// - An ImplementationSpecific path is an NGINX prefix location.
+func generateIngressLocationPaths(path string, pathType *networking.PathType) []string {
 	if pathType == nil {
-		return path
+		return []string{path}
 	}
-	if *pathType == networking.PathTypeExact {
-		path = "= " + path
+
+	switch *pathType {
+	case networking.PathTypeExact:
+		return []string{"= " + path}
+	case networking.PathTypePrefix:
+		trimmed := strings.TrimRight(path, "/")
+		if trimmed == "" {
+			return []string{"/"}
+		}
+		return []string{"= " + trimmed, trimmed + "/"}
 	}
 
-	return path
+	return []string{path}
+}
+
+func isExactIngressPath(path networking.HTTPIngressPath) bool {
+	return path.PathType != nil && *path.PathType == networking.PathTypeExact
+}
+
+// getExactIngressLocationPaths returns the paths of the NGINX locations generated for the Exact paths of an Ingress rule.
+func getExactIngressLocationPaths(paths []networking.HTTPIngressPath) map[string]bool {
+	result := make(map[string]bool)
+
+	for _, path := range paths {
+		if isExactIngressPath(path) {
+			for _, locPath := range generateIngressLocationPaths(pathOrDefault(path.Path), path.PathType) {
+				result[locPath] = true
+			}
+		}
+	}
+
+	return result
 }
 
-func createLocation(path string, upstream version1.Upstream, cfg *ConfigParams, websocket bool, rewrite string, ssl bool, grpc bool, proxySSLName string, pathType *networking.PathType, serviceName string) version1.Location {
+func createLocation(path string, upstream version1.Upstream, cfg *ConfigParams, websocket bool, rewrite string, ssl bool, grpc bool, proxySSLName string, serviceName string) version1.Location {
 	loc := version1.Location{
-		Path:                 generateIngressPath(path, pathType),
+		Path:                 path,
 		Upstream:             upstream,
 		ProxyConnectTimeout:  cfg.ProxyConnectTimeout,
 		ProxyReadTimeout:     cfg.ProxyReadTimeout,
@@ -396,13 +464,13 @@ func createUpstream(ingEx *IngressEx, name string, backend *networking.IngressBa
 	isPlus bool, isResolverConfigured bool, isLatencyMetricsEnabled bool) version1.Upstream {
 	var ups version1.Upstream
 	labels := version1.UpstreamLabels{
-		Service:           backend.ServiceName,
+		Service:           backend.Service.Name,
 		ResourceType:      "ingress",
 		ResourceName:      ingEx.Ingress.Name,
 		ResourceNamespace: ingEx.Ingress.Namespace,
 	}
 	if isPlus {
-		queue, timeout := upstreamRequiresQueue(backend.ServiceName+backend.ServicePort.String(), ingEx, cfg)
+		queue, timeout := upstreamRequiresQueue(GetKeyForIngressBackend(backend), ingEx, cfg)
 		ups = version1.Upstream{Name: name, StickyCookie: stickyCookie, Queue: queue, QueueTimeout: timeout, UpstreamLabels: labels}
 	} else {
 		ups = version1.NewUpstreamWithDefaultServer(name)
@@ -411,13 +479,13 @@ func createUpstream(ingEx *IngressEx, name string, backend *networking.IngressBa
 		}
 	}
 
-	endps, exists := ingEx.Endpoints[backend.ServiceName+backend.ServicePort.String()]
+	endps, exists := ingEx.Endpoints[GetKeyForIngressBackend(backend)]
 	if exists {
 		var upsServers []version1.UpstreamServer
 		// Always false for NGINX OSS
-		_, isExternalNameSvc := ingEx.ExternalNameSvcs[backend.ServiceName]
+		_, isExternalNameSvc := ingEx.ExternalNameSvcs[backend.Service.Name]
 		if isExternalNameSvc && !isResolverConfigured {
-			glog.Warningf("A resolver must be configured for Type ExternalName service %s, no upstream servers will be created", backend.ServiceName)
+			glog.Warningf("A resolver must be configured for Type ExternalName service %s, no upstream servers will be created", backend.Service.Name)
 			endps = []string{}
 		}
 
@@ -472,8 +540,29 @@ func pathOrDefault(path string) string {
 	return path
 }
 
+// GetBackendPortAsString returns the port of the service of an Ingress backend as a string: the name of the port if
+// it is set or the number of the port otherwise.
+func GetBackendPortAsString(port networking.ServiceBackendPort) string {
+	if port.Name != "" {
+		return port.Name
+	}
+
+	return strconv.Itoa(int(port.Number))
+}
+
+// isResourceBackend checks if the backend of an Ingress references a resource instead of a service.
+// The resource backends are not supported, so the paths with them are ignored.
+func isResourceBackend(backend *networking.IngressBackend) bool {
+	return backend.Service == nil
+}
+
+// GetKeyForIngressBackend returns the key of the endpoints and the health check of a service backend of an Ingress.
+func GetKeyForIngressBackend(backend *networking.IngressBackend) string {
+	return backend.Service.Name + GetBackendPortAsString(backend.Service.Port)
+}
+
 func getNameForUpstream(ing *networking.Ingress, host string, backend *networking.IngressBackend) string {
-	return fmt.Sprintf("%v-%v-%v-%v-%v", ing.Namespace, ing.Name, host, backend.ServiceName, backend.ServicePort.String())
+	return fmt.Sprintf("%v-%v-%v-%v-%v", ing.Namespace, ing.Name, host, backend.Service.Name, GetBackendPortAsString(backend.Service.Port))
 }
 
 func getNameForRedirectLocation(ing *networking.Ingress) string {
@@ -546,7 +635,7 @@ func generateNginxCfgForMergeableIngresses(mergeableIngs *MergeableIngresses, ma
 		minion.Ingress = minion.Ingress.DeepCopy()
 
 		// Remove the default backend so that "/" will not be generated
-		minion.Ingress.Spec.Backend = nil
+		minion.Ingress.Spec.DefaultBackend = nil
 
 		// Add acceptable master annotations to minion
 		mergeMasterAnnotationsIntoMinion(minion.Ingress.Annotations, mergeableIngs.Master.Ingress.Annotations)
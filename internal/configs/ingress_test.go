@@ -9,9 +9,8 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/nginxinc/kubernetes-ingress/internal/k8s/secrets"
 	v1 "k8s.io/api/core/v1"
-	networking "k8s.io/api/networking/v1beta1"
+	networking "k8s.io/api/networking/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/nginxinc/kubernetes-ingress/internal/configs/version1"
 )
@@ -120,6 +119,40 @@ func TestGenerateNginxCfgWithWildcardTLSSecret(t *testing.T) {
 	}
 }
 
+func TestGenerateNginxCfgWithResourceBackends(t *testing.T) {
+	cafeIngressEx := createCafeIngressEx()
+	assetsBackend := networking.IngressBackend{
+		Resource: &v1.TypedLocalObjectReference{
+			Kind: "StorageBucket",
+			Name: "static-assets",
+		},
+	}
+	cafeIngressEx.Ingress.Spec.DefaultBackend = &assetsBackend
+	cafeIngressEx.Ingress.Spec.Rules[0].HTTP.Paths = append(cafeIngressEx.Ingress.Spec.Rules[0].HTTP.Paths, networking.HTTPIngressPath{
+		Path:    "/assets",
+		Backend: assetsBackend,
+	})
+	configParams := NewDefaultConfigParams()
+
+	expected := createExpectedConfigForCafeIngressEx()
+	expectedWarnings := Warnings{
+		cafeIngressEx.Ingress: {
+			"The default backend is ignored: resource backends are not supported",
+			"The path /assets of the host cafe.example.com is ignored: resource backends are not supported",
+		},
+	}
+
+	apRes := make(map[string]string)
+	result, warnings := generateNginxCfg(&cafeIngressEx, apRes, false, configParams, false, false, &StaticConfigParams{}, false)
+
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("generateNginxCfg() returned unexpected result (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedWarnings, warnings); diff != "" {
+		t.Errorf("generateNginxCfg() returned unexpected warnings (-want +got):\n%s", diff)
+	}
+}
+
 func TestPathOrDefaultReturnDefault(t *testing.T) {
 	path := ""
 	expected := "/"
@@ -135,40 +168,76 @@ func TestPathOrDefaultReturnActual(t *testing.T) {
 	}
 }
 
-func TestGenerateIngressPath(t *testing.T) {
+func TestGenerateIngressLocationPaths(t *testing.T) {
 	exact := networking.PathTypeExact
 	prefix := networking.PathTypePrefix
 	impSpec := networking.PathTypeImplementationSpecific
 	tests := []struct {
 		pathType *networking.PathType
 		path     string
-		expected string
+		expected []string
 	}{
 		{
 			pathType: &exact,
 			path:     "/path/to/resource",
-			expected: "= /path/to/resource",
+			expected: []string{"= /path/to/resource"},
 		},
 		{
 			pathType: &prefix,
 			path:     "/path/to/resource",
-			expected: "/path/to/resource",
+			expected: []string{"= /path/to/resource", "/path/to/resource/"},
+		},
+		{
+			pathType: &prefix,
+			path:     "/path/to/resource/",
+			expected: []string{"= /path/to/resource", "/path/to/resource/"},
+		},
+		{
+			pathType: &prefix,
+			path:     "/",
+			expected: []string{"/"},
 		},
 		{
 			pathType: &impSpec,
 			path:     "/path/to/resource",
-			expected: "/path/to/resource",
+			expected: []string{"/path/to/resource"},
 		},
 		{
 			pathType: nil,
 			path:     "/path/to/resource",
-			expected: "/path/to/resource",
+			expected: []string{"/path/to/resource"},
+		},
+	}
+	for _, test := range tests {
+		result := generateIngressLocationPaths(test.path, test.pathType)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("generateIngressLocationPaths(%v, %v) returned %v, but expected %v", test.path, test.pathType, result, test.expected)
+		}
+	}
+}
+
+func TestGetBackendPortAsString(t *testing.T) {
+	tests := []struct {
+		port     networking.ServiceBackendPort
+		expected string
+	}{
+		{
+			port: networking.ServiceBackendPort{
+				Name: "http",
+			},
+			expected: "http",
+		},
+		{
+			port: networking.ServiceBackendPort{
+				Number: 80,
+			},
+			expected: "80",
 		},
 	}
 	for _, test := range tests {
-		result := generateIngressPath(test.path, test.pathType)
+		result := GetBackendPortAsString(test.port)
 		if result != test.expected {
-			t.Errorf("generateIngressPath(%v, %v) returned %v, but expected %v", test.path, test.pathType, result, test.expected)
+			t.Errorf("GetBackendPortAsString(%+v) returned %v, but expected %v", test.port, result, test.expected)
 		}
 	}
 }
@@ -282,15 +351,23 @@ func createCafeIngressEx() IngressEx {
 								{
 									Path: "/coffee",
 									Backend: networking.IngressBackend{
-										ServiceName: "coffee-svc",
-										ServicePort: intstr.FromString("80"),
+										Service: &networking.IngressServiceBackend{
+											Name: "coffee-svc",
+											Port: networking.ServiceBackendPort{
+												Number: 80,
+											},
+										},
 									},
 								},
 								{
 									Path: "/tea",
 									Backend: networking.IngressBackend{
-										ServiceName: "tea-svc",
-										ServicePort: intstr.FromString("80"),
+										Service: &networking.IngressServiceBackend{
+											Name: "tea-svc",
+											Port: networking.ServiceBackendPort{
+												Number: 80,
+											},
+										},
 									},
 								},
 							},
@@ -483,8 +560,12 @@ func createMergeableCafeIngress() *MergeableIngresses {
 								{
 									Path: "/coffee",
 									Backend: networking.IngressBackend{
-										ServiceName: "coffee-svc",
-										ServicePort: intstr.FromString("80"),
+										Service: &networking.IngressServiceBackend{
+											Name: "coffee-svc",
+											Port: networking.ServiceBackendPort{
+												Number: 80,
+											},
+										},
 									},
 								},
 							},
@@ -514,8 +595,12 @@ func createMergeableCafeIngress() *MergeableIngresses {
 								{
 									Path: "/tea",
 									Backend: networking.IngressBackend{
-										ServiceName: "tea-svc",
-										ServicePort: intstr.FromString("80"),
+										Service: &networking.IngressServiceBackend{
+											Name: "tea-svc",
+											Port: networking.ServiceBackendPort{
+												Number: 80,
+											},
+										},
 									},
 								},
 							},
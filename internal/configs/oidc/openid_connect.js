@@ -81,6 +81,9 @@ function auth(r) {
                         // ID Token is valid, update keyval
                         r.log("OIDC refresh success, updating id_token for " + r.variables.cookie_auth_token);
                         r.variables.session_jwt = tokenset.id_token; // Update key-value store
+                        if (tokenset.access_token) {
+                            r.variables.access_token = tokenset.access_token; // Update key-value store
+                        }
 
                         // Update refresh token (if we got a new one)
                         if (r.variables.refresh_token != tokenset.refresh_token) {
@@ -165,6 +168,9 @@ function codeExchange(r) {
                     // Add opaque token to keyval session store
                     r.log("OIDC success, creating session " + r.variables.request_id);
                     r.variables.new_session = tokenset.id_token; // Create key-value store entry
+                    if (tokenset.access_token) {
+                        r.variables.new_access_token = tokenset.access_token; // Create key-value store entry
+                    }
                     r.headersOut["Set-Cookie"] = "auth_token=" + r.variables.request_id + "; " + r.variables.oidc_cookie_flags;
                     r.return(302, r.variables.redirect_base + r.variables.cookie_auth_redir);
                 }
@@ -233,8 +239,22 @@ function validateIdToken(r) {
 
 function logout(r) {
     r.log("OIDC logout for " + r.variables.cookie_auth_token);
+    var idToken = r.variables.session_jwt;
     r.variables.session_jwt = "-";
+    r.variables.access_token = "-";
     r.variables.refresh_token = "-";
+
+    // If the IdP supports RP-initiated logout, end the session at the IdP too
+    if (r.variables.oidc_end_session_endpoint) {
+        var endSessionArgs = "post_logout_redirect_uri=" + encodeURIComponent(r.variables.redirect_base + r.variables.oidc_logout_redirect);
+        if (idToken && idToken != "-") {
+            endSessionArgs += "&id_token_hint=" + idToken;
+        }
+        var separator = r.variables.oidc_end_session_endpoint.indexOf("?") == -1 ? "?" : "&";
+        r.return(302, r.variables.oidc_end_session_endpoint + separator + endSessionArgs);
+        return;
+    }
+
     r.return(302, r.variables.oidc_logout_redirect);
 }
 
@@ -261,6 +281,10 @@ function getAuthZArgs(r) {
     } else {
         authZArgs += "&state=0";
     }
+
+    if (r.variables.oidc_authz_extra_args) {
+        authZArgs += "&" + r.variables.oidc_authz_extra_args;
+    }
     return authZArgs;
 }
 
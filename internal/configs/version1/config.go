@@ -74,6 +74,8 @@ type Server struct {
 	GRPCOnly              bool
 	StatusZone            string
 	HTTP2                 bool
+	HTTP3                 bool
+	AltSvc                string
 	RedirectToHTTPS       bool
 	SSLRedirect           bool
 	ProxyProtocol         bool
@@ -150,6 +152,7 @@ type MainConfig struct {
 	HealthStatus                       bool
 	HealthStatusURI                    string
 	HTTP2                              bool
+	HTTP3                              bool
 	HTTPSnippets                       []string
 	KeepaliveRequests                  int64
 	KeepaliveTimeout                   string
@@ -200,6 +203,9 @@ type MainConfig struct {
 	InternalRouteServerName            string
 	LatencyMetrics                     bool
 	PreviewPolicies                    bool
+	BrotliLoadModule                   bool
+	GeoIP2CountryDatabase              string
+	GeoIP2ASNDatabase                  string
 }
 
 // NewUpstreamWithDefaultServer creates an upstream with the default server.
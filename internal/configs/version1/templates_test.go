@@ -51,8 +51,10 @@ var ingCfg = IngressNginxConfig{
 			SSL:               true,
 			SSLCertificate:    "secret.pem",
 			SSLCertificateKey: "secret.pem",
-			SSLPorts:          []int{443},
+			SSLPorts:          []int{443, 8443},
 			SSLRedirect:       true,
+			HTTP3:             true,
+			AltSvc:            `h3=":443"; ma=86400, h3=":8443"; ma=86400`,
 			Locations: []Location{
 				{
 					Path:                "/tea",
@@ -111,6 +113,10 @@ var mainCfg = MainConfig{
 	VariablesHashBucketSize: 256,
 	VariablesHashMaxSize:    1024,
 	TLSPassthrough:          true,
+	HTTP3:                   true,
+	PreviewPolicies:         true,
+	GeoIP2CountryDatabase:   "/etc/nginx/geoip/GeoLite2-Country.mmdb",
+	GeoIP2ASNDatabase:       "/etc/nginx/geoip/GeoLite2-ASN.mmdb",
 }
 
 func TestIngressForNGINXPlus(t *testing.T) {
@@ -12,14 +12,18 @@ type UpstreamLabels struct {
 
 // VirtualServerConfig holds NGINX configuration for a VirtualServer.
 type VirtualServerConfig struct {
-	HTTPSnippets  []string
-	LimitReqZones []LimitReqZone
-	Maps          []Map
-	Server        Server
-	SpiffeCerts   bool
-	SplitClients  []SplitClient
-	StatusMatches []StatusMatch
-	Upstreams     []Upstream
+	CacheZones     []CacheZone
+	Geos           []Geo
+	HTTPSnippets   []string
+	JWTClaimSets   []JWTClaimSet
+	LimitConnZones []LimitConnZone
+	LimitReqZones  []LimitReqZone
+	Maps           []Map
+	Server         Server
+	SpiffeCerts    bool
+	SplitClients   []SplitClient
+	StatusMatches  []StatusMatch
+	Upstreams      []Upstream
 }
 
 // Upstream defines an upstream.
@@ -28,6 +32,7 @@ type Upstream struct {
 	Servers          []UpstreamServer
 	LBMethod         string
 	Resolve          bool
+	Resolver         *UpstreamResolver
 	Keepalive        int
 	MaxFails         int
 	MaxConns         int
@@ -39,9 +44,20 @@ type Upstream struct {
 	UpstreamLabels   UpstreamLabels
 }
 
+// UpstreamResolver defines the resolver of an upstream.
+type UpstreamResolver struct {
+	Addresses []string
+	Valid     string
+	IPv6      bool
+	Timeout   string
+}
+
 // UpstreamServer defines an upstream server.
 type UpstreamServer struct {
 	Address string
+	Weight  int
+	Drain   bool
+	Backup  bool
 }
 
 // Server defines a server.
@@ -59,6 +75,9 @@ type Server struct {
 	Locations                 []Location
 	ErrorPageLocations        []ErrorPageLocation
 	ReturnLocations           []ReturnLocation
+	CORSPreflightLocations    []CORSPreflightLocation
+	ExternalAuthLocations     []ExternalAuthLocation
+	JWKSLocations             []JWKSLocation
 	HealthChecks              []HealthCheck
 	TLSRedirect               *TLSRedirect
 	TLSPassthrough            bool
@@ -66,11 +85,15 @@ type Server struct {
 	Deny                      []string
 	LimitReqOptions           LimitReqOptions
 	LimitReqs                 []LimitReq
+	LimitConns                []LimitConn
 	JWTAuth                   *JWTAuth
+	BasicAuth                 *BasicAuth
 	IngressMTLS               *IngressMTLS
 	EgressMTLS                *EgressMTLS
 	OIDC                      *OIDC
 	WAF                       *WAF
+	ClientHeaderBufferSize    string
+	LargeClientHeaderBuffers  string
 	PoliciesErrorReturn       *Return
 	VSNamespace               string
 	VSName                    string
@@ -79,6 +102,8 @@ type Server struct {
 // SSL defines SSL configuration for a server.
 type SSL struct {
 	HTTP2           bool
+	HTTP3           bool
+	AltSvc          string
 	Certificate     string
 	CertificateKey  string
 	RejectHandshake bool
@@ -106,13 +131,19 @@ type EgressMTLS struct {
 }
 
 type OIDC struct {
-	AuthEndpoint  string
-	ClientID      string
-	ClientSecret  string
-	JwksURI       string
-	Scope         string
-	TokenEndpoint string
-	RedirectURI   string
+	AuthEndpoint          string
+	ClientID              string
+	ClientSecret          string
+	JwksURI               string
+	Scope                 string
+	TokenEndpoint         string
+	RedirectURI           string
+	EndSessionEndpoint    string
+	PostLogoutRedirectURI string
+	AuthExtraArgs         string
+	PKCEEnable            bool
+	ForwardTokenHeader    string
+	ForwardTokenValue     string
 }
 
 // WAF defines WAF configuration.
@@ -132,11 +163,14 @@ type Location struct {
 	ProxyReadTimeout         string
 	ProxySendTimeout         string
 	ClientMaxBodySize        string
+	ClientBodyBufferSize     string
+	ProxyRequestBufferingOff bool
 	ProxyMaxTempFileSize     string
 	ProxyBuffering           bool
 	ProxyBuffers             string
 	ProxyBufferSize          string
 	ProxyPass                string
+	GRPCPass                 string
 	ProxyNextUpstream        string
 	ProxyNextUpstreamTimeout string
 	ProxyNextUpstreamTries   int
@@ -152,22 +186,146 @@ type Location struct {
 	HasKeepalive             bool
 	ErrorPages               []ErrorPage
 	ProxySSLName             string
+	ProxySSLServerName       string
 	InternalProxyPass        string
 	Allow                    []string
 	Deny                     []string
 	LimitReqOptions          LimitReqOptions
 	LimitReqs                []LimitReq
+	LimitConns               []LimitConn
 	JWTAuth                  *JWTAuth
+	BasicAuth                *BasicAuth
 	EgressMTLS               *EgressMTLS
 	OIDC                     bool
 	WAF                      *WAF
 	PoliciesErrorReturn      *Return
+	UpstreamErrorReturn      *Return
+	Mirror                   *Mirror
+	MirrorTarget             *MirrorTarget
+	CORS                     *CORS
+	ExternalAuth             *ExternalAuth
+	Cache                    *Cache
+	Compression              *Compression
+	IPAccessControl          *IPAccessControl
 	ServiceName              string
 	IsVSR                    bool
 	VSRName                  string
 	VSRNamespace             string
 }
 
+// HasAddHeaders tells if the location has its own add_header directives, which cancel the add_header directives
+// inherited from the server and the http contexts.
+func (l Location) HasAddHeaders() bool {
+	return l.CORS != nil || len(l.AddHeaders) > 0 || (l.ProxyPass != "" && l.Cache != nil && l.Cache.StatusHeader != "")
+}
+
+// Mirror defines the mirroring of the requests of a location to internal locations.
+type Mirror struct {
+	Paths       []string
+	RequestBody bool
+}
+
+// MirrorTarget defines an internal location that receives mirrored requests.
+// When SampleVariable is set, only the requests where the variable is not empty are proxied.
+type MirrorTarget struct {
+	SampleVariable string
+	RequestBody    bool
+}
+
+// CORS defines the CORS headers of the responses of a location.
+// The preflight requests are rewritten to PreflightPath.
+type CORS struct {
+	OriginVariable   string
+	ExposeHeaders    string
+	AllowCredentials bool
+	PreflightPath    string
+}
+
+// CORSPreflightLocation defines an internal location that responds to the CORS preflight requests.
+type CORSPreflightLocation struct {
+	Path             string
+	OriginVariable   string
+	AllowMethods     string
+	AllowHeaders     string
+	AllowCredentials bool
+	MaxAge           string
+}
+
+// ExternalAuth defines the external authentication of the requests of a location.
+// NGINX sends a subrequest to AuthPath and passes the ResponseHeaders of the auth response to the upstream.
+type ExternalAuth struct {
+	AuthPath        string
+	ResponseHeaders []ExternalAuthResponseHeader
+	SigninURL       string
+}
+
+// ExternalAuthResponseHeader defines a header of an auth response that NGINX passes to the upstream.
+type ExternalAuthResponseHeader struct {
+	Name     string
+	Variable string
+	Value    string
+}
+
+// ExternalAuthLocation defines an internal location that passes the auth subrequests to the external auth service.
+type ExternalAuthLocation struct {
+	Path      string
+	ProxyPass string
+	SSLServer bool
+	CacheZone string
+	CacheTTL  string
+}
+
+// CacheZone defines a proxy cache zone.
+type CacheZone struct {
+	Name     string
+	Path     string
+	Size     string
+	MaxSize  string
+	Inactive string
+}
+
+// Cache defines the caching of the responses of a location.
+type Cache struct {
+	ZoneName         string
+	Key              string
+	Methods          []string
+	Valid            []CacheValid
+	MinUses          int
+	UseStale         []string
+	BackgroundUpdate bool
+	Lock             bool
+	Bypass           []string
+	StatusHeader     string
+}
+
+// CacheValid defines the caching time of the responses with the status codes.
+type CacheValid struct {
+	Codes []int
+	Time  string
+}
+
+// Compression defines the compression of the responses of a location.
+type Compression struct {
+	Gzip   *Gzip
+	Brotli *Brotli
+}
+
+// Gzip defines the gzip compression of the responses.
+type Gzip struct {
+	Types     []string
+	Level     int
+	MinLength int
+	Proxied   []string
+	Vary      bool
+}
+
+// Brotli defines the brotli compression of the responses.
+type Brotli struct {
+	Types     []string
+	Level     int
+	MinLength int
+}
+
 // ReturnLocation defines a location for returning a fixed response.
 type ReturnLocation struct {
 	Name        string
@@ -225,11 +383,14 @@ type HealthCheck struct {
 	Passes              int
 	Port                int
 	ProxyPass           string
+	GRPCPass            string
 	ProxyConnectTimeout string
 	ProxyReadTimeout    string
 	ProxySendTimeout    string
 	Headers             map[string]string
 	Match               string
+	GRPCStatus          *int
+	GRPCService         string
 }
 
 // TLSRedirect defines a redirect in a Server.
@@ -255,6 +416,15 @@ type Distribution struct {
 	Value  string
 }
 
+// IPAccessControl defines the denial of the requests of a location.
+// NGINX denies a request when DenyVariable is not empty or 0 and logs it with the Policy key.
+type IPAccessControl struct {
+	DenyVariable string
+	Code         int
+	Body         string
+	Policy       string
+}
+
 // InternalRedirectLocation defines a location for internally redirecting requests to named locations.
 type InternalRedirectLocation struct {
 	Path        string
@@ -268,6 +438,12 @@ type Map struct {
 	Parameters []Parameter
 }
 
+// Geo defines a geo block that maps the client address to a Variable.
+type Geo struct {
+	Variable   string
+	Parameters []Parameter
+}
+
 // Parameter defines a Parameter in a Map.
 type Parameter struct {
 	Value  string
@@ -310,6 +486,27 @@ func (rl LimitReq) String() string {
 	return fmt.Sprintf("{ZoneName %q, Burst %q, NoDelay %v, Delay %q}", rl.ZoneName, rl.Burst, rl.NoDelay, rl.Delay)
 }
 
+// LimitConnZone defines a connection limit shared memory zone.
+type LimitConnZone struct {
+	Key      string
+	ZoneName string
+	ZoneSize string
+}
+
+func (lcz LimitConnZone) String() string {
+	return fmt.Sprintf("{Key %q, ZoneName %q, ZoneSize %v}", lcz.Key, lcz.ZoneName, lcz.ZoneSize)
+}
+
+// LimitConn defines a connection limit.
+type LimitConn struct {
+	ZoneName    string
+	Connections int
+}
+
+func (lc LimitConn) String() string {
+	return fmt.Sprintf("{ZoneName %q, Connections %v}", lc.ZoneName, lc.Connections)
+}
+
 // LimitReqOptions defines rate limit options.
 type LimitReqOptions struct {
 	DryRun     bool
@@ -323,7 +520,32 @@ func (rl LimitReqOptions) String() string {
 
 // JWTAuth holds JWT authentication configuration.
 type JWTAuth struct {
+	Secret       string
+	Realm        string
+	Token        string
+	KeyRequest   string
+	KeyCache     string
+	Require      []string
+	ClaimHeaders []Header
+}
+
+// JWKSLocation defines a location that fetches the JWKS of a JWT policy.
+type JWKSLocation struct {
+	Path      string
+	URI       string
+	SSLServer bool
+	CacheZone string
+	CacheTTL  string
+}
+
+// JWTClaimSet defines a variable that holds a claim of a JWT.
+type JWTClaimSet struct {
+	Variable string
+	Claim    []string
+}
+
+// BasicAuth holds HTTP Basic authentication configuration.
+type BasicAuth struct {
 	Secret string
 	Realm  string
-	Token  string
 }
@@ -1,6 +1,7 @@
 package version2
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -10,11 +11,45 @@ const nginxPlusTransportServerTmpl = "nginx-plus.transportserver.tmpl"
 const nginxTransportServerTmpl = "nginx.transportserver.tmpl"
 
 var virtualServerCfg = VirtualServerConfig{
+	CacheZones: []CacheZone{
+		{
+			Name: "pol_ext_auth_default_ext-auth_default_cafe",
+			Path: "/var/cache/nginx/pol_ext_auth_default_ext-auth_default_cafe",
+			Size: "1m",
+		},
+		{
+			Name:     "pol_cache_default_cache_default_cafe",
+			Path:     "/var/cache/nginx/pol_cache_default_cache_default_cafe",
+			Size:     "10m",
+			MaxSize:  "1g",
+			Inactive: "1h",
+		},
+	},
+	Geos: []Geo{
+		{
+			Variable: "$pol_ipac_default_ipac_default_cafe_address",
+			Parameters: []Parameter{
+				{
+					Value:  "default",
+					Result: "0",
+				},
+				{
+					Value:  "10.0.0.0/8",
+					Result: "1",
+				},
+			},
+		},
+	},
 	LimitReqZones: []LimitReqZone{
 		{
 			ZoneName: "pol_rl_test_test_test", Rate: "10r/s", ZoneSize: "10m", Key: "$url",
 		},
 	},
+	LimitConnZones: []LimitConnZone{
+		{
+			ZoneName: "pol_lc_test_test_test", ZoneSize: "10m", Key: "$binary_remote_addr",
+		},
+	},
 	Upstreams: []Upstream{
 		{
 			Name: "test-upstream",
@@ -57,6 +92,23 @@ var virtualServerCfg = VirtualServerConfig{
 			MaxConns:         4,
 			UpstreamZoneSize: "256k",
 		},
+		{
+			Name: "api",
+			Servers: []UpstreamServer{
+				{
+					Address: "api.example.com:443",
+				},
+			},
+			Resolve: true,
+			Resolver: &UpstreamResolver{
+				Addresses: []string{"10.0.0.10"},
+				Valid:     "10s",
+				Timeout:   "5s",
+			},
+			MaxFails:         1,
+			FailTimeout:      "10s",
+			UpstreamZoneSize: "256k",
+		},
 	},
 	SplitClients: []SplitClient{
 		{
@@ -104,6 +156,16 @@ var virtualServerCfg = VirtualServerConfig{
 			},
 		},
 	},
+	JWTClaimSets: []JWTClaimSet{
+		{
+			Variable: "$pol_jwt_default_jwt_default_cafe_claim_0",
+			Claim:    []string{"realm_access", "roles"},
+		},
+		{
+			Variable: "$pol_jwt_default_jwt_default_cafe_claim_1",
+			Claim:    []string{"sub"},
+		},
+	},
 	HTTPSnippets: []string{"# HTTP snippet"},
 	Server: Server{
 		ServerName:    "example.com",
@@ -111,6 +173,8 @@ var virtualServerCfg = VirtualServerConfig{
 		ProxyProtocol: true,
 		SSL: &SSL{
 			HTTP2:          true,
+			HTTP3:          true,
+			AltSvc:         `h3=":443"; ma=86400`,
 			Certificate:    "cafe-secret.pem",
 			CertificateKey: "cafe-secret.pem",
 		},
@@ -118,12 +182,14 @@ var virtualServerCfg = VirtualServerConfig{
 			BasedOn: "$scheme",
 			Code:    301,
 		},
-		ServerTokens:    "off",
-		SetRealIPFrom:   []string{"0.0.0.0/0"},
-		RealIPHeader:    "X-Real-IP",
-		RealIPRecursive: true,
-		Allow:           []string{"127.0.0.1"},
-		Deny:            []string{"127.0.0.1"},
+		ServerTokens:             "off",
+		SetRealIPFrom:            []string{"0.0.0.0/0"},
+		RealIPHeader:             "X-Real-IP",
+		RealIPRecursive:          true,
+		ClientHeaderBufferSize:   "2k",
+		LargeClientHeaderBuffers: "4 16k",
+		Allow:                    []string{"127.0.0.1"},
+		Deny:                     []string{"127.0.0.1"},
 		LimitReqs: []LimitReq{
 			{
 				ZoneName: "pol_rl_test_test_test",
@@ -135,9 +201,41 @@ var virtualServerCfg = VirtualServerConfig{
 			LogLevel:   "error",
 			RejectCode: 503,
 		},
+		LimitConns: []LimitConn{
+			{
+				ZoneName:    "pol_lc_test_test_test",
+				Connections: 10,
+			},
+		},
 		JWTAuth: &JWTAuth{
-			Realm:  "My Api",
-			Secret: "jwk-secret",
+			Realm:      "My Api",
+			KeyRequest: "/internal_location_pol_jwt_default_jwt",
+			KeyCache:   "1h",
+			Require:    []string{"$pol_jwt_default_jwt_default_cafe_require_0"},
+			ClaimHeaders: []Header{
+				{
+					Name:  "X-User",
+					Value: "$pol_jwt_default_jwt_default_cafe_claim_1",
+				},
+			},
+		},
+		BasicAuth: &BasicAuth{
+			Realm:  "My Tool",
+			Secret: "htpasswd-secret",
+		},
+		OIDC: &OIDC{
+			AuthEndpoint:          "https://idp.example.com/auth",
+			ClientID:              "public-client",
+			JwksURI:               "https://idp.example.com/certs",
+			Scope:                 "openid",
+			TokenEndpoint:         "https://idp.example.com/token",
+			RedirectURI:           "/_codexch",
+			EndSessionEndpoint:    "https://idp.example.com/logout",
+			PostLogoutRedirectURI: "/_logout",
+			AuthExtraArgs:         "prompt=login",
+			PKCEEnable:            true,
+			ForwardTokenHeader:    "Authorization",
+			ForwardTokenValue:     "Bearer $access_token",
 		},
 		IngressMTLS: &IngressMTLS{
 			ClientCert:   "ingress-mtls-secret",
@@ -171,6 +269,65 @@ var virtualServerCfg = VirtualServerConfig{
 						ZoneName: "loc_pol_rl_test_test_test",
 					},
 				},
+				LimitConns: []LimitConn{
+					{
+						ZoneName:    "loc_pol_lc_test_test_test",
+						Connections: 5,
+					},
+				},
+				BasicAuth: &BasicAuth{
+					Realm:  "My Tool",
+					Secret: "htpasswd-secret",
+				},
+				ExternalAuth: &ExternalAuth{
+					AuthPath: "/internal_location_pol_ext_auth_default_ext-auth",
+					ResponseHeaders: []ExternalAuthResponseHeader{
+						{
+							Name:     "X-User",
+							Variable: "$ext_auth_x_user",
+							Value:    "$upstream_http_x_user",
+						},
+					},
+					SigninURL: "https://auth.example.com/signin",
+				},
+				Cache: &Cache{
+					ZoneName: "pol_cache_default_cache_default_cafe",
+					Key:      "$scheme$proxy_host$request_uri",
+					Methods:  []string{"GET", "HEAD"},
+					Valid: []CacheValid{
+						{
+							Codes: []int{200, 302},
+							Time:  "10m",
+						},
+					},
+					MinUses:          2,
+					UseStale:         []string{"error", "updating"},
+					BackgroundUpdate: true,
+					Lock:             true,
+					Bypass:           []string{"$cookie_nocache"},
+					StatusHeader:     "X-Cache-Status",
+				},
+				Compression: &Compression{
+					Gzip: &Gzip{
+						Types:     []string{"application/json"},
+						Level:     5,
+						MinLength: 1000,
+						Proxied:   []string{"any"},
+						Vary:      true,
+					},
+					Brotli: &Brotli{
+						Types: []string{"application/json"},
+						Level: 6,
+					},
+				},
+				IPAccessControl: &IPAccessControl{
+					DenyVariable: "$pol_ipac_default_ipac_default_cafe_deny",
+					Code:         403,
+					Body:         "Access denied",
+					Policy:       "default/ipac",
+				},
+				ClientBodyBufferSize:     "16k",
+				ProxyRequestBufferingOff: true,
 				ProxyConnectTimeout:      "30s",
 				ProxyReadTimeout:         "31s",
 				ProxySendTimeout:         "32s",
@@ -242,6 +399,31 @@ var virtualServerCfg = VirtualServerConfig{
 				ProxyPass:                "http://coffee-v2",
 				ProxyNextUpstream:        "error timeout",
 				ProxyNextUpstreamTimeout: "5s",
+				Mirror: &Mirror{
+					Paths:       []string{"/internal_location_mirror_0_0"},
+					RequestBody: false,
+				},
+				CORS: &CORS{
+					OriginVariable:   "$pol_cors_default_cors_default_cafe",
+					ExposeHeaders:    "X-Request-ID",
+					AllowCredentials: true,
+					PreflightPath:    "/internal_location_pol_cors_default_cors",
+				},
+			},
+			{
+				Path:                     "/internal_location_mirror_0_0",
+				Internal:                 true,
+				ProxyConnectTimeout:      "30s",
+				ProxyReadTimeout:         "31s",
+				ProxySendTimeout:         "32s",
+				ClientMaxBodySize:        "1m",
+				ProxyPass:                "http://coffee-v3$request_uri",
+				ProxyNextUpstream:        "error timeout",
+				ProxyNextUpstreamTimeout: "5s",
+				MirrorTarget: &MirrorTarget{
+					SampleVariable: "$mirror_0",
+					RequestBody:    false,
+				},
 			},
 			{
 				Path:                     "@match_loc_0",
@@ -275,8 +457,59 @@ var virtualServerCfg = VirtualServerConfig{
 				},
 				InternalProxyPass: "http://unix:/var/lib/nginx/nginx-418-server.sock",
 			},
+			{
+				Path:                     "/helloworld.Greeter",
+				ProxyConnectTimeout:      "30s",
+				ProxyReadTimeout:         "31s",
+				ProxySendTimeout:         "32s",
+				ClientMaxBodySize:        "1m",
+				ClientBodyBufferSize:     "32k",
+				GRPCPass:                 "grpc://vs_default_cafe_greeter",
+				ProxyNextUpstream:        "error timeout",
+				ProxyNextUpstreamTimeout: "5s",
+				ProxyNextUpstreamTries:   3,
+				ErrorPages: []ErrorPage{
+					{
+						Name:         "@grpc_unavailable",
+						Codes:        "429 502 503 504",
+						ResponseCode: 204,
+					},
+				},
+			},
+		},
+		HealthChecks: []HealthCheck{
+			{
+				Name:                "vs_default_cafe_greeter",
+				Interval:            "5s",
+				Jitter:              "0s",
+				Fails:               1,
+				Passes:              1,
+				Port:                50051,
+				GRPCPass:            "grpc://vs_default_cafe_greeter",
+				ProxyConnectTimeout: "60s",
+				ProxyReadTimeout:    "60s",
+				ProxySendTimeout:    "60s",
+				Headers:             map[string]string{},
+				GRPCStatus:          createPointerFromInt(12),
+				GRPCService:         "helloworld.Greeter",
+			},
 		},
 		ErrorPageLocations: []ErrorPageLocation{
+			{
+				Name:        "@grpc_unavailable",
+				DefaultType: "application/grpc",
+				Return:      &Return{},
+				Headers: []Header{
+					{
+						Name:  "grpc-status",
+						Value: "14",
+					},
+					{
+						Name:  "grpc-message",
+						Value: "unavailable",
+					},
+				},
+			},
 			{
 				Name:        "@vs_cafe_cafe_vsr_tea_tea_tea__tea_error_page_0",
 				DefaultType: "application/json",
@@ -315,6 +548,33 @@ var virtualServerCfg = VirtualServerConfig{
 				},
 			},
 		},
+		CORSPreflightLocations: []CORSPreflightLocation{
+			{
+				Path:             "/internal_location_pol_cors_default_cors",
+				OriginVariable:   "$pol_cors_default_cors_default_cafe",
+				AllowMethods:     "GET, POST",
+				AllowHeaders:     "Content-Type",
+				AllowCredentials: true,
+				MaxAge:           "3600",
+			},
+		},
+		ExternalAuthLocations: []ExternalAuthLocation{
+			{
+				Path:      "/internal_location_pol_ext_auth_default_ext-auth",
+				ProxyPass: "http://vs_default_cafe_pol_ext_auth_default_ext-auth/oauth2/auth",
+				CacheZone: "pol_ext_auth_default_ext-auth_default_cafe",
+				CacheTTL:  "30s",
+			},
+		},
+		JWKSLocations: []JWKSLocation{
+			{
+				Path:      "/internal_location_pol_jwt_default_jwt",
+				URI:       "https://idp.example.com/jwks",
+				SSLServer: true,
+				CacheZone: "pol_jwt_default_jwt_default_cafe",
+				CacheTTL:  "12h",
+			},
+		},
 	},
 }
 
@@ -385,6 +645,136 @@ func TestVirtualServerForNginx(t *testing.T) {
 	t.Log(string(data))
 }
 
+func TestExternalAuthLocationCacheKey(t *testing.T) {
+	// the auth service decides per request, so the cached responses must not be shared among the URIs and methods
+	expected := `proxy_cache_key "$request_method$host$request_uri$http_authorization$http_cookie";`
+
+	for _, tmpl := range []string{nginxPlusVirtualServerTmpl, nginxVirtualServerTmpl} {
+		executor, err := NewTemplateExecutor(tmpl, nginxTransportServerTmpl)
+		if err != nil {
+			t.Fatalf("Failed to create template executor: %v", err)
+		}
+
+		data, err := executor.ExecuteVirtualServerTemplate(&virtualServerCfg)
+		if err != nil {
+			t.Fatalf("Failed to execute template %v: %v", tmpl, err)
+		}
+
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Template %v didn't generate the cache key %q for the external auth location", tmpl, expected)
+		}
+	}
+}
+
+func TestVirtualServerLocationsWithAltSvc(t *testing.T) {
+	// the add_header directives of a location cancel the add_header directives inherited from the server,
+	// so every location with its own headers, like the CORS headers, must add the Alt-Svc header of HTTP/3.
+	// The other locations must inherit it, so that they also inherit the headers of the server snippets.
+	altSvc := `add_header Alt-Svc 'h3=":443"; ma=86400' always;`
+
+	for _, tmpl := range []string{nginxPlusVirtualServerTmpl, nginxVirtualServerTmpl} {
+		executor, err := NewTemplateExecutor(tmpl, nginxTransportServerTmpl)
+		if err != nil {
+			t.Fatalf("Failed to create template executor: %v", err)
+		}
+
+		data, err := executor.ExecuteVirtualServerTemplate(&virtualServerCfg)
+		if err != nil {
+			t.Fatalf("Failed to execute template %v: %v", tmpl, err)
+		}
+
+		blocks := strings.Split(string(data), "\n    location ")
+		locationsWithoutHeaders := 0
+		for _, block := range blocks[1:] {
+			hasHeaders := strings.Count(block, "add_header ") > strings.Count(block, altSvc)
+			hasAltSvc := strings.Contains(block, altSvc)
+			if hasHeaders && !hasAltSvc {
+				t.Errorf("Template %v didn't generate the Alt-Svc header in the location %v", tmpl, strings.SplitN(block, "\n", 2)[0])
+			}
+			if !hasHeaders {
+				locationsWithoutHeaders++
+				if hasAltSvc {
+					t.Errorf("Template %v generated the Alt-Svc header in the location %v without its own headers", tmpl, strings.SplitN(block, "\n", 2)[0])
+				}
+			}
+		}
+		if locationsWithoutHeaders == 0 {
+			t.Errorf("Template %v generated no locations without their own headers", tmpl)
+		}
+		if !strings.Contains(blocks[0], altSvc) {
+			t.Errorf("Template %v didn't generate the Alt-Svc header in the server", tmpl)
+		}
+	}
+}
+
+func TestGRPCLocationClientBodyBufferSize(t *testing.T) {
+	expected := "client_body_buffer_size 32k;"
+
+	for _, tmpl := range []string{nginxPlusVirtualServerTmpl, nginxVirtualServerTmpl} {
+		executor, err := NewTemplateExecutor(tmpl, nginxTransportServerTmpl)
+		if err != nil {
+			t.Fatalf("Failed to create template executor: %v", err)
+		}
+
+		data, err := executor.ExecuteVirtualServerTemplate(&virtualServerCfg)
+		if err != nil {
+			t.Fatalf("Failed to execute template %v: %v", tmpl, err)
+		}
+
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Template %v didn't generate %q for the gRPC location", tmpl, expected)
+		}
+	}
+}
+
+func TestGRPCLocationEgressMTLS(t *testing.T) {
+	cfg := VirtualServerConfig{
+		Server: Server{
+			ServerName: "example.com",
+			Locations: []Location{
+				{
+					Path:               "/helloworld.Greeter",
+					GRPCPass:           "grpcs://vs_default_cafe_grpc-app",
+					ProxySSLServerName: "grpc.example.com",
+					EgressMTLS: &EgressMTLS{
+						Certificate:    "/etc/nginx/secrets/default-egress-mtls-secret",
+						CertificateKey: "/etc/nginx/secrets/default-egress-mtls-secret",
+						VerifyServer:   true,
+						VerifyDepth:    1,
+						Ciphers:        "DEFAULT",
+						Protocols:      "TLSv1.3",
+						ServerName:     true,
+						SSLName:        "grpc.example.com",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tmpl := range []string{nginxPlusVirtualServerTmpl, nginxVirtualServerTmpl} {
+		executor, err := NewTemplateExecutor(tmpl, nginxTransportServerTmpl)
+		if err != nil {
+			t.Fatalf("Failed to create template executor: %v", err)
+		}
+
+		data, err := executor.ExecuteVirtualServerTemplate(&cfg)
+		if err != nil {
+			t.Fatalf("Failed to execute template %v: %v", tmpl, err)
+		}
+		result := string(data)
+
+		if !strings.Contains(result, "grpc_ssl_certificate /etc/nginx/secrets/default-egress-mtls-secret;") {
+			t.Errorf("Template %v didn't generate the egress mTLS certificate for the gRPC location", tmpl)
+		}
+		if strings.Contains(result, "proxy_ssl_") {
+			t.Errorf("Template %v generated the proxy_ssl directives for the gRPC location", tmpl)
+		}
+		if count := strings.Count(result, "grpc_ssl_server_name"); count != 1 {
+			t.Errorf("Template %v generated %v grpc_ssl_server_name directives but expected 1", tmpl, count)
+		}
+	}
+}
+
 func TestTransportServerForNginxPlus(t *testing.T) {
 	executor, err := NewTemplateExecutor(nginxPlusVirtualServerTmpl, nginxPlusTransportServerTmpl)
 	if err != nil {
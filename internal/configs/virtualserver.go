@@ -2,6 +2,9 @@ package configs
 
 import (
 	"fmt"
+	"path"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -25,6 +28,14 @@ const (
 	specContext            = "spec"
 	routeContext           = "route"
 	subRouteContext        = "subroute"
+	defaultBasicAuthRealm  = "Restricted"
+	defaultCacheKey        = "$scheme$proxy_host$request_uri"
+	defaultCachePath       = "/var/cache/nginx"
+	grpcUpstreamType       = "grpc"
+	// defaultWebSocketIdleTimeout is the default idle timeout of the WebSocket connections of an upstream.
+	defaultWebSocketIdleTimeout = "1h"
+	// virtualServerSSLPort is the port of the TLS listeners of the VirtualServer templates.
+	virtualServerSSLPort = 443
 )
 
 var incompatibleLBMethodsForSlowStart = map[string]bool{
@@ -63,6 +74,12 @@ type VirtualServerEx struct {
 	SecretRefs          map[string]*secrets.SecretReference
 	ApPolRefs           map[string]*unstructured.Unstructured
 	LogConfRefs         map[string]*unstructured.Unstructured
+	// CanaryWeights maps the path of a Route with a canary release to the current weight of the canary upstream.
+	CanaryWeights map[string]int
+	// RolledBackUpstreams maps the path of a Route with splits to the set of the rolled back upstreams.
+	RolledBackUpstreams map[string]map[string]bool
+	// DrainingEndpoints maps the endpoints key of an upstream with a drain timeout to the endpoints of its terminating pods.
+	DrainingEndpoints map[string][]string
 }
 
 func (vsx *VirtualServerEx) String() string {
@@ -90,6 +107,125 @@ func GenerateEndpointsKey(
 	return fmt.Sprintf("%s/%s:%d", serviceNamespace, serviceName, port)
 }
 
+const defaultTopologyMinHealthyPercent = 50
+
+// UpstreamEndpointsSubset defines the endpoints of a subset of the pods of an upstream of a VirtualServer or VirtualServerRoute.
+type UpstreamEndpointsSubset struct {
+	// EndpointsKey is the key of the endpoints of the subset in VirtualServerEx.Endpoints.
+	EndpointsKey string
+	Subselector  map[string]string
+	Weight       int
+}
+
+// GetUpstreamEndpointsSubsets returns the subsets of the endpoints of an upstream of a VirtualServer or VirtualServerRoute.
+// An upstream without subsets has a single subset with the subselector of the upstream and no weight.
+func GetUpstreamEndpointsSubsets(namespace string, upstream conf_v1.Upstream) []UpstreamEndpointsSubset {
+	if len(upstream.Subsets) == 0 {
+		return []UpstreamEndpointsSubset{
+			{
+				EndpointsKey: generateEndpointsKeyForUpstream(namespace, upstream, upstream.Subselector),
+				Subselector:  upstream.Subselector,
+			},
+		}
+	}
+
+	var subsets []UpstreamEndpointsSubset
+
+	for _, s := range upstream.Subsets {
+		subselector := labels.Merge(upstream.Subselector, s.Subselector)
+		subsets = append(subsets, UpstreamEndpointsSubset{
+			EndpointsKey: generateEndpointsKeyForUpstream(namespace, upstream, subselector),
+			Subselector:  subselector,
+			Weight:       s.Weight,
+		})
+	}
+
+	return subsets
+}
+
+// GetTopologyMinHealthyPercent returns the minimum percentage of the healthy capacity in the zone of the Ingress Controller
+// for the topology aware routing of an upstream.
+func GetTopologyMinHealthyPercent(topologyAwareRouting *conf_v1.TopologyAwareRouting) int {
+	if topologyAwareRouting.MinHealthyPercent == nil {
+		return defaultTopologyMinHealthyPercent
+	}
+
+	return *topologyAwareRouting.MinHealthyPercent
+}
+
+// IsTopologyAwareRoutingEnabled tells if the endpoints of an upstream are selected by the zone of the Ingress Controller.
+func IsTopologyAwareRoutingEnabled(upstream conf_v1.Upstream) bool {
+	return upstream.TopologyAwareRouting != nil && upstream.TopologyAwareRouting.Enable
+}
+
+// generateEndpointsKeyForUpstream generates the key of the endpoints of an upstream. The endpoints of an upstream with
+// the topology aware routing depend on its settings, so they have a separate key.
+func generateEndpointsKeyForUpstream(namespace string, upstream conf_v1.Upstream, subselector map[string]string) string {
+	key := GenerateEndpointsKey(namespace, upstream.Service, subselector, upstream.Port)
+
+	if IsTopologyAwareRoutingEnabled(upstream) {
+		return fmt.Sprintf("%s_zone:%d", key, GetTopologyMinHealthyPercent(upstream.TopologyAwareRouting))
+	}
+
+	return key
+}
+
+// getEndpointsForUpstreamSubsets returns the endpoints of all subsets of the upstream, the weights of the endpoints
+// of the subsets with a weight and the set of the draining endpoints. If an endpoint belongs to several subsets,
+// the first subset applies. The draining endpoints follow the active ones.
+func getEndpointsForUpstreamSubsets(
+	namespace string,
+	upstream conf_v1.Upstream,
+	endpoints map[string][]string,
+	drainingEndpoints map[string][]string,
+) ([]string, map[string]int, map[string]bool) {
+	subsets := GetUpstreamEndpointsSubsets(namespace, upstream)
+	if len(subsets) == 1 && subsets[0].Weight == 0 && len(drainingEndpoints[subsets[0].EndpointsKey]) == 0 {
+		return endpoints[subsets[0].EndpointsKey], nil, nil
+	}
+
+	var result []string
+	var weights map[string]int
+	var draining map[string]bool
+	added := make(map[string]bool)
+
+	addEndpoint := func(e string, weight int) bool {
+		if added[e] {
+			return false
+		}
+		added[e] = true
+		result = append(result, e)
+
+		if weight > 0 {
+			if weights == nil {
+				weights = make(map[string]int)
+			}
+			weights[e] = weight
+		}
+
+		return true
+	}
+
+	for _, subset := range subsets {
+		for _, e := range endpoints[subset.EndpointsKey] {
+			addEndpoint(e, subset.Weight)
+		}
+	}
+
+	for _, subset := range subsets {
+		for _, e := range drainingEndpoints[subset.EndpointsKey] {
+			if addEndpoint(e, subset.Weight) {
+				if draining == nil {
+					draining = make(map[string]bool)
+				}
+				draining[e] = true
+			}
+		}
+	}
+
+	return result, weights, draining
+}
+
 type upstreamNamer struct {
 	prefix    string
 	namespace string
@@ -125,20 +261,28 @@ func newUpstreamNamerForTransportServer(transportServer *conf_v1alpha1.Transport
 }
 
 func (namer *upstreamNamer) GetNameForUpstreamFromAction(action *conf_v1.Action) string {
-	var upstream string
-	if action.Proxy != nil && action.Proxy.Upstream != "" {
-		upstream = action.Proxy.Upstream
-	} else {
-		upstream = action.Pass
-	}
-
-	return fmt.Sprintf("%s_%s", namer.prefix, upstream)
+	return fmt.Sprintf("%s_%s", namer.prefix, getUpstreamFromAction(action))
 }
 
 func (namer *upstreamNamer) GetNameForUpstream(upstream string) string {
 	return fmt.Sprintf("%s_%s", namer.prefix, upstream)
 }
 
+// GetUpstreamNameForVirtualServer returns the name of the NGINX upstream for an upstream of a VirtualServer.
+func GetUpstreamNameForVirtualServer(virtualServer *conf_v1.VirtualServer, upstream string) string {
+	return newUpstreamNamerForVirtualServer(virtualServer).GetNameForUpstream(upstream)
+}
+
+// GetUpstreamNameForVirtualServerRoute returns the name of the NGINX upstream for an upstream of a VirtualServerRoute.
+func GetUpstreamNameForVirtualServerRoute(virtualServer *conf_v1.VirtualServer, virtualServerRoute *conf_v1.VirtualServerRoute, upstream string) string {
+	return newUpstreamNamerForVirtualServerRoute(virtualServer, virtualServerRoute).GetNameForUpstream(upstream)
+}
+
+// GetUpstreamNameForTransportServer returns the name of the NGINX upstream for an upstream of a TransportServer.
+func GetUpstreamNameForTransportServer(transportServer *conf_v1alpha1.TransportServer, upstream string) string {
+	return newUpstreamNamerForTransportServer(transportServer).GetNameForUpstream(upstream)
+}
+
 type variableNamer struct {
 	safeNsName string
 }
@@ -154,6 +298,10 @@ func (namer *variableNamer) GetNameForSplitClientVariable(index int) string {
 	return fmt.Sprintf("$vs_%s_splits_%d", namer.safeNsName, index)
 }
 
+func (namer *variableNamer) GetNameForMirrorSampleVariable(index int) string {
+	return fmt.Sprintf("$vs_%s_mirror_%d", namer.safeNsName, index)
+}
+
 func (namer *variableNamer) GetNameForVariableForMatchesRouteMap(
 	matchesIndex int,
 	matchIndex int,
@@ -197,6 +345,12 @@ type virtualServerConfigurator struct {
 	warnings             Warnings
 	spiffeCerts          bool
 	oidcPolCfg           *oidcPolicyCfg
+	geoIP2Country        bool
+	geoIP2ASN            bool
+	topologyAwareRouting bool
+	http3Supported       bool
+	jwtRequireSupported  bool
+	jwtKeyCacheSupported bool
 }
 
 type oidcPolicyCfg struct {
@@ -234,6 +388,12 @@ func newVirtualServerConfigurator(
 		warnings:             make(map[runtime.Object][]string),
 		spiffeCerts:          staticParams.NginxServiceMesh,
 		oidcPolCfg:           &oidcPolicyCfg{},
+		geoIP2Country:        staticParams.GeoIP2CountryDatabase != "",
+		geoIP2ASN:            staticParams.GeoIP2ASNDatabase != "",
+		topologyAwareRouting: staticParams.EnableTopologyAwareRouting,
+		http3Supported:       staticParams.HTTP3Supported,
+		jwtRequireSupported:  staticParams.JWTRequireSupported,
+		jwtKeyCacheSupported: staticParams.JWTKeyCacheSupported,
 	}
 }
 
@@ -242,12 +402,30 @@ func (vsc *virtualServerConfigurator) generateEndpointsForUpstream(
 	namespace string,
 	upstream conf_v1.Upstream,
 	virtualServerEx *VirtualServerEx,
-) []string {
-	endpointsKey := GenerateEndpointsKey(namespace, upstream.Service, upstream.Subselector, upstream.Port)
+) ([]string, map[string]int, map[string]bool) {
+	if IsTopologyAwareRoutingEnabled(upstream) && !vsc.topologyAwareRouting {
+		msgFmt := "Topology aware routing of upstream %v will be ignored. To use topology aware routing, it must be enabled via the -enable-topology-aware-routing command-line argument"
+		vsc.addWarningf(owner, msgFmt, upstream.Name)
+	}
+
+	if upstream.Host != "" {
+		if !vsc.isResolverConfigured {
+			msgFmt := "Upstream %v is invalid: host %v can't be resolved, because no resolver is configured. The requests to the upstream will be rejected with the 500 status code. To use a host in an upstream, a resolver must be configured via the resolver-addresses key in the ConfigMap"
+			vsc.addWarningf(owner, msgFmt, upstream.Name, upstream.Host)
+			return []string{}, nil, nil
+		}
+
+		return []string{fmt.Sprintf("%s:%d", upstream.Host, upstream.Port)}, nil, nil
+	}
+
 	externalNameSvcKey := GenerateExternalNameSvcKey(namespace, upstream.Service)
-	endpoints := virtualServerEx.Endpoints[endpointsKey]
+	endpoints, weights, draining := getEndpointsForUpstreamSubsets(namespace, upstream, virtualServerEx.Endpoints, virtualServerEx.DrainingEndpoints)
+	if !vsc.isPlus {
+		endpoints, draining = vsc.generateDrainingEndpointsForOSS(owner, upstream, endpoints, draining)
+	}
+
 	if !vsc.isPlus && len(endpoints) == 0 {
-		return []string{nginx502Server}
+		return []string{nginx502Server}, nil, nil
 	}
 
 	_, isExternalNameSvc := virtualServerEx.ExternalNameSvcs[externalNameSvcKey]
@@ -257,7 +435,59 @@ func (vsc *virtualServerConfigurator) generateEndpointsForUpstream(
 		endpoints = []string{}
 	}
 
-	return endpoints
+	return endpoints, weights, draining
+}
+
+// generateDrainingEndpointsForOSS removes the draining endpoints of the upstream if they can't be configured as backup
+// servers: if the lb method is incompatible with backup servers or if there are no active endpoints, because NGINX
+// requires an upstream to have at least one server that is not a backup.
+func (vsc *virtualServerConfigurator) generateDrainingEndpointsForOSS(
+	owner runtime.Object,
+	upstream conf_v1.Upstream,
+	endpoints []string,
+	draining map[string]bool,
+) ([]string, map[string]bool) {
+	if upstream.DrainTimeout == "" {
+		return endpoints, draining
+	}
+
+	lbMethod := generateLBMethod(upstream.LBMethod, vsc.cfgParams.LBMethod)
+	if isLBMethodIncompatibleWithBackup(lbMethod) {
+		msgFmt := "Draining of the endpoints of upstream %v will be disabled because lb method '%v' is incompatible with backup servers"
+		vsc.addWarningf(owner, msgFmt, upstream.Name, lbMethod)
+	} else if len(draining) < len(endpoints) {
+		return endpoints, draining
+	}
+
+	if len(draining) == 0 {
+		return endpoints, nil
+	}
+
+	var active []string
+	for _, e := range endpoints {
+		if !draining[e] {
+			active = append(active, e)
+		}
+	}
+
+	return active, nil
+}
+
+// checkGRPCUpstream adds a warning if the clients can't send gRPC requests to a grpc upstream. gRPC requires HTTP/2,
+// which NGINX only enables for the TLS listener of a VirtualServer when the http2 ConfigMap key is set.
+func (vsc *virtualServerConfigurator) checkGRPCUpstream(owner runtime.Object, upstream conf_v1.Upstream, tls bool) {
+	if upstream.Type != grpcUpstreamType {
+		return
+	}
+
+	if !tls || !vsc.cfgParams.HTTP2 {
+		msgFmt := "gRPC upstream %v requires HTTP/2 enabled via the http2 ConfigMap key and TLS termination"
+		vsc.addWarningf(owner, msgFmt, upstream.Name)
+	}
+}
+
+func isLBMethodIncompatibleWithBackup(lbMethod string) bool {
+	return lbMethod == "ip_hash" || strings.HasPrefix(lbMethod, "hash") || strings.HasPrefix(lbMethod, "random")
 }
 
 // GenerateVirtualServerConfig generates a full configuration for a VirtualServer
@@ -290,22 +520,27 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(vsEx *VirtualS
 	var statusMatches []version2.StatusMatch
 	var healthChecks []version2.HealthCheck
 	var limitReqZones []version2.LimitReqZone
+	var limitConnZones []version2.LimitConnZone
+	var rateLimitMaps []version2.Map
 
 	limitReqZones = append(limitReqZones, policiesCfg.LimitReqZones...)
+	limitConnZones = append(limitConnZones, policiesCfg.LimitConnZones...)
+	rateLimitMaps = append(rateLimitMaps, policiesCfg.RateLimitMaps...)
 
 	// generate upstreams for VirtualServer
 	for _, u := range vsEx.VirtualServer.Spec.Upstreams {
 		upstreamName := virtualServerUpstreamNamer.GetNameForUpstream(u.Name)
 		upstreamNamespace := vsEx.VirtualServer.Namespace
-		endpoints := vsc.generateEndpointsForUpstream(vsEx.VirtualServer, upstreamNamespace, u, vsEx)
+		endpoints, weights, draining := vsc.generateEndpointsForUpstream(vsEx.VirtualServer, upstreamNamespace, u, vsEx)
 
 		// isExternalNameSvc is always false for OSS
 		_, isExternalNameSvc := vsEx.ExternalNameSvcs[GenerateExternalNameSvcKey(upstreamNamespace, u.Service)]
-		ups := vsc.generateUpstream(vsEx.VirtualServer, upstreamName, u, isExternalNameSvc, endpoints)
+		ups := vsc.generateUpstream(vsEx.VirtualServer, upstreamName, u, isExternalNameSvc, endpoints, weights, draining)
 		upstreams = append(upstreams, ups)
 
 		u.TLS.Enable = isTLSEnabled(u, vsc.spiffeCerts)
 		crUpstreams[upstreamName] = u
+		vsc.checkGRPCUpstream(vsEx.VirtualServer, u, sslConfig != nil)
 
 		if hc := generateHealthCheck(u, upstreamName, vsc.cfgParams); hc != nil {
 			healthChecks = append(healthChecks, *hc)
@@ -323,14 +558,15 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(vsEx *VirtualS
 		for _, u := range vsr.Spec.Upstreams {
 			upstreamName := upstreamNamer.GetNameForUpstream(u.Name)
 			upstreamNamespace := vsr.Namespace
-			endpoints := vsc.generateEndpointsForUpstream(vsr, upstreamNamespace, u, vsEx)
+			endpoints, weights, draining := vsc.generateEndpointsForUpstream(vsr, upstreamNamespace, u, vsEx)
 
 			// isExternalNameSvc is always false for OSS
 			_, isExternalNameSvc := vsEx.ExternalNameSvcs[GenerateExternalNameSvcKey(upstreamNamespace, u.Service)]
-			ups := vsc.generateUpstream(vsr, upstreamName, u, isExternalNameSvc, endpoints)
+			ups := vsc.generateUpstream(vsr, upstreamName, u, isExternalNameSvc, endpoints, weights, draining)
 			upstreams = append(upstreams, ups)
 			u.TLS.Enable = isTLSEnabled(u, vsc.spiffeCerts)
 			crUpstreams[upstreamName] = u
+			vsc.checkGRPCUpstream(vsr, u, sslConfig != nil)
 
 			if hc := generateHealthCheck(u, upstreamName, vsc.cfgParams); hc != nil {
 				healthChecks = append(healthChecks, *hc)
@@ -344,6 +580,17 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(vsEx *VirtualS
 		}
 	}
 
+	// generate upstreams for the services of the external auth policies
+	for _, key := range getExternalAuthPolicyKeys(vsEx.Policies) {
+		pol := vsEx.Policies[key]
+		u := generateExternalAuthUpstream(pol.Spec.ExternalAuth)
+		upstreamName := getNameForExternalAuthUpstream(vsEx.VirtualServer.Namespace, vsEx.VirtualServer.Name, pol.Namespace, pol.Name)
+		endpoints, weights, draining := vsc.generateEndpointsForUpstream(vsEx.VirtualServer, pol.Namespace, u, vsEx)
+
+		ups := vsc.generateUpstream(vsEx.VirtualServer, upstreamName, u, false, endpoints, weights, draining)
+		upstreams = append(upstreams, ups)
+	}
+
 	var locations []version2.Location
 	var internalRedirectLocations []version2.InternalRedirectLocation
 	var returnLocations []version2.ReturnLocation
@@ -355,12 +602,31 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(vsEx *VirtualS
 	vsrLocationSnippetsFromVs := make(map[string]string)
 	vsrPoliciesFromVs := make(map[string][]conf_v1.PolicyReference)
 	isVSR := false
+	var corsCfgs []corsCfg
+	var externalAuthCfgs []externalAuthCfg
+	var cacheCfgs []cacheCfg
+	var ipAccessControlCfgs []ipAccessControlCfg
+	if policiesCfg.IPAccessControl != nil {
+		ipAccessControlCfgs = append(ipAccessControlCfgs, *policiesCfg.IPAccessControl)
+	}
+	var jwtAuthCfgs []jwtAuthCfg
+	if policiesCfg.JWTAuth != nil {
+		jwtAuthCfgs = append(jwtAuthCfgs, *policiesCfg.JWTAuth)
+	}
 	matchesRoutes := 0
+	mirrorRoutes := 0
 
 	variableNamer := newVariableNamer(vsEx.VirtualServer)
 
 	// generates config for VirtualServer routes
 	for _, r := range vsEx.VirtualServer.Spec.Routes {
+		if r.Canary != nil {
+			r = generateRouteForCanary(r, vsEx.CanaryWeights)
+		}
+		if rolledBack := vsEx.RolledBackUpstreams[r.Path]; len(r.Splits) > 0 && len(rolledBack) > 0 {
+			r = generateRouteForRollback(r, rolledBack)
+		}
+
 		errorPageIndex := len(errorPageLocations)
 		errorPageLocations = append(errorPageLocations, generateErrorPageLocations(errorPageIndex, r.ErrorPages)...)
 
@@ -401,7 +667,58 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(vsEx *VirtualS
 		if policiesCfg.OIDC {
 			routePoliciesCfg.OIDC = policiesCfg.OIDC
 		}
+		// use the VirtualServer retry policy if the route does not define any
+		if routePoliciesCfg.Retry == nil {
+			routePoliciesCfg.Retry = policiesCfg.Retry
+		}
+		// use the VirtualServer cors policy if the route does not define any
+		if routePoliciesCfg.CORS == nil {
+			routePoliciesCfg.CORS = policiesCfg.CORS
+		}
+		if routePoliciesCfg.CORS != nil {
+			corsCfgs = append(corsCfgs, *routePoliciesCfg.CORS)
+		}
+		// use the VirtualServer jwt policy if the route does not define any jwt or oidc policy,
+		// so that the location passes the claims of the token to the upstream
+		if routePoliciesCfg.JWTAuth == nil && !routePoliciesCfg.OIDC {
+			routePoliciesCfg.JWTAuth = policiesCfg.JWTAuth
+		}
+		if routePoliciesCfg.JWTAuth != nil {
+			jwtAuthCfgs = append(jwtAuthCfgs, *routePoliciesCfg.JWTAuth)
+		}
+		// use the VirtualServer externalAuth policy if the route does not define any
+		if routePoliciesCfg.ExternalAuth == nil {
+			routePoliciesCfg.ExternalAuth = policiesCfg.ExternalAuth
+		}
+		if routePoliciesCfg.ExternalAuth != nil {
+			externalAuthCfgs = append(externalAuthCfgs, *routePoliciesCfg.ExternalAuth)
+		}
+		// use the VirtualServer cache policy if the route does not define any
+		if routePoliciesCfg.Cache == nil {
+			routePoliciesCfg.Cache = policiesCfg.Cache
+		}
+		if routePoliciesCfg.Cache != nil {
+			cacheCfgs = append(cacheCfgs, *routePoliciesCfg.Cache)
+		}
+		// use the VirtualServer ipAccessControl policy if the route does not define any
+		if routePoliciesCfg.IPAccessControl == nil {
+			routePoliciesCfg.IPAccessControl = policiesCfg.IPAccessControl
+		}
+		if routePoliciesCfg.IPAccessControl != nil {
+			ipAccessControlCfgs = append(ipAccessControlCfgs, *routePoliciesCfg.IPAccessControl)
+		}
+		// use the VirtualServer compression and request limits policies if the route does not define any
+		if routePoliciesCfg.Compression == nil {
+			routePoliciesCfg.Compression = policiesCfg.Compression
+		}
+		if routePoliciesCfg.RequestLimits == nil {
+			routePoliciesCfg.RequestLimits = policiesCfg.RequestLimits
+		}
 		limitReqZones = append(limitReqZones, routePoliciesCfg.LimitReqZones...)
+		limitConnZones = append(limitConnZones, routePoliciesCfg.LimitConnZones...)
+		rateLimitMaps = append(rateLimitMaps, routePoliciesCfg.RateLimitMaps...)
+
+		routeLocationIndex := len(locations)
 
 		if len(r.Matches) > 0 {
 			cfg := generateMatchesConfig(
@@ -452,6 +769,16 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(vsEx *VirtualS
 				returnLocations = append(returnLocations, *returnLoc)
 			}
 		}
+
+		if r.Mirror != nil {
+			cfg := generateMirrorConfig(r.Mirror, virtualServerUpstreamNamer, crUpstreams, variableNamer, mirrorRoutes,
+				vsc.cfgParams, isVSR, "", "")
+			addMirrorCfgToLocations(cfg.Mirror, locations[routeLocationIndex:])
+
+			locations = append(locations, cfg.Locations...)
+			splitClients = append(splitClients, cfg.SplitClients...)
+			mirrorRoutes++
+		}
 	}
 
 	// generate config for subroutes of each VirtualServerRoute
@@ -459,6 +786,13 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(vsEx *VirtualS
 		isVSR := true
 		upstreamNamer := newUpstreamNamerForVirtualServerRoute(vsEx.VirtualServer, vsr)
 		for _, r := range vsr.Spec.Subroutes {
+			if r.Canary != nil {
+				r = generateRouteForCanary(r, vsEx.CanaryWeights)
+			}
+			if rolledBack := vsEx.RolledBackUpstreams[r.Path]; len(r.Splits) > 0 && len(rolledBack) > 0 {
+				r = generateRouteForRollback(r, rolledBack)
+			}
+
 			errorPageIndex := len(errorPageLocations)
 			errorPageLocations = append(errorPageLocations, generateErrorPageLocations(errorPageIndex, r.ErrorPages)...)
 			errorPages := r.ErrorPages
@@ -504,7 +838,59 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(vsEx *VirtualS
 			if policiesCfg.OIDC {
 				routePoliciesCfg.OIDC = policiesCfg.OIDC
 			}
+			// use the VirtualServer retry policy if the route does not define any
+			if routePoliciesCfg.Retry == nil {
+				routePoliciesCfg.Retry = policiesCfg.Retry
+			}
+			// use the VirtualServer cors policy if the route does not define any
+			if routePoliciesCfg.CORS == nil {
+				routePoliciesCfg.CORS = policiesCfg.CORS
+			}
+			if routePoliciesCfg.CORS != nil {
+				corsCfgs = append(corsCfgs, *routePoliciesCfg.CORS)
+			}
+			// use the VirtualServer jwt policy if the route does not define any jwt or oidc policy,
+			// so that the location passes the claims of the token to the upstream
+			if routePoliciesCfg.JWTAuth == nil && !routePoliciesCfg.OIDC {
+				routePoliciesCfg.JWTAuth = policiesCfg.JWTAuth
+			}
+			if routePoliciesCfg.JWTAuth != nil {
+				jwtAuthCfgs = append(jwtAuthCfgs, *routePoliciesCfg.JWTAuth)
+			}
+			// use the VirtualServer externalAuth policy if the route does not define any
+			if routePoliciesCfg.ExternalAuth == nil {
+				routePoliciesCfg.ExternalAuth = policiesCfg.ExternalAuth
+			}
+			if routePoliciesCfg.ExternalAuth != nil {
+				externalAuthCfgs = append(externalAuthCfgs, *routePoliciesCfg.ExternalAuth)
+			}
+			// use the VirtualServer cache policy if the route does not define any
+			if routePoliciesCfg.Cache == nil {
+				routePoliciesCfg.Cache = policiesCfg.Cache
+			}
+			if routePoliciesCfg.Cache != nil {
+				cacheCfgs = append(cacheCfgs, *routePoliciesCfg.Cache)
+			}
+			// use the VirtualServer ipAccessControl policy if the route does not define any
+			if routePoliciesCfg.IPAccessControl == nil {
+				routePoliciesCfg.IPAccessControl = policiesCfg.IPAccessControl
+			}
+			if routePoliciesCfg.IPAccessControl != nil {
+				ipAccessControlCfgs = append(ipAccessControlCfgs, *routePoliciesCfg.IPAccessControl)
+			}
+			// use the VirtualServer compression and request limits policies if the route does not define any
+			if routePoliciesCfg.Compression == nil {
+				routePoliciesCfg.Compression = policiesCfg.Compression
+			}
+			if routePoliciesCfg.RequestLimits == nil {
+				routePoliciesCfg.RequestLimits = policiesCfg.RequestLimits
+			}
 			limitReqZones = append(limitReqZones, routePoliciesCfg.LimitReqZones...)
+			limitConnZones = append(limitConnZones, routePoliciesCfg.LimitConnZones...)
+			rateLimitMaps = append(rateLimitMaps, routePoliciesCfg.RateLimitMaps...)
+
+			routeLocationIndex := len(locations)
+
 			if len(r.Matches) > 0 {
 				cfg := generateMatchesConfig(
 					r,
@@ -554,9 +940,56 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(vsEx *VirtualS
 					returnLocations = append(returnLocations, *returnLoc)
 				}
 			}
+
+			if r.Mirror != nil {
+				cfg := generateMirrorConfig(r.Mirror, upstreamNamer, crUpstreams, variableNamer, mirrorRoutes,
+					vsc.cfgParams, isVSR, vsr.Name, vsr.Namespace)
+				addMirrorCfgToLocations(cfg.Mirror, locations[routeLocationIndex:])
+
+				locations = append(locations, cfg.Locations...)
+				splitClients = append(splitClients, cfg.SplitClients...)
+				mirrorRoutes++
+			}
 		}
 	}
 
+	maps = append(maps, removeDuplicateMaps(rateLimitMaps)...)
+
+	corsMaps, corsPreflightLocations := generateCORSMapsAndPreflightLocations(corsCfgs)
+	maps = append(maps, corsMaps...)
+
+	geos, ipAccessControlMaps := generateIPAccessControlGeosAndMaps(ipAccessControlCfgs)
+	maps = append(maps, ipAccessControlMaps...)
+
+	// the cache zones are generated in the http context of the VirtualServer config file rather than in the main config,
+	// so that they are added and rolled back together with the VirtualServer that uses them.
+	externalAuthLocations, cacheZones := generateExternalAuthLocationsAndCacheZones(externalAuthCfgs)
+	cacheZones = append(cacheZones, generateCacheZones(cacheCfgs)...)
+
+	jwtClaimSets, jwtMaps, jwksLocations, jwksCacheZones := generateJWTClaimSetsMapsAndJWKSLocations(jwtAuthCfgs)
+	maps = append(maps, jwtMaps...)
+	cacheZones = append(cacheZones, jwksCacheZones...)
+
+	var jwtAuth *version2.JWTAuth
+	if policiesCfg.JWTAuth != nil {
+		jwtAuth = &policiesCfg.JWTAuth.Location
+	}
+
+	var clientHeaderBufferSize, largeClientHeaderBuffers string
+	if policiesCfg.RequestLimits != nil {
+		clientHeaderBufferSize = policiesCfg.RequestLimits.ClientHeaderBufferSize
+		largeClientHeaderBuffers = policiesCfg.RequestLimits.LargeClientHeaderBuffers
+	}
+
+	setRealIPFrom := vsc.cfgParams.SetRealIPFrom
+	realIPHeader := vsc.cfgParams.RealIPHeader
+	realIPRecursive := vsc.cfgParams.RealIPRecursive
+	if policiesCfg.IPAccessControl != nil && policiesCfg.IPAccessControl.ClientIP != nil {
+		setRealIPFrom = policiesCfg.IPAccessControl.ClientIP.TrustedAddresses
+		realIPHeader = policiesCfg.IPAccessControl.ClientIP.Header
+		realIPRecursive = policiesCfg.IPAccessControl.ClientIP.Recursive
+	}
+
 	httpSnippets := generateSnippets(vsc.enableSnippets, vsEx.VirtualServer.Spec.HTTPSnippets, []string{})
 	serverSnippets := generateSnippets(
 		vsc.enableSnippets,
@@ -564,26 +997,37 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(vsEx *VirtualS
 		vsc.cfgParams.ServerSnippets,
 	)
 
+	if hasGRPCLocations(locations) {
+		errorPageLocations = append(errorPageLocations, generateGRPCErrorPageLocations()...)
+	}
+
 	vsCfg := version2.VirtualServerConfig{
-		Upstreams:     upstreams,
-		SplitClients:  splitClients,
-		Maps:          maps,
-		StatusMatches: statusMatches,
-		LimitReqZones: removeDuplicateLimitReqZones(limitReqZones),
-		HTTPSnippets:  httpSnippets,
+		CacheZones:     cacheZones,
+		Geos:           geos,
+		Upstreams:      upstreams,
+		SplitClients:   splitClients,
+		Maps:           maps,
+		StatusMatches:  statusMatches,
+		LimitReqZones:  removeDuplicateLimitReqZones(limitReqZones),
+		LimitConnZones: removeDuplicateLimitConnZones(limitConnZones),
+		HTTPSnippets:   httpSnippets,
+		JWTClaimSets:   jwtClaimSets,
 		Server: version2.Server{
 			ServerName:                vsEx.VirtualServer.Spec.Host,
 			StatusZone:                vsEx.VirtualServer.Spec.Host,
 			ProxyProtocol:             vsc.cfgParams.ProxyProtocol,
 			SSL:                       sslConfig,
 			ServerTokens:              vsc.cfgParams.ServerTokens,
-			SetRealIPFrom:             vsc.cfgParams.SetRealIPFrom,
-			RealIPHeader:              vsc.cfgParams.RealIPHeader,
-			RealIPRecursive:           vsc.cfgParams.RealIPRecursive,
+			SetRealIPFrom:             setRealIPFrom,
+			RealIPHeader:              realIPHeader,
+			RealIPRecursive:           realIPRecursive,
 			Snippets:                  serverSnippets,
 			InternalRedirectLocations: internalRedirectLocations,
 			Locations:                 locations,
 			ReturnLocations:           returnLocations,
+			CORSPreflightLocations:    corsPreflightLocations,
+			ExternalAuthLocations:     externalAuthLocations,
+			JWKSLocations:             jwksLocations,
 			HealthChecks:              healthChecks,
 			TLSRedirect:               tlsRedirectConfig,
 			ErrorPageLocations:        errorPageLocations,
@@ -592,11 +1036,15 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(vsEx *VirtualS
 			Deny:                      policiesCfg.Deny,
 			LimitReqOptions:           policiesCfg.LimitReqOptions,
 			LimitReqs:                 policiesCfg.LimitReqs,
-			JWTAuth:                   policiesCfg.JWTAuth,
+			LimitConns:                policiesCfg.LimitConns,
+			JWTAuth:                   jwtAuth,
+			BasicAuth:                 policiesCfg.BasicAuth,
 			IngressMTLS:               policiesCfg.IngressMTLS,
 			EgressMTLS:                policiesCfg.EgressMTLS,
 			OIDC:                      vsc.oidcPolCfg.oidc,
 			WAF:                       policiesCfg.WAF,
+			ClientHeaderBufferSize:    clientHeaderBufferSize,
+			LargeClientHeaderBuffers:  largeClientHeaderBuffers,
 			PoliciesErrorReturn:       policiesCfg.ErrorReturn,
 			VSNamespace:               vsEx.VirtualServer.Namespace,
 			VSName:                    vsEx.VirtualServer.Name,
@@ -613,14 +1061,85 @@ type policiesCfg struct {
 	LimitReqOptions version2.LimitReqOptions
 	LimitReqZones   []version2.LimitReqZone
 	LimitReqs       []version2.LimitReq
-	JWTAuth         *version2.JWTAuth
+	LimitConnZones  []version2.LimitConnZone
+	LimitConns      []version2.LimitConn
+	RateLimitMaps   []version2.Map
+	JWTAuth         *jwtAuthCfg
+	BasicAuth       *version2.BasicAuth
 	IngressMTLS     *version2.IngressMTLS
 	EgressMTLS      *version2.EgressMTLS
 	OIDC            bool
 	WAF             *version2.WAF
+	Retry           *retryCfg
+	CORS            *corsCfg
+	ExternalAuth    *externalAuthCfg
+	Cache           *cacheCfg
+	Compression     *version2.Compression
+	RequestLimits   *requestLimitsCfg
+	IPAccessControl *ipAccessControlCfg
 	ErrorReturn     *version2.Return
 }
 
+// retryCfg holds the settings of a retry policy that override the next upstream settings of the upstreams.
+type retryCfg struct {
+	NextUpstream        string
+	NextUpstreamTries   *int
+	NextUpstreamTimeout string
+	PerTryTimeout       string
+}
+
+// requestLimitsCfg holds the settings of a request limits policy. The body settings override the settings
+// of the upstreams, while the header buffers are applied to the server.
+type requestLimitsCfg struct {
+	ClientMaxBodySize        string
+	ClientBodyBufferSize     string
+	RequestBufferingOff      bool
+	ClientHeaderBufferSize   string
+	LargeClientHeaderBuffers string
+}
+
+// jwtAuthCfg holds the configuration of a JWT policy: the JWT settings of the locations, the variables that hold
+// the claims of the token, the maps that check the required claims, the location that fetches the JWKS and
+// the zone that caches the JWKS when NGINX Plus doesn't support auth_jwt_key_cache.
+type jwtAuthCfg struct {
+	Location     version2.JWTAuth
+	ClaimSets    []version2.JWTClaimSet
+	Maps         []version2.Map
+	JWKSLocation *version2.JWKSLocation
+	CacheZone    *version2.CacheZone
+}
+
+// ipAccessControlCfg holds the configuration of an ipAccessControl policy: the deny settings of the locations,
+// the geo and the maps that match the client of a request and the client IP settings of the server.
+type ipAccessControlCfg struct {
+	Location version2.IPAccessControl
+	Geo      *version2.Geo
+	Maps     []version2.Map
+	ClientIP *conf_v1.ClientIP
+}
+
+// corsCfg holds the configuration of a CORS policy: the CORS headers of the locations, the map that checks
+// the origin of a request and the location that responds to the preflight requests.
+type corsCfg struct {
+	Location          version2.CORS
+	Map               version2.Map
+	PreflightLocation version2.CORSPreflightLocation
+}
+
+// externalAuthCfg holds the configuration of an external auth policy: the auth settings of the locations,
+// the location that passes the auth subrequests to the auth service and the zone that caches the auth responses.
+type externalAuthCfg struct {
+	Location     version2.ExternalAuth
+	AuthLocation version2.ExternalAuthLocation
+	CacheZone    *version2.CacheZone
+}
+
+// cacheCfg holds the configuration of a cache policy: the cache settings of the locations and the cache zone.
+type cacheCfg struct {
+	Location version2.Cache
+	Zone     version2.CacheZone
+}
+
 func newPoliciesConfig() *policiesCfg {
 	return &policiesCfg{}
 }
@@ -672,10 +1191,7 @@ func (p *policiesCfg) addRateLimitConfig(
 	vsName string,
 ) *validationResults {
 	res := newValidationResults()
-	rlZoneName := fmt.Sprintf("pol_rl_%v_%v_%v_%v", polNamespace, polName, vsNamespace, vsName)
-	p.LimitReqs = append(p.LimitReqs, generateLimitReq(rlZoneName, rateLimit))
-	p.LimitReqZones = append(p.LimitReqZones, generateLimitReqZone(rlZoneName, rateLimit))
-	if len(p.LimitReqs) == 1 {
+	if len(p.LimitReqs) == 0 {
 		p.LimitReqOptions = generateLimitReqOptions(rateLimit)
 	} else {
 		curOptions := generateLimitReqOptions(rateLimit)
@@ -689,6 +1205,50 @@ func (p *policiesCfg) addRateLimitConfig(
 			res.addWarningf("RateLimit policy %s with limit request option rejectCode='%v' is overridden to rejectCode='%v' by the first policy reference in this context", polKey, curOptions.RejectCode, p.LimitReqOptions.RejectCode)
 		}
 	}
+
+	rlZoneName := fmt.Sprintf("pol_rl_%v_%v_%v_%v", polNamespace, polName, vsNamespace, vsName)
+	limits := append([]conf_v1.RateLimitTier{
+		{
+			Rate:      rateLimit.Rate,
+			Key:       rateLimit.Key,
+			Delay:     rateLimit.Delay,
+			NoDelay:   rateLimit.NoDelay,
+			Burst:     rateLimit.Burst,
+			ZoneSize:  rateLimit.ZoneSize,
+			Condition: rateLimit.Condition,
+		},
+	}, rateLimit.Limits...)
+
+	for i, l := range limits {
+		zoneName := rlZoneName
+		if i > 0 {
+			zoneName = fmt.Sprintf("%v_%v", rlZoneName, i)
+		}
+
+		key := l.Key
+		if l.Condition != nil {
+			m := generateRateLimitKeyMap(zoneName, l.Key, *l.Condition)
+			p.RateLimitMaps = append(p.RateLimitMaps, m)
+			key = m.Variable
+		}
+
+		p.LimitReqs = append(p.LimitReqs, generateLimitReq(zoneName, l))
+		p.LimitReqZones = append(p.LimitReqZones, generateLimitReqZone(zoneName, key, l))
+	}
+
+	if rateLimit.LimitConn != nil {
+		lcZoneName := fmt.Sprintf("pol_lc_%v_%v_%v_%v", polNamespace, polName, vsNamespace, vsName)
+		p.LimitConnZones = append(p.LimitConnZones, version2.LimitConnZone{
+			Key:      rateLimit.LimitConn.Key,
+			ZoneName: lcZoneName,
+			ZoneSize: rateLimit.LimitConn.ZoneSize,
+		})
+		p.LimitConns = append(p.LimitConns, version2.LimitConn{
+			ZoneName:    lcZoneName,
+			Connections: rateLimit.LimitConn.Connections,
+		})
+	}
+
 	return res
 }
 
@@ -696,7 +1256,12 @@ func (p *policiesCfg) addJWTAuthConfig(
 	jwtAuth *conf_v1.JWTAuth,
 	polKey string,
 	polNamespace string,
+	polName string,
+	vsNamespace string,
+	vsName string,
 	secretRefs map[string]*secrets.SecretReference,
+	requireSupported bool,
+	keyCacheSupported bool,
 ) *validationResults {
 	res := newValidationResults()
 	if p.JWTAuth != nil {
@@ -704,26 +1269,175 @@ func (p *policiesCfg) addJWTAuthConfig(
 		return res
 	}
 
-	jwtSecretKey := fmt.Sprintf("%v/%v", polNamespace, jwtAuth.Secret)
-	secretRef := secretRefs[jwtSecretKey]
+	// ignoring the required claims would let in the tokens that don't satisfy them
+	if len(jwtAuth.RequireClaims) > 0 && !requireSupported {
+		res.addWarningf("JWT policy %s requires claims, which is not supported by this version of NGINX Plus. NGINX Plus R25 or newer is required", polKey)
+		res.isError = true
+		return res
+	}
+
+	cfg := &jwtAuthCfg{
+		Location: version2.JWTAuth{
+			Realm: jwtAuth.Realm,
+			Token: jwtAuth.Token,
+		},
+	}
+
+	if jwtAuth.Secret != "" {
+		jwtSecretKey := fmt.Sprintf("%v/%v", polNamespace, jwtAuth.Secret)
+		secretRef := secretRefs[jwtSecretKey]
+		var secretType api_v1.SecretType
+		if secretRef.Secret != nil {
+			secretType = secretRef.Secret.Type
+		}
+		if secretType != "" && secretType != secrets.SecretTypeJWK {
+			res.addWarningf("JWT policy %s references a secret %s of a wrong type '%s', must be '%s'", polKey, jwtSecretKey, secretType, secrets.SecretTypeJWK)
+			res.isError = true
+			return res
+		} else if secretRef.Error != nil {
+			res.addWarningf("JWT policy %s references an invalid secret %s: %v", polKey, jwtSecretKey, secretRef.Error)
+			res.isError = true
+			return res
+		}
+
+		cfg.Location.Secret = secretRef.Path
+	} else {
+		keyRequestPath := fmt.Sprintf("/%vpol_jwt_%v_%v", internalLocationPrefix, polNamespace, polName)
+
+		cfg.Location.KeyRequest = keyRequestPath
+		cfg.JWKSLocation = &version2.JWKSLocation{
+			Path:      keyRequestPath,
+			URI:       jwtAuth.JWKSURI,
+			SSLServer: strings.HasPrefix(jwtAuth.JWKSURI, "https://"),
+		}
+	}
+
+	if jwtAuth.KeyCache != "" {
+		switch {
+		case keyCacheSupported:
+			cfg.Location.KeyCache = generateTime(jwtAuth.KeyCache)
+		case cfg.JWKSLocation != nil:
+			// the zone is defined in the http context, so its name must be unique among all VirtualServers
+			zoneName := fmt.Sprintf("pol_jwt_%v_%v_%v_%v", polNamespace, polName, vsNamespace, vsName)
+			cfg.CacheZone = &version2.CacheZone{
+				Name: zoneName,
+				Path: path.Join(defaultCachePath, zoneName),
+				Size: "1m",
+			}
+			cfg.JWKSLocation.CacheZone = zoneName
+			cfg.JWKSLocation.CacheTTL = generateTime(jwtAuth.KeyCache)
+		default:
+			res.addWarningf("JWT policy %s: keyCache is ignored, because this version of NGINX Plus doesn't support caching the key from a secret. NGINX Plus R26 or newer is required", polKey)
+		}
+	}
+
+	// the claim sets and the maps are defined in the http context, so their variables must be unique among all VirtualServers
+	variablePrefix := strings.NewReplacer("-", "_", ".", "_").Replace(
+		fmt.Sprintf("$pol_jwt_%v_%v_%v_%v", polNamespace, polName, vsNamespace, vsName))
+
+	claimVariables := make(map[string]string)
+	for _, claim := range getJWTClaims(jwtAuth) {
+		variable := fmt.Sprintf("%v_claim_%d", variablePrefix, len(cfg.ClaimSets))
+		claimVariables[claim] = variable
+		cfg.ClaimSets = append(cfg.ClaimSets, version2.JWTClaimSet{
+			Variable: variable,
+			Claim:    strings.Split(claim, "."),
+		})
+	}
+
+	for i, r := range jwtAuth.RequireClaims {
+		value := fmt.Sprintf(`"~^%v$"`, regexp.QuoteMeta(r.Equals))
+		if r.Contains != "" {
+			// the values of an array claim are separated by commas
+			value = fmt.Sprintf(`"~(^|,)%v(,|$)"`, regexp.QuoteMeta(r.Contains))
+		}
+
+		variable := fmt.Sprintf("%v_require_%d", variablePrefix, i)
+		cfg.Maps = append(cfg.Maps, version2.Map{
+			Source:   claimVariables[r.Claim],
+			Variable: variable,
+			Parameters: []version2.Parameter{
+				{
+					Value:  "default",
+					Result: "0",
+				},
+				{
+					Value:  value,
+					Result: "1",
+				},
+			},
+		})
+		cfg.Location.Require = append(cfg.Location.Require, variable)
+	}
+
+	for _, h := range jwtAuth.ClaimHeaders {
+		cfg.Location.ClaimHeaders = append(cfg.Location.ClaimHeaders, version2.Header{
+			Name:  h.Header,
+			Value: claimVariables[h.Claim],
+		})
+	}
+
+	p.JWTAuth = cfg
+	return res
+}
+
+// getJWTClaims returns the unique claims of the claim requirements and the claim headers of a JWT policy.
+func getJWTClaims(jwtAuth *conf_v1.JWTAuth) []string {
+	var claims []string
+	encountered := make(map[string]bool)
+
+	for _, r := range jwtAuth.RequireClaims {
+		if !encountered[r.Claim] {
+			encountered[r.Claim] = true
+			claims = append(claims, r.Claim)
+		}
+	}
+	for _, h := range jwtAuth.ClaimHeaders {
+		if !encountered[h.Claim] {
+			encountered[h.Claim] = true
+			claims = append(claims, h.Claim)
+		}
+	}
+
+	return claims
+}
+
+func (p *policiesCfg) addBasicAuthConfig(
+	basicAuth *conf_v1.BasicAuth,
+	polKey string,
+	polNamespace string,
+	secretRefs map[string]*secrets.SecretReference,
+) *validationResults {
+	res := newValidationResults()
+	if p.BasicAuth != nil {
+		res.addWarningf("Multiple basic auth policies in the same context is not valid. Basic auth policy %s will be ignored", polKey)
+		return res
+	}
+
+	htpasswdSecretKey := fmt.Sprintf("%v/%v", polNamespace, basicAuth.Secret)
+	secretRef := secretRefs[htpasswdSecretKey]
 	var secretType api_v1.SecretType
 	if secretRef.Secret != nil {
 		secretType = secretRef.Secret.Type
 	}
-	if secretType != "" && secretType != secrets.SecretTypeJWK {
-		res.addWarningf("JWT policy %s references a secret %s of a wrong type '%s', must be '%s'", polKey, jwtSecretKey, secretType, secrets.SecretTypeJWK)
+	if secretType != "" && secretType != secrets.SecretTypeHtpasswd {
+		res.addWarningf("Basic auth policy %s references a secret %s of a wrong type '%s', must be '%s'", polKey, htpasswdSecretKey, secretType, secrets.SecretTypeHtpasswd)
 		res.isError = true
 		return res
 	} else if secretRef.Error != nil {
-		res.addWarningf("JWT policy %s references an invalid secret %s: %v", polKey, jwtSecretKey, secretRef.Error)
+		res.addWarningf("Basic auth policy %s references an invalid secret %s: %v", polKey, htpasswdSecretKey, secretRef.Error)
 		res.isError = true
 		return res
 	}
 
-	p.JWTAuth = &version2.JWTAuth{
+	realm := basicAuth.Realm
+	if realm == "" {
+		realm = defaultBasicAuthRealm
+	}
+
+	p.BasicAuth = &version2.BasicAuth{
 		Secret: secretRef.Path,
-		Realm:  jwtAuth.Realm,
-		Token:  jwtAuth.Token,
+		Realm:  realm,
 	}
 	return res
 }
@@ -861,8 +1575,453 @@ func (p *policiesCfg) addEgressMTLSConfig(
 	return res
 }
 
-func (p *policiesCfg) addOIDCConfig(
-	oidc *conf_v1.OIDC,
+func (p *policiesCfg) addRetryConfig(retry *conf_v1.Retry, polKey string) *validationResults {
+	res := newValidationResults()
+	if p.Retry != nil {
+		res.addWarningf("Multiple retry policies in the same context is not valid. Retry policy %s will be ignored", polKey)
+		return res
+	}
+
+	nextUpstream := "error timeout"
+	if len(retry.Conditions) > 0 {
+		nextUpstream = strings.Join(retry.Conditions, " ")
+	}
+	if retry.NonIdempotent {
+		nextUpstream += " non_idempotent"
+	}
+
+	p.Retry = &retryCfg{
+		NextUpstream:        nextUpstream,
+		NextUpstreamTries:   retry.Tries,
+		NextUpstreamTimeout: retry.Timeout,
+		PerTryTimeout:       retry.PerTryTimeout,
+	}
+	return res
+}
+
+func (p *policiesCfg) addCacheConfig(
+	cache *conf_v1.Cache,
+	polKey string,
+	polNamespace string,
+	polName string,
+	vsNamespace string,
+	vsName string,
+) *validationResults {
+	res := newValidationResults()
+	if p.Cache != nil {
+		res.addWarningf("Multiple cache policies in the same context is not valid. Cache policy %s will be ignored", polKey)
+		return res
+	}
+
+	var valid []version2.CacheValid
+	for _, v := range cache.Valid {
+		valid = append(valid, version2.CacheValid{
+			Codes: v.Codes,
+			Time:  v.Time,
+		})
+	}
+
+	// the zone is defined in the http context, so its name must be unique among all VirtualServers
+	zoneName := fmt.Sprintf("pol_cache_%v_%v_%v_%v", polNamespace, polName, vsNamespace, vsName)
+
+	p.Cache = &cacheCfg{
+		Location: version2.Cache{
+			ZoneName:         zoneName,
+			Key:              generateString(cache.Key, defaultCacheKey),
+			Methods:          cache.Methods,
+			Valid:            valid,
+			MinUses:          generateIntFromPointer(cache.MinUses, 0),
+			UseStale:         cache.UseStale,
+			BackgroundUpdate: cache.BackgroundUpdate,
+			Lock:             cache.Lock,
+			Bypass:           cache.Bypass,
+			StatusHeader:     cache.StatusHeader,
+		},
+		Zone: version2.CacheZone{
+			Name:     zoneName,
+			Path:     path.Join(generateString(cache.Path, defaultCachePath), zoneName),
+			Size:     cache.ZoneSize,
+			MaxSize:  cache.MaxSize,
+			Inactive: cache.Inactive,
+		},
+	}
+	return res
+}
+
+func (p *policiesCfg) addCompressionConfig(compression *conf_v1.Compression, polKey string) *validationResults {
+	res := newValidationResults()
+	if p.Compression != nil {
+		res.addWarningf(
+			"Multiple compression policies in the same context is not valid. Compression policy %s will be ignored",
+			polKey,
+		)
+		return res
+	}
+
+	p.Compression = &version2.Compression{}
+
+	if gzip := compression.Gzip; gzip != nil {
+		p.Compression.Gzip = &version2.Gzip{
+			Types:     gzip.Types,
+			Level:     generateIntFromPointer(gzip.Level, 0),
+			MinLength: generateIntFromPointer(gzip.MinLength, 0),
+			Proxied:   gzip.Proxied,
+			Vary:      gzip.Vary,
+		}
+	}
+
+	if brotli := compression.Brotli; brotli != nil {
+		p.Compression.Brotli = &version2.Brotli{
+			Types:     brotli.Types,
+			Level:     generateIntFromPointer(brotli.Level, 0),
+			MinLength: generateIntFromPointer(brotli.MinLength, 0),
+		}
+	}
+
+	return res
+}
+
+func (p *policiesCfg) addRequestLimitsConfig(
+	requestLimits *conf_v1.RequestLimits,
+	polKey string,
+	context string,
+) *validationResults {
+	res := newValidationResults()
+	if p.RequestLimits != nil {
+		res.addWarningf(
+			"Multiple requestLimits policies in the same context is not valid. RequestLimits policy %s will be ignored",
+			polKey,
+		)
+		return res
+	}
+
+	p.RequestLimits = &requestLimitsCfg{
+		ClientMaxBodySize:    requestLimits.ClientMaxBodySize,
+		ClientBodyBufferSize: requestLimits.ClientBodyBufferSize,
+		RequestBufferingOff:  !generateBool(requestLimits.RequestBuffering, true),
+	}
+
+	if requestLimits.ClientHeaderBufferSize == "" && requestLimits.LargeClientHeaderBuffers == nil {
+		return res
+	}
+
+	// the header buffers can only be configured for the whole server
+	if context != specContext {
+		res.addWarningf("The header buffers of RequestLimits policy %s are not allowed in the %v context and will be ignored", polKey, context)
+		return res
+	}
+
+	p.RequestLimits.ClientHeaderBufferSize = requestLimits.ClientHeaderBufferSize
+	p.RequestLimits.LargeClientHeaderBuffers = generateBuffers(requestLimits.LargeClientHeaderBuffers, "")
+
+	return res
+}
+
+const (
+	defaultIPAccessControlDenyCode = 403
+	geoIP2CountryCodeVariable      = "$geoip2_country_code"
+	geoIP2ASNVariable              = "$geoip2_asn"
+)
+
+func (p *policiesCfg) addIPAccessControlConfig(
+	ipAccessControl *conf_v1.IPAccessControl,
+	polKey string,
+	polNamespace string,
+	polName string,
+	vsNamespace string,
+	vsName string,
+	context string,
+	geoIP2Country bool,
+	geoIP2ASN bool,
+) *validationResults {
+	res := newValidationResults()
+	if p.IPAccessControl != nil {
+		res.addWarningf(
+			"Multiple ipAccessControl policies in the same context is not valid. IPAccessControl policy %s will be ignored",
+			polKey,
+		)
+		return res
+	}
+
+	rule := ipAccessControl.Allow
+	if rule == nil {
+		rule = ipAccessControl.Deny
+	}
+
+	if len(rule.Countries) > 0 && !geoIP2Country {
+		res.addWarningf("IPAccessControl policy %s references countries, but the GeoIP2 country database is not configured", polKey)
+		res.isError = true
+		return res
+	}
+	if len(rule.ASNs) > 0 && !geoIP2ASN {
+		res.addWarningf("IPAccessControl policy %s references ASNs, but the GeoIP2 ASN database is not configured", polKey)
+		res.isError = true
+		return res
+	}
+
+	// the geo and the maps are defined in the http context, so their variables must be unique among all VirtualServers
+	variablePrefix := strings.NewReplacer("-", "_", ".", "_").Replace(
+		fmt.Sprintf("$pol_ipac_%v_%v_%v_%v", polNamespace, polName, vsNamespace, vsName))
+
+	cfg := &ipAccessControlCfg{}
+	var matchVariables []string
+
+	if len(rule.Addresses) > 0 {
+		variable := variablePrefix + "_address"
+		params := []version2.Parameter{{Value: "default", Result: "0"}}
+		for _, a := range rule.Addresses {
+			params = append(params, version2.Parameter{Value: a, Result: "1"})
+		}
+		cfg.Geo = &version2.Geo{
+			Variable:   variable,
+			Parameters: params,
+		}
+		matchVariables = append(matchVariables, variable)
+	}
+
+	if len(rule.Countries) > 0 {
+		variable := variablePrefix + "_country"
+		params := []version2.Parameter{{Value: "default", Result: "0"}}
+		for _, c := range rule.Countries {
+			params = append(params, version2.Parameter{Value: fmt.Sprintf("%q", c), Result: "1"})
+		}
+		cfg.Maps = append(cfg.Maps, version2.Map{
+			Source:     geoIP2CountryCodeVariable,
+			Variable:   variable,
+			Parameters: params,
+		})
+		matchVariables = append(matchVariables, variable)
+	}
+
+	if len(rule.ASNs) > 0 {
+		variable := variablePrefix + "_asn"
+		params := []version2.Parameter{{Value: "default", Result: "0"}}
+		for _, asn := range rule.ASNs {
+			params = append(params, version2.Parameter{Value: fmt.Sprintf(`"%d"`, asn), Result: "1"})
+		}
+		cfg.Maps = append(cfg.Maps, version2.Map{
+			Source:     geoIP2ASNVariable,
+			Variable:   variable,
+			Parameters: params,
+		})
+		matchVariables = append(matchVariables, variable)
+	}
+
+	// a request matches the rule if any of the variables is 1
+	var source string
+	for _, v := range matchVariables {
+		source += fmt.Sprintf("${%v}", strings.TrimPrefix(v, "$"))
+	}
+
+	matchResult, noMatchResult := "0", "1"
+	if ipAccessControl.Deny != nil {
+		matchResult, noMatchResult = "1", "0"
+	}
+
+	denyVariable := variablePrefix + "_deny"
+	cfg.Maps = append(cfg.Maps, version2.Map{
+		Source:   fmt.Sprintf("%q", source),
+		Variable: denyVariable,
+		Parameters: []version2.Parameter{
+			{Value: "default", Result: noMatchResult},
+			{Value: `"~1"`, Result: matchResult},
+		},
+	})
+
+	cfg.Location = version2.IPAccessControl{
+		DenyVariable: denyVariable,
+		Code:         defaultIPAccessControlDenyCode,
+		Policy:       polKey,
+	}
+	if ipAccessControl.DenyResponse != nil {
+		if ipAccessControl.DenyResponse.Code != 0 {
+			cfg.Location.Code = ipAccessControl.DenyResponse.Code
+		}
+		cfg.Location.Body = ipAccessControl.DenyResponse.Body
+	}
+
+	p.IPAccessControl = cfg
+
+	if ipAccessControl.ClientIP == nil {
+		return res
+	}
+
+	// the client IP can only be configured for the whole server
+	if context != specContext {
+		res.addWarningf("The clientIP of IPAccessControl policy %s is not allowed in the %v context and will be ignored", polKey, context)
+		return res
+	}
+
+	cfg.ClientIP = ipAccessControl.ClientIP
+
+	return res
+}
+
+func (p *policiesCfg) addCORSConfig(
+	cors *conf_v1.CORS,
+	polKey string,
+	polNamespace string,
+	polName string,
+	vsNamespace string,
+	vsName string,
+) *validationResults {
+	res := newValidationResults()
+	if p.CORS != nil {
+		res.addWarningf("Multiple cors policies in the same context is not valid. CORS policy %s will be ignored", polKey)
+		return res
+	}
+
+	// the map is defined in the http context, so its variable must be unique among all VirtualServers
+	originVariable := strings.NewReplacer("-", "_", ".", "_").Replace(
+		fmt.Sprintf("$pol_cors_%v_%v_%v_%v", polNamespace, polName, vsNamespace, vsName))
+
+	params := []version2.Parameter{
+		{
+			Value:  "default",
+			Result: `""`,
+		},
+	}
+	for _, o := range cors.AllowOrigins {
+		if o == "*" {
+			params[0].Result = "$http_origin"
+			continue
+		}
+		params = append(params, version2.Parameter{
+			Value:  fmt.Sprintf(`"%v"`, o),
+			Result: "$http_origin",
+		})
+	}
+
+	allowMethods := "GET, HEAD, POST"
+	if len(cors.AllowMethods) > 0 {
+		allowMethods = strings.Join(cors.AllowMethods, ", ")
+	}
+
+	var maxAge string
+	if cors.MaxAge != nil {
+		maxAge = strconv.Itoa(*cors.MaxAge)
+	}
+
+	preflightPath := fmt.Sprintf("/%vpol_cors_%v_%v", internalLocationPrefix, polNamespace, polName)
+
+	p.CORS = &corsCfg{
+		Location: version2.CORS{
+			OriginVariable:   originVariable,
+			ExposeHeaders:    strings.Join(cors.ExposeHeaders, ", "),
+			AllowCredentials: cors.AllowCredentials,
+			PreflightPath:    preflightPath,
+		},
+		Map: version2.Map{
+			Source:     "$http_origin",
+			Variable:   originVariable,
+			Parameters: params,
+		},
+		PreflightLocation: version2.CORSPreflightLocation{
+			Path:             preflightPath,
+			OriginVariable:   originVariable,
+			AllowMethods:     allowMethods,
+			AllowHeaders:     strings.Join(cors.AllowHeaders, ", "),
+			AllowCredentials: cors.AllowCredentials,
+			MaxAge:           maxAge,
+		},
+	}
+	return res
+}
+
+func (p *policiesCfg) addExternalAuthConfig(
+	externalAuth *conf_v1.ExternalAuth,
+	polKey string,
+	polNamespace string,
+	polName string,
+	vsNamespace string,
+	vsName string,
+) *validationResults {
+	res := newValidationResults()
+	if p.ExternalAuth != nil {
+		res.addWarningf("Multiple externalAuth policies in the same context is not valid. ExternalAuth policy %s will be ignored", polKey)
+		return res
+	}
+
+	authLocation := version2.ExternalAuthLocation{
+		Path: fmt.Sprintf("/%vpol_ext_auth_%v_%v", internalLocationPrefix, polNamespace, polName),
+	}
+	if externalAuth.AuthServiceName != "" {
+		authPath := externalAuth.AuthPath
+		if authPath == "" {
+			authPath = "/"
+		}
+		upstreamName := getNameForExternalAuthUpstream(vsNamespace, vsName, polNamespace, polName)
+		authLocation.ProxyPass = fmt.Sprintf("http://%v%v", upstreamName, authPath)
+	} else {
+		authLocation.ProxyPass = externalAuth.AuthURL
+		authLocation.SSLServer = strings.HasPrefix(externalAuth.AuthURL, "https://")
+	}
+
+	var cacheZone *version2.CacheZone
+	if externalAuth.CacheTTL != "" {
+		// the zone is defined in the http context, so its name must be unique among all VirtualServers
+		zoneName := fmt.Sprintf("pol_ext_auth_%v_%v_%v_%v", polNamespace, polName, vsNamespace, vsName)
+		cacheZone = &version2.CacheZone{
+			Name: zoneName,
+			Path: path.Join(defaultCachePath, zoneName),
+			Size: "1m",
+		}
+		authLocation.CacheZone = zoneName
+		authLocation.CacheTTL = generateTime(externalAuth.CacheTTL)
+	}
+
+	var headers []version2.ExternalAuthResponseHeader
+	for _, h := range externalAuth.ResponseHeaders {
+		name := strings.ToLower(strings.ReplaceAll(h, "-", "_"))
+		headers = append(headers, version2.ExternalAuthResponseHeader{
+			Name:     h,
+			Variable: fmt.Sprintf("$ext_auth_%v", name),
+			Value:    fmt.Sprintf("$upstream_http_%v", name),
+		})
+	}
+
+	p.ExternalAuth = &externalAuthCfg{
+		Location: version2.ExternalAuth{
+			AuthPath:        authLocation.Path,
+			ResponseHeaders: headers,
+			SigninURL:       externalAuth.SigninURL,
+		},
+		AuthLocation: authLocation,
+		CacheZone:    cacheZone,
+	}
+	return res
+}
+
+func getNameForExternalAuthUpstream(vsNamespace string, vsName string, polNamespace string, polName string) string {
+	return fmt.Sprintf("vs_%v_%v_pol_ext_auth_%v_%v", vsNamespace, vsName, polNamespace, polName)
+}
+
+// generateExternalAuthUpstream generates the upstream for the Service of an external auth policy.
+func generateExternalAuthUpstream(externalAuth *conf_v1.ExternalAuth) conf_v1.Upstream {
+	return conf_v1.Upstream{
+		Name:    externalAuth.AuthServiceName,
+		Service: externalAuth.AuthServiceName,
+		Port:    externalAuth.AuthServicePort,
+	}
+}
+
+// getExternalAuthPolicyKeys returns the sorted keys of the external auth policies that reference a Service.
+func getExternalAuthPolicyKeys(policies map[string]*conf_v1.Policy) []string {
+	var keys []string
+
+	for key, pol := range policies {
+		if pol.Spec.ExternalAuth != nil && pol.Spec.ExternalAuth.AuthServiceName != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (p *policiesCfg) addOIDCConfig(
+	oidc *conf_v1.OIDC,
 	polKey string,
 	polNamespace string,
 	secretRefs map[string]*secrets.SecretReference,
@@ -888,42 +2047,58 @@ func (p *policiesCfg) addOIDCConfig(
 			return res
 		}
 	} else {
-		secretKey := fmt.Sprintf("%v/%v", polNamespace, oidc.ClientSecret)
-		secretRef := secretRefs[secretKey]
+		// a public client with PKCE doesn't have a client secret
+		var clientSecret []byte
+		if oidc.ClientSecret != "" {
+			secretKey := fmt.Sprintf("%v/%v", polNamespace, oidc.ClientSecret)
+			secretRef := secretRefs[secretKey]
+
+			var secretType api_v1.SecretType
+			if secretRef.Secret != nil {
+				secretType = secretRef.Secret.Type
+			}
+			if secretType != "" && secretType != secrets.SecretTypeOIDC {
+				res.addWarningf("OIDC policy %s references a secret %s of a wrong type '%s', must be '%s'", polKey, secretKey, secretType, secrets.SecretTypeOIDC)
+				res.isError = true
+				return res
+			} else if secretRef.Error != nil {
+				res.addWarningf("OIDC policy %s references an invalid secret %s: %v", polKey, secretKey, secretRef.Error)
+				res.isError = true
+				return res
+			}
 
-		var secretType api_v1.SecretType
-		if secretRef.Secret != nil {
-			secretType = secretRef.Secret.Type
+			clientSecret = secretRef.Secret.Data[ClientSecretKey]
 		}
-		if secretType != "" && secretType != secrets.SecretTypeOIDC {
-			res.addWarningf("OIDC policy %s references a secret %s of a wrong type '%s', must be '%s'", polKey, secretKey, secretType, secrets.SecretTypeOIDC)
-			res.isError = true
-			return res
-		} else if secretRef.Error != nil {
-			res.addWarningf("OIDC policy %s references an invalid secret %s: %v", polKey, secretKey, secretRef.Error)
-			res.isError = true
-			return res
-		}
-
-		clientSecret := secretRef.Secret.Data[ClientSecretKey]
 
 		redirectURI := oidc.RedirectURI
 		if redirectURI == "" {
 			redirectURI = "/_codexch"
 		}
+		postLogoutRedirectURI := oidc.PostLogoutRedirectURI
+		if postLogoutRedirectURI == "" {
+			postLogoutRedirectURI = "/_logout"
+		}
 		scope := oidc.Scope
 		if scope == "" {
 			scope = "openid"
 		}
 
+		forwardTokenHeader, forwardTokenValue := generateOIDCForwardToken(oidc.ForwardToken, oidc.ForwardTokenHeader)
+
 		oidcPolCfg.oidc = &version2.OIDC{
-			AuthEndpoint:  oidc.AuthEndpoint,
-			TokenEndpoint: oidc.TokenEndpoint,
-			JwksURI:       oidc.JWKSURI,
-			ClientID:      oidc.ClientID,
-			ClientSecret:  string(clientSecret),
-			Scope:         scope,
-			RedirectURI:   redirectURI,
+			AuthEndpoint:          oidc.AuthEndpoint,
+			TokenEndpoint:         oidc.TokenEndpoint,
+			JwksURI:               oidc.JWKSURI,
+			ClientID:              oidc.ClientID,
+			ClientSecret:          string(clientSecret),
+			Scope:                 scope,
+			RedirectURI:           redirectURI,
+			EndSessionEndpoint:    oidc.EndSessionEndpoint,
+			PostLogoutRedirectURI: postLogoutRedirectURI,
+			AuthExtraArgs:         strings.Join(oidc.AuthExtraArgs, "&"),
+			PKCEEnable:            oidc.PKCEEnable,
+			ForwardTokenHeader:    forwardTokenHeader,
+			ForwardTokenValue:     forwardTokenValue,
 		}
 		oidcPolCfg.key = polKey
 	}
@@ -933,6 +2108,26 @@ func (p *policiesCfg) addOIDCConfig(
 	return res
 }
 
+// generateOIDCForwardToken generates the header that passes the ID or the access token of an OIDC policy to the upstream.
+// By default, the token is passed as a Bearer token in the Authorization header.
+func generateOIDCForwardToken(token string, header string) (string, string) {
+	var variable string
+	switch token {
+	case "id":
+		variable = "$session_jwt"
+	case "access":
+		variable = "$access_token"
+	default:
+		return "", ""
+	}
+
+	if header == "" || strings.EqualFold(header, "Authorization") {
+		return "Authorization", "Bearer " + variable
+	}
+
+	return header, variable
+}
+
 func (p *policiesCfg) addWAFConfig(
 	waf *conf_v1.WAF,
 	polKey string,
@@ -1020,7 +2215,19 @@ func (vsc *virtualServerConfigurator) generatePolicies(
 					ownerDetails.vsName,
 				)
 			case pol.Spec.JWTAuth != nil:
-				res = config.addJWTAuthConfig(pol.Spec.JWTAuth, key, polNamespace, policyOpts.secretRefs)
+				res = config.addJWTAuthConfig(
+					pol.Spec.JWTAuth,
+					key,
+					polNamespace,
+					p.Name,
+					ownerDetails.vsNamespace,
+					ownerDetails.vsName,
+					policyOpts.secretRefs,
+					vsc.jwtRequireSupported,
+					vsc.jwtKeyCacheSupported,
+				)
+			case pol.Spec.BasicAuth != nil:
+				res = config.addBasicAuthConfig(pol.Spec.BasicAuth, key, polNamespace, policyOpts.secretRefs)
 			case pol.Spec.IngressMTLS != nil:
 				res = config.addIngressMTLSConfig(
 					pol.Spec.IngressMTLS,
@@ -1036,6 +2243,51 @@ func (vsc *virtualServerConfigurator) generatePolicies(
 				res = config.addOIDCConfig(pol.Spec.OIDC, key, polNamespace, policyOpts.secretRefs, vsc.oidcPolCfg)
 			case pol.Spec.WAF != nil:
 				res = config.addWAFConfig(pol.Spec.WAF, key, polNamespace, policyOpts.apResources)
+			case pol.Spec.Retry != nil:
+				res = config.addRetryConfig(pol.Spec.Retry, key)
+			case pol.Spec.CORS != nil:
+				res = config.addCORSConfig(
+					pol.Spec.CORS,
+					key,
+					polNamespace,
+					p.Name,
+					ownerDetails.vsNamespace,
+					ownerDetails.vsName,
+				)
+			case pol.Spec.ExternalAuth != nil:
+				res = config.addExternalAuthConfig(
+					pol.Spec.ExternalAuth,
+					key,
+					polNamespace,
+					p.Name,
+					ownerDetails.vsNamespace,
+					ownerDetails.vsName,
+				)
+			case pol.Spec.Compression != nil:
+				res = config.addCompressionConfig(pol.Spec.Compression, key)
+			case pol.Spec.RequestLimits != nil:
+				res = config.addRequestLimitsConfig(pol.Spec.RequestLimits, key, context)
+			case pol.Spec.IPAccessControl != nil:
+				res = config.addIPAccessControlConfig(
+					pol.Spec.IPAccessControl,
+					key,
+					polNamespace,
+					p.Name,
+					ownerDetails.vsNamespace,
+					ownerDetails.vsName,
+					context,
+					vsc.geoIP2Country,
+					vsc.geoIP2ASN,
+				)
+			case pol.Spec.Cache != nil:
+				res = config.addCacheConfig(
+					pol.Spec.Cache,
+					key,
+					polNamespace,
+					p.Name,
+					ownerDetails.vsNamespace,
+					ownerDetails.vsName,
+				)
 			default:
 				res = newValidationResults()
 			}
@@ -1056,19 +2308,19 @@ func (vsc *virtualServerConfigurator) generatePolicies(
 	return *config
 }
 
-func generateLimitReq(zoneName string, rateLimitPol *conf_v1.RateLimit) version2.LimitReq {
+func generateLimitReq(zoneName string, limit conf_v1.RateLimitTier) version2.LimitReq {
 	var limitReq version2.LimitReq
 
 	limitReq.ZoneName = zoneName
 
-	if rateLimitPol.Burst != nil {
-		limitReq.Burst = *rateLimitPol.Burst
+	if limit.Burst != nil {
+		limitReq.Burst = *limit.Burst
 	}
-	if rateLimitPol.Delay != nil {
-		limitReq.Delay = *rateLimitPol.Delay
+	if limit.Delay != nil {
+		limitReq.Delay = *limit.Delay
 	}
 
-	limitReq.NoDelay = generateBool(rateLimitPol.NoDelay, false)
+	limitReq.NoDelay = generateBool(limit.NoDelay, false)
 	if limitReq.NoDelay {
 		limitReq.Delay = 0
 	}
@@ -1076,12 +2328,39 @@ func generateLimitReq(zoneName string, rateLimitPol *conf_v1.RateLimit) version2
 	return limitReq
 }
 
-func generateLimitReqZone(zoneName string, rateLimitPol *conf_v1.RateLimit) version2.LimitReqZone {
+func generateLimitReqZone(zoneName string, key string, limit conf_v1.RateLimitTier) version2.LimitReqZone {
 	return version2.LimitReqZone{
 		ZoneName: zoneName,
-		Key:      rateLimitPol.Key,
-		ZoneSize: rateLimitPol.ZoneSize,
-		Rate:     rateLimitPol.Rate,
+		Key:      key,
+		ZoneSize: limit.ZoneSize,
+		Rate:     limit.Rate,
+	}
+}
+
+// generateRateLimitKeyMap generates a map that evaluates to the key of a rate limit for the requests that match
+// the condition and to an empty string for the rest of the requests, which NGINX doesn't account then.
+func generateRateLimitKeyMap(zoneName string, key string, condition conf_v1.Condition) version2.Map {
+	value, isNegative := generateValueForMatchesRouteMap(condition.Value)
+
+	valueResult := fmt.Sprintf(`"%s"`, key)
+	defaultResult := `""`
+	if isNegative {
+		valueResult, defaultResult = defaultResult, valueResult
+	}
+
+	return version2.Map{
+		Source:   getNameForSourceForMatchesRouteMapFromCondition(condition),
+		Variable: fmt.Sprintf("$%v_key", strings.NewReplacer("-", "_", ".", "_").Replace(zoneName)),
+		Parameters: []version2.Parameter{
+			{
+				Value:  value,
+				Result: valueResult,
+			},
+			{
+				Value:  "default",
+				Result: defaultResult,
+			},
+		},
 	}
 }
 
@@ -1093,11 +2372,146 @@ func generateLimitReqOptions(rateLimitPol *conf_v1.RateLimit) version2.LimitReqO
 	}
 }
 
-func removeDuplicateLimitReqZones(rlz []version2.LimitReqZone) []version2.LimitReqZone {
+// generateCORSMapsAndPreflightLocations generates the maps and the preflight locations of the CORS policies.
+// A policy referenced by multiple routes gets a single map and preflight location.
+func generateCORSMapsAndPreflightLocations(cfgs []corsCfg) ([]version2.Map, []version2.CORSPreflightLocation) {
+	var maps []version2.Map
+	var locations []version2.CORSPreflightLocation
+
+	encountered := make(map[string]bool)
+
+	for _, cfg := range cfgs {
+		if encountered[cfg.Map.Variable] {
+			continue
+		}
+		encountered[cfg.Map.Variable] = true
+
+		maps = append(maps, cfg.Map)
+		locations = append(locations, cfg.PreflightLocation)
+	}
+
+	return maps, locations
+}
+
+// generateIPAccessControlGeosAndMaps generates the geos and the maps of the ipAccessControl policies.
+// The same policy can be referenced by multiple routes, so they are deduplicated.
+func generateIPAccessControlGeosAndMaps(cfgs []ipAccessControlCfg) ([]version2.Geo, []version2.Map) {
+	var geos []version2.Geo
+	var maps []version2.Map
+
+	encountered := make(map[string]bool)
+
+	for _, cfg := range cfgs {
+		if encountered[cfg.Location.DenyVariable] {
+			continue
+		}
+		encountered[cfg.Location.DenyVariable] = true
+
+		if cfg.Geo != nil {
+			geos = append(geos, *cfg.Geo)
+		}
+		maps = append(maps, cfg.Maps...)
+	}
+
+	return geos, maps
+}
+
+// generateJWTClaimSetsMapsAndJWKSLocations generates the claim sets, the maps, the JWKS locations and the JWKS cache zones
+// of the JWT policies. The same policy can be referenced by multiple routes, so they are deduplicated.
+func generateJWTClaimSetsMapsAndJWKSLocations(cfgs []jwtAuthCfg) ([]version2.JWTClaimSet, []version2.Map, []version2.JWKSLocation, []version2.CacheZone) {
+	var claimSets []version2.JWTClaimSet
+	var maps []version2.Map
+	var locations []version2.JWKSLocation
+	var zones []version2.CacheZone
+
+	encountered := make(map[string]bool)
+
+	for _, cfg := range cfgs {
+		for _, c := range cfg.ClaimSets {
+			if !encountered[c.Variable] {
+				encountered[c.Variable] = true
+				claimSets = append(claimSets, c)
+			}
+		}
+		for _, m := range cfg.Maps {
+			if !encountered[m.Variable] {
+				encountered[m.Variable] = true
+				maps = append(maps, m)
+			}
+		}
+		if cfg.JWKSLocation != nil && !encountered[cfg.JWKSLocation.Path] {
+			encountered[cfg.JWKSLocation.Path] = true
+			locations = append(locations, *cfg.JWKSLocation)
+			if cfg.CacheZone != nil {
+				zones = append(zones, *cfg.CacheZone)
+			}
+		}
+	}
+
+	return claimSets, maps, locations, zones
+}
+
+// generateExternalAuthLocationsAndCacheZones generates the auth locations and the cache zones of the external auth policies.
+// The same policy can be referenced by multiple routes, so the locations and the zones are deduplicated.
+func generateExternalAuthLocationsAndCacheZones(cfgs []externalAuthCfg) ([]version2.ExternalAuthLocation, []version2.CacheZone) {
+	var locations []version2.ExternalAuthLocation
+	var zones []version2.CacheZone
+
+	encountered := make(map[string]bool)
+
+	for _, cfg := range cfgs {
+		if encountered[cfg.AuthLocation.Path] {
+			continue
+		}
+		encountered[cfg.AuthLocation.Path] = true
+
+		locations = append(locations, cfg.AuthLocation)
+		if cfg.CacheZone != nil {
+			zones = append(zones, *cfg.CacheZone)
+		}
+	}
+
+	return locations, zones
+}
+
+// generateCacheZones generates the cache zones of the cache policies.
+// The same policy can be referenced by multiple routes, so the zones are deduplicated.
+func generateCacheZones(cfgs []cacheCfg) []version2.CacheZone {
+	var zones []version2.CacheZone
+
+	encountered := make(map[string]bool)
+
+	for _, cfg := range cfgs {
+		if encountered[cfg.Zone.Name] {
+			continue
+		}
+		encountered[cfg.Zone.Name] = true
+
+		zones = append(zones, cfg.Zone)
+	}
+
+	return zones
+}
+
+func removeDuplicateLimitReqZones(rlz []version2.LimitReqZone) []version2.LimitReqZone {
+	encountered := make(map[string]bool)
+	result := []version2.LimitReqZone{}
+
+	for _, v := range rlz {
+		if !encountered[v.ZoneName] {
+			encountered[v.ZoneName] = true
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+func removeDuplicateLimitConnZones(lcz []version2.LimitConnZone) []version2.LimitConnZone {
 	encountered := make(map[string]bool)
-	result := []version2.LimitReqZone{}
+	var result []version2.LimitConnZone
 
-	for _, v := range rlz {
+	for _, v := range lcz {
 		if !encountered[v.ZoneName] {
 			encountered[v.ZoneName] = true
 			result = append(result, v)
@@ -1107,16 +2521,92 @@ func removeDuplicateLimitReqZones(rlz []version2.LimitReqZone) []version2.LimitR
 	return result
 }
 
+func removeDuplicateMaps(maps []version2.Map) []version2.Map {
+	encountered := make(map[string]bool)
+	var result []version2.Map
+
+	for _, m := range maps {
+		if !encountered[m.Variable] {
+			encountered[m.Variable] = true
+			result = append(result, m)
+		}
+	}
+
+	return result
+}
+
 func addPoliciesCfgToLocation(cfg policiesCfg, location *version2.Location) {
 	location.Allow = cfg.Allow
 	location.Deny = cfg.Deny
 	location.LimitReqOptions = cfg.LimitReqOptions
 	location.LimitReqs = cfg.LimitReqs
-	location.JWTAuth = cfg.JWTAuth
+	location.LimitConns = cfg.LimitConns
+	location.JWTAuth = nil
+	if cfg.JWTAuth != nil {
+		location.JWTAuth = &cfg.JWTAuth.Location
+	}
+	location.BasicAuth = cfg.BasicAuth
 	location.EgressMTLS = cfg.EgressMTLS
 	location.OIDC = cfg.OIDC
 	location.WAF = cfg.WAF
 	location.PoliciesErrorReturn = cfg.ErrorReturn
+
+	if cfg.Retry != nil {
+		addRetryCfgToLocation(cfg.Retry, location)
+	}
+
+	location.CORS = nil
+	if cfg.CORS != nil {
+		location.CORS = &cfg.CORS.Location
+	}
+
+	location.ExternalAuth = nil
+	if cfg.ExternalAuth != nil {
+		location.ExternalAuth = &cfg.ExternalAuth.Location
+	}
+
+	location.Cache = nil
+	if cfg.Cache != nil {
+		location.Cache = &cfg.Cache.Location
+	}
+
+	location.Compression = cfg.Compression
+
+	location.IPAccessControl = nil
+	if cfg.IPAccessControl != nil {
+		location.IPAccessControl = &cfg.IPAccessControl.Location
+	}
+
+	if cfg.RequestLimits != nil {
+		addRequestLimitsCfgToLocation(cfg.RequestLimits, location)
+	}
+}
+
+// addRequestLimitsCfgToLocation overrides the request body settings of the location.
+func addRequestLimitsCfgToLocation(cfg *requestLimitsCfg, location *version2.Location) {
+	if cfg.ClientMaxBodySize != "" {
+		location.ClientMaxBodySize = cfg.ClientMaxBodySize
+	}
+	location.ClientBodyBufferSize = cfg.ClientBodyBufferSize
+	location.ProxyRequestBufferingOff = cfg.RequestBufferingOff
+}
+
+// addRetryCfgToLocation overrides the next upstream settings and, if the per-try timeout is set,
+// the proxy timeouts of the location.
+func addRetryCfgToLocation(cfg *retryCfg, location *version2.Location) {
+	location.ProxyNextUpstream = cfg.NextUpstream
+	if cfg.NextUpstreamTries != nil {
+		location.ProxyNextUpstreamTries = *cfg.NextUpstreamTries
+	}
+	if cfg.NextUpstreamTimeout != "" {
+		location.ProxyNextUpstreamTimeout = generateTime(cfg.NextUpstreamTimeout)
+	}
+	if cfg.PerTryTimeout != "" {
+		perTryTimeout := generateTime(cfg.PerTryTimeout)
+		location.ProxyConnectTimeout = perTryTimeout
+		location.ProxyReadTimeout = perTryTimeout
+		location.ProxySendTimeout = perTryTimeout
+	}
 }
 
 func addPoliciesCfgToLocations(cfg policiesCfg, locations []version2.Location) {
@@ -1152,11 +2642,21 @@ func (vsc *virtualServerConfigurator) generateUpstream(
 	upstream conf_v1.Upstream,
 	isExternalNameSvc bool,
 	endpoints []string,
+	weights map[string]int,
+	draining map[string]bool,
 ) version2.Upstream {
 	var upsServers []version2.UpstreamServer
 	for _, e := range endpoints {
 		s := version2.UpstreamServer{
 			Address: e,
+			Weight:  weights[e],
+		}
+		if draining[e] {
+			if vsc.isPlus {
+				s.Drain = true
+			} else {
+				s.Backup = true
+			}
 		}
 		upsServers = append(upsServers, s)
 	}
@@ -1166,11 +2666,13 @@ func (vsc *virtualServerConfigurator) generateUpstream(
 	upstreamLabels := getUpstreamResourceLabels(owner)
 	upstreamLabels.Service = upstream.Service
 
+	resolve := isExternalNameSvc || upstream.Host != ""
+
 	ups := version2.Upstream{
 		Name:             upstreamName,
 		UpstreamLabels:   upstreamLabels,
 		Servers:          upsServers,
-		Resolve:          isExternalNameSvc,
+		Resolve:          resolve,
 		LBMethod:         lbMethod,
 		Keepalive:        generateIntFromPointer(upstream.Keepalive, vsc.cfgParams.Keepalive),
 		MaxFails:         generateIntFromPointer(upstream.MaxFails, vsc.cfgParams.MaxFails),
@@ -1183,11 +2685,30 @@ func (vsc *virtualServerConfigurator) generateUpstream(
 		ups.SlowStart = vsc.generateSlowStartForPlus(owner, upstream, lbMethod)
 		ups.Queue = generateQueueForPlus(upstream.Queue, "60s")
 		ups.SessionCookie = generateSessionCookie(upstream.SessionCookie)
+		if resolve {
+			ups.Resolver = generateUpstreamResolver(upstream.Resolver, vsc.cfgParams)
+		}
 	}
 
 	return ups
 }
 
+// generateUpstreamResolver generates the resolver of an upstream with the resolver settings. The addresses and the
+// settings that the upstream doesn't override come from the ConfigMap. It returns nil if the upstream doesn't have
+// the resolver settings, so that the resolver of the http context is used.
+func generateUpstreamResolver(resolver *conf_v1.UpstreamResolver, cfgParams *ConfigParams) *version2.UpstreamResolver {
+	if resolver == nil || len(cfgParams.ResolverAddresses) == 0 {
+		return nil
+	}
+
+	return &version2.UpstreamResolver{
+		Addresses: cfgParams.ResolverAddresses,
+		Valid:     generateTimeWithDefault(resolver.Valid, cfgParams.ResolverValid),
+		IPv6:      generateBool(resolver.IPv6, cfgParams.ResolverIPV6),
+		Timeout:   generateTimeWithDefault(resolver.Timeout, cfgParams.ResolverTimeout),
+	}
+}
+
 func (vsc *virtualServerConfigurator) generateSlowStartForPlus(
 	owner runtime.Object,
 	upstream conf_v1.Upstream,
@@ -1267,6 +2788,20 @@ func generateHealthCheck(
 		hc.Match = generateStatusMatchName(upstreamName)
 	}
 
+	if upstream.Type == grpcUpstreamType {
+		tlsEnabled := upstream.TLS.Enable
+		if upstream.HealthCheck.TLS != nil {
+			tlsEnabled = upstream.HealthCheck.TLS.Enable
+		}
+
+		// gRPC health checks use the grpc.health.v1 protocol, so they don't send requests to a URI.
+		hc.URI = ""
+		hc.ProxyPass = ""
+		hc.GRPCPass = generateGRPCPass(tlsEnabled, upstreamName)
+		hc.GRPCStatus = upstream.HealthCheck.GRPCStatus
+		hc.GRPCService = upstream.HealthCheck.GRPCService
+	}
+
 	return hc
 }
 
@@ -1380,6 +2915,33 @@ func generateProxyPass(tlsEnabled bool, upstreamName string, internal bool, prox
 	return proxyPass
 }
 
+func generateGRPCPass(tlsEnabled bool, upstreamName string) string {
+	if tlsEnabled {
+		return fmt.Sprintf("grpcs://%v", upstreamName)
+	}
+	return fmt.Sprintf("grpc://%v", upstreamName)
+}
+
+// generateGRPCRewrites generates the rewrites for a location of a grpc upstream. grpc_pass doesn't accept a URI,
+// so unlike for proxy_pass, the URI of the request must be always rewritten in the location.
+func generateGRPCRewrites(path string, proxy *conf_v1.ActionProxy, internal bool, originalPath string) []string {
+	if proxy == nil || proxy.RewritePath == "" {
+		if internal {
+			// For internal locations (splits locations) only, recover the original request_uri.
+			return []string{"^ $request_uri break"}
+		}
+		return nil
+	}
+
+	if internal || strings.HasPrefix(path, "~") {
+		return generateRewrites(path, proxy, internal, originalPath)
+	}
+
+	trimmedPath := strings.TrimSpace(strings.TrimPrefix(path, "="))
+
+	return []string{fmt.Sprintf(`"^%v(.*)$" "%v$1" break`, trimmedPath, proxy.RewritePath)}
+}
+
 func generateProxyPassProtocol(enableTLS bool) string {
 	if enableTLS {
 		return "https"
@@ -1556,13 +3118,18 @@ func generateProxyAddHeaders(proxy *conf_v1.ActionProxy) []version2.AddHeader {
 func generateLocationForProxying(path string, upstreamName string, upstream conf_v1.Upstream,
 	cfgParams *ConfigParams, errorPages []conf_v1.ErrorPage, internal bool, errPageIndex int,
 	proxySSLName string, proxy *conf_v1.ActionProxy, originalPath string, locationSnippets []string, isVSR bool, vsrName string, vsrNamespace string) version2.Location {
+	if upstream.Type == grpcUpstreamType {
+		return generateLocationForGRPC(path, upstreamName, upstream, cfgParams, errorPages, internal, errPageIndex,
+			proxySSLName, proxy, originalPath, locationSnippets, isVSR, vsrName, vsrNamespace)
+	}
+
 	return version2.Location{
 		Path:                     generatePath(path),
 		Internal:                 internal,
 		Snippets:                 locationSnippets,
 		ProxyConnectTimeout:      generateTimeWithDefault(upstream.ProxyConnectTimeout, cfgParams.ProxyConnectTimeout),
-		ProxyReadTimeout:         generateTimeWithDefault(upstream.ProxyReadTimeout, cfgParams.ProxyReadTimeout),
-		ProxySendTimeout:         generateTimeWithDefault(upstream.ProxySendTimeout, cfgParams.ProxySendTimeout),
+		ProxyReadTimeout:         generateReadOrSendTimeout(upstream.WebSocket, upstream.ProxyReadTimeout, cfgParams.ProxyReadTimeout),
+		ProxySendTimeout:         generateReadOrSendTimeout(upstream.WebSocket, upstream.ProxySendTimeout, cfgParams.ProxySendTimeout),
 		ClientMaxBodySize:        generateString(upstream.ClientMaxBodySize, cfgParams.ClientMaxBodySize),
 		ProxyMaxTempFileSize:     cfgParams.ProxyMaxTempFileSize,
 		ProxyBuffering:           generateBool(upstream.ProxyBuffering, cfgParams.ProxyBuffering),
@@ -1584,6 +3151,44 @@ func generateLocationForProxying(path string, upstreamName string, upstream conf
 		HasKeepalive:             upstreamHasKeepalive(upstream, cfgParams),
 		ErrorPages:               generateErrorPages(errPageIndex, errorPages),
 		ProxySSLName:             proxySSLName,
+		ProxySSLServerName:       generateProxySSLServerName(upstream),
+		UpstreamErrorReturn:      generateUpstreamErrorReturn(upstream, cfgParams),
+		ServiceName:              upstream.Service,
+		IsVSR:                    isVSR,
+		VSRName:                  vsrName,
+		VSRNamespace:             vsrNamespace,
+	}
+}
+
+// generateLocationForGRPC generates a location that passes the requests to a grpc upstream. The buffering settings of
+// the upstream are not supported by grpc_pass, so they are not generated.
+func generateLocationForGRPC(path string, upstreamName string, upstream conf_v1.Upstream,
+	cfgParams *ConfigParams, errorPages []conf_v1.ErrorPage, internal bool, errPageIndex int,
+	proxySSLName string, proxy *conf_v1.ActionProxy, originalPath string, locationSnippets []string, isVSR bool, vsrName string, vsrNamespace string) version2.Location {
+	return version2.Location{
+		Path:                     generatePath(path),
+		Internal:                 internal,
+		Snippets:                 locationSnippets,
+		ProxyConnectTimeout:      generateTimeWithDefault(upstream.ProxyConnectTimeout, cfgParams.ProxyConnectTimeout),
+		ProxyReadTimeout:         generateTimeWithDefault(upstream.ProxyReadTimeout, cfgParams.ProxyReadTimeout),
+		ProxySendTimeout:         generateTimeWithDefault(upstream.ProxySendTimeout, cfgParams.ProxySendTimeout),
+		ClientMaxBodySize:        generateString(upstream.ClientMaxBodySize, cfgParams.ClientMaxBodySize),
+		GRPCPass:                 generateGRPCPass(upstream.TLS.Enable, upstreamName),
+		ProxyNextUpstream:        generateString(upstream.ProxyNextUpstream, "error timeout"),
+		ProxyNextUpstreamTimeout: generateTimeWithDefault(upstream.ProxyNextUpstreamTimeout, "0s"),
+		ProxyNextUpstreamTries:   upstream.ProxyNextUpstreamTries,
+		ProxyInterceptErrors:     generateProxyInterceptErrors(errorPages),
+		ProxySetHeaders:          generateProxySetHeaders(proxy),
+		ProxyHideHeaders:         generateProxyHideHeaders(proxy),
+		ProxyPassHeaders:         generateProxyPassHeaders(proxy),
+		ProxyIgnoreHeaders:       generateProxyIgnoreHeaders(proxy),
+		AddHeaders:               generateProxyAddHeaders(proxy),
+		Rewrites:                 generateGRPCRewrites(path, proxy, internal, originalPath),
+		HasKeepalive:             upstreamHasKeepalive(upstream, cfgParams),
+		ErrorPages:               append(generateErrorPages(errPageIndex, errorPages), generateGRPCErrorPages(errorPages)...),
+		ProxySSLName:             proxySSLName,
+		ProxySSLServerName:       generateProxySSLServerName(upstream),
+		UpstreamErrorReturn:      generateUpstreamErrorReturn(upstream, cfgParams),
 		ServiceName:              upstream.Service,
 		IsVSR:                    isVSR,
 		VSRName:                  vsrName,
@@ -1591,6 +3196,28 @@ func generateLocationForProxying(path string, upstreamName string, upstream conf
 	}
 }
 
+// generateUpstreamErrorReturn generates the error return of the locations that pass the requests to an invalid upstream:
+// an upstream with a host, which NGINX can't resolve without a resolver.
+func generateUpstreamErrorReturn(upstream conf_v1.Upstream, cfgParams *ConfigParams) *version2.Return {
+	if upstream.Host != "" && len(cfgParams.ResolverAddresses) == 0 {
+		return &version2.Return{Code: 500}
+	}
+
+	return nil
+}
+
+// generateReadOrSendTimeout generates the read or send timeout of a location. NGINX applies these timeouts to the
+// WebSocket connections, so if the WebSocket support of the upstream is enabled, the idle timeout is used instead.
+// The validation ensures that the upstream doesn't set its own timeouts in this case. The timeouts don't support
+// variables, so the idle timeout applies to all requests of the location, not only to the upgraded ones.
+func generateReadOrSendTimeout(ws *conf_v1.UpstreamWebSocket, timeout string, defaultTimeout string) string {
+	if ws != nil && ws.Enable {
+		return generateTimeWithDefault(ws.IdleTimeout, defaultWebSocketIdleTimeout)
+	}
+
+	return generateTimeWithDefault(timeout, defaultTimeout)
+}
+
 func generateProxyInterceptErrors(errorPages []conf_v1.ErrorPage) bool {
 	return len(errorPages) > 0
 }
@@ -1655,6 +3282,86 @@ func generateLocationForReturn(path string, locationSnippets []string, actionRet
 		}
 }
 
+// generateRouteForCanary converts the canary release of the route into an action or splits with the current weight
+// of the canary upstream, and a match for the override.
+func generateRouteForCanary(route conf_v1.Route, canaryWeights map[string]int) conf_v1.Route {
+	canary := route.Canary
+	route.Canary = nil
+
+	weight, exists := canaryWeights[route.Path]
+	if !exists {
+		weight = canary.Steps[0].Weight
+	}
+
+	stableAction := &conf_v1.Action{Pass: canary.Stable}
+	canaryAction := &conf_v1.Action{Pass: canary.Canary}
+
+	switch weight {
+	case 0:
+		route.Action = stableAction
+	case 100:
+		route.Action = canaryAction
+	default:
+		route.Splits = []conf_v1.Split{
+			{
+				Weight: 100 - weight,
+				Action: stableAction,
+			},
+			{
+				Weight: weight,
+				Action: canaryAction,
+			},
+		}
+	}
+
+	if canary.Override != nil {
+		route.Matches = []conf_v1.Match{
+			{
+				Conditions: []conf_v1.Condition{
+					{
+						Header: canary.Override.Header,
+						Cookie: canary.Override.Cookie,
+						Value:  canary.Override.Value,
+					},
+				},
+				Action: canaryAction,
+			},
+		}
+	}
+
+	return route
+}
+
+// generateRouteForRollback removes the splits with the rolled back upstreams from the route and gives their weight
+// to the first split. If only the first split remains, it becomes the action of the route.
+func generateRouteForRollback(route conf_v1.Route, rolledBack map[string]bool) conf_v1.Route {
+	splits := []conf_v1.Split{route.Splits[0]}
+
+	for _, s := range route.Splits[1:] {
+		if s.Action != nil && rolledBack[getUpstreamFromAction(s.Action)] {
+			splits[0].Weight += s.Weight
+			continue
+		}
+		splits = append(splits, s)
+	}
+
+	if len(splits) == 1 {
+		route.Action = splits[0].Action
+		route.Splits = nil
+	} else {
+		route.Splits = splits
+	}
+
+	return route
+}
+
+func getUpstreamFromAction(action *conf_v1.Action) string {
+	if action.Proxy != nil && action.Proxy.Upstream != "" {
+		return action.Proxy.Upstream
+	}
+	return action.Pass
+}
+
 type routingCfg struct {
 	Maps                     []version2.Map
 	SplitClients             []version2.SplitClient
@@ -1751,6 +3458,90 @@ func generateDefaultSplitsConfig(
 	}
 }
 
+type mirrorCfg struct {
+	Mirror       *version2.Mirror
+	Locations    []version2.Location
+	SplitClients []version2.SplitClient
+}
+
+// generateMirrorConfig generates the internal locations that proxy the mirrored requests of a route to the mirror upstreams.
+func generateMirrorConfig(
+	mirror *conf_v1.Mirror,
+	upstreamNamer *upstreamNamer,
+	crUpstreams map[string]conf_v1.Upstream,
+	variableNamer *variableNamer,
+	index int,
+	cfgParams *ConfigParams,
+	isVSR bool,
+	vsrName string,
+	vsrNamespace string,
+) mirrorCfg {
+	requestBody := true
+	if mirror.RequestBody != nil {
+		requestBody = *mirror.RequestBody
+	}
+
+	var splitClients []version2.SplitClient
+	var sampleVariable string
+
+	if mirror.Percentage != nil && *mirror.Percentage < 100 {
+		sampleVariable = variableNamer.GetNameForMirrorSampleVariable(index)
+		// the source differs from the one of the splits, so that the sampled requests don't depend on the split
+		splitClients = append(splitClients, version2.SplitClient{
+			Source:   `"${request_id}mirror"`,
+			Variable: sampleVariable,
+			Distributions: []version2.Distribution{
+				{
+					Weight: fmt.Sprintf("%d%%", *mirror.Percentage),
+					Value:  "1",
+				},
+				{
+					Weight: "*",
+					Value:  `""`,
+				},
+			},
+		})
+	}
+
+	var paths []string
+	var locations []version2.Location
+
+	for i, u := range mirror.Upstreams {
+		path := fmt.Sprintf("/%vmirror_%d_%d", internalLocationPrefix, index, i)
+		upstreamName := upstreamNamer.GetNameForUpstream(u)
+		upstream := crUpstreams[upstreamName]
+		proxySSLName := generateProxySSLName(upstream.Service, upstreamNamer.namespace)
+
+		loc := generateLocationForProxying(path, upstreamName, upstream, cfgParams, nil, true, 0, proxySSLName, nil, path,
+			nil, isVSR, vsrName, vsrNamespace)
+		loc.MirrorTarget = &version2.MirrorTarget{
+			SampleVariable: sampleVariable,
+			RequestBody:    requestBody,
+		}
+
+		paths = append(paths, path)
+		locations = append(locations, loc)
+	}
+
+	return mirrorCfg{
+		Mirror: &version2.Mirror{
+			Paths:       paths,
+			RequestBody: requestBody,
+		},
+		Locations:    locations,
+		SplitClients: splitClients,
+	}
+}
+
+// addMirrorCfgToLocations adds the mirror to the locations that proxy requests.
+func addMirrorCfgToLocations(mirror *version2.Mirror, locations []version2.Location) {
+	for i := range locations {
+		if locations[i].ProxyPass != "" {
+			locations[i].Mirror = mirror
+		}
+	}
+}
+
 func generateMatchesConfig(route conf_v1.Route, upstreamNamer *upstreamNamer, crUpstreams map[string]conf_v1.Upstream,
 	variableNamer *variableNamer, index int, scIndex int, cfgParams *ConfigParams, errorPages []conf_v1.ErrorPage,
 	errPageIndex int, locSnippets string, enableSnippets bool, retLocIndex int, isVSR bool, vsrName string, vsrNamespace string) routingCfg {
@@ -2007,8 +3798,12 @@ func (vsc *virtualServerConfigurator) generateSSLConfig(owner runtime.Object, tl
 		name = secretRef.Path
 	}
 
+	http3 := vsc.generateHTTP3(owner, tls, cfgParams)
+
 	ssl := version2.SSL{
 		HTTP2:           cfgParams.HTTP2,
+		HTTP3:           http3,
+		AltSvc:          generateAltSvc(http3, []int{virtualServerSSLPort}),
 		Certificate:     name,
 		CertificateKey:  name,
 		RejectHandshake: rejectHandshake,
@@ -2017,6 +3812,36 @@ func (vsc *virtualServerConfigurator) generateSSLConfig(owner runtime.Object, tl
 	return &ssl
 }
 
+// generateHTTP3 checks if HTTP/3 is enabled for the TLS termination. The http3 field of the TLS overrides
+// the http3 ConfigMap key.
+func (vsc *virtualServerConfigurator) generateHTTP3(owner runtime.Object, tls *conf_v1.TLS, cfgParams *ConfigParams) bool {
+	if tls.HTTP3 == nil {
+		return cfgParams.HTTP3 && vsc.http3Supported
+	}
+
+	if *tls.HTTP3 && !vsc.http3Supported {
+		vsc.addWarningf(owner, "HTTP/3 will be disabled, because NGINX doesn't support it")
+		return false
+	}
+
+	return *tls.HTTP3
+}
+
+// generateAltSvc generates the value of the Alt-Svc header, which advertises the HTTP/3 listeners on the SSL ports.
+// The header is empty if HTTP/3 is disabled.
+func generateAltSvc(http3 bool, sslPorts []int) string {
+	if !http3 {
+		return ""
+	}
+
+	services := make([]string, 0, len(sslPorts))
+	for _, port := range sslPorts {
+		services = append(services, fmt.Sprintf(`h3=":%d"; ma=86400`, port))
+	}
+
+	return strings.Join(services, ", ")
+}
+
 func generateTLSRedirectConfig(tls *conf_v1.TLS) *version2.TLSRedirect {
 	if tls == nil || tls.Redirect == nil || !tls.Redirect.Enable {
 		return nil
@@ -2059,6 +3884,11 @@ func createUpstreamsForPlus(
 	vsc := newVirtualServerConfigurator(baseCfgParams, isPlus, false, staticParams)
 
 	for _, u := range virtualServerEx.VirtualServer.Spec.Upstreams {
+		if u.Host != "" {
+			glog.V(3).Infof("Upstream %s has a host, skipping NGINX Plus endpoints update via API", u.Name)
+			continue
+		}
+
 		isExternalNameSvc := virtualServerEx.ExternalNameSvcs[GenerateExternalNameSvcKey(virtualServerEx.VirtualServer.Namespace, u.Service)]
 		if isExternalNameSvc {
 			glog.V(3).Infof("Service %s is Type ExternalName, skipping NGINX Plus endpoints update via API", u.Service)
@@ -2068,16 +3898,20 @@ func createUpstreamsForPlus(
 		upstreamName := upstreamNamer.GetNameForUpstream(u.Name)
 		upstreamNamespace := virtualServerEx.VirtualServer.Namespace
 
-		endpointsKey := GenerateEndpointsKey(upstreamNamespace, u.Service, u.Subselector, u.Port)
-		endpoints := virtualServerEx.Endpoints[endpointsKey]
+		endpoints, weights, draining := getEndpointsForUpstreamSubsets(upstreamNamespace, u, virtualServerEx.Endpoints, virtualServerEx.DrainingEndpoints)
 
-		ups := vsc.generateUpstream(virtualServerEx.VirtualServer, upstreamName, u, isExternalNameSvc, endpoints)
+		ups := vsc.generateUpstream(virtualServerEx.VirtualServer, upstreamName, u, isExternalNameSvc, endpoints, weights, draining)
 		upstreams = append(upstreams, ups)
 	}
 
 	for _, vsr := range virtualServerEx.VirtualServerRoutes {
 		upstreamNamer = newUpstreamNamerForVirtualServerRoute(virtualServerEx.VirtualServer, vsr)
 		for _, u := range vsr.Spec.Upstreams {
+			if u.Host != "" {
+				glog.V(3).Infof("Upstream %s has a host, skipping NGINX Plus endpoints update via API", u.Name)
+				continue
+			}
+
 			isExternalNameSvc := virtualServerEx.ExternalNameSvcs[GenerateExternalNameSvcKey(vsr.Namespace, u.Service)]
 			if isExternalNameSvc {
 				glog.V(
@@ -2092,14 +3926,25 @@ func createUpstreamsForPlus(
 			upstreamName := upstreamNamer.GetNameForUpstream(u.Name)
 			upstreamNamespace := vsr.Namespace
 
-			endpointsKey := GenerateEndpointsKey(upstreamNamespace, u.Service, u.Subselector, u.Port)
-			endpoints := virtualServerEx.Endpoints[endpointsKey]
+			endpoints, weights, draining := getEndpointsForUpstreamSubsets(upstreamNamespace, u, virtualServerEx.Endpoints, virtualServerEx.DrainingEndpoints)
 
-			ups := vsc.generateUpstream(vsr, upstreamName, u, isExternalNameSvc, endpoints)
+			ups := vsc.generateUpstream(vsr, upstreamName, u, isExternalNameSvc, endpoints, weights, draining)
 			upstreams = append(upstreams, ups)
 		}
 	}
 
+	for _, key := range getExternalAuthPolicyKeys(virtualServerEx.Policies) {
+		pol := virtualServerEx.Policies[key]
+		u := generateExternalAuthUpstream(pol.Spec.ExternalAuth)
+		upstreamName := getNameForExternalAuthUpstream(virtualServerEx.VirtualServer.Namespace, virtualServerEx.VirtualServer.Name, pol.Namespace, pol.Name)
+
+		endpointsKey := GenerateEndpointsKey(pol.Namespace, u.Service, nil, u.Port)
+		endpoints := virtualServerEx.Endpoints[endpointsKey]
+
+		ups := vsc.generateUpstream(virtualServerEx.VirtualServer, upstreamName, u, false, endpoints, nil, nil)
+		upstreams = append(upstreams, ups)
+	}
+
 	return upstreams
 }
 
@@ -2107,11 +3952,31 @@ func createUpstreamServersConfigForPlus(upstream version2.Upstream) nginx.Server
 	if len(upstream.Servers) == 0 {
 		return nginx.ServerConfig{}
 	}
+
+	var weights map[string]int
+	var draining map[string]bool
+	for _, s := range upstream.Servers {
+		if s.Weight > 0 {
+			if weights == nil {
+				weights = make(map[string]int)
+			}
+			weights[s.Address] = s.Weight
+		}
+		if s.Drain {
+			if draining == nil {
+				draining = make(map[string]bool)
+			}
+			draining[s.Address] = true
+		}
+	}
+
 	return nginx.ServerConfig{
 		MaxFails:    upstream.MaxFails,
 		FailTimeout: upstream.FailTimeout,
 		MaxConns:    upstream.MaxConns,
 		SlowStart:   upstream.SlowStart,
+		Weights:     weights,
+		Draining:    draining,
 	}
 }
 
@@ -2168,6 +4033,93 @@ func generateErrorPages(errPageIndex int, errorPages []conf_v1.ErrorPage) []vers
 	return ePages
 }
 
+// grpcErrorPage defines the gRPC status of the errors with the HTTP status codes, so that NGINX responds to gRPC
+// clients with the errors they understand.
+type grpcErrorPage struct {
+	name    string
+	codes   []int
+	status  int
+	message string
+}
+
+// grpcErrorPages are based on the mapping of the HTTP status codes to the gRPC status codes of the gRPC specification.
+var grpcErrorPages = []grpcErrorPage{
+	{name: "@grpc_deadline_exceeded", codes: []int{408}, status: 4, message: "deadline exceeded"},
+	{name: "@grpc_permission_denied", codes: []int{403}, status: 7, message: "permission denied"},
+	{name: "@grpc_resource_exhausted", codes: []int{413, 414}, status: 8, message: "resource exhausted"},
+	{name: "@grpc_unimplemented", codes: []int{404}, status: 12, message: "unimplemented"},
+	{name: "@grpc_internal", codes: []int{400, 405, 415, 426, 497, 500, 501}, status: 13, message: "internal error"},
+	{name: "@grpc_unavailable", codes: []int{429, 502, 503, 504}, status: 14, message: "unavailable"},
+	{name: "@grpc_unauthenticated", codes: []int{401, 495, 496}, status: 16, message: "unauthenticated"},
+}
+
+// generateGRPCErrorPages generates the error pages of a location of a grpc upstream for the codes that are not used
+// in the error pages of the route.
+func generateGRPCErrorPages(errorPages []conf_v1.ErrorPage) []version2.ErrorPage {
+	usedCodes := make(map[int]bool)
+	for _, e := range errorPages {
+		for _, c := range e.Codes {
+			usedCodes[c] = true
+		}
+	}
+
+	var ePages []version2.ErrorPage
+
+	for _, e := range grpcErrorPages {
+		var codes []int
+		for _, c := range e.codes {
+			if !usedCodes[c] {
+				codes = append(codes, c)
+			}
+		}
+
+		if len(codes) == 0 {
+			continue
+		}
+
+		ePages = append(ePages, version2.ErrorPage{
+			Name:         e.name,
+			Codes:        generateErrorPageCodes(codes),
+			ResponseCode: 204,
+		})
+	}
+
+	return ePages
+}
+
+func generateGRPCErrorPageLocations() []version2.ErrorPageLocation {
+	var errorPageLocations []version2.ErrorPageLocation
+
+	for _, e := range grpcErrorPages {
+		errorPageLocations = append(errorPageLocations, version2.ErrorPageLocation{
+			Name:        e.name,
+			DefaultType: "application/grpc",
+			Return:      &version2.Return{},
+			Headers: []version2.Header{
+				{
+					Name:  "grpc-status",
+					Value: strconv.Itoa(e.status),
+				},
+				{
+					Name:  "grpc-message",
+					Value: e.message,
+				},
+			},
+		})
+	}
+
+	return errorPageLocations
+}
+
+func hasGRPCLocations(locations []version2.Location) bool {
+	for _, l := range locations {
+		if l.GRPCPass != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func generateErrorPageLocations(errPageIndex int, errorPages []conf_v1.ErrorPage) []version2.ErrorPageLocation {
 	var errorPageLocations []version2.ErrorPageLocation
 	for i, e := range errorPages {
@@ -2207,6 +4159,16 @@ func generateProxySSLName(svcName, ns string) string {
 	return fmt.Sprintf("%s.%s.svc", svcName, ns)
 }
 
+// generateProxySSLServerName returns the host of the upstream for the SNI of the TLS connections to the upstream.
+// External hosts usually require SNI, unlike the services in the cluster.
+func generateProxySSLServerName(upstream conf_v1.Upstream) string {
+	if upstream.Host == "" || !upstream.TLS.Enable {
+		return ""
+	}
+
+	return upstream.Host
+}
+
 func isTLSEnabled(u conf_v1.Upstream, spiffeCerts bool) bool {
 	return u.TLS.Enable || spiffeCerts
 }
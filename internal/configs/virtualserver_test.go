@@ -20,6 +20,10 @@ func createPointerFromBool(b bool) *bool {
 	return &b
 }
 
+func createPointerFromInt(n int) *int {
+	return &n
+}
+
 func TestVirtualServerExString(t *testing.T) {
 	tests := []struct {
 		input    *VirtualServerEx
@@ -82,6 +86,161 @@ func TestGenerateEndpointsKey(t *testing.T) {
 	}
 }
 
+func TestGetUpstreamEndpointsSubsets(t *testing.T) {
+	tests := []struct {
+		upstream conf_v1.Upstream
+		expected []UpstreamEndpointsSubset
+		msg      string
+	}{
+		{
+			upstream: conf_v1.Upstream{
+				Service: "test",
+				Port:    80,
+			},
+			expected: []UpstreamEndpointsSubset{
+				{
+					EndpointsKey: "default/test:80",
+				},
+			},
+			msg: "no subsets",
+		},
+		{
+			upstream: conf_v1.Upstream{
+				Service:     "test",
+				Port:        80,
+				Subselector: map[string]string{"app": "test"},
+				Subsets: []conf_v1.UpstreamSubset{
+					{
+						Subselector: map[string]string{"version": "v1"},
+						Weight:      9,
+					},
+					{
+						Subselector: map[string]string{"version": "v2"},
+						Weight:      1,
+					},
+				},
+			},
+			expected: []UpstreamEndpointsSubset{
+				{
+					EndpointsKey: "default/test_app=test,version=v1:80",
+					Subselector:  map[string]string{"app": "test", "version": "v1"},
+					Weight:       9,
+				},
+				{
+					EndpointsKey: "default/test_app=test,version=v2:80",
+					Subselector:  map[string]string{"app": "test", "version": "v2"},
+					Weight:       1,
+				},
+			},
+			msg: "subsets",
+		},
+		{
+			upstream: conf_v1.Upstream{
+				Service: "test",
+				Port:    80,
+				TopologyAwareRouting: &conf_v1.TopologyAwareRouting{
+					Enable: true,
+				},
+			},
+			expected: []UpstreamEndpointsSubset{
+				{
+					EndpointsKey: "default/test:80_zone:50",
+				},
+			},
+			msg: "topology aware routing",
+		},
+		{
+			upstream: conf_v1.Upstream{
+				Service: "test",
+				Port:    80,
+				TopologyAwareRouting: &conf_v1.TopologyAwareRouting{
+					Enable:            false,
+					MinHealthyPercent: createPointerFromInt(70),
+				},
+			},
+			expected: []UpstreamEndpointsSubset{
+				{
+					EndpointsKey: "default/test:80",
+				},
+			},
+			msg: "disabled topology aware routing",
+		},
+	}
+
+	for _, test := range tests {
+		result := GetUpstreamEndpointsSubsets("default", test.upstream)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("GetUpstreamEndpointsSubsets() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
+	}
+}
+
+func TestGetEndpointsForUpstreamSubsets(t *testing.T) {
+	upstream := conf_v1.Upstream{
+		Service: "test",
+		Port:    80,
+		Subsets: []conf_v1.UpstreamSubset{
+			{
+				Subselector: map[string]string{"version": "v1"},
+				Weight:      9,
+			},
+			{
+				Subselector: map[string]string{"track": "canary"},
+				Weight:      1,
+			},
+		},
+	}
+	endpoints := map[string][]string{
+		"default/test_version=v1:80":   {"10.0.0.1:80", "10.0.0.2:80"},
+		"default/test_track=canary:80": {"10.0.0.2:80", "10.0.0.3:80"},
+	}
+
+	expectedEndpoints := []string{"10.0.0.1:80", "10.0.0.2:80", "10.0.0.3:80"}
+	expectedWeights := map[string]int{
+		"10.0.0.1:80": 9,
+		"10.0.0.2:80": 9,
+		"10.0.0.3:80": 1,
+	}
+
+	resultEndpoints, resultWeights, _ := getEndpointsForUpstreamSubsets("default", upstream, endpoints, nil)
+	if !reflect.DeepEqual(resultEndpoints, expectedEndpoints) {
+		t.Errorf("getEndpointsForUpstreamSubsets() returned endpoints %v but expected %v", resultEndpoints, expectedEndpoints)
+	}
+	if !reflect.DeepEqual(resultWeights, expectedWeights) {
+		t.Errorf("getEndpointsForUpstreamSubsets() returned weights %v but expected %v", resultWeights, expectedWeights)
+	}
+}
+
+func TestGetEndpointsForUpstreamSubsetsWithDraining(t *testing.T) {
+	upstream := conf_v1.Upstream{
+		Service:      "test",
+		Port:         80,
+		DrainTimeout: "30s",
+	}
+	endpoints := map[string][]string{
+		"default/test:80": {"10.0.0.1:80"},
+	}
+	drainingEndpoints := map[string][]string{
+		"default/test:80": {"10.0.0.2:80", "10.0.0.1:80"},
+	}
+
+	expectedEndpoints := []string{"10.0.0.1:80", "10.0.0.2:80"}
+	expectedDraining := map[string]bool{
+		"10.0.0.2:80": true,
+	}
+
+	resultEndpoints, resultWeights, resultDraining := getEndpointsForUpstreamSubsets("default", upstream, endpoints, drainingEndpoints)
+	if !reflect.DeepEqual(resultEndpoints, expectedEndpoints) {
+		t.Errorf("getEndpointsForUpstreamSubsets() returned endpoints %v but expected %v", resultEndpoints, expectedEndpoints)
+	}
+	if resultWeights != nil {
+		t.Errorf("getEndpointsForUpstreamSubsets() returned weights %v but expected nil", resultWeights)
+	}
+	if !reflect.DeepEqual(resultDraining, expectedDraining) {
+		t.Errorf("getEndpointsForUpstreamSubsets() returned draining endpoints %v but expected %v", resultDraining, expectedDraining)
+	}
+}
+
 func TestUpstreamNamerForVirtualServer(t *testing.T) {
 	virtualServer := conf_v1.VirtualServer{
 		ObjectMeta: meta_v1.ObjectMeta{
@@ -1869,6 +2028,12 @@ func TestGeneratePolicies(t *testing.T) {
 				},
 				Path: "/etc/nginx/secrets/default-jwt-secret",
 			},
+			"default/htpasswd-secret": {
+				Secret: &api_v1.Secret{
+					Type: secrets.SecretTypeHtpasswd,
+				},
+				Path: "/etc/nginx/secrets/default-htpasswd-secret",
+			},
 			"default/oidc-secret": {
 				Secret: &api_v1.Secret{
 					Type: secrets.SecretTypeOIDC,
@@ -2064,6 +2229,119 @@ func TestGeneratePolicies(t *testing.T) {
 			},
 			msg: "multi rate limit reference",
 		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "rateLimit-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/rateLimit-policy": {
+					Spec: conf_v1.PolicySpec{
+						RateLimit: &conf_v1.RateLimit{
+							Key:      "${binary_remote_addr}",
+							ZoneSize: "10M",
+							Rate:     "10r/s",
+							Condition: &conf_v1.Condition{
+								Header: "X-API-Key",
+								Value:  "",
+							},
+							Limits: []conf_v1.RateLimitTier{
+								{
+									Key:      "${http_x_api_key}",
+									ZoneSize: "10M",
+									Rate:     "100r/s",
+									Burst:    createPointerFromInt(50),
+									Condition: &conf_v1.Condition{
+										Header: "X-API-Key",
+										Value:  "!",
+									},
+								},
+							},
+							LimitConn: &conf_v1.LimitConn{
+								Key:         "${binary_remote_addr}",
+								Connections: 10,
+								ZoneSize:    "10M",
+							},
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				LimitReqZones: []version2.LimitReqZone{
+					{
+						Key:      "$pol_rl_default_rateLimit_policy_default_test_key",
+						ZoneSize: "10M",
+						Rate:     "10r/s",
+						ZoneName: "pol_rl_default_rateLimit-policy_default_test",
+					},
+					{
+						Key:      "$pol_rl_default_rateLimit_policy_default_test_1_key",
+						ZoneSize: "10M",
+						Rate:     "100r/s",
+						ZoneName: "pol_rl_default_rateLimit-policy_default_test_1",
+					},
+				},
+				LimitReqOptions: version2.LimitReqOptions{
+					LogLevel:   "error",
+					RejectCode: 503,
+				},
+				LimitReqs: []version2.LimitReq{
+					{
+						ZoneName: "pol_rl_default_rateLimit-policy_default_test",
+					},
+					{
+						ZoneName: "pol_rl_default_rateLimit-policy_default_test_1",
+						Burst:    50,
+					},
+				},
+				RateLimitMaps: []version2.Map{
+					{
+						Source:   "$http_X_API_Key",
+						Variable: "$pol_rl_default_rateLimit_policy_default_test_key",
+						Parameters: []version2.Parameter{
+							{
+								Value:  `""`,
+								Result: `"${binary_remote_addr}"`,
+							},
+							{
+								Value:  "default",
+								Result: `""`,
+							},
+						},
+					},
+					{
+						Source:   "$http_X_API_Key",
+						Variable: "$pol_rl_default_rateLimit_policy_default_test_1_key",
+						Parameters: []version2.Parameter{
+							{
+								Value:  `""`,
+								Result: `""`,
+							},
+							{
+								Value:  "default",
+								Result: `"${http_x_api_key}"`,
+							},
+						},
+					},
+				},
+				LimitConnZones: []version2.LimitConnZone{
+					{
+						Key:      "${binary_remote_addr}",
+						ZoneName: "pol_lc_default_rateLimit-policy_default_test",
+						ZoneSize: "10M",
+					},
+				},
+				LimitConns: []version2.LimitConn{
+					{
+						ZoneName:    "pol_lc_default_rateLimit-policy_default_test",
+						Connections: 10,
+					},
+				},
+			},
+			msg: "rate limit reference with conditional limits and a connection limit",
+		},
 		{
 			policyRefs: []conf_v1.PolicyReference{
 				{
@@ -2086,13 +2364,159 @@ func TestGeneratePolicies(t *testing.T) {
 				},
 			},
 			expected: policiesCfg{
-				JWTAuth: &version2.JWTAuth{
-					Secret: "/etc/nginx/secrets/default-jwt-secret",
-					Realm:  "My Test API",
+				JWTAuth: &jwtAuthCfg{
+					Location: version2.JWTAuth{
+						Secret: "/etc/nginx/secrets/default-jwt-secret",
+						Realm:  "My Test API",
+					},
 				},
 			},
 			msg: "jwt reference",
 		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "jwt-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/jwt-policy": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "jwt-policy",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						JWTAuth: &conf_v1.JWTAuth{
+							Realm:    "My Test API",
+							JWKSURI:  "https://idp.example.com/jwks",
+							KeyCache: "1h",
+							RequireClaims: []conf_v1.JWTClaimRequirement{
+								{
+									Claim:  "iss",
+									Equals: "https://idp.example.com/",
+								},
+								{
+									Claim:    "realm_access.roles",
+									Contains: "admin",
+								},
+							},
+							ClaimHeaders: []conf_v1.JWTClaimHeader{
+								{
+									Claim:  "sub",
+									Header: "X-User",
+								},
+								{
+									Claim:  "iss",
+									Header: "X-Issuer",
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				JWTAuth: &jwtAuthCfg{
+					Location: version2.JWTAuth{
+						Realm:      "My Test API",
+						KeyRequest: "/internal_location_pol_jwt_default_jwt-policy",
+						KeyCache:   "1h",
+						Require: []string{
+							"$pol_jwt_default_jwt_policy_default_test_require_0",
+							"$pol_jwt_default_jwt_policy_default_test_require_1",
+						},
+						ClaimHeaders: []version2.Header{
+							{
+								Name:  "X-User",
+								Value: "$pol_jwt_default_jwt_policy_default_test_claim_2",
+							},
+							{
+								Name:  "X-Issuer",
+								Value: "$pol_jwt_default_jwt_policy_default_test_claim_0",
+							},
+						},
+					},
+					ClaimSets: []version2.JWTClaimSet{
+						{
+							Variable: "$pol_jwt_default_jwt_policy_default_test_claim_0",
+							Claim:    []string{"iss"},
+						},
+						{
+							Variable: "$pol_jwt_default_jwt_policy_default_test_claim_1",
+							Claim:    []string{"realm_access", "roles"},
+						},
+						{
+							Variable: "$pol_jwt_default_jwt_policy_default_test_claim_2",
+							Claim:    []string{"sub"},
+						},
+					},
+					Maps: []version2.Map{
+						{
+							Source:   "$pol_jwt_default_jwt_policy_default_test_claim_0",
+							Variable: "$pol_jwt_default_jwt_policy_default_test_require_0",
+							Parameters: []version2.Parameter{
+								{
+									Value:  "default",
+									Result: "0",
+								},
+								{
+									Value:  `"~^https://idp\.example\.com/$"`,
+									Result: "1",
+								},
+							},
+						},
+						{
+							Source:   "$pol_jwt_default_jwt_policy_default_test_claim_1",
+							Variable: "$pol_jwt_default_jwt_policy_default_test_require_1",
+							Parameters: []version2.Parameter{
+								{
+									Value:  "default",
+									Result: "0",
+								},
+								{
+									Value:  `"~(^|,)admin(,|$)"`,
+									Result: "1",
+								},
+							},
+						},
+					},
+					JWKSLocation: &version2.JWKSLocation{
+						Path:      "/internal_location_pol_jwt_default_jwt-policy",
+						URI:       "https://idp.example.com/jwks",
+						SSLServer: true,
+					},
+				},
+			},
+			msg: "jwt reference with jwks uri, claim requirements and claim headers",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "basic-auth-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/basic-auth-policy": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "basic-auth-policy",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						BasicAuth: &conf_v1.BasicAuth{
+							Secret: "htpasswd-secret",
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				BasicAuth: &version2.BasicAuth{
+					Secret: "/etc/nginx/secrets/default-htpasswd-secret",
+					Realm:  "Restricted",
+				},
+			},
+			msg: "basic auth reference",
+		},
 		{
 			policyRefs: []conf_v1.PolicyReference{
 				{
@@ -2224,28 +2648,398 @@ func TestGeneratePolicies(t *testing.T) {
 			},
 			msg: "WAF reference",
 		},
-	}
-
-	vsc := newVirtualServerConfigurator(&ConfigParams{}, false, false, &StaticConfigParams{})
-
-	for _, test := range tests {
-		result := vsc.generatePolicies(ownerDetails, test.policyRefs, test.policies, test.context, policyOpts)
-		if diff := cmp.Diff(test.expected, result); diff != "" {
-			t.Errorf("generatePolicies() '%v' mismatch (-want +got):\n%s", test.msg, diff)
-		}
-		if len(vsc.warnings) > 0 {
-			t.Errorf("generatePolicies() returned unexpected warnings %v for the case of %s", vsc.warnings, test.msg)
-		}
-	}
-}
-
-func TestGeneratePoliciesFails(t *testing.T) {
-	ownerDetails := policyOwnerDetails{
-		owner:          nil, // nil is OK for the unit test
-		ownerNamespace: "default",
-		vsNamespace:    "default",
-		vsName:         "test",
-	}
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "retry-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/retry-policy": {
+					Spec: conf_v1.PolicySpec{
+						Retry: &conf_v1.Retry{
+							Conditions:    []string{"error", "http_503"},
+							Tries:         createPointerFromInt(3),
+							PerTryTimeout: "5s",
+							Timeout:       "20s",
+							NonIdempotent: true,
+						},
+					},
+				},
+			},
+			context: "route",
+			expected: policiesCfg{
+				Retry: &retryCfg{
+					NextUpstream:        "error http_503 non_idempotent",
+					NextUpstreamTries:   createPointerFromInt(3),
+					NextUpstreamTimeout: "20s",
+					PerTryTimeout:       "5s",
+				},
+			},
+			msg: "retry reference",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "cors-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/cors-policy": {
+					Spec: conf_v1.PolicySpec{
+						CORS: &conf_v1.CORS{
+							AllowOrigins:     []string{"https://example.com", `~^https://.*\.example\.com$`},
+							AllowMethods:     []string{"GET", "PUT"},
+							AllowHeaders:     []string{"Content-Type", "Authorization"},
+							ExposeHeaders:    []string{"X-Request-ID"},
+							AllowCredentials: true,
+							MaxAge:           createPointerFromInt(3600),
+						},
+					},
+				},
+			},
+			context: "route",
+			expected: policiesCfg{
+				CORS: &corsCfg{
+					Location: version2.CORS{
+						OriginVariable:   "$pol_cors_default_cors_policy_default_test",
+						ExposeHeaders:    "X-Request-ID",
+						AllowCredentials: true,
+						PreflightPath:    "/internal_location_pol_cors_default_cors-policy",
+					},
+					Map: version2.Map{
+						Source:   "$http_origin",
+						Variable: "$pol_cors_default_cors_policy_default_test",
+						Parameters: []version2.Parameter{
+							{
+								Value:  "default",
+								Result: `""`,
+							},
+							{
+								Value:  `"https://example.com"`,
+								Result: "$http_origin",
+							},
+							{
+								Value:  `"~^https://.*\.example\.com$"`,
+								Result: "$http_origin",
+							},
+						},
+					},
+					PreflightLocation: version2.CORSPreflightLocation{
+						Path:             "/internal_location_pol_cors_default_cors-policy",
+						OriginVariable:   "$pol_cors_default_cors_policy_default_test",
+						AllowMethods:     "GET, PUT",
+						AllowHeaders:     "Content-Type, Authorization",
+						AllowCredentials: true,
+						MaxAge:           "3600",
+					},
+				},
+			},
+			msg: "cors reference",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "ext-auth-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/ext-auth-policy": {
+					Spec: conf_v1.PolicySpec{
+						ExternalAuth: &conf_v1.ExternalAuth{
+							AuthServiceName: "oauth2-proxy",
+							AuthServicePort: 4180,
+							AuthPath:        "/oauth2/auth",
+							ResponseHeaders: []string{"X-Auth-Request-User"},
+							SigninURL:       "https://auth.example.com/oauth2/start",
+							CacheTTL:        "30s",
+						},
+					},
+				},
+			},
+			context: "route",
+			expected: policiesCfg{
+				ExternalAuth: &externalAuthCfg{
+					Location: version2.ExternalAuth{
+						AuthPath: "/internal_location_pol_ext_auth_default_ext-auth-policy",
+						ResponseHeaders: []version2.ExternalAuthResponseHeader{
+							{
+								Name:     "X-Auth-Request-User",
+								Variable: "$ext_auth_x_auth_request_user",
+								Value:    "$upstream_http_x_auth_request_user",
+							},
+						},
+						SigninURL: "https://auth.example.com/oauth2/start",
+					},
+					AuthLocation: version2.ExternalAuthLocation{
+						Path:      "/internal_location_pol_ext_auth_default_ext-auth-policy",
+						ProxyPass: "http://vs_default_test_pol_ext_auth_default_ext-auth-policy/oauth2/auth",
+						CacheZone: "pol_ext_auth_default_ext-auth-policy_default_test",
+						CacheTTL:  "30s",
+					},
+					CacheZone: &version2.CacheZone{
+						Name: "pol_ext_auth_default_ext-auth-policy_default_test",
+						Path: "/var/cache/nginx/pol_ext_auth_default_ext-auth-policy_default_test",
+						Size: "1m",
+					},
+				},
+			},
+			msg: "external auth reference with a service",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "ext-auth-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/ext-auth-policy": {
+					Spec: conf_v1.PolicySpec{
+						ExternalAuth: &conf_v1.ExternalAuth{
+							AuthURL: "https://authz.example.com/verify",
+						},
+					},
+				},
+			},
+			context: "route",
+			expected: policiesCfg{
+				ExternalAuth: &externalAuthCfg{
+					Location: version2.ExternalAuth{
+						AuthPath: "/internal_location_pol_ext_auth_default_ext-auth-policy",
+					},
+					AuthLocation: version2.ExternalAuthLocation{
+						Path:      "/internal_location_pol_ext_auth_default_ext-auth-policy",
+						ProxyPass: "https://authz.example.com/verify",
+						SSLServer: true,
+					},
+				},
+			},
+			msg: "external auth reference with a url",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "cache-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/cache-policy": {
+					Spec: conf_v1.PolicySpec{
+						Cache: &conf_v1.Cache{
+							ZoneSize: "10m",
+							MaxSize:  "1g",
+							Valid: []conf_v1.CacheValid{
+								{
+									Codes: []int{200},
+									Time:  "10m",
+								},
+							},
+							StatusHeader: "X-Cache-Status",
+						},
+					},
+				},
+			},
+			context: "route",
+			expected: policiesCfg{
+				Cache: &cacheCfg{
+					Location: version2.Cache{
+						ZoneName: "pol_cache_default_cache-policy_default_test",
+						Key:      "$scheme$proxy_host$request_uri",
+						Valid: []version2.CacheValid{
+							{
+								Codes: []int{200},
+								Time:  "10m",
+							},
+						},
+						StatusHeader: "X-Cache-Status",
+					},
+					Zone: version2.CacheZone{
+						Name:    "pol_cache_default_cache-policy_default_test",
+						Path:    "/var/cache/nginx/pol_cache_default_cache-policy_default_test",
+						Size:    "10m",
+						MaxSize: "1g",
+					},
+				},
+			},
+			msg: "cache reference",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "compression-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/compression-policy": {
+					Spec: conf_v1.PolicySpec{
+						Compression: &conf_v1.Compression{
+							Gzip: &conf_v1.Gzip{
+								Types:   []string{"application/json"},
+								Level:   createPointerFromInt(5),
+								Proxied: []string{"any"},
+								Vary:    true,
+							},
+							Brotli: &conf_v1.Brotli{
+								MinLength: createPointerFromInt(1000),
+							},
+						},
+					},
+				},
+			},
+			context: "route",
+			expected: policiesCfg{
+				Compression: &version2.Compression{
+					Gzip: &version2.Gzip{
+						Types:   []string{"application/json"},
+						Level:   5,
+						Proxied: []string{"any"},
+						Vary:    true,
+					},
+					Brotli: &version2.Brotli{
+						MinLength: 1000,
+					},
+				},
+			},
+			msg: "compression reference",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "request-limits-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/request-limits-policy": {
+					Spec: conf_v1.PolicySpec{
+						RequestLimits: &conf_v1.RequestLimits{
+							ClientMaxBodySize:      "10m",
+							ClientBodyBufferSize:   "16k",
+							RequestBuffering:       createPointerFromBool(false),
+							ClientHeaderBufferSize: "2k",
+							LargeClientHeaderBuffers: &conf_v1.UpstreamBuffers{
+								Number: 4,
+								Size:   "16k",
+							},
+						},
+					},
+				},
+			},
+			context: "spec",
+			expected: policiesCfg{
+				RequestLimits: &requestLimitsCfg{
+					ClientMaxBodySize:        "10m",
+					ClientBodyBufferSize:     "16k",
+					RequestBufferingOff:      true,
+					ClientHeaderBufferSize:   "2k",
+					LargeClientHeaderBuffers: "4 16k",
+				},
+			},
+			msg: "request limits reference",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "ipac-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/ipac-policy": {
+					Spec: conf_v1.PolicySpec{
+						IPAccessControl: &conf_v1.IPAccessControl{
+							Deny: &conf_v1.IPAccessRule{
+								Addresses: []string{"10.0.0.0/8"},
+								Countries: []string{"US"},
+							},
+							ClientIP: &conf_v1.ClientIP{
+								Header:           "X-Forwarded-For",
+								TrustedAddresses: []string{"192.168.0.0/16"},
+								Recursive:        true,
+							},
+							DenyResponse: &conf_v1.DenyResponse{
+								Code: 451,
+								Body: "Unavailable",
+							},
+						},
+					},
+				},
+			},
+			context: "spec",
+			expected: policiesCfg{
+				IPAccessControl: &ipAccessControlCfg{
+					Location: version2.IPAccessControl{
+						DenyVariable: "$pol_ipac_default_ipac_policy_default_test_deny",
+						Code:         451,
+						Body:         "Unavailable",
+						Policy:       "default/ipac-policy",
+					},
+					Geo: &version2.Geo{
+						Variable: "$pol_ipac_default_ipac_policy_default_test_address",
+						Parameters: []version2.Parameter{
+							{Value: "default", Result: "0"},
+							{Value: "10.0.0.0/8", Result: "1"},
+						},
+					},
+					Maps: []version2.Map{
+						{
+							Source:   "$geoip2_country_code",
+							Variable: "$pol_ipac_default_ipac_policy_default_test_country",
+							Parameters: []version2.Parameter{
+								{Value: "default", Result: "0"},
+								{Value: `"US"`, Result: "1"},
+							},
+						},
+						{
+							Source:   `"${pol_ipac_default_ipac_policy_default_test_address}${pol_ipac_default_ipac_policy_default_test_country}"`,
+							Variable: "$pol_ipac_default_ipac_policy_default_test_deny",
+							Parameters: []version2.Parameter{
+								{Value: "default", Result: "0"},
+								{Value: `"~1"`, Result: "1"},
+							},
+						},
+					},
+					ClientIP: &conf_v1.ClientIP{
+						Header:           "X-Forwarded-For",
+						TrustedAddresses: []string{"192.168.0.0/16"},
+						Recursive:        true,
+					},
+				},
+			},
+			msg: "ipAccessControl reference",
+		},
+	}
+
+	vsc := newVirtualServerConfigurator(&ConfigParams{}, false, false, &StaticConfigParams{
+		GeoIP2CountryDatabase: "/etc/nginx/geoip/GeoLite2-Country.mmdb",
+		JWTRequireSupported:   true,
+		JWTKeyCacheSupported:  true,
+	})
+
+	for _, test := range tests {
+		result := vsc.generatePolicies(ownerDetails, test.policyRefs, test.policies, test.context, policyOpts)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("generatePolicies() '%v' mismatch (-want +got):\n%s", test.msg, diff)
+		}
+		if len(vsc.warnings) > 0 {
+			t.Errorf("generatePolicies() returned unexpected warnings %v for the case of %s", vsc.warnings, test.msg)
+		}
+	}
+}
+
+func TestGeneratePoliciesFails(t *testing.T) {
+	ownerDetails := policyOwnerDetails{
+		owner:          nil, // nil is OK for the unit test
+		ownerNamespace: "default",
+		vsNamespace:    "default",
+		vsName:         "test",
+	}
 
 	dryRunOverride := true
 	rejectCodeOverride := 505
@@ -2536,9 +3330,11 @@ func TestGeneratePoliciesFails(t *testing.T) {
 				},
 			},
 			expected: policiesCfg{
-				JWTAuth: &version2.JWTAuth{
-					Secret: "/etc/nginx/secrets/default-jwt-secret",
-					Realm:  "test",
+				JWTAuth: &jwtAuthCfg{
+					Location: version2.JWTAuth{
+						Secret: "/etc/nginx/secrets/default-jwt-secret",
+						Realm:  "test",
+					},
 				},
 			},
 			expectedWarnings: Warnings{
@@ -2552,32 +3348,31 @@ func TestGeneratePoliciesFails(t *testing.T) {
 		{
 			policyRefs: []conf_v1.PolicyReference{
 				{
-					Name:      "ingress-mtls-policy",
+					Name:      "jwt-policy",
 					Namespace: "default",
 				},
 			},
 			policies: map[string]*conf_v1.Policy{
-				"default/ingress-mtls-policy": {
+				"default/jwt-policy": {
 					ObjectMeta: meta_v1.ObjectMeta{
-						Name:      "ingress-mtls-policy",
+						Name:      "jwt-policy",
 						Namespace: "default",
 					},
 					Spec: conf_v1.PolicySpec{
-						IngressMTLS: &conf_v1.IngressMTLS{
-							ClientCertSecret: "ingress-mtls-secret",
+						JWTAuth: &conf_v1.JWTAuth{
+							Realm:   "test",
+							JWKSURI: "https://idp.example.com/jwks",
+							RequireClaims: []conf_v1.JWTClaimRequirement{
+								{
+									Claim:  "iss",
+									Equals: "https://idp.example.com/",
+								},
+							},
 						},
 					},
 				},
 			},
-			policyOpts: policyOptions{
-				tls: true,
-				secretRefs: map[string]*secrets.SecretReference{
-					"default/ingress-mtls-secret": {
-						Error: errors.New("secret is invalid"),
-					},
-				},
-			},
-			context: "spec",
+			policyOpts: policyOptions{},
 			expected: policiesCfg{
 				ErrorReturn: &version2.Return{
 					Code: 500,
@@ -2585,43 +3380,43 @@ func TestGeneratePoliciesFails(t *testing.T) {
 			},
 			expectedWarnings: Warnings{
 				nil: {
-					`IngressMTLS policy "default/ingress-mtls-policy" references an invalid secret default/ingress-mtls-secret: secret is invalid`,
+					"JWT policy default/jwt-policy requires claims, which is not supported by this version of NGINX Plus. NGINX Plus R25 or newer is required",
 				},
 			},
 			expectedOidc: &oidcPolicyCfg{},
-			msg:          "ingress mtls reference an invalid secret",
+			msg:          "jwt reference with claim requirements not supported by NGINX Plus",
 		},
 		{
 			policyRefs: []conf_v1.PolicyReference{
 				{
-					Name:      "ingress-mtls-policy",
+					Name:      "basic-auth-policy",
 					Namespace: "default",
 				},
 			},
 			policies: map[string]*conf_v1.Policy{
-				"default/ingress-mtls-policy": {
+				"default/basic-auth-policy": {
 					ObjectMeta: meta_v1.ObjectMeta{
-						Name:      "ingress-mtls-policy",
+						Name:      "basic-auth-policy",
 						Namespace: "default",
 					},
 					Spec: conf_v1.PolicySpec{
-						IngressMTLS: &conf_v1.IngressMTLS{
-							ClientCertSecret: "ingress-mtls-secret",
+						BasicAuth: &conf_v1.BasicAuth{
+							Realm:  "test",
+							Secret: "jwt-secret",
 						},
 					},
 				},
 			},
 			policyOpts: policyOptions{
-				tls: true,
 				secretRefs: map[string]*secrets.SecretReference{
-					"default/ingress-mtls-secret": {
+					"default/jwt-secret": {
 						Secret: &api_v1.Secret{
-							Type: api_v1.SecretTypeTLS,
+							Type: secrets.SecretTypeJWK,
 						},
+						Path: "/etc/nginx/secrets/default-jwt-secret",
 					},
 				},
 			},
-			context: "spec",
 			expected: policiesCfg{
 				ErrorReturn: &version2.Return{
 					Code: 500,
@@ -2629,11 +3424,11 @@ func TestGeneratePoliciesFails(t *testing.T) {
 			},
 			expectedWarnings: Warnings{
 				nil: {
-					`IngressMTLS policy default/ingress-mtls-policy references a secret default/ingress-mtls-secret of a wrong type 'kubernetes.io/tls', must be 'nginx.org/ca'`,
+					`Basic auth policy default/basic-auth-policy references a secret default/jwt-secret of a wrong type 'nginx.org/jwk', must be 'nginx.org/htpasswd'`,
 				},
 			},
 			expectedOidc: &oidcPolicyCfg{},
-			msg:          "ingress mtls references wrong secret type",
+			msg:          "basic auth reference with a secret of a wrong type",
 		},
 		{
 			policyRefs: []conf_v1.PolicyReference{
@@ -2641,10 +3436,6 @@ func TestGeneratePoliciesFails(t *testing.T) {
 					Name:      "ingress-mtls-policy",
 					Namespace: "default",
 				},
-				{
-					Name:      "ingress-mtls-policy2",
-					Namespace: "default",
-				},
 			},
 			policies: map[string]*conf_v1.Policy{
 				"default/ingress-mtls-policy": {
@@ -2658,21 +3449,111 @@ func TestGeneratePoliciesFails(t *testing.T) {
 						},
 					},
 				},
-				"default/ingress-mtls-policy2": {
-					Spec: conf_v1.PolicySpec{
-						IngressMTLS: &conf_v1.IngressMTLS{
-							ClientCertSecret: "ingress-mtls-secret2",
-						},
-					},
-				},
 			},
 			policyOpts: policyOptions{
 				tls: true,
 				secretRefs: map[string]*secrets.SecretReference{
 					"default/ingress-mtls-secret": {
-						Secret: &api_v1.Secret{
-							Type: secrets.SecretTypeCA,
-						},
+						Error: errors.New("secret is invalid"),
+					},
+				},
+			},
+			context: "spec",
+			expected: policiesCfg{
+				ErrorReturn: &version2.Return{
+					Code: 500,
+				},
+			},
+			expectedWarnings: Warnings{
+				nil: {
+					`IngressMTLS policy "default/ingress-mtls-policy" references an invalid secret default/ingress-mtls-secret: secret is invalid`,
+				},
+			},
+			expectedOidc: &oidcPolicyCfg{},
+			msg:          "ingress mtls reference an invalid secret",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "ingress-mtls-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/ingress-mtls-policy": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "ingress-mtls-policy",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						IngressMTLS: &conf_v1.IngressMTLS{
+							ClientCertSecret: "ingress-mtls-secret",
+						},
+					},
+				},
+			},
+			policyOpts: policyOptions{
+				tls: true,
+				secretRefs: map[string]*secrets.SecretReference{
+					"default/ingress-mtls-secret": {
+						Secret: &api_v1.Secret{
+							Type: api_v1.SecretTypeTLS,
+						},
+					},
+				},
+			},
+			context: "spec",
+			expected: policiesCfg{
+				ErrorReturn: &version2.Return{
+					Code: 500,
+				},
+			},
+			expectedWarnings: Warnings{
+				nil: {
+					`IngressMTLS policy default/ingress-mtls-policy references a secret default/ingress-mtls-secret of a wrong type 'kubernetes.io/tls', must be 'nginx.org/ca'`,
+				},
+			},
+			expectedOidc: &oidcPolicyCfg{},
+			msg:          "ingress mtls references wrong secret type",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "ingress-mtls-policy",
+					Namespace: "default",
+				},
+				{
+					Name:      "ingress-mtls-policy2",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/ingress-mtls-policy": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "ingress-mtls-policy",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						IngressMTLS: &conf_v1.IngressMTLS{
+							ClientCertSecret: "ingress-mtls-secret",
+						},
+					},
+				},
+				"default/ingress-mtls-policy2": {
+					Spec: conf_v1.PolicySpec{
+						IngressMTLS: &conf_v1.IngressMTLS{
+							ClientCertSecret: "ingress-mtls-secret2",
+						},
+					},
+				},
+			},
+			policyOpts: policyOptions{
+				tls: true,
+				secretRefs: map[string]*secrets.SecretReference{
+					"default/ingress-mtls-secret": {
+						Secret: &api_v1.Secret{
+							Type: secrets.SecretTypeCA,
+						},
 						Path: "/etc/nginx/secrets/default-ingress-mtls-secret",
 					},
 				},
@@ -2849,6 +3730,120 @@ func TestGeneratePoliciesFails(t *testing.T) {
 			expectedOidc: &oidcPolicyCfg{},
 			msg:          "multi egress mtls",
 		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "retry-policy",
+					Namespace: "default",
+				},
+				{
+					Name:      "retry-policy2",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/retry-policy": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "retry-policy",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						Retry: &conf_v1.Retry{},
+					},
+				},
+				"default/retry-policy2": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "retry-policy2",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						Retry: &conf_v1.Retry{
+							Conditions: []string{"http_503"},
+						},
+					},
+				},
+			},
+			context: "route",
+			expected: policiesCfg{
+				Retry: &retryCfg{
+					NextUpstream: "error timeout",
+				},
+			},
+			expectedWarnings: Warnings{
+				nil: {
+					`Multiple retry policies in the same context is not valid. Retry policy default/retry-policy2 will be ignored`,
+				},
+			},
+			expectedOidc: &oidcPolicyCfg{},
+			msg:          "multi retry",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "cors-policy",
+					Namespace: "default",
+				},
+				{
+					Name:      "cors-policy2",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/cors-policy": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "cors-policy",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						CORS: &conf_v1.CORS{
+							AllowOrigins: []string{"*"},
+						},
+					},
+				},
+				"default/cors-policy2": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "cors-policy2",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						CORS: &conf_v1.CORS{
+							AllowOrigins: []string{"https://example.com"},
+						},
+					},
+				},
+			},
+			context: "route",
+			expected: policiesCfg{
+				CORS: &corsCfg{
+					Location: version2.CORS{
+						OriginVariable: "$pol_cors_default_cors_policy_default_test",
+						PreflightPath:  "/internal_location_pol_cors_default_cors-policy",
+					},
+					Map: version2.Map{
+						Source:   "$http_origin",
+						Variable: "$pol_cors_default_cors_policy_default_test",
+						Parameters: []version2.Parameter{
+							{
+								Value:  "default",
+								Result: "$http_origin",
+							},
+						},
+					},
+					PreflightLocation: version2.CORSPreflightLocation{
+						Path:           "/internal_location_pol_cors_default_cors-policy",
+						OriginVariable: "$pol_cors_default_cors_policy_default_test",
+						AllowMethods:   "GET, HEAD, POST",
+					},
+				},
+			},
+			expectedWarnings: Warnings{
+				nil: {
+					`Multiple cors policies in the same context is not valid. CORS policy default/cors-policy2 will be ignored`,
+				},
+			},
+			expectedOidc: &oidcPolicyCfg{},
+			msg:          "multi cors",
+		},
 		{
 			policyRefs: []conf_v1.PolicyReference{
 				{
@@ -3271,13 +4266,14 @@ func TestGeneratePoliciesFails(t *testing.T) {
 			},
 			expectedOidc: &oidcPolicyCfg{
 				&version2.OIDC{
-					AuthEndpoint:  "https://foo.com/auth",
-					TokenEndpoint: "https://foo.com/token",
-					JwksURI:       "https://foo.com/certs",
-					ClientID:      "foo",
-					ClientSecret:  "super_secret_123",
-					RedirectURI:   "/_codexch",
-					Scope:         "openid",
+					AuthEndpoint:          "https://foo.com/auth",
+					TokenEndpoint:         "https://foo.com/token",
+					JwksURI:               "https://foo.com/certs",
+					ClientID:              "foo",
+					ClientSecret:          "super_secret_123",
+					RedirectURI:           "/_codexch",
+					PostLogoutRedirectURI: "/_logout",
+					Scope:                 "openid",
 				},
 				"default/oidc-policy",
 			},
@@ -3341,71 +4337,194 @@ func TestGeneratePoliciesFails(t *testing.T) {
 			expectedOidc: &oidcPolicyCfg{},
 			msg:          "multi waf",
 		},
-	}
-
-	for _, test := range tests {
-		vsc := newVirtualServerConfigurator(&ConfigParams{}, false, false, &StaticConfigParams{})
-
-		if test.oidcPolCfg != nil {
-			vsc.oidcPolCfg = test.oidcPolCfg
-		}
-
-		result := vsc.generatePolicies(ownerDetails, test.policyRefs, test.policies, test.context, test.policyOpts)
-		if diff := cmp.Diff(test.expected, result); diff != "" {
-			t.Errorf("generatePolicies() '%v' mismatch (-want +got):\n%s", test.msg, diff)
-		}
-		if !reflect.DeepEqual(vsc.warnings, test.expectedWarnings) {
-			t.Errorf(
-				"generatePolicies() returned warnings of \n%v but expected \n%v for the case of %s",
-				vsc.warnings,
-				test.expectedWarnings,
-				test.msg,
-			)
-		}
-		if diff := cmp.Diff(test.expectedOidc.oidc, vsc.oidcPolCfg.oidc); diff != "" {
-			t.Errorf("generatePolicies() '%v' mismatch (-want +got):\n%s", test.msg, diff)
-		}
-		if diff := cmp.Diff(test.expectedOidc.key, vsc.oidcPolCfg.key); diff != "" {
-			t.Errorf("generatePolicies() '%v' mismatch (-want +got):\n%s", test.msg, diff)
-		}
-	}
-}
-
-func TestRemoveDuplicates(t *testing.T) {
-	tests := []struct {
-		rlz      []version2.LimitReqZone
-		expected []version2.LimitReqZone
-	}{
 		{
-			rlz: []version2.LimitReqZone{
-				{ZoneName: "test"},
-				{ZoneName: "test"},
-				{ZoneName: "test2"},
-				{ZoneName: "test3"},
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "request-limits-policy",
+					Namespace: "default",
+				},
 			},
-			expected: []version2.LimitReqZone{
-				{ZoneName: "test"},
-				{ZoneName: "test2"},
-				{ZoneName: "test3"},
+			policies: map[string]*conf_v1.Policy{
+				"default/request-limits-policy": {
+					Spec: conf_v1.PolicySpec{
+						RequestLimits: &conf_v1.RequestLimits{
+							ClientMaxBodySize:      "10m",
+							ClientHeaderBufferSize: "2k",
+						},
+					},
+				},
 			},
-		},
-		{
-			rlz: []version2.LimitReqZone{
-				{ZoneName: "test"},
-				{ZoneName: "test"},
-				{ZoneName: "test2"},
-				{ZoneName: "test3"},
-				{ZoneName: "test3"},
+			policyOpts: policyOptions{},
+			context:    "route",
+			expected: policiesCfg{
+				RequestLimits: &requestLimitsCfg{
+					ClientMaxBodySize: "10m",
+				},
 			},
-			expected: []version2.LimitReqZone{
-				{ZoneName: "test"},
-				{ZoneName: "test2"},
-				{ZoneName: "test3"},
+			expectedWarnings: Warnings{
+				nil: {
+					`The header buffers of RequestLimits policy default/request-limits-policy are not allowed in the route context and will be ignored`,
+				},
 			},
+			expectedOidc: &oidcPolicyCfg{},
+			msg:          "request limits with header buffers in route context",
 		},
-	}
-	for _, test := range tests {
-		result := removeDuplicateLimitReqZones(test.rlz)
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "ipac-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/ipac-policy": {
+					Spec: conf_v1.PolicySpec{
+						IPAccessControl: &conf_v1.IPAccessControl{
+							Allow: &conf_v1.IPAccessRule{
+								Countries: []string{"US"},
+							},
+						},
+					},
+				},
+			},
+			policyOpts: policyOptions{},
+			context:    "route",
+			expected: policiesCfg{
+				ErrorReturn: &version2.Return{
+					Code: 500,
+				},
+			},
+			expectedWarnings: Warnings{
+				nil: {
+					`IPAccessControl policy default/ipac-policy references countries, but the GeoIP2 country database is not configured`,
+				},
+			},
+			expectedOidc: &oidcPolicyCfg{},
+			msg:          "ipAccessControl with countries without the GeoIP2 country database",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "ipac-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/ipac-policy": {
+					Spec: conf_v1.PolicySpec{
+						IPAccessControl: &conf_v1.IPAccessControl{
+							Allow: &conf_v1.IPAccessRule{
+								Addresses: []string{"10.0.0.0/8"},
+							},
+							ClientIP: &conf_v1.ClientIP{
+								Header:           "X-Forwarded-For",
+								TrustedAddresses: []string{"192.168.0.0/16"},
+							},
+						},
+					},
+				},
+			},
+			policyOpts: policyOptions{},
+			context:    "route",
+			expected: policiesCfg{
+				IPAccessControl: &ipAccessControlCfg{
+					Location: version2.IPAccessControl{
+						DenyVariable: "$pol_ipac_default_ipac_policy_default_test_deny",
+						Code:         403,
+						Policy:       "default/ipac-policy",
+					},
+					Geo: &version2.Geo{
+						Variable: "$pol_ipac_default_ipac_policy_default_test_address",
+						Parameters: []version2.Parameter{
+							{Value: "default", Result: "0"},
+							{Value: "10.0.0.0/8", Result: "1"},
+						},
+					},
+					Maps: []version2.Map{
+						{
+							Source:   `"${pol_ipac_default_ipac_policy_default_test_address}"`,
+							Variable: "$pol_ipac_default_ipac_policy_default_test_deny",
+							Parameters: []version2.Parameter{
+								{Value: "default", Result: "1"},
+								{Value: `"~1"`, Result: "0"},
+							},
+						},
+					},
+				},
+			},
+			expectedWarnings: Warnings{
+				nil: {
+					`The clientIP of IPAccessControl policy default/ipac-policy is not allowed in the route context and will be ignored`,
+				},
+			},
+			expectedOidc: &oidcPolicyCfg{},
+			msg:          "ipAccessControl with client ip in route context",
+		},
+	}
+
+	for _, test := range tests {
+		vsc := newVirtualServerConfigurator(&ConfigParams{}, false, false, &StaticConfigParams{})
+
+		if test.oidcPolCfg != nil {
+			vsc.oidcPolCfg = test.oidcPolCfg
+		}
+
+		result := vsc.generatePolicies(ownerDetails, test.policyRefs, test.policies, test.context, test.policyOpts)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("generatePolicies() '%v' mismatch (-want +got):\n%s", test.msg, diff)
+		}
+		if !reflect.DeepEqual(vsc.warnings, test.expectedWarnings) {
+			t.Errorf(
+				"generatePolicies() returned warnings of \n%v but expected \n%v for the case of %s",
+				vsc.warnings,
+				test.expectedWarnings,
+				test.msg,
+			)
+		}
+		if diff := cmp.Diff(test.expectedOidc.oidc, vsc.oidcPolCfg.oidc); diff != "" {
+			t.Errorf("generatePolicies() '%v' mismatch (-want +got):\n%s", test.msg, diff)
+		}
+		if diff := cmp.Diff(test.expectedOidc.key, vsc.oidcPolCfg.key); diff != "" {
+			t.Errorf("generatePolicies() '%v' mismatch (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
+func TestRemoveDuplicates(t *testing.T) {
+	tests := []struct {
+		rlz      []version2.LimitReqZone
+		expected []version2.LimitReqZone
+	}{
+		{
+			rlz: []version2.LimitReqZone{
+				{ZoneName: "test"},
+				{ZoneName: "test"},
+				{ZoneName: "test2"},
+				{ZoneName: "test3"},
+			},
+			expected: []version2.LimitReqZone{
+				{ZoneName: "test"},
+				{ZoneName: "test2"},
+				{ZoneName: "test3"},
+			},
+		},
+		{
+			rlz: []version2.LimitReqZone{
+				{ZoneName: "test"},
+				{ZoneName: "test"},
+				{ZoneName: "test2"},
+				{ZoneName: "test3"},
+				{ZoneName: "test3"},
+			},
+			expected: []version2.LimitReqZone{
+				{ZoneName: "test"},
+				{ZoneName: "test2"},
+				{ZoneName: "test3"},
+			},
+		},
+	}
+	for _, test := range tests {
+		result := removeDuplicateLimitReqZones(test.rlz)
 		if !reflect.DeepEqual(result, test.expected) {
 			t.Errorf("removeDuplicateLimitReqZones() returned \n%v, but expected \n%v", result, test.expected)
 		}
@@ -3444,6 +4563,356 @@ func TestAddPoliciesCfgToLocations(t *testing.T) {
 	}
 }
 
+func TestGenerateCORSMapsAndPreflightLocations(t *testing.T) {
+	cfg := corsCfg{
+		Map: version2.Map{
+			Variable: "$pol_cors_default_cors_default_cafe",
+		},
+		PreflightLocation: version2.CORSPreflightLocation{
+			Path: "/internal_location_pol_cors_default_cors",
+		},
+	}
+	otherCfg := corsCfg{
+		Map: version2.Map{
+			Variable: "$pol_cors_tea_cors_default_cafe",
+		},
+		PreflightLocation: version2.CORSPreflightLocation{
+			Path: "/internal_location_pol_cors_tea_cors",
+		},
+	}
+
+	expectedMaps := []version2.Map{cfg.Map, otherCfg.Map}
+	expectedLocations := []version2.CORSPreflightLocation{cfg.PreflightLocation, otherCfg.PreflightLocation}
+
+	maps, locations := generateCORSMapsAndPreflightLocations([]corsCfg{cfg, otherCfg, cfg})
+	if !reflect.DeepEqual(maps, expectedMaps) {
+		t.Errorf("generateCORSMapsAndPreflightLocations() returned maps %+v but expected %+v", maps, expectedMaps)
+	}
+	if !reflect.DeepEqual(locations, expectedLocations) {
+		t.Errorf("generateCORSMapsAndPreflightLocations() returned locations %+v but expected %+v", locations, expectedLocations)
+	}
+}
+
+func TestGenerateOIDCForwardToken(t *testing.T) {
+	tests := []struct {
+		token          string
+		header         string
+		expectedHeader string
+		expectedValue  string
+	}{
+		{
+			token:          "",
+			header:         "",
+			expectedHeader: "",
+			expectedValue:  "",
+		},
+		{
+			token:          "none",
+			header:         "",
+			expectedHeader: "",
+			expectedValue:  "",
+		},
+		{
+			token:          "id",
+			header:         "",
+			expectedHeader: "Authorization",
+			expectedValue:  "Bearer $session_jwt",
+		},
+		{
+			token:          "access",
+			header:         "authorization",
+			expectedHeader: "Authorization",
+			expectedValue:  "Bearer $access_token",
+		},
+		{
+			token:          "access",
+			header:         "X-Access-Token",
+			expectedHeader: "X-Access-Token",
+			expectedValue:  "$access_token",
+		},
+	}
+
+	for _, test := range tests {
+		header, value := generateOIDCForwardToken(test.token, test.header)
+		if header != test.expectedHeader || value != test.expectedValue {
+			t.Errorf("generateOIDCForwardToken(%q, %q) returned %q, %q but expected %q, %q",
+				test.token, test.header, header, value, test.expectedHeader, test.expectedValue)
+		}
+	}
+}
+
+func TestAddJWTAuthConfigKeyCacheWithoutNGINXSupport(t *testing.T) {
+	jwtAuth := &conf_v1.JWTAuth{
+		Realm:    "test",
+		JWKSURI:  "https://idp.example.com/jwks",
+		KeyCache: "1h",
+	}
+
+	expected := &jwtAuthCfg{
+		Location: version2.JWTAuth{
+			Realm:      "test",
+			KeyRequest: "/internal_location_pol_jwt_default_jwt-policy",
+		},
+		JWKSLocation: &version2.JWKSLocation{
+			Path:      "/internal_location_pol_jwt_default_jwt-policy",
+			URI:       "https://idp.example.com/jwks",
+			SSLServer: true,
+			CacheZone: "pol_jwt_default_jwt-policy_default_cafe",
+			CacheTTL:  "1h",
+		},
+		CacheZone: &version2.CacheZone{
+			Name: "pol_jwt_default_jwt-policy_default_cafe",
+			Path: "/var/cache/nginx/pol_jwt_default_jwt-policy_default_cafe",
+			Size: "1m",
+		},
+	}
+
+	p := newPoliciesConfig()
+	res := p.addJWTAuthConfig(jwtAuth, "default/jwt-policy", "default", "jwt-policy", "default", "cafe", nil, true, false)
+	if res.isError || len(res.warnings) > 0 {
+		t.Errorf("addJWTAuthConfig() returned unexpected result %+v", res)
+	}
+	if diff := cmp.Diff(expected, p.JWTAuth); diff != "" {
+		t.Errorf("addJWTAuthConfig() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateJWTClaimSetsMapsAndJWKSLocations(t *testing.T) {
+	cfg := jwtAuthCfg{
+		ClaimSets: []version2.JWTClaimSet{
+			{
+				Variable: "$pol_jwt_default_jwt_default_cafe_claim_0",
+				Claim:    []string{"aud"},
+			},
+		},
+		Maps: []version2.Map{
+			{
+				Source:   "$pol_jwt_default_jwt_default_cafe_claim_0",
+				Variable: "$pol_jwt_default_jwt_default_cafe_require_0",
+			},
+		},
+		JWKSLocation: &version2.JWKSLocation{
+			Path:      "/internal_location_pol_jwt_default_jwt",
+			CacheZone: "pol_jwt_default_jwt_default_cafe",
+		},
+		CacheZone: &version2.CacheZone{
+			Name: "pol_jwt_default_jwt_default_cafe",
+		},
+	}
+	secretCfg := jwtAuthCfg{
+		Location: version2.JWTAuth{
+			Secret: "/etc/nginx/secrets/default-jwk",
+		},
+	}
+
+	expectedClaimSets := cfg.ClaimSets
+	expectedMaps := cfg.Maps
+	expectedLocations := []version2.JWKSLocation{*cfg.JWKSLocation}
+	expectedZones := []version2.CacheZone{*cfg.CacheZone}
+
+	claimSets, maps, locations, zones := generateJWTClaimSetsMapsAndJWKSLocations([]jwtAuthCfg{cfg, secretCfg, cfg})
+	if !reflect.DeepEqual(claimSets, expectedClaimSets) {
+		t.Errorf("generateJWTClaimSetsMapsAndJWKSLocations() returned claim sets %+v but expected %+v", claimSets, expectedClaimSets)
+	}
+	if !reflect.DeepEqual(maps, expectedMaps) {
+		t.Errorf("generateJWTClaimSetsMapsAndJWKSLocations() returned maps %+v but expected %+v", maps, expectedMaps)
+	}
+	if !reflect.DeepEqual(locations, expectedLocations) {
+		t.Errorf("generateJWTClaimSetsMapsAndJWKSLocations() returned locations %+v but expected %+v", locations, expectedLocations)
+	}
+	if !reflect.DeepEqual(zones, expectedZones) {
+		t.Errorf("generateJWTClaimSetsMapsAndJWKSLocations() returned zones %+v but expected %+v", zones, expectedZones)
+	}
+}
+
+func TestGenerateExternalAuthLocationsAndCacheZones(t *testing.T) {
+	cfg := externalAuthCfg{
+		AuthLocation: version2.ExternalAuthLocation{
+			Path:      "/internal_location_pol_ext_auth_default_ext-auth",
+			CacheZone: "pol_ext_auth_default_ext-auth_default_cafe",
+		},
+		CacheZone: &version2.CacheZone{
+			Name: "pol_ext_auth_default_ext-auth_default_cafe",
+		},
+	}
+	otherCfg := externalAuthCfg{
+		AuthLocation: version2.ExternalAuthLocation{
+			Path: "/internal_location_pol_ext_auth_tea_ext-auth",
+		},
+	}
+
+	expectedLocations := []version2.ExternalAuthLocation{cfg.AuthLocation, otherCfg.AuthLocation}
+	expectedZones := []version2.CacheZone{*cfg.CacheZone}
+
+	locations, zones := generateExternalAuthLocationsAndCacheZones([]externalAuthCfg{cfg, otherCfg, cfg})
+	if !reflect.DeepEqual(locations, expectedLocations) {
+		t.Errorf("generateExternalAuthLocationsAndCacheZones() returned locations %+v but expected %+v", locations, expectedLocations)
+	}
+	if !reflect.DeepEqual(zones, expectedZones) {
+		t.Errorf("generateExternalAuthLocationsAndCacheZones() returned zones %+v but expected %+v", zones, expectedZones)
+	}
+}
+
+func TestGenerateCacheZones(t *testing.T) {
+	cfg := cacheCfg{
+		Zone: version2.CacheZone{
+			Name: "pol_cache_default_cache_default_cafe",
+			Path: "/var/cache/nginx/pol_cache_default_cache_default_cafe",
+			Size: "10m",
+		},
+	}
+	otherCfg := cacheCfg{
+		Zone: version2.CacheZone{
+			Name: "pol_cache_tea_cache_default_cafe",
+			Path: "/var/cache/nginx/pol_cache_tea_cache_default_cafe",
+			Size: "1m",
+		},
+	}
+
+	expected := []version2.CacheZone{cfg.Zone, otherCfg.Zone}
+
+	result := generateCacheZones([]cacheCfg{cfg, otherCfg, cfg})
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("generateCacheZones() returned %+v but expected %+v", result, expected)
+	}
+}
+
+func TestGenerateIPAccessControlGeosAndMaps(t *testing.T) {
+	cfg := ipAccessControlCfg{
+		Location: version2.IPAccessControl{
+			DenyVariable: "$pol_ipac_default_ipac_default_cafe_deny",
+		},
+		Geo: &version2.Geo{
+			Variable: "$pol_ipac_default_ipac_default_cafe_address",
+		},
+		Maps: []version2.Map{
+			{
+				Variable: "$pol_ipac_default_ipac_default_cafe_deny",
+			},
+		},
+	}
+	otherCfg := ipAccessControlCfg{
+		Location: version2.IPAccessControl{
+			DenyVariable: "$pol_ipac_tea_ipac_default_cafe_deny",
+		},
+		Maps: []version2.Map{
+			{
+				Variable: "$pol_ipac_tea_ipac_default_cafe_country",
+			},
+			{
+				Variable: "$pol_ipac_tea_ipac_default_cafe_deny",
+			},
+		},
+	}
+
+	expectedGeos := []version2.Geo{*cfg.Geo}
+	expectedMaps := []version2.Map{cfg.Maps[0], otherCfg.Maps[0], otherCfg.Maps[1]}
+
+	geos, maps := generateIPAccessControlGeosAndMaps([]ipAccessControlCfg{cfg, otherCfg, cfg})
+	if !reflect.DeepEqual(geos, expectedGeos) {
+		t.Errorf("generateIPAccessControlGeosAndMaps() returned geos %+v but expected %+v", geos, expectedGeos)
+	}
+	if !reflect.DeepEqual(maps, expectedMaps) {
+		t.Errorf("generateIPAccessControlGeosAndMaps() returned maps %+v but expected %+v", maps, expectedMaps)
+	}
+}
+
+func TestAddRequestLimitsCfgToLocation(t *testing.T) {
+	location := version2.Location{
+		Path:              "/",
+		ClientMaxBodySize: "1m",
+	}
+
+	expected := version2.Location{
+		Path:                     "/",
+		ClientMaxBodySize:        "10m",
+		ClientBodyBufferSize:     "16k",
+		ProxyRequestBufferingOff: true,
+	}
+
+	addRequestLimitsCfgToLocation(&requestLimitsCfg{
+		ClientMaxBodySize:    "10m",
+		ClientBodyBufferSize: "16k",
+		RequestBufferingOff:  true,
+	}, &location)
+	if !reflect.DeepEqual(location, expected) {
+		t.Errorf("addRequestLimitsCfgToLocation() returned \n%+v but expected \n%+v", location, expected)
+	}
+
+	// the client max body size of the upstream is kept if the policy doesn't define it
+	location = version2.Location{
+		Path:              "/",
+		ClientMaxBodySize: "1m",
+	}
+	expected = version2.Location{
+		Path:                 "/",
+		ClientMaxBodySize:    "1m",
+		ClientBodyBufferSize: "16k",
+	}
+
+	addRequestLimitsCfgToLocation(&requestLimitsCfg{
+		ClientBodyBufferSize: "16k",
+	}, &location)
+	if !reflect.DeepEqual(location, expected) {
+		t.Errorf("addRequestLimitsCfgToLocation() returned \n%+v but expected \n%+v", location, expected)
+	}
+}
+
+func TestAddRetryCfgToLocation(t *testing.T) {
+	cfg := &retryCfg{
+		NextUpstream:        "error timeout http_503",
+		NextUpstreamTries:   createPointerFromInt(0),
+		NextUpstreamTimeout: "1m",
+		PerTryTimeout:       "5s",
+	}
+
+	location := version2.Location{
+		Path:                     "/",
+		ProxyConnectTimeout:      "60s",
+		ProxyReadTimeout:         "60s",
+		ProxySendTimeout:         "60s",
+		ProxyNextUpstream:        "error",
+		ProxyNextUpstreamTimeout: "10s",
+		ProxyNextUpstreamTries:   5,
+	}
+
+	expected := version2.Location{
+		Path:                     "/",
+		ProxyConnectTimeout:      "5s",
+		ProxyReadTimeout:         "5s",
+		ProxySendTimeout:         "5s",
+		ProxyNextUpstream:        "error timeout http_503",
+		ProxyNextUpstreamTimeout: "1m",
+		ProxyNextUpstreamTries:   0,
+	}
+
+	addRetryCfgToLocation(cfg, &location)
+	if !reflect.DeepEqual(location, expected) {
+		t.Errorf("addRetryCfgToLocation() returned \n%+v but expected \n%+v", location, expected)
+	}
+
+	// the settings not defined in the policy are kept
+	cfg = &retryCfg{
+		NextUpstream: "error timeout",
+	}
+	location = version2.Location{
+		ProxyReadTimeout:         "60s",
+		ProxyNextUpstreamTimeout: "10s",
+		ProxyNextUpstreamTries:   5,
+	}
+	expected = version2.Location{
+		ProxyReadTimeout:         "60s",
+		ProxyNextUpstream:        "error timeout",
+		ProxyNextUpstreamTimeout: "10s",
+		ProxyNextUpstreamTries:   5,
+	}
+
+	addRetryCfgToLocation(cfg, &location)
+	if !reflect.DeepEqual(location, expected) {
+		t.Errorf("addRetryCfgToLocation() returned \n%+v but expected \n%+v", location, expected)
+	}
+}
+
 func TestGenerateUpstream(t *testing.T) {
 	name := "test-upstream"
 	upstream := conf_v1.Upstream{Service: name, Port: 80}
@@ -3478,7 +4947,7 @@ func TestGenerateUpstream(t *testing.T) {
 	}
 
 	vsc := newVirtualServerConfigurator(&cfgParams, false, false, &StaticConfigParams{})
-	result := vsc.generateUpstream(nil, name, upstream, false, endpoints)
+	result := vsc.generateUpstream(nil, name, upstream, false, endpoints, nil, nil)
 	if !reflect.DeepEqual(result, expected) {
 		t.Errorf("generateUpstream() returned %v but expected %v", result, expected)
 	}
@@ -3556,7 +5025,7 @@ func TestGenerateUpstreamWithKeepalive(t *testing.T) {
 
 	for _, test := range tests {
 		vsc := newVirtualServerConfigurator(test.cfgParams, false, false, &StaticConfigParams{})
-		result := vsc.generateUpstream(nil, name, test.upstream, false, endpoints)
+		result := vsc.generateUpstream(nil, name, test.upstream, false, endpoints, nil, nil)
 		if !reflect.DeepEqual(result, test.expected) {
 			t.Errorf("generateUpstream() returned %v but expected %v for the case of %v", result, test.expected, test.msg)
 		}
@@ -3578,22 +5047,228 @@ func TestGenerateUpstreamForExternalNameService(t *testing.T) {
 		UpstreamLabels: version2.UpstreamLabels{
 			Service: "test-upstream",
 		},
-		Servers: []version2.UpstreamServer{
-			{
-				Address: "example.com",
-			},
+		Servers: []version2.UpstreamServer{
+			{
+				Address: "example.com",
+			},
+		},
+		Resolve: true,
+	}
+
+	vsc := newVirtualServerConfigurator(&cfgParams, true, true, &StaticConfigParams{})
+	result := vsc.generateUpstream(nil, name, upstream, true, endpoints, nil, nil)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("generateUpstream() returned %v but expected %v", result, expected)
+	}
+
+	if len(vsc.warnings) != 0 {
+		t.Errorf("generateUpstream() returned warnings for %v", upstream)
+	}
+}
+
+func TestGenerateUpstreamWithDrainingEndpoints(t *testing.T) {
+	name := "test-upstream"
+	endpoints := []string{"10.0.0.1:80", "10.0.0.2:80"}
+	draining := map[string]bool{"10.0.0.2:80": true}
+	upstream := conf_v1.Upstream{Service: name, DrainTimeout: "30s"}
+
+	tests := []struct {
+		isPlus   bool
+		expected []version2.UpstreamServer
+	}{
+		{
+			isPlus: true,
+			expected: []version2.UpstreamServer{
+				{
+					Address: "10.0.0.1:80",
+				},
+				{
+					Address: "10.0.0.2:80",
+					Drain:   true,
+				},
+			},
+		},
+		{
+			isPlus: false,
+			expected: []version2.UpstreamServer{
+				{
+					Address: "10.0.0.1:80",
+				},
+				{
+					Address: "10.0.0.2:80",
+					Backup:  true,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		vsc := newVirtualServerConfigurator(&ConfigParams{}, test.isPlus, false, &StaticConfigParams{})
+		result := vsc.generateUpstream(nil, name, upstream, false, endpoints, nil, draining)
+		if !reflect.DeepEqual(result.Servers, test.expected) {
+			t.Errorf("generateUpstream(isPlus=%v) returned servers %v but expected %v", test.isPlus, result.Servers, test.expected)
+		}
+	}
+}
+
+func TestGenerateUpstreamWithHost(t *testing.T) {
+	name := "test-upstream"
+	upstream := conf_v1.Upstream{
+		Host: "api.example.com",
+		Port: 443,
+		Resolver: &conf_v1.UpstreamResolver{
+			Valid: "10s",
+		},
+	}
+	cfgParams := ConfigParams{
+		ResolverAddresses: []string{"10.0.0.10", "10.0.0.11"},
+		ResolverIPV6:      false,
+		ResolverTimeout:   "5s",
+		ResolverValid:     "60s",
+	}
+
+	expected := version2.Upstream{
+		Name: name,
+		Servers: []version2.UpstreamServer{
+			{
+				Address: "api.example.com:443",
+			},
+		},
+		Resolve: true,
+		Resolver: &version2.UpstreamResolver{
+			Addresses: []string{"10.0.0.10", "10.0.0.11"},
+			Valid:     "10s",
+			IPv6:      false,
+			Timeout:   "5s",
+		},
+	}
+
+	vsc := newVirtualServerConfigurator(&cfgParams, true, true, &StaticConfigParams{})
+	result := vsc.generateUpstream(nil, name, upstream, false, []string{"api.example.com:443"}, nil, nil)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("generateUpstream() returned %v but expected %v", result, expected)
+	}
+}
+
+func TestGenerateUpstreamResolver(t *testing.T) {
+	cfgParams := &ConfigParams{
+		ResolverAddresses: []string{"10.0.0.10"},
+		ResolverIPV6:      true,
+		ResolverValid:     "60s",
+	}
+
+	tests := []struct {
+		resolver  *conf_v1.UpstreamResolver
+		cfgParams *ConfigParams
+		expected  *version2.UpstreamResolver
+		msg       string
+	}{
+		{
+			resolver:  nil,
+			cfgParams: cfgParams,
+			expected:  nil,
+			msg:       "no resolver settings",
+		},
+		{
+			resolver:  &conf_v1.UpstreamResolver{Valid: "10s"},
+			cfgParams: &ConfigParams{},
+			expected:  nil,
+			msg:       "no resolver addresses in the ConfigMap",
+		},
+		{
+			resolver:  &conf_v1.UpstreamResolver{},
+			cfgParams: cfgParams,
+			expected: &version2.UpstreamResolver{
+				Addresses: []string{"10.0.0.10"},
+				Valid:     "60s",
+				IPv6:      true,
+			},
+			msg: "ConfigMap defaults",
+		},
+		{
+			resolver: &conf_v1.UpstreamResolver{
+				Valid:   "10s",
+				Timeout: "5s",
+				IPv6:    createPointerFromBool(false),
+			},
+			cfgParams: cfgParams,
+			expected: &version2.UpstreamResolver{
+				Addresses: []string{"10.0.0.10"},
+				Valid:     "10s",
+				IPv6:      false,
+				Timeout:   "5s",
+			},
+			msg: "upstream settings",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateUpstreamResolver(test.resolver, test.cfgParams)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("generateUpstreamResolver() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
+	}
+}
+
+func TestGenerateProxySSLServerName(t *testing.T) {
+	tests := []struct {
+		upstream conf_v1.Upstream
+		expected string
+	}{
+		{
+			upstream: conf_v1.Upstream{Service: "test", TLS: conf_v1.UpstreamTLS{Enable: true}},
+			expected: "",
+		},
+		{
+			upstream: conf_v1.Upstream{Host: "api.example.com"},
+			expected: "",
+		},
+		{
+			upstream: conf_v1.Upstream{Host: "api.example.com", TLS: conf_v1.UpstreamTLS{Enable: true}},
+			expected: "api.example.com",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateProxySSLServerName(test.upstream)
+		if result != test.expected {
+			t.Errorf("generateProxySSLServerName(%v) returned %q but expected %q", test.upstream, result, test.expected)
+		}
+	}
+}
+
+func TestGenerateUpstreamErrorReturn(t *testing.T) {
+	tests := []struct {
+		upstream  conf_v1.Upstream
+		cfgParams *ConfigParams
+		expected  *version2.Return
+		msg       string
+	}{
+		{
+			upstream:  conf_v1.Upstream{Service: "test"},
+			cfgParams: &ConfigParams{},
+			expected:  nil,
+			msg:       "service",
+		},
+		{
+			upstream:  conf_v1.Upstream{Host: "api.example.com"},
+			cfgParams: &ConfigParams{ResolverAddresses: []string{"example.com"}},
+			expected:  nil,
+			msg:       "host with resolver configured",
+		},
+		{
+			upstream:  conf_v1.Upstream{Host: "api.example.com"},
+			cfgParams: &ConfigParams{},
+			expected:  &version2.Return{Code: 500},
+			msg:       "host without resolver configured",
 		},
-		Resolve: true,
-	}
-
-	vsc := newVirtualServerConfigurator(&cfgParams, true, true, &StaticConfigParams{})
-	result := vsc.generateUpstream(nil, name, upstream, true, endpoints)
-	if !reflect.DeepEqual(result, expected) {
-		t.Errorf("generateUpstream() returned %v but expected %v", result, expected)
 	}
 
-	if len(vsc.warnings) != 0 {
-		t.Errorf("generateUpstream() returned warnings for %v", upstream)
+	for _, test := range tests {
+		result := generateUpstreamErrorReturn(test.upstream, test.cfgParams)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("generateUpstreamErrorReturn() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
 	}
 }
 
@@ -3796,6 +5471,244 @@ func TestGenerateLocationForProxying(t *testing.T) {
 	}
 }
 
+func TestGenerateLocationForProxyingForGRPC(t *testing.T) {
+	cfgParams := ConfigParams{
+		ProxyConnectTimeout: "30s",
+		ProxyReadTimeout:    "31s",
+		ProxySendTimeout:    "32s",
+		ClientMaxBodySize:   "1m",
+		ProxyBuffering:      true,
+		ProxyBuffers:        "8 4k",
+		ProxyBufferSize:     "4k",
+	}
+	upstream := conf_v1.Upstream{
+		Type:              "grpc",
+		ProxyNextUpstream: "error timeout unavailable",
+		TLS: conf_v1.UpstreamTLS{
+			Enable: true,
+		},
+	}
+	errorPages := []conf_v1.ErrorPage{
+		{
+			Codes: []int{502, 503},
+			Return: &conf_v1.ErrorPageReturn{
+				ActionReturn: conf_v1.ActionReturn{
+					Code: 200,
+					Body: "Unavailable",
+				},
+			},
+		},
+	}
+
+	expected := version2.Location{
+		Path:                     "/helloworld.Greeter",
+		ProxyConnectTimeout:      "30s",
+		ProxyReadTimeout:         "31s",
+		ProxySendTimeout:         "32s",
+		ClientMaxBodySize:        "1m",
+		GRPCPass:                 "grpcs://test-upstream",
+		ProxyNextUpstream:        "error timeout unavailable",
+		ProxyNextUpstreamTimeout: "0s",
+		ProxyInterceptErrors:     true,
+		ProxySetHeaders:          []version2.Header{{Name: "Host", Value: "$host"}},
+		ErrorPages: []version2.ErrorPage{
+			{
+				Name:         "@error_page_0_0",
+				Codes:        "502 503",
+				ResponseCode: 200,
+			},
+			{
+				Name:         "@grpc_deadline_exceeded",
+				Codes:        "408",
+				ResponseCode: 204,
+			},
+			{
+				Name:         "@grpc_permission_denied",
+				Codes:        "403",
+				ResponseCode: 204,
+			},
+			{
+				Name:         "@grpc_resource_exhausted",
+				Codes:        "413 414",
+				ResponseCode: 204,
+			},
+			{
+				Name:         "@grpc_unimplemented",
+				Codes:        "404",
+				ResponseCode: 204,
+			},
+			{
+				Name:         "@grpc_internal",
+				Codes:        "400 405 415 426 497 500 501",
+				ResponseCode: 204,
+			},
+			{
+				Name:         "@grpc_unavailable",
+				Codes:        "429 504",
+				ResponseCode: 204,
+			},
+			{
+				Name:         "@grpc_unauthenticated",
+				Codes:        "401 495 496",
+				ResponseCode: 204,
+			},
+		},
+	}
+
+	result := generateLocationForProxying("/helloworld.Greeter", "test-upstream", upstream, &cfgParams, errorPages, false, 0, "",
+		nil, "", nil, false, "", "")
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("generateLocationForProxying() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateReadOrSendTimeout(t *testing.T) {
+	tests := []struct {
+		ws       *conf_v1.UpstreamWebSocket
+		timeout  string
+		expected string
+		msg      string
+	}{
+		{
+			ws:       nil,
+			timeout:  "30s",
+			expected: "30s",
+			msg:      "no websocket",
+		},
+		{
+			ws:       nil,
+			expected: "60s",
+			msg:      "no websocket and no timeout",
+		},
+		{
+			ws:       &conf_v1.UpstreamWebSocket{Enable: false},
+			timeout:  "30s",
+			expected: "30s",
+			msg:      "disabled websocket",
+		},
+		{
+			ws:       &conf_v1.UpstreamWebSocket{Enable: true},
+			timeout:  "30s",
+			expected: "1h",
+			msg:      "enabled websocket with default idle timeout",
+		},
+		{
+			ws:       &conf_v1.UpstreamWebSocket{Enable: true, IdleTimeout: "10m"},
+			timeout:  "30s",
+			expected: "10m",
+			msg:      "enabled websocket with idle timeout",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateReadOrSendTimeout(test.ws, test.timeout, "60s")
+		if result != test.expected {
+			t.Errorf("generateReadOrSendTimeout() returned %q but expected %q for the case of %s", result, test.expected, test.msg)
+		}
+	}
+}
+
+func TestGenerateGRPCRewrites(t *testing.T) {
+	tests := []struct {
+		path         string
+		proxy        *conf_v1.ActionProxy
+		internal     bool
+		originalPath string
+		expected     []string
+	}{
+		{
+			path:     "/helloworld.Greeter",
+			expected: nil,
+		},
+		{
+			path:         "/internal_location_splits_0_split_0",
+			internal:     true,
+			originalPath: "/helloworld.Greeter",
+			expected:     []string{`^ $request_uri break`},
+		},
+		{
+			path: "/greeter",
+			proxy: &conf_v1.ActionProxy{
+				RewritePath: "/helloworld.Greeter",
+			},
+			expected: []string{`"^/greeter(.*)$" "/helloworld.Greeter$1" break`},
+		},
+		{
+			path: "=/greeter",
+			proxy: &conf_v1.ActionProxy{
+				RewritePath: "/helloworld.Greeter/SayHello",
+			},
+			expected: []string{`"^/greeter(.*)$" "/helloworld.Greeter/SayHello$1" break`},
+		},
+		{
+			path: "~/greeter",
+			proxy: &conf_v1.ActionProxy{
+				RewritePath: "/helloworld.Greeter",
+			},
+			expected: []string{`"^/greeter" "/helloworld.Greeter" break`},
+		},
+		{
+			path:     "/internal_location_splits_0_split_0",
+			internal: true,
+			proxy: &conf_v1.ActionProxy{
+				RewritePath: "/helloworld.Greeter",
+			},
+			originalPath: "/greeter",
+			expected:     []string{`^ $request_uri`, `"^/greeter(.*)$" "/helloworld.Greeter$1" break`},
+		},
+	}
+
+	for _, test := range tests {
+		result := generateGRPCRewrites(test.path, test.proxy, test.internal, test.originalPath)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("generateGRPCRewrites(%v, %v, %v, %v) returned \n %v but expected \n %v",
+				test.path, test.proxy, test.internal, test.originalPath, result, test.expected)
+		}
+	}
+}
+
+func TestCheckGRPCUpstream(t *testing.T) {
+	tests := []struct {
+		upstream         conf_v1.Upstream
+		http2            bool
+		tls              bool
+		warningsExpected bool
+		msg              string
+	}{
+		{
+			upstream: conf_v1.Upstream{Name: "test"},
+			msg:      "http upstream",
+		},
+		{
+			upstream: conf_v1.Upstream{Name: "test", Type: "grpc"},
+			http2:    true,
+			tls:      true,
+			msg:      "grpc upstream with http2 and tls",
+		},
+		{
+			upstream:         conf_v1.Upstream{Name: "test", Type: "grpc"},
+			tls:              true,
+			warningsExpected: true,
+			msg:              "grpc upstream without http2",
+		},
+		{
+			upstream:         conf_v1.Upstream{Name: "test", Type: "grpc"},
+			http2:            true,
+			warningsExpected: true,
+			msg:              "grpc upstream without tls",
+		},
+	}
+
+	for _, test := range tests {
+		vsc := newVirtualServerConfigurator(&ConfigParams{HTTP2: test.http2}, false, false, &StaticConfigParams{})
+
+		vsc.checkGRPCUpstream(&conf_v1.VirtualServer{}, test.upstream, test.tls)
+		if (len(vsc.warnings) > 0) != test.warningsExpected {
+			t.Errorf("checkGRPCUpstream() returned warnings %v for the case of %s", vsc.warnings, test.msg)
+		}
+	}
+}
+
 func TestGenerateReturnBlock(t *testing.T) {
 	tests := []struct {
 		text        string
@@ -3930,46 +5843,234 @@ func TestGenerateLocationForRedirect(t *testing.T) {
 				Snippets: []string{"# location snippet"},
 				ErrorPages: []version2.ErrorPage{
 					{
-						Name:         "http://nginx.org",
-						Codes:        "418",
-						ResponseCode: 301,
+						Name:         "http://nginx.org",
+						Codes:        "418",
+						ResponseCode: 301,
+					},
+				},
+				ProxyInterceptErrors: true,
+				InternalProxyPass:    "http://unix:/var/lib/nginx/nginx-418-server.sock",
+			},
+			msg: "redirect without code",
+		},
+		{
+			redirect: &conf_v1.ActionRedirect{
+				Code: 302,
+				URL:  "http://nginx.org",
+			},
+
+			expected: version2.Location{
+				Path:     "/",
+				Snippets: []string{"# location snippet"},
+				ErrorPages: []version2.ErrorPage{
+					{
+						Name:         "http://nginx.org",
+						Codes:        "418",
+						ResponseCode: 302,
+					},
+				},
+				ProxyInterceptErrors: true,
+				InternalProxyPass:    "http://unix:/var/lib/nginx/nginx-418-server.sock",
+			},
+			msg: "redirect with all fields defined",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateLocationForRedirect("/", []string{"# location snippet"}, test.redirect)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("generateLocationForReturn() returned \n%+v but expected \n%+v for the case of %s",
+				result, test.expected, test.msg)
+		}
+	}
+}
+
+func TestGenerateRouteForCanary(t *testing.T) {
+	canary := &conf_v1.Canary{
+		Stable: "stable",
+		Canary: "canary",
+		Steps: []conf_v1.CanaryStep{
+			{Weight: 0, Duration: "10m"},
+			{Weight: 20, Duration: "10m"},
+			{Weight: 100},
+		},
+	}
+	canaryWithOverride := canary.DeepCopy()
+	canaryWithOverride.Override = &conf_v1.CanaryOverride{
+		Cookie: "canary",
+		Value:  "always",
+	}
+
+	tests := []struct {
+		route         conf_v1.Route
+		canaryWeights map[string]int
+		expected      conf_v1.Route
+		msg           string
+	}{
+		{
+			route: conf_v1.Route{
+				Path:   "/",
+				Canary: canary,
+			},
+			canaryWeights: nil,
+			expected: conf_v1.Route{
+				Path: "/",
+				Action: &conf_v1.Action{
+					Pass: "stable",
+				},
+			},
+			msg: "no weight uses the first step",
+		},
+		{
+			route: conf_v1.Route{
+				Path:   "/",
+				Canary: canary,
+			},
+			canaryWeights: map[string]int{
+				"/": 20,
+			},
+			expected: conf_v1.Route{
+				Path: "/",
+				Splits: []conf_v1.Split{
+					{
+						Weight: 80,
+						Action: &conf_v1.Action{
+							Pass: "stable",
+						},
+					},
+					{
+						Weight: 20,
+						Action: &conf_v1.Action{
+							Pass: "canary",
+						},
+					},
+				},
+			},
+			msg: "splits",
+		},
+		{
+			route: conf_v1.Route{
+				Path:   "/",
+				Canary: canaryWithOverride,
+			},
+			canaryWeights: map[string]int{
+				"/": 100,
+			},
+			expected: conf_v1.Route{
+				Path: "/",
+				Action: &conf_v1.Action{
+					Pass: "canary",
+				},
+				Matches: []conf_v1.Match{
+					{
+						Conditions: []conf_v1.Condition{
+							{
+								Cookie: "canary",
+								Value:  "always",
+							},
+						},
+						Action: &conf_v1.Action{
+							Pass: "canary",
+						},
+					},
+				},
+			},
+			msg: "completed canary with override",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateRouteForCanary(test.route, test.canaryWeights)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("generateRouteForCanary() returned unexpected result for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
+func TestGenerateRouteForRollback(t *testing.T) {
+	splits := []conf_v1.Split{
+		{
+			Weight: 60,
+			Action: &conf_v1.Action{
+				Pass: "coffee-v1",
+			},
+		},
+		{
+			Weight: 30,
+			Action: &conf_v1.Action{
+				Pass: "coffee-v2",
+			},
+		},
+		{
+			Weight: 10,
+			Action: &conf_v1.Action{
+				Proxy: &conf_v1.ActionProxy{
+					Upstream: "coffee-v3",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		rolledBack map[string]bool
+		expected   conf_v1.Route
+		msg        string
+	}{
+		{
+			rolledBack: map[string]bool{
+				"coffee-v2": true,
+			},
+			expected: conf_v1.Route{
+				Path: "/",
+				Splits: []conf_v1.Split{
+					{
+						Weight: 90,
+						Action: &conf_v1.Action{
+							Pass: "coffee-v1",
+						},
+					},
+					{
+						Weight: 10,
+						Action: &conf_v1.Action{
+							Proxy: &conf_v1.ActionProxy{
+								Upstream: "coffee-v3",
+							},
+						},
 					},
 				},
-				ProxyInterceptErrors: true,
-				InternalProxyPass:    "http://unix:/var/lib/nginx/nginx-418-server.sock",
 			},
-			msg: "redirect without code",
+			msg: "one split rolled back",
 		},
 		{
-			redirect: &conf_v1.ActionRedirect{
-				Code: 302,
-				URL:  "http://nginx.org",
+			rolledBack: map[string]bool{
+				"coffee-v2": true,
+				"coffee-v3": true,
 			},
-
-			expected: version2.Location{
-				Path:     "/",
-				Snippets: []string{"# location snippet"},
-				ErrorPages: []version2.ErrorPage{
-					{
-						Name:         "http://nginx.org",
-						Codes:        "418",
-						ResponseCode: 302,
-					},
+			expected: conf_v1.Route{
+				Path: "/",
+				Action: &conf_v1.Action{
+					Pass: "coffee-v1",
 				},
-				ProxyInterceptErrors: true,
-				InternalProxyPass:    "http://unix:/var/lib/nginx/nginx-418-server.sock",
 			},
-			msg: "redirect with all fields defined",
+			msg: "all splits except the first rolled back",
 		},
 	}
 
 	for _, test := range tests {
-		result := generateLocationForRedirect("/", []string{"# location snippet"}, test.redirect)
-		if !reflect.DeepEqual(result, test.expected) {
-			t.Errorf("generateLocationForReturn() returned \n%+v but expected \n%+v for the case of %s",
-				result, test.expected, test.msg)
+		route := conf_v1.Route{
+			Path:   "/",
+			Splits: splits,
+		}
+
+		result := generateRouteForRollback(route, test.rolledBack)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("generateRouteForRollback() returned unexpected result for the case of %s (-want +got):\n%s", test.msg, diff)
 		}
 	}
+
+	if splits[0].Weight != 60 {
+		t.Errorf("generateRouteForRollback() modified the splits of the original route")
+	}
 }
 
 func TestGenerateSSLConfig(t *testing.T) {
@@ -4079,6 +6180,96 @@ func TestGenerateSSLConfig(t *testing.T) {
 	}
 }
 
+func TestGenerateAltSvc(t *testing.T) {
+	tests := []struct {
+		http3    bool
+		sslPorts []int
+		expected string
+	}{
+		{
+			http3:    false,
+			sslPorts: []int{443},
+			expected: "",
+		},
+		{
+			http3:    true,
+			sslPorts: []int{443},
+			expected: `h3=":443"; ma=86400`,
+		},
+		{
+			http3:    true,
+			sslPorts: []int{443, 8443},
+			expected: `h3=":443"; ma=86400, h3=":8443"; ma=86400`,
+		},
+	}
+
+	for _, test := range tests {
+		result := generateAltSvc(test.http3, test.sslPorts)
+		if result != test.expected {
+			t.Errorf("generateAltSvc(%v, %v) returned %q but expected %q", test.http3, test.sslPorts, result, test.expected)
+		}
+	}
+}
+
+func TestGenerateHTTP3(t *testing.T) {
+	tests := []struct {
+		tls              *conf_v1.TLS
+		cfgHTTP3         bool
+		http3Supported   bool
+		expected         bool
+		warningsExpected bool
+		msg              string
+	}{
+		{
+			tls:            &conf_v1.TLS{},
+			cfgHTTP3:       true,
+			http3Supported: true,
+			expected:       true,
+			msg:            "enabled in ConfigMap",
+		},
+		{
+			tls:            &conf_v1.TLS{},
+			cfgHTTP3:       true,
+			http3Supported: false,
+			expected:       false,
+			msg:            "enabled in ConfigMap but not supported",
+		},
+		{
+			tls:            &conf_v1.TLS{HTTP3: createPointerFromBool(false)},
+			cfgHTTP3:       true,
+			http3Supported: true,
+			expected:       false,
+			msg:            "disabled in TLS",
+		},
+		{
+			tls:            &conf_v1.TLS{HTTP3: createPointerFromBool(true)},
+			http3Supported: true,
+			expected:       true,
+			msg:            "enabled in TLS",
+		},
+		{
+			tls:              &conf_v1.TLS{HTTP3: createPointerFromBool(true)},
+			http3Supported:   false,
+			expected:         false,
+			warningsExpected: true,
+			msg:              "enabled in TLS but not supported",
+		},
+	}
+
+	for _, test := range tests {
+		vsc := newVirtualServerConfigurator(&ConfigParams{}, false, false, &StaticConfigParams{HTTP3Supported: test.http3Supported})
+
+		result := vsc.generateHTTP3(&conf_v1.VirtualServer{}, test.tls, &ConfigParams{HTTP3: test.cfgHTTP3})
+		if result != test.expected {
+			t.Errorf("generateHTTP3() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
+
+		if (len(vsc.warnings) > 0) != test.warningsExpected {
+			t.Errorf("generateHTTP3() returned warnings %v for the case of %s", vsc.warnings, test.msg)
+		}
+	}
+}
+
 func TestGenerateRedirectConfig(t *testing.T) {
 	tests := []struct {
 		inputTLS *conf_v1.TLS
@@ -4383,6 +6574,62 @@ func TestCreateUpstreamServersConfigForPlus(t *testing.T) {
 	}
 }
 
+func TestCreateUpstreamServersConfigForPlusWithWeights(t *testing.T) {
+	upstream := version2.Upstream{
+		Servers: []version2.UpstreamServer{
+			{
+				Address: "10.0.0.20:80",
+				Weight:  9,
+			},
+			{
+				Address: "10.0.0.21:80",
+				Weight:  1,
+			},
+		},
+		MaxFails: 1,
+	}
+
+	expected := nginx.ServerConfig{
+		MaxFails: 1,
+		Weights: map[string]int{
+			"10.0.0.20:80": 9,
+			"10.0.0.21:80": 1,
+		},
+	}
+
+	result := createUpstreamServersConfigForPlus(upstream)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("createUpstreamServersConfigForPlus returned %v but expected %v", result, expected)
+	}
+}
+
+func TestCreateUpstreamServersConfigForPlusWithDraining(t *testing.T) {
+	upstream := version2.Upstream{
+		Servers: []version2.UpstreamServer{
+			{
+				Address: "10.0.0.20:80",
+			},
+			{
+				Address: "10.0.0.21:80",
+				Drain:   true,
+			},
+		},
+		MaxFails: 1,
+	}
+
+	expected := nginx.ServerConfig{
+		MaxFails: 1,
+		Draining: map[string]bool{
+			"10.0.0.21:80": true,
+		},
+	}
+
+	result := createUpstreamServersConfigForPlus(upstream)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("createUpstreamServersConfigForPlus returned %v but expected %v", result, expected)
+	}
+}
+
 func TestCreateUpstreamServersConfigForPlusNoUpstreams(t *testing.T) {
 	noUpstream := version2.Upstream{}
 	expected := nginx.ServerConfig{}
@@ -4707,6 +6954,94 @@ func TestGenerateDefaultSplitsConfig(t *testing.T) {
 	}
 }
 
+func TestGenerateMirrorConfig(t *testing.T) {
+	virtualServer := conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	upstreamNamer := newUpstreamNamerForVirtualServer(&virtualServer)
+	variableNamer := newVariableNamer(&virtualServer)
+	cfgParams := ConfigParams{}
+	crUpstreams := map[string]conf_v1.Upstream{
+		"vs_default_cafe_coffee-v2": {
+			Service: "coffee-v2",
+		},
+	}
+	percentage := 10
+	requestBody := false
+
+	mirror := &conf_v1.Mirror{
+		Upstreams:   []string{"coffee-v2"},
+		Percentage:  &percentage,
+		RequestBody: &requestBody,
+	}
+
+	expected := mirrorCfg{
+		Mirror: &version2.Mirror{
+			Paths:       []string{"/internal_location_mirror_1_0"},
+			RequestBody: false,
+		},
+		Locations: []version2.Location{
+			{
+				Path:                     "/internal_location_mirror_1_0",
+				ProxyPass:                "http://vs_default_cafe_coffee-v2$request_uri",
+				ProxyNextUpstream:        "error timeout",
+				ProxyNextUpstreamTimeout: "0s",
+				ProxyNextUpstreamTries:   0,
+				Internal:                 true,
+				ProxySSLName:             "coffee-v2.default.svc",
+				ProxyPassRequestHeaders:  true,
+				ProxySetHeaders:          []version2.Header{{Name: "Host", Value: "$host"}},
+				ServiceName:              "coffee-v2",
+				MirrorTarget: &version2.MirrorTarget{
+					SampleVariable: "$vs_default_cafe_mirror_1",
+					RequestBody:    false,
+				},
+			},
+		},
+		SplitClients: []version2.SplitClient{
+			{
+				Source:   `"${request_id}mirror"`,
+				Variable: "$vs_default_cafe_mirror_1",
+				Distributions: []version2.Distribution{
+					{
+						Weight: "10%",
+						Value:  "1",
+					},
+					{
+						Weight: "*",
+						Value:  `""`,
+					},
+				},
+			},
+		},
+	}
+
+	result := generateMirrorConfig(mirror, upstreamNamer, crUpstreams, variableNamer, 1, &cfgParams, false, "", "")
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("generateMirrorConfig() returned \n%+v but expected \n%+v", result, expected)
+	}
+
+	// mirror all requests with their bodies by default
+	mirror = &conf_v1.Mirror{
+		Upstreams: []string{"coffee-v2"},
+	}
+
+	result = generateMirrorConfig(mirror, upstreamNamer, crUpstreams, variableNamer, 1, &cfgParams, false, "", "")
+	if len(result.SplitClients) != 0 {
+		t.Errorf("generateMirrorConfig() returned split clients %+v for a mirror without percentage", result.SplitClients)
+	}
+	expectedTarget := &version2.MirrorTarget{RequestBody: true}
+	if !reflect.DeepEqual(result.Locations[0].MirrorTarget, expectedTarget) {
+		t.Errorf("generateMirrorConfig() returned mirror target %+v but expected %+v", result.Locations[0].MirrorTarget, expectedTarget)
+	}
+	if !result.Mirror.RequestBody {
+		t.Errorf("generateMirrorConfig() returned mirror without request body by default")
+	}
+}
+
 func TestGenerateMatchesConfig(t *testing.T) {
 	route := conf_v1.Route{
 		Path: "/",
@@ -5892,6 +8227,37 @@ func TestGenerateHealthCheck(t *testing.T) {
 			},
 			msg: "HealthCheck with time parameters have correct format",
 		},
+		{
+			upstream: conf_v1.Upstream{
+				Type: "grpc",
+				Port: 50051,
+				HealthCheck: &conf_v1.HealthCheck{
+					Enable:      true,
+					GRPCStatus:  createPointerFromInt(12),
+					GRPCService: "helloworld.Greeter",
+					TLS: &conf_v1.UpstreamTLS{
+						Enable: true,
+					},
+				},
+			},
+			upstreamName: upstreamName,
+			expected: &version2.HealthCheck{
+				Name:                upstreamName,
+				ProxyConnectTimeout: "5s",
+				ProxySendTimeout:    "5s",
+				ProxyReadTimeout:    "5s",
+				GRPCPass:            fmt.Sprintf("grpcs://%v", upstreamName),
+				Interval:            "5s",
+				Jitter:              "0s",
+				Fails:               1,
+				Passes:              1,
+				Port:                50051,
+				Headers:             make(map[string]string),
+				GRPCStatus:          createPointerFromInt(12),
+				GRPCService:         "helloworld.Greeter",
+			},
+			msg: "HealthCheck for grpc upstream",
+		},
 	}
 
 	baseCfgParams := &ConfigParams{
@@ -5970,6 +8336,43 @@ func TestGenerateEndpointsForUpstream(t *testing.T) {
 			expected:             []string{},
 			msg:                  "ExternalName service without resolver configured",
 		},
+		{
+			upstream: conf_v1.Upstream{
+				Host: "api.example.com",
+				Port: 443,
+			},
+			vsEx: &VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      name,
+						Namespace: namespace,
+					},
+				},
+			},
+			isPlus:               true,
+			isResolverConfigured: true,
+			expected:             []string{"api.example.com:443"},
+			msg:                  "host",
+		},
+		{
+			upstream: conf_v1.Upstream{
+				Host: "api.example.com",
+				Port: 443,
+			},
+			vsEx: &VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      name,
+						Namespace: namespace,
+					},
+				},
+			},
+			isPlus:               true,
+			isResolverConfigured: false,
+			warningsExpected:     true,
+			expected:             []string{},
+			msg:                  "host without resolver configured",
+		},
 		{
 			upstream: conf_v1.Upstream{
 				Service: name,
@@ -6082,7 +8485,7 @@ func TestGenerateEndpointsForUpstream(t *testing.T) {
 			test.isResolverConfigured,
 			&StaticConfigParams{},
 		)
-		result := vsc.generateEndpointsForUpstream(test.vsEx.VirtualServer, namespace, test.upstream, test.vsEx)
+		result, _, _ := vsc.generateEndpointsForUpstream(test.vsEx.VirtualServer, namespace, test.upstream, test.vsEx)
 		if !reflect.DeepEqual(result, test.expected) {
 			t.Errorf("generateEndpointsForUpstream(isPlus=%v, isResolverConfigured=%v) returned %v, but expected %v for case: %v",
 				test.isPlus, test.isResolverConfigured, result, test.expected, test.msg)
@@ -6104,6 +8507,67 @@ func TestGenerateEndpointsForUpstream(t *testing.T) {
 	}
 }
 
+func TestGenerateDrainingEndpointsForOSS(t *testing.T) {
+	tests := []struct {
+		upstream          conf_v1.Upstream
+		endpoints         []string
+		draining          map[string]bool
+		expectedEndpoints []string
+		expectedDraining  map[string]bool
+		warningsExpected  bool
+		msg               string
+	}{
+		{
+			upstream:          conf_v1.Upstream{Name: "test", DrainTimeout: "30s"},
+			endpoints:         []string{"10.0.0.1:80", "10.0.0.2:80"},
+			draining:          map[string]bool{"10.0.0.2:80": true},
+			expectedEndpoints: []string{"10.0.0.1:80", "10.0.0.2:80"},
+			expectedDraining:  map[string]bool{"10.0.0.2:80": true},
+			msg:               "active and draining endpoints",
+		},
+		{
+			upstream:          conf_v1.Upstream{Name: "test", DrainTimeout: "30s"},
+			endpoints:         []string{"10.0.0.2:80"},
+			draining:          map[string]bool{"10.0.0.2:80": true},
+			expectedEndpoints: nil,
+			expectedDraining:  nil,
+			msg:               "only draining endpoints",
+		},
+		{
+			upstream:          conf_v1.Upstream{Name: "test", DrainTimeout: "30s", LBMethod: "ip_hash"},
+			endpoints:         []string{"10.0.0.1:80", "10.0.0.2:80"},
+			draining:          map[string]bool{"10.0.0.2:80": true},
+			expectedEndpoints: []string{"10.0.0.1:80"},
+			expectedDraining:  nil,
+			warningsExpected:  true,
+			msg:               "lb method incompatible with backup servers",
+		},
+		{
+			upstream:          conf_v1.Upstream{Name: "test", DrainTimeout: "30s", LBMethod: "hash $request_id"},
+			endpoints:         []string{"10.0.0.1:80"},
+			draining:          nil,
+			expectedEndpoints: []string{"10.0.0.1:80"},
+			expectedDraining:  nil,
+			warningsExpected:  true,
+			msg:               "lb method incompatible with backup servers without draining endpoints",
+		},
+	}
+
+	for _, test := range tests {
+		vsc := newVirtualServerConfigurator(&ConfigParams{}, false, false, &StaticConfigParams{})
+
+		endpoints, draining := vsc.generateDrainingEndpointsForOSS(&conf_v1.VirtualServer{}, test.upstream, test.endpoints, test.draining)
+		if !reflect.DeepEqual(endpoints, test.expectedEndpoints) || !reflect.DeepEqual(draining, test.expectedDraining) {
+			t.Errorf("generateDrainingEndpointsForOSS() returned %v, %v but expected %v, %v for the case of %s",
+				endpoints, draining, test.expectedEndpoints, test.expectedDraining, test.msg)
+		}
+
+		if (len(vsc.warnings) > 0) != test.warningsExpected {
+			t.Errorf("generateDrainingEndpointsForOSS() returned warnings %v for the case of %s", vsc.warnings, test.msg)
+		}
+	}
+}
+
 func TestGenerateSlowStartForPlusWithInCompatibleLBMethods(t *testing.T) {
 	serviceName := "test-slowstart-with-incompatible-LBMethods"
 	upstream := conf_v1.Upstream{Service: serviceName, Port: 80, SlowStart: "10s"}
@@ -6254,7 +8718,7 @@ func TestGenerateUpstreamWithQueue(t *testing.T) {
 
 	for _, test := range tests {
 		vsc := newVirtualServerConfigurator(&ConfigParams{}, test.isPlus, false, &StaticConfigParams{})
-		result := vsc.generateUpstream(nil, test.name, test.upstream, false, []string{})
+		result := vsc.generateUpstream(nil, test.name, test.upstream, false, []string{}, nil, nil)
 		if !reflect.DeepEqual(result, test.expected) {
 			t.Errorf("generateUpstream() returned %v but expected %v for the case of %v", result, test.expected, test.msg)
 		}
@@ -0,0 +1,228 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// canaryRelease holds the progress of the canary release of a route.
+type canaryRelease struct {
+	stable        string
+	canary        string
+	steps         []conf_v1.CanaryStep
+	step          int
+	stepStartTime time.Time
+	rolledBack    bool
+}
+
+// weight returns the weight of the canary upstream for the current step.
+func (r *canaryRelease) weight() int {
+	if r.rolledBack {
+		return 0
+	}
+	return r.steps[r.step].Weight
+}
+
+// isCompleted tells if the canary release has reached the last step.
+func (r *canaryRelease) isCompleted() bool {
+	return r.step == len(r.steps)-1
+}
+
+// nextStepTime returns the time when the canary release moves to the next step.
+// For a completed or rolled back canary release, it returns the zero time.
+func (r *canaryRelease) nextStepTime() time.Time {
+	if r.isCompleted() || r.rolledBack {
+		return time.Time{}
+	}
+
+	d := parseDurationOrDefault(r.steps[r.step].Duration, 0, fmt.Sprintf("duration of the canary step %d", r.step))
+	if d == 0 {
+		return time.Time{}
+	}
+
+	return r.stepStartTime.Add(d)
+}
+
+// advance moves the canary release through all the steps that have ended by now.
+func (r *canaryRelease) advance(now time.Time) {
+	for !r.isCompleted() {
+		next := r.nextStepTime()
+		if next.IsZero() || next.After(now) {
+			return
+		}
+
+		r.step++
+		r.stepStartTime = next
+	}
+}
+
+// canaryReleases keeps the progress of the canary releases of the routes of VirtualServers and VirtualServerRoutes.
+// The releases of a VirtualServer are keyed by the path of the route.
+type canaryReleases struct {
+	releases       map[string]map[string]*canaryRelease
+	scheduledSyncs map[string]time.Time
+}
+
+func newCanaryReleases() *canaryReleases {
+	return &canaryReleases{
+		releases:       make(map[string]map[string]*canaryRelease),
+		scheduledSyncs: make(map[string]time.Time),
+	}
+}
+
+// update updates the canary releases of the VirtualServer and its VirtualServerRoutes according to the current time
+// and the rolled back upstreams, and returns the weights of the canary upstreams keyed by the path of the route.
+// If the Ingress Controller doesn't know about a release yet, it resumes the release from the status of the VirtualServer.
+func (c *canaryReleases) update(vs *conf_v1.VirtualServer, vsrs []*conf_v1.VirtualServerRoute, rolledBack map[string]map[string]bool, now time.Time) map[string]int {
+	key := getResourceKey(&vs.ObjectMeta)
+
+	var routes []conf_v1.Route
+	routes = append(routes, vs.Spec.Routes...)
+	for _, vsr := range vsrs {
+		routes = append(routes, vsr.Spec.Subroutes...)
+	}
+
+	oldReleases := c.releases[key]
+	newReleases := make(map[string]*canaryRelease)
+	weights := make(map[string]int)
+
+	for _, r := range routes {
+		if r.Canary == nil || len(r.Canary.Steps) == 0 {
+			continue
+		}
+
+		release, exists := oldReleases[r.Path]
+		if !exists || release.stable != r.Canary.Stable || release.canary != r.Canary.Canary {
+			release = newCanaryReleaseFromStatus(r.Path, r.Canary, vs.Status.Canaries, now)
+		}
+
+		release.steps = r.Canary.Steps
+		if release.step >= len(release.steps) {
+			release.step = len(release.steps) - 1
+		}
+
+		wasRolledBack := release.rolledBack
+		release.rolledBack = rolledBack[r.Path][r.Canary.Canary]
+		if wasRolledBack && !release.rolledBack {
+			// the rollback was cancelled, so the current step starts over
+			release.stepStartTime = now
+		}
+
+		oldStep := release.step
+		release.advance(now)
+		if release.step != oldStep {
+			glog.V(2).Infof("Canary release of the route %v of VirtualServer %v moved to step %d with weight %d", r.Path, key, release.step, release.weight())
+		}
+
+		newReleases[r.Path] = release
+		weights[r.Path] = release.weight()
+	}
+
+	if len(newReleases) == 0 {
+		delete(c.releases, key)
+	} else {
+		c.releases[key] = newReleases
+	}
+
+	return weights
+}
+
+func newCanaryReleaseFromStatus(path string, canary *conf_v1.Canary, statuses []conf_v1.CanaryStatus, now time.Time) *canaryRelease {
+	release := &canaryRelease{
+		stable:        canary.Stable,
+		canary:        canary.Canary,
+		stepStartTime: now,
+	}
+
+	for _, s := range statuses {
+		if s.Path != path || s.Stable != canary.Stable || s.Canary != canary.Canary {
+			continue
+		}
+
+		if s.Step >= 0 && !s.StepStartTime.IsZero() {
+			release.step = s.Step
+			release.stepStartTime = s.StepStartTime.Time
+		}
+
+		break
+	}
+
+	return release
+}
+
+// getStatuses returns the statuses of the canary releases of the VirtualServer.
+func (c *canaryReleases) getStatuses(vsKey string) []conf_v1.CanaryStatus {
+	releases := c.releases[vsKey]
+
+	var paths []string
+	for path := range releases {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var statuses []conf_v1.CanaryStatus
+	for _, path := range paths {
+		r := releases[path]
+
+		state := conf_v1.CanaryStateProgressing
+		if r.rolledBack {
+			state = conf_v1.CanaryStateRolledBack
+		} else if r.isCompleted() {
+			state = conf_v1.CanaryStateCompleted
+		}
+
+		statuses = append(statuses, conf_v1.CanaryStatus{
+			Path:          path,
+			Stable:        r.stable,
+			Canary:        r.canary,
+			Step:          r.step,
+			Weight:        r.weight(),
+			State:         state,
+			StepStartTime: meta_v1.NewTime(r.stepStartTime),
+		})
+	}
+
+	return statuses
+}
+
+// getNextStepTime returns the earliest time when one of the canary releases of the VirtualServer moves to the next step.
+// If all releases are completed, it returns the zero time.
+func (c *canaryReleases) getNextStepTime(vsKey string) time.Time {
+	var earliest time.Time
+
+	for _, r := range c.releases[vsKey] {
+		next := r.nextStepTime()
+		if next.IsZero() {
+			continue
+		}
+
+		if earliest.IsZero() || next.Before(earliest) {
+			earliest = next
+		}
+	}
+
+	return earliest
+}
+
+// needsSync tells if a sync of the VirtualServer at the specified time needs to be scheduled.
+// It returns false if a sync at that time is already scheduled.
+func (c *canaryReleases) needsSync(vsKey string, t time.Time) bool {
+	if c.scheduledSyncs[vsKey].Equal(t) {
+		return false
+	}
+
+	c.scheduledSyncs[vsKey] = t
+
+	return true
+}
+
+// delete removes the canary releases of the VirtualServer.
+func (c *canaryReleases) delete(vsKey string) {
+	delete(c.releases, vsKey)
+	delete(c.scheduledSyncs, vsKey)
+}
@@ -0,0 +1,141 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func createTestVirtualServerWithCanary() *conf_v1.VirtualServer {
+	return &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+		Spec: conf_v1.VirtualServerSpec{
+			Routes: []conf_v1.Route{
+				{
+					Path: "/",
+					Canary: &conf_v1.Canary{
+						Stable: "stable",
+						Canary: "canary",
+						Steps: []conf_v1.CanaryStep{
+							{Weight: 10, Duration: "10m"},
+							{Weight: 50, Duration: "5m"},
+							{Weight: 100},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestCanaryReleasesUpdate(t *testing.T) {
+	vs := createTestVirtualServerWithCanary()
+	start := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		now              time.Time
+		expectedWeight   int
+		expectedNextStep time.Time
+		expectedState    string
+	}{
+		{
+			now:              start,
+			expectedWeight:   10,
+			expectedNextStep: start.Add(10 * time.Minute),
+			expectedState:    conf_v1.CanaryStateProgressing,
+		},
+		{
+			now:              start.Add(9 * time.Minute),
+			expectedWeight:   10,
+			expectedNextStep: start.Add(10 * time.Minute),
+			expectedState:    conf_v1.CanaryStateProgressing,
+		},
+		{
+			now:              start.Add(10 * time.Minute),
+			expectedWeight:   50,
+			expectedNextStep: start.Add(15 * time.Minute),
+			expectedState:    conf_v1.CanaryStateProgressing,
+		},
+		{
+			now:              start.Add(time.Hour),
+			expectedWeight:   100,
+			expectedNextStep: time.Time{},
+			expectedState:    conf_v1.CanaryStateCompleted,
+		},
+	}
+
+	releases := newCanaryReleases()
+
+	for _, test := range tests {
+		weights := releases.update(vs, nil, nil, test.now)
+		if weights["/"] != test.expectedWeight {
+			t.Errorf("update() at %v returned weight %d but expected %d", test.now, weights["/"], test.expectedWeight)
+		}
+
+		next := releases.getNextStepTime("default/cafe")
+		if !next.Equal(test.expectedNextStep) {
+			t.Errorf("getNextStepTime() at %v returned %v but expected %v", test.now, next, test.expectedNextStep)
+		}
+
+		statuses := releases.getStatuses("default/cafe")
+		if len(statuses) != 1 || statuses[0].State != test.expectedState {
+			t.Errorf("getStatuses() at %v returned %+v but expected a single status with state %v", test.now, statuses, test.expectedState)
+		}
+	}
+
+	vs.Spec.Routes[0].Canary = nil
+	releases.update(vs, nil, nil, start)
+	if _, exists := releases.releases["default/cafe"]; exists {
+		t.Errorf("update() didn't remove the release of the route without canary")
+	}
+}
+
+func TestCanaryReleasesUpdateResumesFromStatus(t *testing.T) {
+	vs := createTestVirtualServerWithCanary()
+	stepStart := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+	vs.Status.Canaries = []conf_v1.CanaryStatus{
+		{
+			Path:          "/",
+			Stable:        "stable",
+			Canary:        "canary",
+			Step:          1,
+			Weight:        50,
+			State:         conf_v1.CanaryStateProgressing,
+			StepStartTime: meta_v1.NewTime(stepStart),
+		},
+	}
+
+	releases := newCanaryReleases()
+
+	weights := releases.update(vs, nil, nil, stepStart.Add(time.Minute))
+	if weights["/"] != 50 {
+		t.Errorf("update() returned weight %d but expected 50", weights["/"])
+	}
+
+	expectedNext := stepStart.Add(5 * time.Minute)
+	if next := releases.getNextStepTime("default/cafe"); !next.Equal(expectedNext) {
+		t.Errorf("getNextStepTime() returned %v but expected %v", next, expectedNext)
+	}
+}
+
+func TestCanaryReleasesNeedsSync(t *testing.T) {
+	releases := newCanaryReleases()
+	next := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	if !releases.needsSync("default/cafe", next) {
+		t.Errorf("needsSync() returned false for the first sync")
+	}
+	if releases.needsSync("default/cafe", next) {
+		t.Errorf("needsSync() returned true for the already scheduled sync")
+	}
+
+	releases.delete("default/cafe")
+	if !releases.needsSync("default/cafe", next) {
+		t.Errorf("needsSync() returned false after delete()")
+	}
+}
@@ -11,7 +11,7 @@ import (
 	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
 	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
 	"github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/validation"
-	networking "k8s.io/api/networking/v1beta1"
+	networking "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -762,6 +762,15 @@ func (c *Configuration) buildListenersAndTSConfigurations() (newListeners map[st
 	return newListeners, newTSConfigs
 }
 
+// IsHostTaken checks if the host is taken by an Ingress, a VirtualServer or a TransportServer.
+func (c *Configuration) IsHostTaken(host string) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	_, exists := c.hosts[host]
+	return exists
+}
+
 // GetResources returns all configuration resources.
 func (c *Configuration) GetResources() []Resource {
 	return c.GetResourcesWithFilter(resourceFilter{
@@ -771,8 +780,9 @@ func (c *Configuration) GetResources() []Resource {
 }
 
 type resourceFilter struct {
-	Ingresses      bool
-	VirtualServers bool
+	Ingresses        bool
+	VirtualServers   bool
+	TransportServers bool
 }
 
 // GetResourcesWithFilter returns resources using the filter.
@@ -792,6 +802,16 @@ func (c *Configuration) GetResourcesWithFilter(filter resourceFilter) []Resource
 			if filter.VirtualServers {
 				resources[r.GetKeyWithKind()] = r
 			}
+		case *TransportServerConfiguration:
+			if filter.TransportServers {
+				resources[r.GetKeyWithKind()] = r
+			}
+		}
+	}
+
+	if filter.TransportServers {
+		for _, r := range c.listeners {
+			resources[r.GetKeyWithKind()] = r
 		}
 	}
 
@@ -9,14 +9,13 @@ import (
 	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
 	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
 	"github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/validation"
-	networking "k8s.io/api/networking/v1beta1"
+	networking "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func createTestConfiguration() *Configuration {
 	lbc := LoadBalancerController{
-		ingressClass:        "nginx",
-		useIngressClassOnly: true,
+		ingressClass: "nginx",
 	}
 	isPlus := false
 	appProtectEnabled := false
@@ -33,7 +32,7 @@ func createTestConfiguration() *Configuration {
 			80:  true,
 			443: true,
 		}),
-		validation.NewTransportServerValidator(isTLSPassthroughEnabled, snippetsEnabled),
+		validation.NewTransportServerValidator(isTLSPassthroughEnabled, snippetsEnabled, false),
 		isTLSPassthroughEnabled,
 	)
 }
@@ -2680,6 +2679,14 @@ func createTestIngressMinion(name string, host string, path string) *networking.
 			Paths: []networking.HTTPIngressPath{
 				{
 					Path: path,
+					Backend: networking.IngressBackend{
+						Service: &networking.IngressServiceBackend{
+							Name: "test-service",
+							Port: networking.ServiceBackendPort{
+								Number: 80,
+							},
+						},
+					},
 				},
 			},
 		},
@@ -19,6 +19,7 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -28,11 +29,13 @@ import (
 
 	"github.com/golang/glog"
 	"github.com/nginxinc/kubernetes-ingress/internal/k8s/secrets"
+	"github.com/nginxinc/nginx-plus-go-client/client"
 	"github.com/spiffe/go-spiffe/workload"
 
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	core_v1 "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -42,9 +45,11 @@ import (
 
 	"github.com/nginxinc/kubernetes-ingress/internal/configs"
 	"github.com/nginxinc/kubernetes-ingress/internal/metrics/collectors"
+	"github.com/nginxinc/kubernetes-ingress/internal/nginx"
 
 	api_v1 "k8s.io/api/core/v1"
-	networking "k8s.io/api/networking/v1beta1"
+	discovery_v1 "k8s.io/api/discovery/v1"
+	networking "k8s.io/api/networking/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
@@ -52,6 +57,8 @@ import (
 	"github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/validation"
 	k8s_nginx "github.com/nginxinc/kubernetes-ingress/pkg/client/clientset/versioned"
 	k8s_nginx_informers "github.com/nginxinc/kubernetes-ingress/pkg/client/informers/externalversions"
+	gateway_versioned "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
+	gateway_informers "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -61,6 +68,8 @@ import (
 
 const (
 	ingressClassKey = "kubernetes.io/ingress.class"
+	// isDefaultIngressClassKey is the annotation that marks an IngressClass as the default class
+	isDefaultIngressClassKey = "ingressclass.kubernetes.io/is-default-class"
 	// IngressControllerName holds Ingress Controller name
 	IngressControllerName = "nginx.org/ingress-controller"
 )
@@ -81,6 +90,10 @@ var (
 type podEndpoint struct {
 	Address string
 	PodName string
+	// NodeName, Zone and ZoneHints are used for the topology aware routing
+	NodeName  string
+	Zone      string
+	ZoneHints []string
 	// MeshPodOwner is used for NGINX Service Mesh metrics
 	configs.MeshPodOwner
 }
@@ -99,8 +112,9 @@ type LoadBalancerController struct {
 	globalConfigurationController cache.Controller
 	ingressLinkInformer           cache.SharedIndexInformer
 	ingressLister                 storeToIngressLister
+	ingressClassLister            cache.Store
 	svcLister                     cache.Store
-	endpointLister                storeToEndpointLister
+	endpointSliceLister           indexerToEndpointSliceLister
 	configMapLister               storeToConfigMapLister
 	podLister                     indexerToPodLister
 	secretLister                  cache.Store
@@ -125,7 +139,6 @@ type LoadBalancerController struct {
 	recorder                      record.EventRecorder
 	defaultServerSecret           string
 	ingressClass                  string
-	useIngressClassOnly           bool
 	statusUpdater                 *statusUpdater
 	leaderElector                 *leaderelection.LeaderElector
 	reportIngressStatus           bool
@@ -137,6 +150,7 @@ type LoadBalancerController struct {
 	wildcardTLSSecret             string
 	areCustomResourcesEnabled     bool
 	enablePreviewPolicies         bool
+	enableBrotli                  bool
 	metricsCollector              collectors.ControllerCollector
 	globalConfigurationValidator  *validation.GlobalConfigurationValidator
 	transportServerValidator      *validation.TransportServerValidator
@@ -149,72 +163,120 @@ type LoadBalancerController struct {
 	configuration                 *Configuration
 	secretStore                   secrets.SecretStore
 	appProtectConfiguration       appprotect.Configuration
+	reloadCoalescer               *nginx.ReloadCoalescer
+	canaryReleases                *canaryReleases
+	upstreamRollbacks             *upstreamRollbacks
+	upstreamStats                 *collectors.UpstreamStats
+	plusClient                    upstreamPeersClient
+	outlierDetector               *outlierDetector
+	outlierDetectionCollector     collectors.OutlierDetectionCollector
+	isTopologyAwareRoutingEnabled bool
+	nodeName                      string
+	nodeLister                    cache.Store
+	nodeController                cache.Controller
+	gatewayClient                 gateway_versioned.Interface
+	gatewaySharedInformerFactory  gateway_informers.SharedInformerFactory
+	gatewayClassLister            cache.Store
+	gatewayLister                 cache.Store
+	httpRouteLister               cache.Store
+	tlsRouteLister                cache.Store
+	isGatewayAPIEnabled           bool
+	gatewayControllerName         string
+	gatewayVirtualServers         map[string]*gatewayVirtualServer
+	gatewayTransportServers       map[string]*gatewayTransportServer
 }
 
 var keyFunc = cache.DeletionHandlingMetaNamespaceKeyFunc
 
 // NewLoadBalancerControllerInput holds the input needed to call NewLoadBalancerController.
 type NewLoadBalancerControllerInput struct {
-	KubeClient                   kubernetes.Interface
-	ConfClient                   k8s_nginx.Interface
-	DynClient                    dynamic.Interface
-	ResyncPeriod                 time.Duration
-	Namespace                    string
-	NginxConfigurator            *configs.Configurator
-	DefaultServerSecret          string
-	AppProtectEnabled            bool
-	IsNginxPlus                  bool
-	IngressClass                 string
-	UseIngressClassOnly          bool
-	ExternalServiceName          string
-	IngressLink                  string
-	ControllerNamespace          string
-	ReportIngressStatus          bool
-	IsLeaderElectionEnabled      bool
-	LeaderElectionLockName       string
-	WildcardTLSSecret            string
-	ConfigMaps                   string
-	GlobalConfiguration          string
-	AreCustomResourcesEnabled    bool
-	EnablePreviewPolicies        bool
-	MetricsCollector             collectors.ControllerCollector
-	GlobalConfigurationValidator *validation.GlobalConfigurationValidator
-	TransportServerValidator     *validation.TransportServerValidator
-	VirtualServerValidator       *validation.VirtualServerValidator
-	SpireAgentAddress            string
-	InternalRoutesEnabled        bool
-	IsPrometheusEnabled          bool
-	IsLatencyMetricsEnabled      bool
-	IsTLSPassthroughEnabled      bool
+	KubeClient                    kubernetes.Interface
+	ConfClient                    k8s_nginx.Interface
+	DynClient                     dynamic.Interface
+	ResyncPeriod                  time.Duration
+	Namespace                     string
+	NginxConfigurator             *configs.Configurator
+	DefaultServerSecret           string
+	AppProtectEnabled             bool
+	IsNginxPlus                   bool
+	IngressClass                  string
+	ExternalServiceName           string
+	IngressLink                   string
+	ControllerNamespace           string
+	ReportIngressStatus           bool
+	IsLeaderElectionEnabled       bool
+	LeaderElectionLockName        string
+	WildcardTLSSecret             string
+	ConfigMaps                    string
+	GlobalConfiguration           string
+	AreCustomResourcesEnabled     bool
+	EnablePreviewPolicies         bool
+	EnableBrotli                  bool
+	MetricsCollector              collectors.ControllerCollector
+	GlobalConfigurationValidator  *validation.GlobalConfigurationValidator
+	TransportServerValidator      *validation.TransportServerValidator
+	VirtualServerValidator        *validation.VirtualServerValidator
+	SpireAgentAddress             string
+	InternalRoutesEnabled         bool
+	IsPrometheusEnabled           bool
+	IsLatencyMetricsEnabled       bool
+	IsTLSPassthroughEnabled       bool
+	ReloadCoalescer               *nginx.ReloadCoalescer
+	UpstreamStats                 *collectors.UpstreamStats
+	PlusClient                    *client.NginxClient
+	OutlierDetectionCollector     collectors.OutlierDetectionCollector
+	IsTopologyAwareRoutingEnabled bool
+	NodeName                      string
+	GatewayClient                 gateway_versioned.Interface
+	IsGatewayAPIEnabled           bool
+	GatewayControllerName         string
 }
 
 // NewLoadBalancerController creates a controller
 func NewLoadBalancerController(input NewLoadBalancerControllerInput) *LoadBalancerController {
 	lbc := &LoadBalancerController{
-		client:                       input.KubeClient,
-		confClient:                   input.ConfClient,
-		dynClient:                    input.DynClient,
-		configurator:                 input.NginxConfigurator,
-		defaultServerSecret:          input.DefaultServerSecret,
-		appProtectEnabled:            input.AppProtectEnabled,
-		isNginxPlus:                  input.IsNginxPlus,
-		ingressClass:                 input.IngressClass,
-		useIngressClassOnly:          input.UseIngressClassOnly,
-		reportIngressStatus:          input.ReportIngressStatus,
-		isLeaderElectionEnabled:      input.IsLeaderElectionEnabled,
-		leaderElectionLockName:       input.LeaderElectionLockName,
-		resync:                       input.ResyncPeriod,
-		namespace:                    input.Namespace,
-		controllerNamespace:          input.ControllerNamespace,
-		wildcardTLSSecret:            input.WildcardTLSSecret,
-		areCustomResourcesEnabled:    input.AreCustomResourcesEnabled,
-		enablePreviewPolicies:        input.EnablePreviewPolicies,
-		metricsCollector:             input.MetricsCollector,
-		globalConfigurationValidator: input.GlobalConfigurationValidator,
-		transportServerValidator:     input.TransportServerValidator,
-		internalRoutesEnabled:        input.InternalRoutesEnabled,
-		isPrometheusEnabled:          input.IsPrometheusEnabled,
-		isLatencyMetricsEnabled:      input.IsLatencyMetricsEnabled,
+		client:                        input.KubeClient,
+		confClient:                    input.ConfClient,
+		dynClient:                     input.DynClient,
+		configurator:                  input.NginxConfigurator,
+		defaultServerSecret:           input.DefaultServerSecret,
+		appProtectEnabled:             input.AppProtectEnabled,
+		isNginxPlus:                   input.IsNginxPlus,
+		ingressClass:                  input.IngressClass,
+		reportIngressStatus:           input.ReportIngressStatus,
+		isLeaderElectionEnabled:       input.IsLeaderElectionEnabled,
+		leaderElectionLockName:        input.LeaderElectionLockName,
+		resync:                        input.ResyncPeriod,
+		namespace:                     input.Namespace,
+		controllerNamespace:           input.ControllerNamespace,
+		wildcardTLSSecret:             input.WildcardTLSSecret,
+		areCustomResourcesEnabled:     input.AreCustomResourcesEnabled,
+		enablePreviewPolicies:         input.EnablePreviewPolicies,
+		enableBrotli:                  input.EnableBrotli,
+		metricsCollector:              input.MetricsCollector,
+		globalConfigurationValidator:  input.GlobalConfigurationValidator,
+		transportServerValidator:      input.TransportServerValidator,
+		internalRoutesEnabled:         input.InternalRoutesEnabled,
+		isPrometheusEnabled:           input.IsPrometheusEnabled,
+		isLatencyMetricsEnabled:       input.IsLatencyMetricsEnabled,
+		reloadCoalescer:               input.ReloadCoalescer,
+		canaryReleases:                newCanaryReleases(),
+		upstreamRollbacks:             newUpstreamRollbacks(),
+		upstreamStats:                 input.UpstreamStats,
+		outlierDetector:               newOutlierDetector(),
+		outlierDetectionCollector:     input.OutlierDetectionCollector,
+		isTopologyAwareRoutingEnabled: input.IsTopologyAwareRoutingEnabled,
+		nodeName:                      input.NodeName,
+		gatewayClient:                 input.GatewayClient,
+		isGatewayAPIEnabled:           input.IsGatewayAPIEnabled,
+		gatewayControllerName:         input.GatewayControllerName,
+		gatewayVirtualServers:         make(map[string]*gatewayVirtualServer),
+		gatewayTransportServers:       make(map[string]*gatewayTransportServer),
+	}
+
+	// a nil *client.NginxClient must result in a nil interface
+	if input.PlusClient != nil {
+		lbc.plusClient = input.PlusClient
 	}
 
 	eventBroadcaster := record.NewBroadcaster()
@@ -226,6 +288,12 @@ func NewLoadBalancerController(input NewLoadBalancerControllerInput) *LoadBalanc
 		api_v1.EventSource{Component: "nginx-ingress-controller"})
 
 	lbc.syncQueue = newTaskQueue(lbc.sync)
+	if lbc.reloadCoalescer != nil {
+		// the pending reload is applied by the sync worker, so that it never happens in the middle of a config update
+		lbc.reloadCoalescer.SetReloadNotifier(func() {
+			lbc.syncQueue.EnqueueTask(task{Kind: nginxReload, Key: nginxReloadTaskKey})
+		})
+	}
 	if input.SpireAgentAddress != "" {
 		var err error
 		lbc.spiffeController, err = NewSpiffeController(lbc.syncSVIDRotation, input.SpireAgentAddress)
@@ -241,10 +309,15 @@ func NewLoadBalancerController(input NewLoadBalancerControllerInput) *LoadBalanc
 	// create handlers for resources we care about
 	lbc.addSecretHandler(createSecretHandlers(lbc))
 	lbc.addIngressHandler(createIngressHandlers(lbc))
+	lbc.addIngressClassHandler(createIngressClassHandlers(lbc))
 	lbc.addServiceHandler(createServiceHandlers(lbc))
-	lbc.addEndpointHandler(createEndpointHandlers(lbc))
+	lbc.addEndpointSliceHandler(createEndpointSliceHandlers(lbc))
 	lbc.addPodHandler()
 
+	if lbc.isTopologyAwareRoutingEnabled {
+		lbc.addNodeHandler()
+	}
+
 	if lbc.appProtectEnabled {
 		lbc.dynInformerFactory = dynamicinformer.NewDynamicSharedInformerFactory(lbc.dynClient, 0)
 
@@ -268,6 +341,12 @@ func NewLoadBalancerController(input NewLoadBalancerControllerInput) *LoadBalanc
 		}
 	}
 
+	if lbc.isGatewayAPIEnabled {
+		lbc.gatewaySharedInformerFactory = gateway_informers.NewSharedInformerFactoryWithOptions(lbc.gatewayClient, input.ResyncPeriod, gateway_informers.WithNamespace(lbc.namespace))
+
+		lbc.addGatewayAPIHandlers(createGatewayAPIHandlers(lbc))
+	}
+
 	if input.ConfigMaps != "" {
 		nginxConfigMapsNS, nginxConfigMapsName, err := ParseNamespaceName(input.ConfigMaps)
 		if err != nil {
@@ -298,6 +377,7 @@ func NewLoadBalancerController(input NewLoadBalancerControllerInput) *LoadBalanc
 		policyLister:             lbc.policyLister,
 		keyFunc:                  keyFunc,
 		confClient:               input.ConfClient,
+		gatewayClient:            input.GatewayClient,
 	}
 
 	lbc.configuration = NewConfiguration(
@@ -332,6 +412,21 @@ func (lbc *LoadBalancerController) AddSyncQueue(item interface{}) {
 	lbc.syncQueue.Enqueue(item)
 }
 
+// AddSyncQueueForEndpointSlice enqueues the task to sync the endpoints of the service of the EndpointSlice.
+// The task is keyed by the service, so that the changes of several EndpointSlices of a service are synced at once.
+func (lbc *LoadBalancerController) AddSyncQueueForEndpointSlice(slice *discovery_v1.EndpointSlice) {
+	svcName, exists := slice.Labels[discovery_v1.LabelServiceName]
+	if !exists {
+		glog.V(3).Infof("Ignoring EndpointSlice %v/%v without a service", slice.Namespace, slice.Name)
+		return
+	}
+
+	lbc.syncQueue.EnqueueTask(task{
+		Kind: endpointSlice,
+		Key:  fmt.Sprintf("%s/%s", slice.Namespace, svcName),
+	})
+}
+
 // addappProtectPolicyHandler creates dynamic informers for custom appprotect policy resource
 func (lbc *LoadBalancerController) addAppProtectPolicyHandler(handlers cache.ResourceEventHandlerFuncs) {
 	informer := lbc.dynInformerFactory.ForResource(appprotect.PolicyGVR).Informer()
@@ -379,18 +474,27 @@ func (lbc *LoadBalancerController) addServiceHandler(handlers cache.ResourceEven
 
 // addIngressHandler adds the handler for ingresses to the controller
 func (lbc *LoadBalancerController) addIngressHandler(handlers cache.ResourceEventHandlerFuncs) {
-	informer := lbc.sharedInformerFactory.Networking().V1beta1().Ingresses().Informer()
+	informer := lbc.sharedInformerFactory.Networking().V1().Ingresses().Informer()
 	informer.AddEventHandler(handlers)
 	lbc.ingressLister.Store = informer.GetStore()
 
 	lbc.cacheSyncs = append(lbc.cacheSyncs, informer.HasSynced)
 }
 
-// addEndpointHandler adds the handler for endpoints to the controller
-func (lbc *LoadBalancerController) addEndpointHandler(handlers cache.ResourceEventHandlerFuncs) {
-	informer := lbc.sharedInformerFactory.Core().V1().Endpoints().Informer()
+// addIngressClassHandler adds the handler for ingress classes to the controller
+func (lbc *LoadBalancerController) addIngressClassHandler(handlers cache.ResourceEventHandlerFuncs) {
+	informer := lbc.sharedInformerFactory.Networking().V1().IngressClasses().Informer()
 	informer.AddEventHandler(handlers)
-	lbc.endpointLister.Store = informer.GetStore()
+	lbc.ingressClassLister = informer.GetStore()
+
+	lbc.cacheSyncs = append(lbc.cacheSyncs, informer.HasSynced)
+}
+
+// addEndpointSliceHandler adds the handler for endpoint slices to the controller
+func (lbc *LoadBalancerController) addEndpointSliceHandler(handlers cache.ResourceEventHandlerFuncs) {
+	informer := lbc.sharedInformerFactory.Discovery().V1().EndpointSlices().Informer()
+	informer.AddEventHandler(handlers)
+	lbc.endpointSliceLister.Indexer = informer.GetIndexer()
 
 	lbc.cacheSyncs = append(lbc.cacheSyncs, informer.HasSynced)
 }
@@ -417,6 +521,21 @@ func (lbc *LoadBalancerController) addPodHandler() {
 	lbc.cacheSyncs = append(lbc.cacheSyncs, informer.HasSynced)
 }
 
+// addNodeHandler adds the informer for nodes, which the controller uses to find the zones of the endpoints.
+func (lbc *LoadBalancerController) addNodeHandler() {
+	lbc.nodeLister, lbc.nodeController = cache.NewInformer(
+		cache.NewListWatchFromClient(
+			lbc.client.CoreV1().RESTClient(),
+			"nodes",
+			"",
+			fields.Everything()),
+		&api_v1.Node{},
+		lbc.resync,
+		cache.ResourceEventHandlerFuncs{},
+	)
+	lbc.cacheSyncs = append(lbc.cacheSyncs, lbc.nodeController.HasSynced)
+}
+
 func (lbc *LoadBalancerController) addVirtualServerHandler(handlers cache.ResourceEventHandlerFuncs) {
 	informer := lbc.confSharedInformerFactorry.K8s().V1().VirtualServers().Informer()
 	informer.AddEventHandler(handlers)
@@ -463,6 +582,27 @@ func (lbc *LoadBalancerController) addTransportServerHandler(handlers cache.Reso
 	lbc.cacheSyncs = append(lbc.cacheSyncs, informer.HasSynced)
 }
 
+func (lbc *LoadBalancerController) addGatewayAPIHandlers(handlers cache.ResourceEventHandlerFuncs) {
+	gatewayClassInformer := lbc.gatewaySharedInformerFactory.Networking().V1alpha1().GatewayClasses().Informer()
+	gatewayClassInformer.AddEventHandler(handlers)
+	lbc.gatewayClassLister = gatewayClassInformer.GetStore()
+
+	gatewayInformer := lbc.gatewaySharedInformerFactory.Networking().V1alpha1().Gateways().Informer()
+	gatewayInformer.AddEventHandler(handlers)
+	lbc.gatewayLister = gatewayInformer.GetStore()
+
+	httpRouteInformer := lbc.gatewaySharedInformerFactory.Networking().V1alpha1().HTTPRoutes().Informer()
+	httpRouteInformer.AddEventHandler(handlers)
+	lbc.httpRouteLister = httpRouteInformer.GetStore()
+
+	tlsRouteInformer := lbc.gatewaySharedInformerFactory.Networking().V1alpha1().TLSRoutes().Informer()
+	tlsRouteInformer.AddEventHandler(handlers)
+	lbc.tlsRouteLister = tlsRouteInformer.GetStore()
+
+	lbc.cacheSyncs = append(lbc.cacheSyncs, gatewayClassInformer.HasSynced, gatewayInformer.HasSynced,
+		httpRouteInformer.HasSynced, tlsRouteInformer.HasSynced)
+}
+
 func (lbc *LoadBalancerController) addIngressLinkHandler(handlers cache.ResourceEventHandlerFuncs, name string) {
 	optionsModifier := func(options *meta_v1.ListOptions) {
 		options.FieldSelector = fields.Set{"metadata.name": name}.String()
@@ -497,9 +637,15 @@ func (lbc *LoadBalancerController) Run() {
 	if lbc.watchNginxConfigMaps {
 		go lbc.configMapController.Run(lbc.ctx.Done())
 	}
+	if lbc.isTopologyAwareRoutingEnabled {
+		go lbc.nodeController.Run(lbc.ctx.Done())
+	}
 	if lbc.areCustomResourcesEnabled {
 		go lbc.confSharedInformerFactorry.Start(lbc.ctx.Done())
 	}
+	if lbc.isGatewayAPIEnabled {
+		go lbc.gatewaySharedInformerFactory.Start(lbc.ctx.Done())
+	}
 	if lbc.watchGlobalConfiguration {
 		go lbc.globalConfigurationController.Run(lbc.ctx.Done())
 	}
@@ -519,6 +665,19 @@ func (lbc *LoadBalancerController) Run() {
 	glog.V(3).Infof("Starting the queue with %d initial elements", lbc.syncQueue.Len())
 
 	go lbc.syncQueue.Run(time.Second, lbc.ctx.Done())
+
+	if lbc.areCustomResourcesEnabled && lbc.upstreamStats != nil {
+		go wait.Until(func() {
+			lbc.syncQueue.EnqueueTask(task{Kind: upstreamAnalysis, Key: upstreamAnalysisTaskKey})
+		}, upstreamAnalysisInterval, lbc.ctx.Done())
+	}
+
+	if lbc.areCustomResourcesEnabled && lbc.plusClient != nil {
+		go wait.Until(func() {
+			lbc.syncQueue.EnqueueTask(task{Kind: outlierDetection, Key: outlierDetectionTaskKey})
+		}, outlierDetectionInterval, lbc.ctx.Done())
+	}
+
 	<-lbc.ctx.Done()
 }
 
@@ -529,25 +688,34 @@ func (lbc *LoadBalancerController) Stop() {
 	lbc.syncQueue.Shutdown()
 }
 
-func (lbc *LoadBalancerController) syncEndpoints(task task) {
+func (lbc *LoadBalancerController) syncEndpointSlices(task task) {
 	key := task.Key
-	glog.V(3).Infof("Syncing endpoints %v", key)
+	glog.V(3).Infof("Syncing endpoints of service %v", key)
 
-	obj, endpExists, err := lbc.endpointLister.GetByKey(key)
+	svcNamespace, svcName, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
-		lbc.syncQueue.Requeue(task, err)
+		glog.Errorf("Invalid key %v of the service of EndpointSlices: %v", key, err)
 		return
 	}
 
-	if !endpExists {
-		return
-	}
+	resources := lbc.configuration.FindResourcesForEndpoints(svcNamespace, svcName)
 
-	endp := obj.(*api_v1.Endpoints)
-	resources := lbc.configuration.FindResourcesForEndpoints(endp.Namespace, endp.Name)
+	if lbc.areCustomResourcesEnabled {
+		for _, pol := range lbc.getPoliciesForService(svcNamespace, svcName) {
+			resources = append(resources, lbc.configuration.FindResourcesForPolicy(pol.Namespace, pol.Name)...)
+		}
+
+		resources = removeDuplicateResources(resources)
+	}
 
 	resourceExes := lbc.createExtendedResources(resources)
 
+	if lbc.isGatewayAPIEnabled {
+		vsExes, tsExes := lbc.createGatewayResourceExesForService(svcNamespace, svcName)
+		resourceExes.VirtualServerExes = append(resourceExes.VirtualServerExes, vsExes...)
+		resourceExes.TransportServerExes = append(resourceExes.TransportServerExes, tsExes...)
+	}
+
 	if len(resourceExes.IngressExes) > 0 {
 		glog.V(3).Infof("Updating Endpoints for %v", resourceExes.IngressExes)
 		err = lbc.configurator.UpdateEndpoints(resourceExes.IngressExes)
@@ -633,6 +801,10 @@ func (lbc *LoadBalancerController) syncConfigMap(task task) {
 
 	resourceExes := lbc.createExtendedResources(resources)
 
+	if lbc.isGatewayAPIEnabled {
+		resourceExes.VirtualServerExes = append(resourceExes.VirtualServerExes, lbc.createGatewayVirtualServerExes()...)
+	}
+
 	warnings, updateErr := lbc.configurator.UpdateConfig(cfgParams, resourceExes.IngressExes, resourceExes.MergeableIngresses, resourceExes.VirtualServerExes)
 
 	eventTitle := "Updated"
@@ -655,6 +827,11 @@ func (lbc *LoadBalancerController) syncConfigMap(task task) {
 	}
 
 	lbc.updateResourcesStatusAndEvents(resources, warnings, updateErr)
+
+	if lbc.isGatewayAPIEnabled {
+		// the configuration of the hosts of the routes and the addresses of the Gateways might have changed
+		lbc.AddSyncQueueForGatewayAPI()
+	}
 }
 
 func (lbc *LoadBalancerController) sync(task task) {
@@ -669,8 +846,8 @@ func (lbc *LoadBalancerController) sync(task task) {
 		lbc.updateIngressMetrics()
 	case configMap:
 		lbc.syncConfigMap(task)
-	case endpoints:
-		lbc.syncEndpoints(task)
+	case endpointSlice:
+		lbc.syncEndpointSlices(task)
 	case secret:
 		lbc.syncSecret(task)
 	case service:
@@ -695,14 +872,295 @@ func (lbc *LoadBalancerController) sync(task task) {
 		lbc.syncAppProtectUserSig(task)
 	case ingressLink:
 		lbc.syncIngressLink(task)
-	}
-
-	if !lbc.isNginxReady && lbc.syncQueue.Len() == 0 {
+	case nginxReload:
+		lbc.syncNginxReload()
+	case canaryStep:
+		lbc.syncCanaryStep(task)
+	case upstreamAnalysis:
+		lbc.syncUpstreamAnalysis()
+	case outlierDetection:
+		lbc.syncOutlierDetection()
+	case gatewayAPI:
+		lbc.syncGatewayAPI()
+	}
+
+	if !lbc.isNginxReady && lbc.syncQueue.Len() == 0 && !lbc.hasPendingReload() {
 		lbc.isNginxReady = true
 		glog.V(3).Infof("NGINX is ready")
 	}
 }
 
+func (lbc *LoadBalancerController) syncNginxReload() {
+	glog.V(3).Infof("Applying the pending NGINX reload")
+
+	err := lbc.reloadCoalescer.ApplyPendingReload()
+	if err != nil {
+		glog.Errorf("Error when reloading NGINX to apply the pending changes: %v", err)
+	}
+}
+
+// syncCanaryStep regenerates the config of a VirtualServer, so that its canary releases move to the next step.
+func (lbc *LoadBalancerController) syncCanaryStep(task task) {
+	key := task.Key
+	glog.V(3).Infof("Syncing canary releases of VirtualServer %v", key)
+
+	for _, r := range lbc.configuration.GetResourcesWithFilter(resourceFilter{VirtualServers: true}) {
+		vsConfig := r.(*VirtualServerConfiguration)
+		if getResourceKey(&vsConfig.VirtualServer.ObjectMeta) != key {
+			continue
+		}
+
+		lbc.regenerateVirtualServer(vsConfig)
+
+		return
+	}
+
+	glog.V(3).Infof("VirtualServer %v no longer exists or is invalid, skipping canary releases", key)
+}
+
+// syncUpstreamAnalysis checks the upstreams of the routes with a rollback and rolls back the upstreams
+// that exceed the thresholds.
+func (lbc *LoadBalancerController) syncUpstreamAnalysis() {
+	glog.V(3).Infof("Analyzing upstreams of the routes with a rollback")
+
+	now := time.Now()
+
+	for _, r := range lbc.configuration.GetResourcesWithFilter(resourceFilter{VirtualServers: true}) {
+		vsConfig := r.(*VirtualServerConfiguration)
+
+		if lbc.analyzeUpstreams(vsConfig, now) {
+			lbc.regenerateVirtualServer(vsConfig)
+		}
+	}
+}
+
+// analyzeUpstreams rolls back the upstreams of the routes of the VirtualServer and its VirtualServerRoutes
+// that exceed the thresholds. It returns true if any upstream was rolled back.
+func (lbc *LoadBalancerController) analyzeUpstreams(vsConfig *VirtualServerConfiguration, now time.Time) bool {
+	vs := vsConfig.VirtualServer
+	key := getResourceKey(&vs.ObjectMeta)
+	rolledBack := false
+
+	analyzeRoute := func(route conf_v1.Route, getUpstreamName func(string) string) {
+		if route.Rollback == nil {
+			return
+		}
+
+		window := getRollbackWindow(route.Rollback)
+
+		for _, upstream := range getRollbackTargets(route.Splits, route.Canary) {
+			if lbc.upstreamRollbacks.isRolledBack(key, route.Path, upstream) {
+				continue
+			}
+
+			stats := lbc.upstreamStats.Get(getUpstreamName(upstream), window, now)
+
+			reason := checkRollback(route.Rollback, stats)
+			if reason == "" {
+				continue
+			}
+
+			glog.Warningf("Rolling back upstream %v of the route %v of VirtualServer %v: %v", upstream, route.Path, key, reason)
+			lbc.recorder.Eventf(vs, api_v1.EventTypeWarning, "RolledBack", "Upstream %v of the route %v was rolled back: %v", upstream, route.Path, reason)
+
+			lbc.upstreamRollbacks.rollBack(key, route.Path, upstream, reason, now)
+			rolledBack = true
+		}
+	}
+
+	for _, r := range vs.Spec.Routes {
+		analyzeRoute(r, func(upstream string) string {
+			return configs.GetUpstreamNameForVirtualServer(vs, upstream)
+		})
+	}
+
+	for _, vsr := range vsConfig.VirtualServerRoutes {
+		for _, r := range vsr.Spec.Subroutes {
+			analyzeRoute(r, func(upstream string) string {
+				return configs.GetUpstreamNameForVirtualServerRoute(vs, vsr, upstream)
+			})
+		}
+	}
+
+	return rolledBack
+}
+
+func (lbc *LoadBalancerController) syncOutlierDetection() {
+	glog.V(3).Infof("Checking peers of the upstreams with an outlier detection")
+
+	upstreams := getUpstreamsWithOutlierDetection(lbc.configuration.GetResourcesWithFilter(resourceFilter{
+		VirtualServers:   true,
+		TransportServers: true,
+	}))
+
+	actions := lbc.outlierDetector.retain(upstreams)
+
+	if len(upstreams) > 0 {
+		now := time.Now()
+
+		httpUpstreams, streamUpstreams, err := lbc.getUpstreamPeers(upstreams)
+		if err != nil {
+			glog.Errorf("Error getting the peers of the upstreams with an outlier detection: %v", err)
+		} else {
+			for _, name := range getSortedOutlierUpstreamNames(upstreams) {
+				u := upstreams[name]
+
+				var peers []peerCounters
+				if u.stream {
+					upstream, exists := streamUpstreams[name]
+					if !exists {
+						continue
+					}
+					peers = getStreamPeerCounters(upstream.Peers)
+				} else {
+					upstream, exists := httpUpstreams[name]
+					if !exists {
+						continue
+					}
+					peers = getPeerCounters(upstream.Peers)
+				}
+
+				actions = append(actions, lbc.outlierDetector.detect(name, u.stream, u.settings, peers, now)...)
+			}
+		}
+	}
+
+	for _, a := range actions {
+		lbc.applyOutlierAction(a, upstreams[a.upstream])
+	}
+}
+
+// getUpstreamPeers gets the HTTP and stream upstreams via the NGINX Plus API.
+// It only makes the requests for the kinds of the upstreams that have an outlier detection.
+func (lbc *LoadBalancerController) getUpstreamPeers(upstreams map[string]outlierUpstream) (client.Upstreams, client.StreamUpstreams, error) {
+	var hasHTTP, hasStream bool
+	for _, u := range upstreams {
+		if u.stream {
+			hasStream = true
+		} else {
+			hasHTTP = true
+		}
+	}
+
+	var httpUpstreams client.Upstreams
+	var streamUpstreams client.StreamUpstreams
+
+	if hasHTTP {
+		result, err := lbc.plusClient.GetUpstreams()
+		if err != nil {
+			return nil, nil, err
+		}
+		httpUpstreams = *result
+	}
+
+	if hasStream {
+		result, err := lbc.plusClient.GetStreamUpstreams()
+		if err != nil {
+			return nil, nil, err
+		}
+		streamUpstreams = *result
+	}
+
+	return httpUpstreams, streamUpstreams, nil
+}
+
+// applyOutlierAction updates the peer via the NGINX Plus API and updates the metrics of the peer.
+func (lbc *LoadBalancerController) applyOutlierAction(a outlierAction, u outlierUpstream) {
+	if a.kind == forgetPeer {
+		lbc.outlierDetectionCollector.DeletePeer(a.upstream, a.server)
+		return
+	}
+
+	down := a.kind != restorePeer
+
+	var err error
+	if a.stream {
+		err = lbc.plusClient.UpdateStreamServer(a.upstream, client.StreamUpstreamServer{ID: a.id, Server: a.server, Down: &down})
+	} else {
+		err = lbc.plusClient.UpdateHTTPServer(a.upstream, client.UpstreamServer{ID: a.id, Server: a.server, Down: &down})
+	}
+	if err != nil {
+		glog.Errorf("Error updating peer %v of upstream %v: %v", a.server, a.upstream, err)
+		return
+	}
+
+	switch a.kind {
+	case ejectPeer:
+		glog.Warningf("Ejected peer %v of upstream %v: %v", a.server, a.upstream, a.reason)
+		if u.resource != nil {
+			lbc.recorder.Eventf(u.resource, api_v1.EventTypeWarning, "Ejected", "Peer %v of upstream %v was ejected: %v", a.server, a.upstream, a.reason)
+		}
+		lbc.outlierDetectionCollector.SetPeerEjected(a.upstream, a.server, true)
+		lbc.outlierDetectionCollector.IncPeerEjections(a.upstream, a.server)
+	case reejectPeer:
+		glog.V(3).Infof("Ejected peer %v of upstream %v again", a.server, a.upstream)
+	case restorePeer:
+		glog.Infof("Restored peer %v of upstream %v", a.server, a.upstream)
+		lbc.outlierDetectionCollector.SetPeerEjected(a.upstream, a.server, false)
+	}
+}
+
+func getSortedOutlierUpstreamNames(upstreams map[string]outlierUpstream) []string {
+	var names []string
+	for name := range upstreams {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// regenerateVirtualServer regenerates and applies the config of a VirtualServer, which Configuration considers unchanged.
+func (lbc *LoadBalancerController) regenerateVirtualServer(vsConfig *VirtualServerConfiguration) {
+	vsEx := lbc.createVirtualServerEx(vsConfig.VirtualServer, vsConfig.VirtualServerRoutes)
+
+	warnings, addOrUpdateErr := lbc.configurator.AddOrUpdateVirtualServer(vsEx)
+	lbc.updateVirtualServerStatusAndEvents(vsConfig, warnings, addOrUpdateErr)
+}
+
+// getRollbackWarnings returns the warnings about the rolled back upstreams of a VirtualServer.
+func (lbc *LoadBalancerController) getRollbackWarnings(vsConfig *VirtualServerConfiguration) []string {
+	var messages []string
+
+	if lbc.upstreamStats == nil {
+		for _, r := range getRoutesWithRollback(vsConfig.VirtualServer, vsConfig.VirtualServerRoutes) {
+			messages = append(messages, fmt.Sprintf("rollback of the route %v is ignored because latency metrics are disabled", r.Path))
+		}
+		return messages
+	}
+
+	for _, s := range lbc.upstreamRollbacks.getStatuses(getResourceKey(&vsConfig.VirtualServer.ObjectMeta)) {
+		messages = append(messages, fmt.Sprintf("upstream %v of the route %v was rolled back: %v", s.Upstream, s.Path, s.Reason))
+	}
+
+	return messages
+}
+
+// updateRollouts reports the progress of the canary releases and the rolled back upstreams of a VirtualServer
+// in its status and schedules the sync for the next step of the canary releases.
+func (lbc *LoadBalancerController) updateRollouts(vs *conf_v1.VirtualServer) {
+	key := getResourceKey(&vs.ObjectMeta)
+
+	if lbc.reportCustomResourceStatusEnabled() {
+		err := lbc.statusUpdater.UpdateVirtualServerRolloutStatus(vs, lbc.canaryReleases.getStatuses(key), lbc.upstreamRollbacks.getStatuses(key))
+		if err != nil {
+			glog.Errorf("Error when updating the rollout status for VirtualServer %v: %v", key, err)
+		}
+	}
+
+	next := lbc.canaryReleases.getNextStepTime(key)
+	if next.IsZero() || !lbc.canaryReleases.needsSync(key, next) {
+		return
+	}
+
+	lbc.syncQueue.EnqueueTaskAfter(task{Kind: canaryStep, Key: key}, time.Until(next))
+}
+
+func (lbc *LoadBalancerController) hasPendingReload() bool {
+	return lbc.reloadCoalescer != nil && lbc.reloadCoalescer.HasPendingReload()
+}
+
 func (lbc *LoadBalancerController) syncIngressLink(task task) {
 	key := task.Key
 	glog.V(2).Infof("Adding, Updating or Deleting IngressLink: %v", key)
@@ -771,7 +1229,7 @@ func (lbc *LoadBalancerController) syncPolicy(task task) {
 
 	if polExists {
 		pol := obj.(*conf_v1.Policy)
-		err := validation.ValidatePolicy(pol, lbc.isNginxPlus, lbc.enablePreviewPolicies, lbc.appProtectEnabled)
+		err := validation.ValidatePolicy(pol, lbc.isNginxPlus, lbc.enablePreviewPolicies, lbc.appProtectEnabled, lbc.enableBrotli)
 		if err != nil {
 			msg := fmt.Sprintf("Policy %v/%v is invalid and was rejected: %v", pol.Namespace, pol.Name, err)
 			lbc.recorder.Eventf(pol, api_v1.EventTypeWarning, "Rejected", msg)
@@ -986,6 +1444,9 @@ func (lbc *LoadBalancerController) processChanges(changes []ResourceChange) {
 			case *VirtualServerConfiguration:
 				key := getResourceKey(&impl.VirtualServer.ObjectMeta)
 
+				lbc.canaryReleases.delete(key)
+				lbc.upstreamRollbacks.delete(key)
+
 				deleteErr := lbc.configurator.DeleteVirtualServer(key)
 				if deleteErr != nil {
 					glog.Errorf("Error when deleting configuration for VirtualServer %v: %v", key, deleteErr)
@@ -1036,6 +1497,11 @@ func (lbc *LoadBalancerController) processChanges(changes []ResourceChange) {
 			}
 		}
 	}
+
+	if lbc.isGatewayAPIEnabled && len(changes) > 0 {
+		// the hosts of the routes might have been taken or released by the changed resources
+		lbc.AddSyncQueueForGatewayAPI()
+	}
 }
 
 // processChangesFromGlobalConfiguration processes changes that come from updates to the GlobalConfiguration resource.
@@ -1476,6 +1942,13 @@ func (lbc *LoadBalancerController) updateVirtualServerStatusAndEvents(vsConfig *
 		state = conf_v1.StateWarning
 	}
 
+	if messages := lbc.getRollbackWarnings(vsConfig); len(messages) > 0 {
+		eventType = api_v1.EventTypeWarning
+		eventTitle = "AddedOrUpdatedWithWarning"
+		eventWarningMessage = fmt.Sprintf("%s; with warning(s): %v", eventWarningMessage, formatWarningMessages(messages))
+		state = conf_v1.StateWarning
+	}
+
 	if operationErr != nil {
 		eventType = api_v1.EventTypeWarning
 		eventTitle = "AddedOrUpdatedWithError"
@@ -1493,6 +1966,8 @@ func (lbc *LoadBalancerController) updateVirtualServerStatusAndEvents(vsConfig *
 		}
 	}
 
+	lbc.updateRollouts(vsConfig.VirtualServer)
+
 	for _, vsr := range vsConfig.VirtualServerRoutes {
 		vsrEventType := api_v1.EventTypeNormal
 		vsrEventTitle := "AddedOrUpdated"
@@ -1635,6 +2110,11 @@ func (lbc *LoadBalancerController) syncService(task task) {
 			}
 		}
 
+		if lbc.isGatewayAPIEnabled {
+			// the addresses of the Gateways are reported in their status
+			lbc.AddSyncQueueForGatewayAPI()
+		}
+
 		// we don't return here because technically the same service could be used in the second case
 	}
 
@@ -1645,6 +2125,14 @@ func (lbc *LoadBalancerController) syncService(task task) {
 
 	resources := lbc.configuration.FindResourcesForService(namespace, name)
 
+	if lbc.areCustomResourcesEnabled {
+		for _, pol := range lbc.getPoliciesForService(namespace, name) {
+			resources = append(resources, lbc.configuration.FindResourcesForPolicy(pol.Namespace, pol.Name)...)
+		}
+
+		resources = removeDuplicateResources(resources)
+	}
+
 	if len(resources) == 0 {
 		return
 	}
@@ -1726,6 +2214,9 @@ func (lbc *LoadBalancerController) syncSecret(task task) {
 		if lbc.isSpecialSecret(key) {
 			glog.Warningf("A special TLS Secret %v was removed. Retaining the Secret.", key)
 		}
+		if lbc.isGatewayAPIEnabled {
+			lbc.updateGatewayVirtualServersForSecret(key)
+		}
 		return
 	}
 
@@ -1743,6 +2234,10 @@ func (lbc *LoadBalancerController) syncSecret(task task) {
 	if len(resources) > 0 {
 		lbc.handleSecretUpdate(secret, resources)
 	}
+
+	if lbc.isGatewayAPIEnabled {
+		lbc.updateGatewayVirtualServersForSecret(key)
+	}
 }
 
 func removeDuplicateResources(resources []Resource) []Resource {
@@ -1917,7 +2412,7 @@ func (lbc *LoadBalancerController) updatePoliciesStatus() error {
 	for _, obj := range lbc.policyLister.List() {
 		pol := obj.(*conf_v1.Policy)
 
-		err := validation.ValidatePolicy(pol, lbc.isNginxPlus, lbc.enablePreviewPolicies, lbc.appProtectEnabled)
+		err := validation.ValidatePolicy(pol, lbc.isNginxPlus, lbc.enablePreviewPolicies, lbc.appProtectEnabled, lbc.enableBrotli)
 		if err != nil {
 			msg := fmt.Sprintf("Policy %v/%v is invalid and was rejected: %v", pol.Namespace, pol.Name, err)
 			err = lbc.statusUpdater.UpdatePolicyStatus(pol, conf_v1.StateInvalid, "Rejected", msg)
@@ -2061,32 +2556,33 @@ func (lbc *LoadBalancerController) createIngressEx(ing *networking.Ingress, vali
 	ingEx.ExternalNameSvcs = make(map[string]bool)
 	ingEx.PodsByIP = make(map[string]configs.PodInfo)
 
-	if ing.Spec.Backend != nil {
+	// the resource backends are not supported, so the configurator ignores them
+	if ing.Spec.DefaultBackend != nil && ing.Spec.DefaultBackend.Service != nil {
 		podEndps := []podEndpoint{}
 		var external bool
-		svc, err := lbc.getServiceForIngressBackend(ing.Spec.Backend, ing.Namespace)
+		svc, err := lbc.getServiceForIngressBackend(ing.Spec.DefaultBackend, ing.Namespace)
 		if err != nil {
-			glog.V(3).Infof("Error getting service %v: %v", ing.Spec.Backend.ServiceName, err)
+			glog.V(3).Infof("Error getting service %v: %v", ing.Spec.DefaultBackend.Service.Name, err)
 		} else {
-			podEndps, external, err = lbc.getEndpointsForIngressBackend(ing.Spec.Backend, svc)
+			podEndps, external, err = lbc.getEndpointsForIngressBackend(ing.Spec.DefaultBackend, svc)
 			if err == nil && external && lbc.isNginxPlus {
 				ingEx.ExternalNameSvcs[svc.Name] = true
 			}
 		}
 
 		if err != nil {
-			glog.Warningf("Error retrieving endpoints for the service %v: %v", ing.Spec.Backend.ServiceName, err)
+			glog.Warningf("Error retrieving endpoints for the service %v: %v", ing.Spec.DefaultBackend.Service.Name, err)
 		}
 
 		endps := getIPAddressesFromEndpoints(podEndps)
 
 		// endps is empty if there was any error before this point
-		ingEx.Endpoints[ing.Spec.Backend.ServiceName+ing.Spec.Backend.ServicePort.String()] = endps
+		ingEx.Endpoints[configs.GetKeyForIngressBackend(ing.Spec.DefaultBackend)] = endps
 
 		if lbc.isNginxPlus && lbc.isHealthCheckEnabled(ing) {
-			healthCheck := lbc.getHealthChecksForIngressBackend(ing.Spec.Backend, ing.Namespace)
+			healthCheck := lbc.getHealthChecksForIngressBackend(ing.Spec.DefaultBackend, ing.Namespace)
 			if healthCheck != nil {
-				ingEx.HealthChecks[ing.Spec.Backend.ServiceName+ing.Spec.Backend.ServicePort.String()] = healthCheck
+				ingEx.HealthChecks[configs.GetKeyForIngressBackend(ing.Spec.DefaultBackend)] = healthCheck
 			}
 		}
 
@@ -2118,10 +2614,15 @@ func (lbc *LoadBalancerController) createIngressEx(ing *networking.Ingress, vali
 				continue
 			}
 
+			if path.Backend.Service == nil {
+				glog.V(3).Infof("Skipping path %s with a resource backend for Ingress %s", path.Path, ing.Name)
+				continue
+			}
+
 			var external bool
 			svc, err := lbc.getServiceForIngressBackend(&path.Backend, ing.Namespace)
 			if err != nil {
-				glog.V(3).Infof("Error getting service %v: %v", &path.Backend.ServiceName, err)
+				glog.V(3).Infof("Error getting service %v: %v", path.Backend.Service.Name, err)
 			} else {
 				podEndps, external, err = lbc.getEndpointsForIngressBackend(&path.Backend, svc)
 				if err == nil && external && lbc.isNginxPlus {
@@ -2130,19 +2631,19 @@ func (lbc *LoadBalancerController) createIngressEx(ing *networking.Ingress, vali
 			}
 
 			if err != nil {
-				glog.Warningf("Error retrieving endpoints for the service %v: %v", path.Backend.ServiceName, err)
+				glog.Warningf("Error retrieving endpoints for the service %v: %v", path.Backend.Service.Name, err)
 			}
 
 			endps := getIPAddressesFromEndpoints(podEndps)
 
 			// endps is empty if there was any error before this point
-			ingEx.Endpoints[path.Backend.ServiceName+path.Backend.ServicePort.String()] = endps
+			ingEx.Endpoints[configs.GetKeyForIngressBackend(&path.Backend)] = endps
 
 			// Pull active health checks from k8 api
 			if lbc.isNginxPlus && lbc.isHealthCheckEnabled(ing) {
 				healthCheck := lbc.getHealthChecksForIngressBackend(&path.Backend, ing.Namespace)
 				if healthCheck != nil {
-					ingEx.HealthChecks[path.Backend.ServiceName+path.Backend.ServicePort.String()] = healthCheck
+					ingEx.HealthChecks[configs.GetKeyForIngressBackend(&path.Backend)] = healthCheck
 				}
 			}
 
@@ -2222,6 +2723,10 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 	if err != nil {
 		glog.Warningf("Error getting JWT secrets for VirtualServer %v/%v: %v", virtualServer.Namespace, virtualServer.Name, err)
 	}
+	err = lbc.addBasicAuthSecretRefs(virtualServerEx.SecretRefs, policies)
+	if err != nil {
+		glog.Warningf("Error getting basic auth secrets for VirtualServer %v/%v: %v", virtualServer.Namespace, virtualServer.Name, err)
+	}
 	err = lbc.addIngressMTLSSecretRefs(virtualServerEx.SecretRefs, policies)
 	if err != nil {
 		glog.Warningf("Error getting IngressMTLS secret for VirtualServer %v/%v: %v", virtualServer.Namespace, virtualServer.Name, err)
@@ -2241,54 +2746,58 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 	}
 
 	endpoints := make(map[string][]string)
+	drainingEndpoints := make(map[string][]string)
 	externalNameSvcs := make(map[string]bool)
 	podsByIP := make(map[string]configs.PodInfo)
 
 	for _, u := range virtualServer.Spec.Upstreams {
-		endpointsKey := configs.GenerateEndpointsKey(virtualServer.Namespace, u.Service, u.Subselector, u.Port)
-
-		var endps []string
-		if u.UseClusterIP {
-			s, err := lbc.getServiceForUpstream(virtualServer.Namespace, u.Service, u.Port)
-			if err != nil {
-				glog.Warningf("Error getting Service for Upstream %v: %v", u.Service, err)
-			} else {
-				endps = append(endps, fmt.Sprintf("%s:%d", s.Spec.ClusterIP, u.Port))
-			}
+		if u.Host != "" {
+			// the endpoints of an upstream with a host are resolved by NGINX
+			continue
+		}
 
-		} else {
-			var podEndps []podEndpoint
-			var err error
+		for _, subset := range configs.GetUpstreamEndpointsSubsets(virtualServer.Namespace, u) {
+			var endps []string
+			if u.UseClusterIP {
+				s, err := lbc.getServiceForUpstream(virtualServer.Namespace, u.Service, u.Port)
+				if err != nil {
+					glog.Warningf("Error getting Service for Upstream %v: %v", u.Service, err)
+				} else {
+					endps = append(endps, fmt.Sprintf("%s:%d", s.Spec.ClusterIP, u.Port))
+				}
 
-			if len(u.Subselector) > 0 {
-				podEndps, err = lbc.getEndpointsForSubselector(virtualServer.Namespace, u)
 			} else {
-				var external bool
-				podEndps, external, err = lbc.getEndpointsForUpstream(virtualServer.Namespace, u.Service, u.Port)
+				podEndps, external, err := lbc.getEndpointsForUpstreamSubset(virtualServer.Namespace, u, subset.Subselector)
 
 				if err == nil && external && lbc.isNginxPlus {
 					externalNameSvcs[configs.GenerateExternalNameSvcKey(virtualServer.Namespace, u.Service)] = true
 				}
-			}
 
-			if err != nil {
-				glog.Warningf("Error getting Endpoints for Upstream %v: %v", u.Name, err)
-			}
+				if err != nil {
+					glog.Warningf("Error getting Endpoints for Upstream %v: %v", u.Name, err)
+				}
 
-			endps = getIPAddressesFromEndpoints(podEndps)
+				endps = getIPAddressesFromEndpoints(podEndps)
 
-			if (lbc.isNginxPlus && lbc.isPrometheusEnabled) || lbc.isLatencyMetricsEnabled {
-				for _, endpoint := range podEndps {
-					podsByIP[endpoint.Address] = configs.PodInfo{
-						Name:         endpoint.PodName,
-						MeshPodOwner: endpoint.MeshPodOwner,
+				if drainTimeout := getDrainTimeout(u); drainTimeout > 0 && !external {
+					draining := lbc.getDrainingEndpointsForUpstreamSubset(virtualServer.Namespace, u, subset.Subselector, endps, drainTimeout)
+					if len(draining) > 0 {
+						drainingEndpoints[subset.EndpointsKey] = draining
 					}
 				}
-			}
-		}
 
-		endpoints[endpointsKey] = endps
+				if (lbc.isNginxPlus && lbc.isPrometheusEnabled) || lbc.isLatencyMetricsEnabled {
+					for _, endpoint := range podEndps {
+						podsByIP[endpoint.Address] = configs.PodInfo{
+							Name:         endpoint.PodName,
+							MeshPodOwner: endpoint.MeshPodOwner,
+						}
+					}
+				}
+			}
 
+			endpoints[subset.EndpointsKey] = endps
+		}
 	}
 
 	for _, r := range virtualServer.Spec.Routes {
@@ -2302,6 +2811,10 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 		if err != nil {
 			glog.Warningf("Error getting JWT secrets for VirtualServer %v/%v: %v", virtualServer.Namespace, virtualServer.Name, err)
 		}
+		err = lbc.addBasicAuthSecretRefs(virtualServerEx.SecretRefs, vsRoutePolicies)
+		if err != nil {
+			glog.Warningf("Error getting basic auth secrets for VirtualServer %v/%v: %v", virtualServer.Namespace, virtualServer.Name, err)
+		}
 		err = lbc.addEgressMTLSSecretRefs(virtualServerEx.SecretRefs, vsRoutePolicies)
 		if err != nil {
 			glog.Warningf("Error getting EgressMTLS secrets for VirtualServer %v/%v: %v", virtualServer.Namespace, virtualServer.Name, err)
@@ -2330,6 +2843,11 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 				glog.Warningf("Error getting JWT secrets for VirtualServerRoute %v/%v: %v", vsr.Namespace, vsr.Name, err)
 			}
 
+			err = lbc.addBasicAuthSecretRefs(virtualServerEx.SecretRefs, vsrSubroutePolicies)
+			if err != nil {
+				glog.Warningf("Error getting basic auth secrets for VirtualServerRoute %v/%v: %v", vsr.Namespace, vsr.Name, err)
+			}
+
 			err = lbc.addEgressMTLSSecretRefs(virtualServerEx.SecretRefs, vsrSubroutePolicies)
 			if err != nil {
 				glog.Warningf("Error getting EgressMTLS secrets for VirtualServerRoute %v/%v: %v", vsr.Namespace, vsr.Name, err)
@@ -2347,54 +2865,64 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 		}
 
 		for _, u := range vsr.Spec.Upstreams {
-			endpointsKey := configs.GenerateEndpointsKey(vsr.Namespace, u.Service, u.Subselector, u.Port)
+			if u.Host != "" {
+				// the endpoints of an upstream with a host are resolved by NGINX
+				continue
+			}
 
-			var endps []string
-			if u.UseClusterIP {
-				s, err := lbc.getServiceForUpstream(vsr.Namespace, u.Service, u.Port)
-				if err != nil {
-					glog.Warningf("Error getting Service for Upstream %v: %v", u.Service, err)
-				} else {
-					endps = append(endps, fmt.Sprintf("%s:%d", s.Spec.ClusterIP, u.Port))
-				}
+			for _, subset := range configs.GetUpstreamEndpointsSubsets(vsr.Namespace, u) {
+				var endps []string
+				if u.UseClusterIP {
+					s, err := lbc.getServiceForUpstream(vsr.Namespace, u.Service, u.Port)
+					if err != nil {
+						glog.Warningf("Error getting Service for Upstream %v: %v", u.Service, err)
+					} else {
+						endps = append(endps, fmt.Sprintf("%s:%d", s.Spec.ClusterIP, u.Port))
+					}
 
-			} else {
-				var podEndps []podEndpoint
-				var err error
-				if len(u.Subselector) > 0 {
-					podEndps, err = lbc.getEndpointsForSubselector(vsr.Namespace, u)
 				} else {
-					var external bool
-					podEndps, external, err = lbc.getEndpointsForUpstream(vsr.Namespace, u.Service, u.Port)
+					podEndps, external, err := lbc.getEndpointsForUpstreamSubset(vsr.Namespace, u, subset.Subselector)
 
 					if err == nil && external && lbc.isNginxPlus {
 						externalNameSvcs[configs.GenerateExternalNameSvcKey(vsr.Namespace, u.Service)] = true
 					}
-				}
-				if err != nil {
-					glog.Warningf("Error getting Endpoints for Upstream %v: %v", u.Name, err)
-				}
+					if err != nil {
+						glog.Warningf("Error getting Endpoints for Upstream %v: %v", u.Name, err)
+					}
 
-				endps = getIPAddressesFromEndpoints(podEndps)
+					endps = getIPAddressesFromEndpoints(podEndps)
 
-				if lbc.isNginxPlus || lbc.isLatencyMetricsEnabled {
-					for _, endpoint := range podEndps {
-						podsByIP[endpoint.Address] = configs.PodInfo{
-							Name:         endpoint.PodName,
-							MeshPodOwner: endpoint.MeshPodOwner,
+					if drainTimeout := getDrainTimeout(u); drainTimeout > 0 && !external {
+						draining := lbc.getDrainingEndpointsForUpstreamSubset(vsr.Namespace, u, subset.Subselector, endps, drainTimeout)
+						if len(draining) > 0 {
+							drainingEndpoints[subset.EndpointsKey] = draining
+						}
+					}
+
+					if lbc.isNginxPlus || lbc.isLatencyMetricsEnabled {
+						for _, endpoint := range podEndps {
+							podsByIP[endpoint.Address] = configs.PodInfo{
+								Name:         endpoint.PodName,
+								MeshPodOwner: endpoint.MeshPodOwner,
+							}
 						}
 					}
 				}
+				endpoints[subset.EndpointsKey] = endps
 			}
-			endpoints[endpointsKey] = endps
 		}
 	}
 
+	lbc.addExternalAuthEndpoints(endpoints, policies)
+
 	virtualServerEx.Endpoints = endpoints
+	virtualServerEx.DrainingEndpoints = drainingEndpoints
 	virtualServerEx.VirtualServerRoutes = virtualServerRoutes
 	virtualServerEx.ExternalNameSvcs = externalNameSvcs
 	virtualServerEx.Policies = createPolicyMap(policies)
 	virtualServerEx.PodsByIP = podsByIP
+	virtualServerEx.RolledBackUpstreams = lbc.upstreamRollbacks.update(virtualServer, virtualServerRoutes)
+	virtualServerEx.CanaryWeights = lbc.canaryReleases.update(virtualServer, virtualServerRoutes, virtualServerEx.RolledBackUpstreams, time.Now())
 
 	return &virtualServerEx
 }
@@ -2416,7 +2944,7 @@ func (lbc *LoadBalancerController) getAllPolicies() []*conf_v1.Policy {
 	for _, obj := range lbc.policyLister.List() {
 		pol := obj.(*conf_v1.Policy)
 
-		err := validation.ValidatePolicy(pol, lbc.isNginxPlus, lbc.enablePreviewPolicies, lbc.appProtectEnabled)
+		err := validation.ValidatePolicy(pol, lbc.isNginxPlus, lbc.enablePreviewPolicies, lbc.appProtectEnabled, lbc.enableBrotli)
 		if err != nil {
 			glog.V(3).Infof("Skipping invalid Policy %s/%s: %v", pol.Namespace, pol.Name, err)
 			continue
@@ -2453,7 +2981,7 @@ func (lbc *LoadBalancerController) getPolicies(policies []conf_v1.PolicyReferenc
 
 		policy := policyObj.(*conf_v1.Policy)
 
-		err = validation.ValidatePolicy(policy, lbc.isNginxPlus, lbc.enablePreviewPolicies, lbc.appProtectEnabled)
+		err = validation.ValidatePolicy(policy, lbc.isNginxPlus, lbc.enablePreviewPolicies, lbc.appProtectEnabled, lbc.enableBrotli)
 		if err != nil {
 			errors = append(errors, fmt.Errorf("Policy %s is invalid: %v", policyKey, err))
 			continue
@@ -2467,7 +2995,7 @@ func (lbc *LoadBalancerController) getPolicies(policies []conf_v1.PolicyReferenc
 
 func (lbc *LoadBalancerController) addJWTSecretRefs(secretRefs map[string]*secrets.SecretReference, policies []*conf_v1.Policy) error {
 	for _, pol := range policies {
-		if pol.Spec.JWTAuth == nil {
+		if pol.Spec.JWTAuth == nil || pol.Spec.JWTAuth.Secret == "" {
 			continue
 		}
 
@@ -2484,6 +3012,48 @@ func (lbc *LoadBalancerController) addJWTSecretRefs(secretRefs map[string]*secre
 	return nil
 }
 
+func (lbc *LoadBalancerController) addBasicAuthSecretRefs(secretRefs map[string]*secrets.SecretReference, policies []*conf_v1.Policy) error {
+	for _, pol := range policies {
+		if pol.Spec.BasicAuth == nil {
+			continue
+		}
+
+		secretKey := fmt.Sprintf("%v/%v", pol.Namespace, pol.Spec.BasicAuth.Secret)
+		secretRef := lbc.secretStore.GetSecret(secretKey)
+
+		secretRefs[secretKey] = secretRef
+
+		if secretRef.Error != nil {
+			return secretRef.Error
+		}
+	}
+
+	return nil
+}
+
+// addExternalAuthEndpoints adds the endpoints of the Services of the external auth policies.
+func (lbc *LoadBalancerController) addExternalAuthEndpoints(endpoints map[string][]string, policies []*conf_v1.Policy) {
+	for _, pol := range policies {
+		if pol.Spec.ExternalAuth == nil || pol.Spec.ExternalAuth.AuthServiceName == "" {
+			continue
+		}
+
+		svc := pol.Spec.ExternalAuth.AuthServiceName
+		port := pol.Spec.ExternalAuth.AuthServicePort
+		endpointsKey := configs.GenerateEndpointsKey(pol.Namespace, svc, nil, port)
+		if _, exists := endpoints[endpointsKey]; exists {
+			continue
+		}
+
+		podEndps, _, err := lbc.getEndpointsForUpstream(pol.Namespace, svc, port)
+		if err != nil {
+			glog.Warningf("Error getting Endpoints for the auth service %v/%v of Policy %v/%v: %v", pol.Namespace, svc, pol.Namespace, pol.Name, err)
+		}
+
+		endpoints[endpointsKey] = getIPAddressesFromEndpoints(podEndps)
+	}
+}
+
 func (lbc *LoadBalancerController) addIngressMTLSSecretRefs(secretRefs map[string]*secrets.SecretReference, policies []*conf_v1.Policy) error {
 	for _, pol := range policies {
 		if pol.Spec.IngressMTLS == nil {
@@ -2533,7 +3103,7 @@ func (lbc *LoadBalancerController) addEgressMTLSSecretRefs(secretRefs map[string
 
 func (lbc *LoadBalancerController) addOIDCSecretRefs(secretRefs map[string]*secrets.SecretReference, policies []*conf_v1.Policy) error {
 	for _, pol := range policies {
-		if pol.Spec.OIDC == nil {
+		if pol.Spec.OIDC == nil || pol.Spec.OIDC.ClientSecret == "" {
 			continue
 		}
 
@@ -2601,6 +3171,8 @@ func findPoliciesForSecret(policies []*conf_v1.Policy, secretNamespace string, s
 			res = append(res, pol)
 		} else if pol.Spec.JWTAuth != nil && pol.Spec.JWTAuth.Secret == secretName && pol.Namespace == secretNamespace {
 			res = append(res, pol)
+		} else if pol.Spec.BasicAuth != nil && pol.Spec.BasicAuth.Secret == secretName && pol.Namespace == secretNamespace {
+			res = append(res, pol)
 		} else if pol.Spec.EgressMTLS != nil && pol.Spec.EgressMTLS.TLSSecret == secretName && pol.Namespace == secretNamespace {
 			res = append(res, pol)
 		} else if pol.Spec.EgressMTLS != nil && pol.Spec.EgressMTLS.TrustedCertSecret == secretName && pol.Namespace == secretNamespace {
@@ -2613,6 +3185,22 @@ func findPoliciesForSecret(policies []*conf_v1.Policy, secretNamespace string, s
 	return res
 }
 
+func (lbc *LoadBalancerController) getPoliciesForService(svcNamespace string, svcName string) []*conf_v1.Policy {
+	return findPoliciesForService(lbc.getAllPolicies(), svcNamespace, svcName)
+}
+
+func findPoliciesForService(policies []*conf_v1.Policy, svcNamespace string, svcName string) []*conf_v1.Policy {
+	var res []*conf_v1.Policy
+
+	for _, pol := range policies {
+		if pol.Spec.ExternalAuth != nil && pol.Spec.ExternalAuth.AuthServiceName == svcName && pol.Namespace == svcNamespace {
+			res = append(res, pol)
+		}
+	}
+
+	return res
+}
+
 func getWAFPoliciesForAppProtectPolicy(pols []*conf_v1.Policy, key string) []*conf_v1.Policy {
 	var policies []*conf_v1.Policy
 
@@ -2686,10 +3274,7 @@ func (lbc *LoadBalancerController) getEndpointsForUpstream(namespace string, ups
 		return nil, false, fmt.Errorf("Error getting service %v: %v", upstreamService, err)
 	}
 
-	backend := &networking.IngressBackend{
-		ServiceName: upstreamService,
-		ServicePort: intstr.FromInt(int(upstreamPort)),
-	}
+	backend := createIngressBackendForUpstream(upstreamService, upstreamPort)
 
 	endps, isExternal, err = lbc.getEndpointsForIngressBackend(backend, svc)
 	if err != nil {
@@ -2699,26 +3284,34 @@ func (lbc *LoadBalancerController) getEndpointsForUpstream(namespace string, ups
 	return endps, isExternal, err
 }
 
+// getEndpointsForUpstreamSubset returns the endpoints of the pods of the upstream selected by the subselector.
+// If the upstream has the topology aware routing, it returns the endpoints in the zone of the Ingress Controller
+// when the zone has enough healthy capacity.
+func (lbc *LoadBalancerController) getEndpointsForUpstreamSubset(namespace string, upstream conf_v1.Upstream, subselector map[string]string) (endps []podEndpoint, isExternal bool, err error) {
+	if len(subselector) > 0 {
+		subsetUpstream := upstream
+		subsetUpstream.Subselector = subselector
+		endps, err = lbc.getEndpointsForSubselector(namespace, subsetUpstream)
+	} else {
+		endps, isExternal, err = lbc.getEndpointsForUpstream(namespace, upstream.Service, upstream.Port)
+	}
+
+	if err == nil && lbc.isTopologyAwareRoutingEnabled && configs.IsTopologyAwareRoutingEnabled(upstream) {
+		endps = lbc.selectEndpointsInZone(endps, configs.GetTopologyMinHealthyPercent(upstream.TopologyAwareRouting))
+	}
+
+	return endps, isExternal, err
+}
+
 func (lbc *LoadBalancerController) getEndpointsForSubselector(namespace string, upstream conf_v1.Upstream) (endps []podEndpoint, err error) {
 	svc, err := lbc.getServiceForUpstream(namespace, upstream.Service, upstream.Port)
 	if err != nil {
 		return nil, fmt.Errorf("Error getting service %v: %v", upstream.Service, err)
 	}
 
-	var targetPort int32
-
-	for _, port := range svc.Spec.Ports {
-		if port.Port == int32(upstream.Port) {
-			targetPort, err = lbc.getTargetPort(&port, svc)
-			if err != nil {
-				return nil, fmt.Errorf("Error determining target port for port %v in service %v: %v", upstream.Port, svc.Name, err)
-			}
-			break
-		}
-	}
-
-	if targetPort == 0 {
-		return nil, fmt.Errorf("No port %v in service %s", upstream.Port, svc.Name)
+	targetPort, err := lbc.getTargetPortForUpstream(svc, upstream.Port)
+	if err != nil {
+		return nil, err
 	}
 
 	endps, err = lbc.getEndpointsForServiceWithSubselector(targetPort, upstream.Subselector, svc)
@@ -2729,50 +3322,155 @@ func (lbc *LoadBalancerController) getEndpointsForSubselector(namespace string,
 	return endps, err
 }
 
+func (lbc *LoadBalancerController) getTargetPortForUpstream(svc *api_v1.Service, upstreamPort uint16) (int32, error) {
+	for _, port := range svc.Spec.Ports {
+		if port.Port == int32(upstreamPort) {
+			targetPort, err := lbc.getTargetPort(&port, svc)
+			if err != nil {
+				return 0, fmt.Errorf("Error determining target port for port %v in service %v: %v", upstreamPort, svc.Name, err)
+			}
+			return targetPort, nil
+		}
+	}
+
+	return 0, fmt.Errorf("No port %v in service %s", upstreamPort, svc.Name)
+}
+
 func (lbc *LoadBalancerController) getEndpointsForServiceWithSubselector(targetPort int32, subselector map[string]string, svc *api_v1.Service) (endps []podEndpoint, err error) {
 	pods, err := lbc.podLister.ListByNamespace(svc.Namespace, labels.Merge(svc.Spec.Selector, subselector).AsSelector())
 	if err != nil {
 		return nil, fmt.Errorf("Error getting pods in namespace %v that match the selector %v: %v", svc.Namespace, labels.Merge(svc.Spec.Selector, subselector), err)
 	}
 
-	svcEps, err := lbc.endpointLister.GetServiceEndpoints(svc)
+	slices, err := lbc.endpointSliceLister.GetServiceEndpointSlices(svc)
 	if err != nil {
-		glog.V(3).Infof("Error getting endpoints for service %s from the cache: %v", svc.Name, err)
+		glog.V(3).Infof("Error getting endpoint slices for service %s from the cache: %v", svc.Name, err)
 		return nil, err
 	}
 
-	endps = getEndpointsBySubselectedPods(targetPort, pods, svcEps)
+	endps = getEndpointsBySubselectedPods(targetPort, pods, slices, getServiceAddressType(svc))
 	return endps, nil
 }
 
-func getEndpointsBySubselectedPods(targetPort int32, pods []*api_v1.Pod, svcEps api_v1.Endpoints) (endps []podEndpoint) {
+func getEndpointsBySubselectedPods(targetPort int32, pods []*api_v1.Pod, slices []discovery_v1.EndpointSlice, addressType discovery_v1.AddressType) (endps []podEndpoint) {
+	sliceEndps, _ := getEndpointsFromSlices(slices, targetPort, addressType)
+
 	for _, pod := range pods {
-		for _, subset := range svcEps.Subsets {
-			for _, port := range subset.Ports {
-				if port.Port != targetPort {
-					continue
-				}
-				for _, address := range subset.Addresses {
-					if address.IP == pod.Status.PodIP {
-						addr := fmt.Sprintf("%v:%v", pod.Status.PodIP, targetPort)
-						ownerType, ownerName := getPodOwnerTypeAndName(pod)
-						podEnd := podEndpoint{
-							Address: addr,
-							PodName: getPodName(address.TargetRef),
-							MeshPodOwner: configs.MeshPodOwner{
-								OwnerType: ownerType,
-								OwnerName: ownerName,
-							},
-						}
-						endps = append(endps, podEnd)
-					}
-				}
+		for _, e := range sliceEndps {
+			if e.Addresses[0] != pod.Status.PodIP {
+				continue
 			}
+
+			ownerType, ownerName := getPodOwnerTypeAndName(pod)
+			podEnd := newPodEndpoint(e, targetPort)
+			podEnd.NodeName = pod.Spec.NodeName
+			podEnd.MeshPodOwner = configs.MeshPodOwner{
+				OwnerType: ownerType,
+				OwnerName: ownerName,
+			}
+			endps = append(endps, podEnd)
 		}
 	}
 	return endps
 }
 
+// getEndpointsFromSlices returns the endpoints with the target port and the address type from the EndpointSlices
+// of a service. It returns the ready endpoints or, if there are no ready endpoints, the serving endpoints that are
+// terminating, so that the requests are not dropped while all pods of the service are terminating. The second return
+// value tells if any of the EndpointSlices has the target port.
+func getEndpointsFromSlices(slices []discovery_v1.EndpointSlice, targetPort int32, addressType discovery_v1.AddressType) ([]discovery_v1.Endpoint, bool) {
+	var ready, terminating []discovery_v1.Endpoint
+
+	portFound := forEachEndpointInSlices(slices, targetPort, addressType, func(e discovery_v1.Endpoint) {
+		if isEndpointReady(e) {
+			ready = append(ready, e)
+		} else if isEndpointServingTerminating(e) {
+			terminating = append(terminating, e)
+		}
+	})
+
+	if len(ready) > 0 {
+		return ready, portFound
+	}
+
+	return terminating, portFound
+}
+
+// forEachEndpointInSlices calls fn for every endpoint with the target port and the address type in the EndpointSlices
+// of a service. An endpoint can be temporarily present in several slices, but fn is called only once per address.
+// It returns false if none of the EndpointSlices has the target port.
+func forEachEndpointInSlices(slices []discovery_v1.EndpointSlice, targetPort int32, addressType discovery_v1.AddressType, fn func(e discovery_v1.Endpoint)) bool {
+	seen := make(map[string]bool)
+	portFound := false
+
+	for _, slice := range slices {
+		if slice.AddressType != addressType || !hasEndpointSlicePort(slice, targetPort) {
+			continue
+		}
+		portFound = true
+
+		for _, e := range slice.Endpoints {
+			if len(e.Addresses) == 0 || seen[e.Addresses[0]] {
+				continue
+			}
+			seen[e.Addresses[0]] = true
+
+			fn(e)
+		}
+	}
+
+	return portFound
+}
+
+func hasEndpointSlicePort(slice discovery_v1.EndpointSlice, targetPort int32) bool {
+	for _, port := range slice.Ports {
+		if port.Port != nil && *port.Port == targetPort {
+			return true
+		}
+	}
+	return false
+}
+
+// isEndpointReady tells if the endpoint is ready. A nil ready condition means an unknown state, which must be
+// interpreted as ready.
+func isEndpointReady(e discovery_v1.Endpoint) bool {
+	return e.Conditions.Ready == nil || *e.Conditions.Ready
+}
+
+func isEndpointServingTerminating(e discovery_v1.Endpoint) bool {
+	serving := e.Conditions.Serving != nil && *e.Conditions.Serving
+	terminating := e.Conditions.Terminating != nil && *e.Conditions.Terminating
+	return serving && terminating
+}
+
+// getServiceAddressType returns the address type of the EndpointSlices of the primary IP family of the service.
+func getServiceAddressType(svc *api_v1.Service) discovery_v1.AddressType {
+	if len(svc.Spec.IPFamilies) > 0 && svc.Spec.IPFamilies[0] == api_v1.IPv6Protocol {
+		return discovery_v1.AddressTypeIPv6
+	}
+	return discovery_v1.AddressTypeIPv4
+}
+
+// newPodEndpoint creates a podEndpoint for the endpoint of an EndpointSlice without the owner of the pod.
+func newPodEndpoint(e discovery_v1.Endpoint, port int32) podEndpoint {
+	podEnd := podEndpoint{
+		Address: fmt.Sprintf("%v:%v", e.Addresses[0], port),
+		PodName: getPodName(e.TargetRef),
+	}
+	if e.NodeName != nil {
+		podEnd.NodeName = *e.NodeName
+	}
+	if e.Zone != nil {
+		podEnd.Zone = *e.Zone
+	}
+	if e.Hints != nil {
+		for _, z := range e.Hints.ForZones {
+			podEnd.ZoneHints = append(podEnd.ZoneHints, z.Name)
+		}
+	}
+	return podEnd
+}
+
 func getPodName(pod *api_v1.ObjectReference) string {
 	if pod != nil {
 		return pod.Name
@@ -2783,10 +3481,10 @@ func getPodName(pod *api_v1.ObjectReference) string {
 func (lbc *LoadBalancerController) getHealthChecksForIngressBackend(backend *networking.IngressBackend, namespace string) *api_v1.Probe {
 	svc, err := lbc.getServiceForIngressBackend(backend, namespace)
 	if err != nil {
-		glog.V(3).Infof("Error getting service %v: %v", backend.ServiceName, err)
+		glog.V(3).Infof("Error getting service %v: %v", backend.Service.Name, err)
 		return nil
 	}
-	svcPort := lbc.getServicePortForIngressPort(backend.ServicePort, svc)
+	svcPort := lbc.getServicePortForIngressPort(backend.Service.Port, svc)
 	if svcPort == nil {
 		return nil
 	}
@@ -2830,7 +3528,13 @@ func compareContainerPortAndServicePort(containerPort api_v1.ContainerPort, svcP
 }
 
 func (lbc *LoadBalancerController) getExternalEndpointsForIngressBackend(backend *networking.IngressBackend, svc *api_v1.Service) []podEndpoint {
-	address := fmt.Sprintf("%s:%d", svc.Spec.ExternalName, int32(backend.ServicePort.IntValue()))
+	port := backend.Service.Port.Number
+	// a named port of an ExternalName service is resolved using the ports of the service
+	if svcPort := lbc.getServicePortForIngressPort(backend.Service.Port, svc); svcPort != nil {
+		port = svcPort.Port
+	}
+
+	address := fmt.Sprintf("%s:%d", svc.Spec.ExternalName, port)
 	endpoints := []podEndpoint{
 		{
 			Address: address,
@@ -2841,7 +3545,7 @@ func (lbc *LoadBalancerController) getExternalEndpointsForIngressBackend(backend
 }
 
 func (lbc *LoadBalancerController) getEndpointsForIngressBackend(backend *networking.IngressBackend, svc *api_v1.Service) (result []podEndpoint, isExternal bool, err error) {
-	endps, err := lbc.endpointLister.GetServiceEndpoints(svc)
+	slices, err := lbc.endpointSliceLister.GetServiceEndpointSlices(svc)
 	if err != nil {
 		if svc.Spec.Type == api_v1.ServiceTypeExternalName {
 			if !lbc.isNginxPlus {
@@ -2850,59 +3554,49 @@ func (lbc *LoadBalancerController) getEndpointsForIngressBackend(backend *networ
 			result = lbc.getExternalEndpointsForIngressBackend(backend, svc)
 			return result, true, nil
 		}
-		glog.V(3).Infof("Error getting endpoints for service %s from the cache: %v", svc.Name, err)
+		glog.V(3).Infof("Error getting endpoint slices for service %s from the cache: %v", svc.Name, err)
 		return nil, false, err
 	}
 
-	result, err = lbc.getEndpointsForPort(endps, backend.ServicePort, svc)
+	result, err = lbc.getEndpointsForPort(slices, backend.Service.Port, svc)
 	if err != nil {
-		glog.V(3).Infof("Error getting endpoints for service %s port %v: %v", svc.Name, backend.ServicePort, err)
+		glog.V(3).Infof("Error getting endpoints for service %s port %v: %v", svc.Name, configs.GetBackendPortAsString(backend.Service.Port), err)
 		return nil, false, err
 	}
 	return result, false, nil
 }
 
-func (lbc *LoadBalancerController) getEndpointsForPort(endps api_v1.Endpoints, ingSvcPort intstr.IntOrString, svc *api_v1.Service) ([]podEndpoint, error) {
+func (lbc *LoadBalancerController) getEndpointsForPort(slices []discovery_v1.EndpointSlice, backendPort networking.ServiceBackendPort, svc *api_v1.Service) ([]podEndpoint, error) {
 	var targetPort int32
 	var err error
 
-	for _, port := range svc.Spec.Ports {
-		if (ingSvcPort.Type == intstr.Int && port.Port == int32(ingSvcPort.IntValue())) || (ingSvcPort.Type == intstr.String && port.Name == ingSvcPort.String()) {
-			targetPort, err = lbc.getTargetPort(&port, svc)
-			if err != nil {
-				return nil, fmt.Errorf("Error determining target port for port %v in Ingress: %v", ingSvcPort, err)
-			}
-			break
+	if svcPort := lbc.getServicePortForIngressPort(backendPort, svc); svcPort != nil {
+		targetPort, err = lbc.getTargetPort(svcPort, svc)
+		if err != nil {
+			return nil, fmt.Errorf("Error determining target port for port %v in Ingress: %v", configs.GetBackendPortAsString(backendPort), err)
 		}
 	}
 
 	if targetPort == 0 {
-		return nil, fmt.Errorf("No port %v in service %s", ingSvcPort, svc.Name)
+		return nil, fmt.Errorf("No port %v in service %s", configs.GetBackendPortAsString(backendPort), svc.Name)
 	}
 
-	for _, subset := range endps.Subsets {
-		for _, port := range subset.Ports {
-			if port.Port == targetPort {
-				var endpoints []podEndpoint
-				for _, address := range subset.Addresses {
-					addr := fmt.Sprintf("%v:%v", address.IP, port.Port)
-					podEnd := podEndpoint{
-						Address: addr,
-					}
-					if address.TargetRef != nil {
-						parentType, parentName := lbc.getPodOwnerTypeAndNameFromAddress(address.TargetRef.Namespace, address.TargetRef.Name)
-						podEnd.OwnerType = parentType
-						podEnd.OwnerName = parentName
-						podEnd.PodName = address.TargetRef.Name
-					}
-					endpoints = append(endpoints, podEnd)
-				}
-				return endpoints, nil
-			}
-		}
+	sliceEndps, portFound := getEndpointsFromSlices(slices, targetPort, getServiceAddressType(svc))
+	if !portFound {
+		return nil, fmt.Errorf("No endpoints for target port %v in service %s", targetPort, svc.Name)
 	}
 
-	return nil, fmt.Errorf("No endpoints for target port %v in service %s", targetPort, svc.Name)
+	var endpoints []podEndpoint
+	for _, e := range sliceEndps {
+		podEnd := newPodEndpoint(e, targetPort)
+		if e.TargetRef != nil {
+			parentType, parentName := lbc.getPodOwnerTypeAndNameFromAddress(e.TargetRef.Namespace, e.TargetRef.Name)
+			podEnd.OwnerType = parentType
+			podEnd.OwnerName = parentName
+		}
+		endpoints = append(endpoints, podEnd)
+	}
+	return endpoints, nil
 }
 
 func (lbc *LoadBalancerController) getPodOwnerTypeAndNameFromAddress(ns, name string) (parentType, parentName string) {
@@ -2934,9 +3628,11 @@ func getPodOwnerTypeAndName(pod *api_v1.Pod) (parentType, parentName string) {
 	return parentType, parentName
 }
 
-func (lbc *LoadBalancerController) getServicePortForIngressPort(ingSvcPort intstr.IntOrString, svc *api_v1.Service) *api_v1.ServicePort {
+// getServicePortForIngressPort returns the port of the service referenced by the port of an Ingress backend either
+// by its name or by its number.
+func (lbc *LoadBalancerController) getServicePortForIngressPort(backendPort networking.ServiceBackendPort, svc *api_v1.Service) *api_v1.ServicePort {
 	for _, port := range svc.Spec.Ports {
-		if (ingSvcPort.Type == intstr.Int && port.Port == int32(ingSvcPort.IntValue())) || (ingSvcPort.Type == intstr.String && port.Name == ingSvcPort.String()) {
+		if (backendPort.Name == "" && port.Port == backendPort.Number) || (backendPort.Name != "" && port.Name == backendPort.Name) {
 			return &port
 		}
 	}
@@ -2972,15 +3668,25 @@ func (lbc *LoadBalancerController) getTargetPort(svcPort *api_v1.ServicePort, sv
 }
 
 func (lbc *LoadBalancerController) getServiceForUpstream(namespace string, upstreamService string, upstreamPort uint16) (*api_v1.Service, error) {
-	backend := &networking.IngressBackend{
-		ServiceName: upstreamService,
-		ServicePort: intstr.FromInt(int(upstreamPort)),
-	}
+	backend := createIngressBackendForUpstream(upstreamService, upstreamPort)
 	return lbc.getServiceForIngressBackend(backend, namespace)
 }
 
+// createIngressBackendForUpstream creates an Ingress backend for the service and port of an upstream, so that the
+// endpoints of the upstream are found the same way as the endpoints of an Ingress backend.
+func createIngressBackendForUpstream(upstreamService string, upstreamPort uint16) *networking.IngressBackend {
+	return &networking.IngressBackend{
+		Service: &networking.IngressServiceBackend{
+			Name: upstreamService,
+			Port: networking.ServiceBackendPort{
+				Number: int32(upstreamPort),
+			},
+		},
+	}
+}
+
 func (lbc *LoadBalancerController) getServiceForIngressBackend(backend *networking.IngressBackend, namespace string) (*api_v1.Service, error) {
-	svcKey := namespace + "/" + backend.ServiceName
+	svcKey := namespace + "/" + backend.Service.Name
 	svcObj, svcExists, err := lbc.svcLister.GetByKey(svcKey)
 	if err != nil {
 		return nil, err
@@ -3007,24 +3713,47 @@ func (lbc *LoadBalancerController) HasCorrectIngressClass(obj interface{}) bool
 	case *networking.Ingress:
 		isIngress = true
 		class = obj.Annotations[ingressClassKey]
-		if class == "" && obj.Spec.IngressClassName != nil {
-			class = *obj.Spec.IngressClassName
-		} else {
+		if class != "" {
 			// the annotation takes precedence over the field
 			glog.Warningln("Using the DEPRECATED annotation 'kubernetes.io/ingress.class'. The 'ingressClassName' field will be ignored.")
+		} else if obj.Spec.IngressClassName != nil {
+			class = *obj.Spec.IngressClassName
 		}
 
 	default:
 		return false
 	}
 
-	// useIngressClassOnly only applies for Ingress resources
-	if lbc.useIngressClassOnly && isIngress {
-		return class == lbc.ingressClass
+	// an Ingress without the class belongs to the default IngressClass
+	if isIngress && class == "" {
+		return lbc.isDefaultIngressClass()
 	}
 	return class == lbc.ingressClass || class == ""
 }
 
+// isDefaultIngressClass checks if the IngressClass of the Ingress Controller is marked as the default class.
+func (lbc *LoadBalancerController) isDefaultIngressClass() bool {
+	if lbc.ingressClassLister == nil {
+		return false
+	}
+
+	obj, exists, err := lbc.ingressClassLister.GetByKey(lbc.ingressClass)
+	if err != nil || !exists {
+		return false
+	}
+
+	ingressClass := obj.(*networking.IngressClass)
+	return ingressClass.Annotations[isDefaultIngressClassKey] == "true"
+}
+
+// syncIngressesForIngressClass enqueues all Ingress resources, so that the Ingress resources without the class are
+// added or deleted when the IngressClass of the Ingress Controller becomes or stops being the default class.
+func (lbc *LoadBalancerController) syncIngressesForIngressClass() {
+	for _, obj := range lbc.ingressLister.Store.List() {
+		lbc.AddSyncQueue(obj)
+	}
+}
+
 // isHealthCheckEnabled checks if health checks are enabled so we can only query pods if enabled.
 func (lbc *LoadBalancerController) isHealthCheckEnabled(ing *networking.Ingress) bool {
 	if healthCheckEnabled, exists, err := configs.GetMapKeyAsBool(ing.Annotations, "nginx.com/health-checks", ing); exists {
@@ -20,7 +20,8 @@ import (
 	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
 	api_v1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
-	networking "k8s.io/api/networking/v1beta1"
+	discovery_v1 "k8s.io/api/discovery/v1"
+	networking "k8s.io/api/networking/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -29,203 +30,174 @@ import (
 	"k8s.io/client-go/tools/cache"
 )
 
+func createTestIngressClassLister(name string, isDefault bool) cache.Store {
+	ingressClass := &networking.IngressClass{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name: name,
+		},
+	}
+	if isDefault {
+		ingressClass.Annotations = map[string]string{isDefaultIngressClassKey: "true"}
+	}
+
+	lister := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	_ = lister.Add(ingressClass)
+
+	return lister
+}
+
 func TestHasCorrectIngressClass(t *testing.T) {
 	ingressClass := "ing-ctrl"
 	incorrectIngressClass := "gce"
 	emptyClass := ""
 
-	testsWithoutIngressClassOnly := []struct {
+	ctrl := &LoadBalancerController{
+		ingressClass:       ingressClass,
+		ingressClassLister: createTestIngressClassLister(ingressClass, false),
+		metricsCollector:   collectors.NewControllerFakeCollector(),
+	}
+
+	ctrlWithDefaultClass := &LoadBalancerController{
+		ingressClass:       ingressClass,
+		ingressClassLister: createTestIngressClassLister(ingressClass, true),
+		metricsCollector:   collectors.NewControllerFakeCollector(),
+	}
+
+	tests := []struct {
 		lbc      *LoadBalancerController
 		ing      *networking.Ingress
 		expected bool
+		msg      string
 	}{
 		{
-			&LoadBalancerController{
-				ingressClass:        ingressClass,
-				useIngressClassOnly: false,
-				metricsCollector:    collectors.NewControllerFakeCollector(),
-			},
-			&networking.Ingress{
+			lbc: ctrl,
+			ing: &networking.Ingress{
 				ObjectMeta: meta_v1.ObjectMeta{
 					Annotations: map[string]string{ingressClassKey: emptyClass},
 				},
 			},
-			true,
+			expected: false,
+			msg:      "empty class annotation",
 		},
 		{
-			&LoadBalancerController{
-				ingressClass:        ingressClass,
-				useIngressClassOnly: false,
-				metricsCollector:    collectors.NewControllerFakeCollector(),
-			},
-			&networking.Ingress{
+			lbc: ctrl,
+			ing: &networking.Ingress{
 				ObjectMeta: meta_v1.ObjectMeta{
 					Annotations: map[string]string{ingressClassKey: incorrectIngressClass},
 				},
 			},
-			false,
+			expected: false,
+			msg:      "incorrect class annotation",
 		},
 		{
-			&LoadBalancerController{
-				ingressClass:        ingressClass,
-				useIngressClassOnly: false,
-				metricsCollector:    collectors.NewControllerFakeCollector(),
-			},
-			&networking.Ingress{
+			lbc: ctrl,
+			ing: &networking.Ingress{
 				ObjectMeta: meta_v1.ObjectMeta{
 					Annotations: map[string]string{ingressClassKey: ingressClass},
 				},
 			},
-			true,
+			expected: true,
+			msg:      "correct class annotation",
 		},
 		{
-			&LoadBalancerController{
-				ingressClass:        ingressClass,
-				useIngressClassOnly: false,
-				metricsCollector:    collectors.NewControllerFakeCollector(),
-			},
-			&networking.Ingress{
+			lbc: ctrl,
+			ing: &networking.Ingress{
 				ObjectMeta: meta_v1.ObjectMeta{
 					Annotations: map[string]string{},
 				},
 			},
-			true,
+			expected: false,
+			msg:      "no class",
 		},
-	}
-
-	testsWithIngressClassOnly := []struct {
-		lbc      *LoadBalancerController
-		ing      *networking.Ingress
-		expected bool
-	}{
 		{
-			&LoadBalancerController{
-				ingressClass:        ingressClass,
-				useIngressClassOnly: true,
-				metricsCollector:    collectors.NewControllerFakeCollector(),
-			},
-			&networking.Ingress{
-				ObjectMeta: meta_v1.ObjectMeta{
-					Annotations: map[string]string{ingressClassKey: emptyClass},
+			lbc: ctrl,
+			ing: &networking.Ingress{
+				Spec: networking.IngressSpec{
+					IngressClassName: &incorrectIngressClass,
 				},
 			},
-			false,
+			expected: false,
+			msg:      "incorrect ingressClassName",
 		},
 		{
-			&LoadBalancerController{
-				ingressClass:        ingressClass,
-				useIngressClassOnly: true,
-				metricsCollector:    collectors.NewControllerFakeCollector(),
+			lbc: ctrl,
+			ing: &networking.Ingress{
+				Spec: networking.IngressSpec{
+					IngressClassName: &emptyClass,
+				},
 			},
-			&networking.Ingress{
+			expected: false,
+			msg:      "empty ingressClassName",
+		},
+		{
+			lbc: ctrl,
+			ing: &networking.Ingress{
 				ObjectMeta: meta_v1.ObjectMeta{
 					Annotations: map[string]string{ingressClassKey: incorrectIngressClass},
 				},
+				Spec: networking.IngressSpec{
+					IngressClassName: &ingressClass,
+				},
 			},
-			false,
+			expected: false,
+			msg:      "incorrect class annotation takes precedence over correct ingressClassName",
 		},
 		{
-			&LoadBalancerController{
-				ingressClass:        ingressClass,
-				useIngressClassOnly: true,
-				metricsCollector:    collectors.NewControllerFakeCollector(),
-			},
-			&networking.Ingress{
-				ObjectMeta: meta_v1.ObjectMeta{
-					Annotations: map[string]string{ingressClassKey: ingressClass},
+			lbc: ctrl,
+			ing: &networking.Ingress{
+				Spec: networking.IngressSpec{
+					IngressClassName: &ingressClass,
 				},
 			},
-			true,
+			expected: true,
+			msg:      "correct ingressClassName",
 		},
 		{
-			&LoadBalancerController{
-				ingressClass:        ingressClass,
-				useIngressClassOnly: true,
-				metricsCollector:    collectors.NewControllerFakeCollector(),
-			},
-			&networking.Ingress{
+			lbc: ctrlWithDefaultClass,
+			ing: &networking.Ingress{
 				ObjectMeta: meta_v1.ObjectMeta{
 					Annotations: map[string]string{},
 				},
 			},
-			false,
+			expected: true,
+			msg:      "no class with the default IngressClass",
 		},
 		{
-			&LoadBalancerController{
-				ingressClass:        ingressClass,
-				useIngressClassOnly: true, // always true for k8s >= 1.18
-				metricsCollector:    collectors.NewControllerFakeCollector(),
-			},
-			&networking.Ingress{
+			lbc: ctrlWithDefaultClass,
+			ing: &networking.Ingress{
 				Spec: networking.IngressSpec{
 					IngressClassName: &incorrectIngressClass,
 				},
 			},
-			false,
+			expected: false,
+			msg:      "incorrect ingressClassName with the default IngressClass",
 		},
 		{
-			&LoadBalancerController{
-				ingressClass:        ingressClass,
-				useIngressClassOnly: true, // always true for k8s >= 1.18
-				metricsCollector:    collectors.NewControllerFakeCollector(),
-			},
-			&networking.Ingress{
-				Spec: networking.IngressSpec{
-					IngressClassName: &emptyClass,
-				},
-			},
-			false,
-		},
-		{
-			&LoadBalancerController{
-				ingressClass:        ingressClass,
-				useIngressClassOnly: true, // always true for k8s >= 1.18
-				metricsCollector:    collectors.NewControllerFakeCollector(),
-			},
-			&networking.Ingress{
-				ObjectMeta: meta_v1.ObjectMeta{
-					Annotations: map[string]string{ingressClassKey: incorrectIngressClass},
-				},
+			lbc: ctrlWithDefaultClass,
+			ing: &networking.Ingress{
 				Spec: networking.IngressSpec{
 					IngressClassName: &ingressClass,
 				},
 			},
-			false,
+			expected: true,
+			msg:      "correct ingressClassName with the default IngressClass",
 		},
 		{
-			&LoadBalancerController{
-				ingressClass:        ingressClass,
-				useIngressClassOnly: true, // always true for k8s >= 1.18
-				metricsCollector:    collectors.NewControllerFakeCollector(),
+			lbc: &LoadBalancerController{
+				ingressClass:       ingressClass,
+				ingressClassLister: createTestIngressClassLister("other-class", true),
+				metricsCollector:   collectors.NewControllerFakeCollector(),
 			},
-			&networking.Ingress{
-				Spec: networking.IngressSpec{
-					IngressClassName: &ingressClass,
-				},
-			},
-			true,
+			ing:      &networking.Ingress{},
+			expected: false,
+			msg:      "no class with another default IngressClass",
 		},
 	}
 
-	for _, test := range testsWithoutIngressClassOnly {
-		if result := test.lbc.HasCorrectIngressClass(test.ing); result != test.expected {
-			classAnnotation := "N/A"
-			if class, exists := test.ing.Annotations[ingressClassKey]; exists {
-				classAnnotation = class
-			}
-			t.Errorf("lbc.HasCorrectIngressClass(ing), lbc.ingressClass=%v, lbc.useIngressClassOnly=%v, ing.Annotations['%v']=%v; got %v, expected %v",
-				test.lbc.ingressClass, test.lbc.useIngressClassOnly, ingressClassKey, classAnnotation, result, test.expected)
-		}
-	}
-
-	for _, test := range testsWithIngressClassOnly {
-		if result := test.lbc.HasCorrectIngressClass(test.ing); result != test.expected {
-			classAnnotation := "N/A"
-			if class, exists := test.ing.Annotations[ingressClassKey]; exists {
-				classAnnotation = class
-			}
-			t.Errorf("lbc.HasCorrectIngressClass(ing), lbc.ingressClass=%v, lbc.useIngressClassOnly=%v, ing.Annotations['%v']=%v; got %v, expected %v",
-				test.lbc.ingressClass, test.lbc.useIngressClassOnly, ingressClassKey, classAnnotation, result, test.expected)
+	for _, test := range tests {
+		result := test.lbc.HasCorrectIngressClass(test.ing)
+		if result != test.expected {
+			t.Errorf("HasCorrectIngressClass() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
 		}
 	}
 }
@@ -251,13 +223,13 @@ func deepCopyWithIngressClass(obj interface{}, class string) interface{} {
 
 func TestIngressClassForCustomResources(t *testing.T) {
 	ctrl := &LoadBalancerController{
-		ingressClass:        "nginx",
-		useIngressClassOnly: false,
+		ingressClass:       "nginx",
+		ingressClassLister: createTestIngressClassLister("nginx", false),
 	}
 
-	ctrlWithUseICOnly := &LoadBalancerController{
-		ingressClass:        "nginx",
-		useIngressClassOnly: true,
+	ctrlWithDefaultClass := &LoadBalancerController{
+		ingressClass:       "nginx",
+		ingressClassLister: createTestIngressClassLister("nginx", true),
 	}
 
 	tests := []struct {
@@ -273,10 +245,10 @@ func TestIngressClassForCustomResources(t *testing.T) {
 			msg:             "Ingress Controller handles a resource that matches its IngressClass",
 		},
 		{
-			lbc:             ctrlWithUseICOnly,
+			lbc:             ctrlWithDefaultClass,
 			objIngressClass: "nginx",
 			expected:        true,
-			msg:             "Ingress Controller with useIngressClassOnly handles a resource that matches its IngressClass",
+			msg:             "Ingress Controller with the default IngressClass handles a resource that matches its IngressClass",
 		},
 		{
 			lbc:             ctrl,
@@ -285,10 +257,10 @@ func TestIngressClassForCustomResources(t *testing.T) {
 			msg:             "Ingress Controller handles a resource with an empty IngressClass",
 		},
 		{
-			lbc:             ctrlWithUseICOnly,
+			lbc:             ctrlWithDefaultClass,
 			objIngressClass: "",
 			expected:        true,
-			msg:             "Ingress Controller with useIngressClassOnly handles a resource with an empty IngressClass",
+			msg:             "Ingress Controller with the default IngressClass handles a resource with an empty IngressClass",
 		},
 		{
 			lbc:             ctrl,
@@ -297,10 +269,10 @@ func TestIngressClassForCustomResources(t *testing.T) {
 			msg:             "Ingress Controller doesn't handle a resource that doesn't match its IngressClass",
 		},
 		{
-			lbc:             ctrlWithUseICOnly,
+			lbc:             ctrlWithDefaultClass,
 			objIngressClass: "gce",
 			expected:        false,
-			msg:             "Ingress Controller with useIngressClassOnly doesn't handle a resource that doesn't match its IngressClass",
+			msg:             "Ingress Controller with the default IngressClass doesn't handle a resource that doesn't match its IngressClass",
 		},
 	}
 
@@ -531,24 +503,24 @@ func TestGetServicePortForIngressPort(t *testing.T) {
 		},
 		Status: v1.ServiceStatus{},
 	}
-	ingSvcPort := intstr.FromString("foo")
+	ingSvcPort := networking.ServiceBackendPort{Name: "foo"}
 	svcPort := lbc.getServicePortForIngressPort(ingSvcPort, &svc)
 	if svcPort == nil || svcPort.Port != 80 {
 		t.Errorf("TargetPort string match failed: %+v", svcPort)
 	}
 
-	ingSvcPort = intstr.FromInt(80)
+	ingSvcPort = networking.ServiceBackendPort{Number: 80}
 	svcPort = lbc.getServicePortForIngressPort(ingSvcPort, &svc)
 	if svcPort == nil || svcPort.Port != 80 {
 		t.Errorf("TargetPort int match failed: %+v", svcPort)
 	}
 
-	ingSvcPort = intstr.FromInt(22)
+	ingSvcPort = networking.ServiceBackendPort{Number: 22}
 	svcPort = lbc.getServicePortForIngressPort(ingSvcPort, &svc)
 	if svcPort != nil {
 		t.Errorf("Mismatched ints should not return port: %+v", svcPort)
 	}
-	ingSvcPort = intstr.FromString("bar")
+	ingSvcPort = networking.ServiceBackendPort{Name: "bar"}
 	svcPort = lbc.getServicePortForIngressPort(ingSvcPort, &svc)
 	if svcPort != nil {
 		t.Errorf("Mismatched strings should not return port: %+v", svcPort)
@@ -566,12 +538,19 @@ func TestFormatWarningsMessages(t *testing.T) {
 	}
 }
 
+func createPointerFromString(s string) *string {
+	return &s
+}
+
+func createPointerFromInt32(n int32) *int32 {
+	return &n
+}
+
 func TestGetEndpointsBySubselectedPods(t *testing.T) {
 	boolPointer := func(b bool) *bool { return &b }
 	tests := []struct {
 		desc        string
 		targetPort  int32
-		svcEps      v1.Endpoints
 		expectedEps []podEndpoint
 	}{
 		{
@@ -579,7 +558,9 @@ func TestGetEndpointsBySubselectedPods(t *testing.T) {
 			targetPort: 80,
 			expectedEps: []podEndpoint{
 				{
-					Address: "1.2.3.4:80",
+					Address:  "1.2.3.4:80",
+					NodeName: "node-1",
+					Zone:     "zone-a",
 					MeshPodOwner: configs.MeshPodOwner{
 						OwnerType: "deployment",
 						OwnerName: "deploy-1",
@@ -605,25 +586,31 @@ func TestGetEndpointsBySubselectedPods(t *testing.T) {
 					},
 				},
 			},
+			Spec: v1.PodSpec{
+				NodeName: "node-1",
+			},
 			Status: v1.PodStatus{
 				PodIP: "1.2.3.4",
 			},
 		},
 	}
 
-	svcEps := v1.Endpoints{
-		Subsets: []v1.EndpointSubset{
-			{
-				Addresses: []v1.EndpointAddress{
-					{
-						IP:       "1.2.3.4",
-						Hostname: "asdf.com",
-					},
+	slices := []discovery_v1.EndpointSlice{
+		{
+			AddressType: discovery_v1.AddressTypeIPv4,
+			Endpoints: []discovery_v1.Endpoint{
+				{
+					Addresses: []string{"1.2.3.4"},
+					Hostname:  createPointerFromString("asdf.com"),
+					Zone:      createPointerFromString("zone-a"),
 				},
-				Ports: []v1.EndpointPort{
-					{
-						Port: 80,
-					},
+				{
+					Addresses: []string{"5.6.7.8"},
+				},
+			},
+			Ports: []discovery_v1.EndpointPort{
+				{
+					Port: createPointerFromInt32(80),
 				},
 			},
 		},
@@ -631,7 +618,7 @@ func TestGetEndpointsBySubselectedPods(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.desc, func(t *testing.T) {
-			gotEndps := getEndpointsBySubselectedPods(test.targetPort, pods, svcEps)
+			gotEndps := getEndpointsBySubselectedPods(test.targetPort, pods, slices, discovery_v1.AddressTypeIPv4)
 			if !reflect.DeepEqual(gotEndps, test.expectedEps) {
 				t.Errorf("getEndpointsBySubselectedPods() = %v, want %v", gotEndps, test.expectedEps)
 			}
@@ -639,6 +626,103 @@ func TestGetEndpointsBySubselectedPods(t *testing.T) {
 	}
 }
 
+func TestGetEndpointsFromSlices(t *testing.T) {
+	boolPointer := func(b bool) *bool { return &b }
+
+	ready := discovery_v1.Endpoint{
+		Addresses:  []string{"10.0.0.1"},
+		Conditions: discovery_v1.EndpointConditions{Ready: boolPointer(true)},
+	}
+	unknown := discovery_v1.Endpoint{
+		Addresses: []string{"10.0.0.2"},
+	}
+	notReady := discovery_v1.Endpoint{
+		Addresses:  []string{"10.0.0.3"},
+		Conditions: discovery_v1.EndpointConditions{Ready: boolPointer(false)},
+	}
+	terminating := discovery_v1.Endpoint{
+		Addresses: []string{"10.0.0.4"},
+		Conditions: discovery_v1.EndpointConditions{
+			Ready:       boolPointer(false),
+			Serving:     boolPointer(true),
+			Terminating: boolPointer(true),
+		},
+	}
+	ipv6 := discovery_v1.Endpoint{
+		Addresses: []string{"fd00::1"},
+	}
+	ports := []discovery_v1.EndpointPort{
+		{
+			Port: createPointerFromInt32(8080),
+		},
+	}
+
+	tests := []struct {
+		slices            []discovery_v1.EndpointSlice
+		targetPort        int32
+		expected          []discovery_v1.Endpoint
+		expectedPortFound bool
+		msg               string
+	}{
+		{
+			slices: []discovery_v1.EndpointSlice{
+				{
+					AddressType: discovery_v1.AddressTypeIPv4,
+					Endpoints:   []discovery_v1.Endpoint{ready, notReady, terminating},
+					Ports:       ports,
+				},
+				{
+					AddressType: discovery_v1.AddressTypeIPv4,
+					Endpoints:   []discovery_v1.Endpoint{unknown, ready},
+					Ports:       ports,
+				},
+				{
+					AddressType: discovery_v1.AddressTypeIPv6,
+					Endpoints:   []discovery_v1.Endpoint{ipv6},
+					Ports:       ports,
+				},
+			},
+			targetPort:        8080,
+			expected:          []discovery_v1.Endpoint{ready, unknown},
+			expectedPortFound: true,
+			msg:               "ready endpoints in several slices",
+		},
+		{
+			slices: []discovery_v1.EndpointSlice{
+				{
+					AddressType: discovery_v1.AddressTypeIPv4,
+					Endpoints:   []discovery_v1.Endpoint{notReady, terminating},
+					Ports:       ports,
+				},
+			},
+			targetPort:        8080,
+			expected:          []discovery_v1.Endpoint{terminating},
+			expectedPortFound: true,
+			msg:               "only terminating endpoints",
+		},
+		{
+			slices: []discovery_v1.EndpointSlice{
+				{
+					AddressType: discovery_v1.AddressTypeIPv4,
+					Endpoints:   []discovery_v1.Endpoint{ready},
+					Ports:       ports,
+				},
+			},
+			targetPort:        9090,
+			expected:          nil,
+			expectedPortFound: false,
+			msg:               "target port mismatch",
+		},
+	}
+
+	for _, test := range tests {
+		result, portFound := getEndpointsFromSlices(test.slices, test.targetPort, discovery_v1.AddressTypeIPv4)
+		if !reflect.DeepEqual(result, test.expected) || portFound != test.expectedPortFound {
+			t.Errorf("getEndpointsFromSlices() returned %v, %v but expected %v, %v for the case of %s", result, portFound, test.expected, test.expectedPortFound, test.msg)
+		}
+	}
+}
+
 func TestGetStatusFromEventTitle(t *testing.T) {
 	tests := []struct {
 		eventTitle string
@@ -758,7 +842,7 @@ func TestGetPolicies(t *testing.T) {
 
 	expectedPolicies := []*conf_v1.Policy{validPolicy}
 	expectedErrors := []error{
-		errors.New("Policy default/invalid-policy is invalid: spec: Invalid value: \"\": must specify exactly one of: `accessControl`, `rateLimit`, `ingressMTLS`, `egressMTLS`, `jwt`, `oidc`, `waf`"),
+		errors.New("Policy default/invalid-policy is invalid: spec: Invalid value: \"\": must specify exactly one of: `accessControl`, `rateLimit`, `ingressMTLS`, `egressMTLS`, `retry`, `cors`, `basicAuth`, `externalAuth`, `cache`, `compression`, `requestLimits`, `ipAccessControl`, `jwt`, `oidc`, `waf`"),
 		errors.New("Policy nginx-ingress/valid-policy doesn't exist"),
 		errors.New("Failed to get policy nginx-ingress/some-policy: GetByKey error"),
 	}
@@ -1077,6 +1161,17 @@ func TestFindPoliciesForSecret(t *testing.T) {
 			},
 		},
 	}
+	basicAuthPol := &conf_v1.Policy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "basic-auth-policy",
+			Namespace: "default",
+		},
+		Spec: conf_v1.PolicySpec{
+			BasicAuth: &conf_v1.BasicAuth{
+				Secret: "htpasswd-secret",
+			},
+		},
+	}
 
 	tests := []struct {
 		policies        []*conf_v1.Policy
@@ -1162,6 +1257,13 @@ func TestFindPoliciesForSecret(t *testing.T) {
 			expected:        []*conf_v1.Policy{oidcPol},
 			msg:             "Find policy in default ns, ignore other types",
 		},
+		{
+			policies:        []*conf_v1.Policy{jwtPol1, basicAuthPol},
+			secretNamespace: "default",
+			secretName:      "htpasswd-secret",
+			expected:        []*conf_v1.Policy{basicAuthPol},
+			msg:             "Find policy in default ns, ignore other types",
+		},
 	}
 	for _, test := range tests {
 		result := findPoliciesForSecret(test.policies, test.secretNamespace, test.secretName)
@@ -1171,6 +1273,73 @@ func TestFindPoliciesForSecret(t *testing.T) {
 	}
 }
 
+func TestFindPoliciesForService(t *testing.T) {
+	extAuthPol1 := &conf_v1.Policy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "ext-auth-policy",
+			Namespace: "default",
+		},
+		Spec: conf_v1.PolicySpec{
+			ExternalAuth: &conf_v1.ExternalAuth{
+				AuthServiceName: "oauth2-proxy",
+				AuthServicePort: 4180,
+			},
+		},
+	}
+	extAuthPol2 := &conf_v1.Policy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "ext-auth-policy",
+			Namespace: "ns-1",
+		},
+		Spec: conf_v1.PolicySpec{
+			ExternalAuth: &conf_v1.ExternalAuth{
+				AuthServiceName: "oauth2-proxy",
+				AuthServicePort: 4180,
+			},
+		},
+	}
+	extAuthURLPol := &conf_v1.Policy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "ext-auth-url-policy",
+			Namespace: "default",
+		},
+		Spec: conf_v1.PolicySpec{
+			ExternalAuth: &conf_v1.ExternalAuth{
+				AuthURL: "https://auth.example.com/verify",
+			},
+		},
+	}
+
+	tests := []struct {
+		policies         []*conf_v1.Policy
+		serviceNamespace string
+		serviceName      string
+		expected         []*conf_v1.Policy
+		msg              string
+	}{
+		{
+			policies:         []*conf_v1.Policy{extAuthPol1, extAuthPol2, extAuthURLPol},
+			serviceNamespace: "default",
+			serviceName:      "oauth2-proxy",
+			expected:         []*conf_v1.Policy{extAuthPol1},
+			msg:              "Find policy in default ns, ignore other",
+		},
+		{
+			policies:         []*conf_v1.Policy{extAuthPol1},
+			serviceNamespace: "default",
+			serviceName:      "coffee",
+			expected:         nil,
+			msg:              "Ignore policies for other services",
+		},
+	}
+	for _, test := range tests {
+		result := findPoliciesForService(test.policies, test.serviceNamespace, test.serviceName)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("findPoliciesForService() '%v' mismatch (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
 func errorComparer(e1, e2 error) bool {
 	if e1 == nil || e2 == nil {
 		return e1 == e2
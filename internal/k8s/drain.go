@@ -0,0 +1,151 @@
+package k8s
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
+	api_v1 "k8s.io/api/core/v1"
+	discovery_v1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// terminatingEndpoint is an endpoint of a terminating pod.
+type terminatingEndpoint struct {
+	address string
+	// since is the time when the pod started terminating
+	since time.Time
+}
+
+// getDrainTimeout returns the drain timeout of the upstream or zero if the upstream doesn't drain the endpoints.
+func getDrainTimeout(upstream conf_v1.Upstream) time.Duration {
+	return parseDurationOrDefault(upstream.DrainTimeout, 0, fmt.Sprintf("drain timeout of upstream %v", upstream.Name))
+}
+
+// getDrainingEndpointsForUpstreamSubset returns the endpoints of the terminating pods of the upstream selected by
+// the subselector that must still be drained. The active endpoints are never drained. If there are draining endpoints,
+// it schedules the update of the endpoints of the service for when the draining of the first of them ends.
+func (lbc *LoadBalancerController) getDrainingEndpointsForUpstreamSubset(
+	namespace string,
+	upstream conf_v1.Upstream,
+	subselector map[string]string,
+	active []string,
+	drainTimeout time.Duration,
+) []string {
+	now := time.Now()
+
+	terminating, err := lbc.getTerminatingEndpointsForUpstreamSubset(namespace, upstream, subselector, now)
+	if err != nil {
+		glog.V(3).Infof("Error getting the terminating endpoints for Upstream %v: %v", upstream.Name, err)
+		return nil
+	}
+
+	draining, nextUpdate := selectDrainingEndpoints(terminating, active, drainTimeout, now)
+	if len(draining) > 0 {
+		lbc.syncQueue.EnqueueTaskAfter(task{
+			Kind: endpointSlice,
+			Key:  fmt.Sprintf("%s/%s", namespace, upstream.Service),
+		}, nextUpdate)
+	}
+
+	return draining
+}
+
+// getTerminatingEndpointsForUpstreamSubset returns the endpoints of the terminating pods of the upstream selected by
+// the subselector. A pod is terminating if it has a deletion timestamp or if its endpoint has the terminating
+// condition, while the cache of the pods is not yet updated.
+func (lbc *LoadBalancerController) getTerminatingEndpointsForUpstreamSubset(
+	namespace string,
+	upstream conf_v1.Upstream,
+	subselector map[string]string,
+	now time.Time,
+) ([]terminatingEndpoint, error) {
+	svc, err := lbc.getServiceForUpstream(namespace, upstream.Service, upstream.Port)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting service %v: %v", upstream.Service, err)
+	}
+
+	targetPort, err := lbc.getTargetPortForUpstream(svc, upstream.Port)
+	if err != nil {
+		return nil, err
+	}
+
+	slices, err := lbc.endpointSliceLister.GetServiceEndpointSlices(svc)
+	if err != nil {
+		return nil, err
+	}
+
+	selector := labels.Set(subselector).AsSelector()
+	var result []terminatingEndpoint
+
+	forEachEndpointInSlices(slices, targetPort, getServiceAddressType(svc), func(e discovery_v1.Endpoint) {
+		if isEndpointReady(e) || e.TargetRef == nil || e.TargetRef.Kind != "Pod" {
+			return
+		}
+
+		obj, exists, err := lbc.podLister.GetByKey(fmt.Sprintf("%s/%s", e.TargetRef.Namespace, e.TargetRef.Name))
+		if err != nil || !exists {
+			return
+		}
+
+		pod := obj.(*api_v1.Pod)
+		if !selector.Matches(labels.Set(pod.Labels)) {
+			return
+		}
+
+		since := now
+		if pod.DeletionTimestamp != nil {
+			since = getPodTerminationStart(pod)
+		} else if e.Conditions.Terminating == nil || !*e.Conditions.Terminating {
+			return
+		}
+
+		result = append(result, terminatingEndpoint{
+			address: fmt.Sprintf("%v:%v", e.Addresses[0], targetPort),
+			since:   since,
+		})
+	})
+
+	return result, nil
+}
+
+// getPodTerminationStart returns the time when the deleted pod started terminating. The deletion timestamp of a pod
+// is the deadline of the graceful termination, so the termination started the grace period before it.
+func getPodTerminationStart(pod *api_v1.Pod) time.Time {
+	if pod.DeletionGracePeriodSeconds == nil {
+		return pod.DeletionTimestamp.Time
+	}
+
+	return pod.DeletionTimestamp.Add(-time.Duration(*pod.DeletionGracePeriodSeconds) * time.Second)
+}
+
+// selectDrainingEndpoints returns the addresses of the terminating endpoints that are not active and started
+// terminating less than the drain timeout ago, and the duration after which the draining of the first of them ends.
+func selectDrainingEndpoints(terminating []terminatingEndpoint, active []string, drainTimeout time.Duration, now time.Time) ([]string, time.Duration) {
+	activeSet := make(map[string]bool)
+	for _, a := range active {
+		activeSet[a] = true
+	}
+
+	var draining []string
+	var nextUpdate time.Duration
+
+	for _, e := range terminating {
+		if activeSet[e.address] {
+			continue
+		}
+
+		remaining := e.since.Add(drainTimeout).Sub(now)
+		if remaining <= 0 {
+			continue
+		}
+
+		draining = append(draining, e.address)
+		if nextUpdate == 0 || remaining < nextUpdate {
+			nextUpdate = remaining
+		}
+	}
+
+	return draining, nextUpdate
+}
@@ -0,0 +1,217 @@
+package k8s
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
+	api_v1 "k8s.io/api/core/v1"
+	discovery_v1 "k8s.io/api/discovery/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestSelectDrainingEndpoints(t *testing.T) {
+	now := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+	drainTimeout := time.Minute
+
+	terminating := []terminatingEndpoint{
+		{address: "10.0.0.1:80", since: now.Add(-10 * time.Second)},
+		{address: "10.0.0.2:80", since: now.Add(-50 * time.Second)},
+		{address: "10.0.0.3:80", since: now.Add(-2 * time.Minute)},
+		{address: "10.0.0.4:80", since: now.Add(-30 * time.Second)},
+	}
+	active := []string{"10.0.0.4:80", "10.0.0.5:80"}
+
+	expectedDraining := []string{"10.0.0.1:80", "10.0.0.2:80"}
+	expectedNextUpdate := 10 * time.Second
+
+	draining, nextUpdate := selectDrainingEndpoints(terminating, active, drainTimeout, now)
+	if !reflect.DeepEqual(draining, expectedDraining) {
+		t.Errorf("selectDrainingEndpoints() returned %v but expected %v", draining, expectedDraining)
+	}
+	if nextUpdate != expectedNextUpdate {
+		t.Errorf("selectDrainingEndpoints() returned the next update in %v but expected %v", nextUpdate, expectedNextUpdate)
+	}
+}
+
+func TestSelectDrainingEndpointsNoDraining(t *testing.T) {
+	now := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	terminating := []terminatingEndpoint{
+		{address: "10.0.0.1:80", since: now.Add(-2 * time.Minute)},
+	}
+
+	draining, nextUpdate := selectDrainingEndpoints(terminating, nil, time.Minute, now)
+	if draining != nil || nextUpdate != 0 {
+		t.Errorf("selectDrainingEndpoints() returned %v, %v but expected no draining endpoints", draining, nextUpdate)
+	}
+}
+
+func TestGetDrainTimeout(t *testing.T) {
+	tests := []struct {
+		upstream conf_v1.Upstream
+		expected time.Duration
+	}{
+		{
+			upstream: conf_v1.Upstream{},
+			expected: 0,
+		},
+		{
+			upstream: conf_v1.Upstream{DrainTimeout: "1m30s"},
+			expected: 90 * time.Second,
+		},
+	}
+
+	for _, test := range tests {
+		result := getDrainTimeout(test.upstream)
+		if result != test.expected {
+			t.Errorf("getDrainTimeout(%q) returned %v but expected %v", test.upstream.DrainTimeout, result, test.expected)
+		}
+	}
+}
+
+func TestGetTerminatingEndpointsForUpstreamSubset(t *testing.T) {
+	now := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+	deletionTimestamp := meta_v1.NewTime(now.Add(20 * time.Second))
+	gracePeriod := int64(30)
+	boolPointer := func(b bool) *bool { return &b }
+	port := int32(8080)
+
+	tests := []struct {
+		pod         *api_v1.Pod
+		conditions  discovery_v1.EndpointConditions
+		subselector map[string]string
+		expected    []terminatingEndpoint
+		msg         string
+	}{
+		{
+			pod: &api_v1.Pod{
+				ObjectMeta: meta_v1.ObjectMeta{
+					DeletionTimestamp:          &deletionTimestamp,
+					DeletionGracePeriodSeconds: &gracePeriod,
+				},
+			},
+			conditions: discovery_v1.EndpointConditions{Ready: boolPointer(false)},
+			expected: []terminatingEndpoint{
+				{address: "10.0.0.1:8080", since: now.Add(-10 * time.Second)},
+			},
+			msg: "deleted pod with grace period",
+		},
+		{
+			pod: &api_v1.Pod{
+				ObjectMeta: meta_v1.ObjectMeta{
+					DeletionTimestamp: &deletionTimestamp,
+				},
+			},
+			conditions: discovery_v1.EndpointConditions{Ready: boolPointer(false)},
+			expected: []terminatingEndpoint{
+				{address: "10.0.0.1:8080", since: deletionTimestamp.Time},
+			},
+			msg: "deleted pod without grace period",
+		},
+		{
+			pod:        &api_v1.Pod{},
+			conditions: discovery_v1.EndpointConditions{Ready: boolPointer(false), Terminating: boolPointer(true)},
+			expected: []terminatingEndpoint{
+				{address: "10.0.0.1:8080", since: now},
+			},
+			msg: "terminating endpoint of a pod not yet deleted in the cache",
+		},
+		{
+			pod:        &api_v1.Pod{},
+			conditions: discovery_v1.EndpointConditions{Ready: boolPointer(false)},
+			expected:   nil,
+			msg:        "not ready endpoint of a running pod",
+		},
+		{
+			pod: &api_v1.Pod{
+				ObjectMeta: meta_v1.ObjectMeta{
+					DeletionTimestamp: &deletionTimestamp,
+				},
+			},
+			conditions: discovery_v1.EndpointConditions{Ready: boolPointer(true)},
+			expected:   nil,
+			msg:        "ready endpoint",
+		},
+		{
+			pod: &api_v1.Pod{
+				ObjectMeta: meta_v1.ObjectMeta{
+					DeletionTimestamp: &deletionTimestamp,
+				},
+			},
+			conditions:  discovery_v1.EndpointConditions{Ready: boolPointer(false)},
+			subselector: map[string]string{"version": "v2"},
+			expected:    nil,
+			msg:         "pod not selected by the subselector",
+		},
+	}
+
+	for _, test := range tests {
+		pod := test.pod.DeepCopy()
+		pod.Name = "coffee-1"
+		pod.Namespace = "default"
+		pod.Labels = map[string]string{"app": "coffee", "version": "v1"}
+
+		svc := &api_v1.Service{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "coffee-svc",
+				Namespace: "default",
+			},
+			Spec: api_v1.ServiceSpec{
+				Selector: map[string]string{"app": "coffee"},
+				Ports: []api_v1.ServicePort{
+					{Port: 80, TargetPort: intstr.FromInt(int(port))},
+				},
+			},
+		}
+
+		slice := &discovery_v1.EndpointSlice{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "coffee-svc-1",
+				Namespace: "default",
+				Labels:    map[string]string{discovery_v1.LabelServiceName: "coffee-svc"},
+			},
+			AddressType: discovery_v1.AddressTypeIPv4,
+			Ports:       []discovery_v1.EndpointPort{{Port: &port}},
+			Endpoints: []discovery_v1.Endpoint{
+				{
+					Addresses:  []string{"10.0.0.1"},
+					Conditions: test.conditions,
+					TargetRef: &api_v1.ObjectReference{
+						Kind:      "Pod",
+						Name:      "coffee-1",
+						Namespace: "default",
+					},
+				},
+			},
+		}
+
+		lbc := &LoadBalancerController{
+			svcLister:           cache.NewStore(cache.MetaNamespaceKeyFunc),
+			endpointSliceLister: indexerToEndpointSliceLister{cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})},
+			podLister:           indexerToPodLister{cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})},
+		}
+		if err := lbc.svcLister.Add(svc); err != nil {
+			t.Fatalf("Failed to add the service to the cache: %v", err)
+		}
+		if err := lbc.endpointSliceLister.Add(slice); err != nil {
+			t.Fatalf("Failed to add the EndpointSlice to the cache: %v", err)
+		}
+		if err := lbc.podLister.Add(pod); err != nil {
+			t.Fatalf("Failed to add the pod to the cache: %v", err)
+		}
+
+		upstream := conf_v1.Upstream{Name: "coffee", Service: "coffee-svc", Port: 80}
+
+		result, err := lbc.getTerminatingEndpointsForUpstreamSubset("default", upstream, test.subselector, now)
+		if err != nil {
+			t.Errorf("getTerminatingEndpointsForUpstreamSubset() returned unexpected error for the case of %s: %v", test.msg, err)
+		}
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("getTerminatingEndpointsForUpstreamSubset() returned %+v but expected %+v for the case of %s", result, test.expected, test.msg)
+		}
+	}
+}
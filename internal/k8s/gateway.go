@@ -0,0 +1,1322 @@
+package k8s
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/nginxinc/kubernetes-ingress/internal/configs"
+	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
+	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
+	"github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/validation"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	gateway_v1alpha1 "sigs.k8s.io/gateway-api/apis/v1alpha1"
+)
+
+const (
+	// gatewayAPITaskKey is the key of the task that syncs the Gateway API resources.
+	gatewayAPITaskKey = "gateway-api"
+
+	// gatewayAPIGroup is the API group of the Gateway API resources.
+	gatewayAPIGroup = "networking.x-k8s.io"
+
+	httpRouteKind = "HTTPRoute"
+	tlsRouteKind  = "TLSRoute"
+
+	// the prefixes of the names of the VirtualServers and TransportServers generated from the routes.
+	// An underscore is not allowed in the names of Kubernetes resources, so the names never conflict with the names of
+	// the VirtualServers and TransportServers created by users.
+	httpRouteVirtualServerPrefix  = "httproute_"
+	tlsRouteTransportServerPrefix = "tlsroute_"
+
+	routeReasonAdmitted                = "Admitted"
+	routeReasonNotAllowed              = "NotAllowedByRoute"
+	routeReasonNoMatchingHostname      = "NoMatchingHostname"
+	routeReasonHostnameConflict        = "HostnameConflict"
+	routeReasonInvalid                 = "Invalid"
+	routeReasonAddedOrUpdatedWithError = "AddedOrUpdatedWithError"
+)
+
+// gatewayResources holds the Gateway API resources.
+type gatewayResources struct {
+	gatewayClasses []*gateway_v1alpha1.GatewayClass
+	gateways       []*gateway_v1alpha1.Gateway
+	httpRoutes     []*gateway_v1alpha1.HTTPRoute
+	tlsRoutes      []*gateway_v1alpha1.TLSRoute
+}
+
+// gatewayConfiguration is the result of the translation of the Gateway API resources into VirtualServers and
+// TransportServers.
+type gatewayConfiguration struct {
+	virtualServers   []*gatewayVirtualServer
+	transportServers []*gatewayTransportServer
+	// gatewayClasses are the names of the GatewayClasses of the Ingress Controller.
+	gatewayClasses map[string]bool
+	// listenerProblems are the problems of the listeners of the Gateways of the Ingress Controller keyed by the Gateway.
+	// A nil problem means the listener is ready.
+	listenerProblems map[string][]*meta_v1.Condition
+	// httpRouteAdmissions and tlsRouteAdmissions are the admissions of the routes keyed by the route and the Gateway.
+	httpRouteAdmissions map[string]map[string]*routeAdmission
+	tlsRouteAdmissions  map[string]map[string]*routeAdmission
+}
+
+// routeAdmission describes the Admitted condition of a route for a Gateway.
+type routeAdmission struct {
+	admitted bool
+	reason   string
+	message  string
+	warnings []string
+}
+
+func (a *routeAdmission) getMessage() string {
+	if len(a.warnings) == 0 {
+		return a.message
+	}
+
+	return fmt.Sprintf("%s with warnings: %s", a.message, strings.Join(a.warnings, "; "))
+}
+
+// gatewayVirtualServer is a VirtualServer generated from the HTTPRoutes of a host.
+type gatewayVirtualServer struct {
+	virtualServer *conf_v1.VirtualServer
+	// tlsSecret is the key of the TLS secret of the HTTPS listeners. The secret belongs to the namespace of the Gateway.
+	tlsSecret string
+	// routes are the keys of the HTTPRoutes of the VirtualServer.
+	routes []string
+	// warnings and err are the result of applying the VirtualServer.
+	warnings []string
+	err      error
+}
+
+// isEqual checks if the generated configuration of the VirtualServers is the same.
+func (gvs *gatewayVirtualServer) isEqual(other *gatewayVirtualServer) bool {
+	return gvs.tlsSecret == other.tlsSecret && reflect.DeepEqual(gvs.virtualServer, other.virtualServer)
+}
+
+// gatewayTransportServer is a TLS Passthrough TransportServer generated from a TLSRoute for a host.
+type gatewayTransportServer struct {
+	transportServer *conf_v1alpha1.TransportServer
+	// route is the key of the TLSRoute of the TransportServer.
+	route string
+	// err is the result of applying the TransportServer.
+	err error
+}
+
+func (gts *gatewayTransportServer) isEqual(other *gatewayTransportServer) bool {
+	return reflect.DeepEqual(gts.transportServer, other.transportServer)
+}
+
+// httpRouteHost holds the HTTPRoutes bound to a host.
+type httpRouteHost struct {
+	namespace       string
+	routes          []*gateway_v1alpha1.HTTPRoute
+	tlsSecret       string
+	hasHTTPListener bool
+}
+
+func (h *httpRouteHost) addRoute(route *gateway_v1alpha1.HTTPRoute) {
+	for _, r := range h.routes {
+		if r == route {
+			return
+		}
+	}
+
+	h.routes = append(h.routes, route)
+}
+
+// tlsRouteHost holds the rule of a TLSRoute bound to a host.
+type tlsRouteHost struct {
+	route *gateway_v1alpha1.TLSRoute
+	rule  *gateway_v1alpha1.TLSRouteRule
+}
+
+type gatewayConfigurationBuilder struct {
+	cfg                      *gatewayConfiguration
+	isTLSPassthroughEnabled  bool
+	isHostTaken              func(host string) bool
+	virtualServerValidator   *validation.VirtualServerValidator
+	transportServerValidator *validation.TransportServerValidator
+
+	httpHosts     map[string]*httpRouteHost
+	httpHostNames []string
+	tlsHosts      map[string]*tlsRouteHost
+	tlsHostNames  []string
+}
+
+// buildGatewayConfiguration translates the Gateway API resources of the GatewayClasses with the controller name into
+// VirtualServers and TransportServers. The HTTPRoutes of a host are translated into a VirtualServer and each host of
+// a TLSRoute is translated into a TLS Passthrough TransportServer. The hosts taken by the resources of the
+// Configuration are not available for the routes. If several Gateways or routes claim a host, the oldest one wins.
+func buildGatewayConfiguration(
+	resources gatewayResources,
+	controllerName string,
+	isTLSPassthroughEnabled bool,
+	isHostTaken func(host string) bool,
+	virtualServerValidator *validation.VirtualServerValidator,
+	transportServerValidator *validation.TransportServerValidator,
+) *gatewayConfiguration {
+	b := &gatewayConfigurationBuilder{
+		cfg: &gatewayConfiguration{
+			gatewayClasses:      make(map[string]bool),
+			listenerProblems:    make(map[string][]*meta_v1.Condition),
+			httpRouteAdmissions: make(map[string]map[string]*routeAdmission),
+			tlsRouteAdmissions:  make(map[string]map[string]*routeAdmission),
+		},
+		isTLSPassthroughEnabled:  isTLSPassthroughEnabled,
+		isHostTaken:              isHostTaken,
+		virtualServerValidator:   virtualServerValidator,
+		transportServerValidator: transportServerValidator,
+		httpHosts:                make(map[string]*httpRouteHost),
+		tlsHosts:                 make(map[string]*tlsRouteHost),
+	}
+
+	for _, gc := range resources.gatewayClasses {
+		if gc.Spec.Controller == controllerName {
+			b.cfg.gatewayClasses[gc.Name] = true
+		}
+	}
+
+	sortGatewayResources(resources)
+
+	for _, gw := range resources.gateways {
+		if !b.cfg.gatewayClasses[gw.Spec.GatewayClassName] {
+			continue
+		}
+
+		b.bindRoutesToGateway(gw, resources)
+	}
+
+	b.generateVirtualServers()
+	b.generateTransportServers()
+
+	return b.cfg
+}
+
+func sortGatewayResources(resources gatewayResources) {
+	sort.SliceStable(resources.gateways, func(i, j int) bool {
+		return chooseObjectMetaWinner(&resources.gateways[i].ObjectMeta, &resources.gateways[j].ObjectMeta)
+	})
+	sort.SliceStable(resources.httpRoutes, func(i, j int) bool {
+		return chooseObjectMetaWinner(&resources.httpRoutes[i].ObjectMeta, &resources.httpRoutes[j].ObjectMeta)
+	})
+	sort.SliceStable(resources.tlsRoutes, func(i, j int) bool {
+		return chooseObjectMetaWinner(&resources.tlsRoutes[i].ObjectMeta, &resources.tlsRoutes[j].ObjectMeta)
+	})
+}
+
+func (b *gatewayConfigurationBuilder) bindRoutesToGateway(gw *gateway_v1alpha1.Gateway, resources gatewayResources) {
+	gwKey := getResourceKey(&gw.ObjectMeta)
+	problems := make([]*meta_v1.Condition, 0, len(gw.Spec.Listeners))
+
+	for i := range gw.Spec.Listeners {
+		l := &gw.Spec.Listeners[i]
+
+		problem := validateGatewayListener(l, b.isTLSPassthroughEnabled)
+		problems = append(problems, problem)
+		if problem != nil {
+			continue
+		}
+
+		switch l.Protocol {
+		case gateway_v1alpha1.HTTPProtocolType, gateway_v1alpha1.HTTPSProtocolType:
+			for _, r := range resources.httpRoutes {
+				if !isRouteSelectedByListener(gw, l, &r.ObjectMeta) {
+					continue
+				}
+
+				routeKey := getResourceKey(&r.ObjectMeta)
+				if !isGatewayAllowedByRoute(gw, r.Namespace, r.Spec.Gateways) {
+					setRouteAdmission(b.cfg.httpRouteAdmissions, routeKey, gwKey, routeAdmission{
+						reason:  routeReasonNotAllowed,
+						message: "The route doesn't allow the Gateway",
+					})
+					continue
+				}
+
+				b.bindHTTPRoute(gw, l, r)
+			}
+		case gateway_v1alpha1.TLSProtocolType:
+			for _, r := range resources.tlsRoutes {
+				if !isRouteSelectedByListener(gw, l, &r.ObjectMeta) {
+					continue
+				}
+
+				routeKey := getResourceKey(&r.ObjectMeta)
+				if !isGatewayAllowedByRoute(gw, r.Namespace, r.Spec.Gateways) {
+					setRouteAdmission(b.cfg.tlsRouteAdmissions, routeKey, gwKey, routeAdmission{
+						reason:  routeReasonNotAllowed,
+						message: "The route doesn't allow the Gateway",
+					})
+					continue
+				}
+
+				b.bindTLSRoute(gw, l, r)
+			}
+		}
+	}
+
+	b.cfg.listenerProblems[gwKey] = problems
+}
+
+func (b *gatewayConfigurationBuilder) bindHTTPRoute(gw *gateway_v1alpha1.Gateway, l *gateway_v1alpha1.Listener, r *gateway_v1alpha1.HTTPRoute) {
+	gwKey := getResourceKey(&gw.ObjectMeta)
+	routeKey := getResourceKey(&r.ObjectMeta)
+
+	hosts := getRouteHostnames(l.Hostname, r.Spec.Hostnames)
+	if len(hosts) == 0 {
+		setRouteAdmission(b.cfg.httpRouteAdmissions, routeKey, gwKey, routeAdmission{
+			reason:  routeReasonNoMatchingHostname,
+			message: "The route has no hostnames matching the listeners of the Gateway",
+		})
+		return
+	}
+
+	var conflicts []string
+
+	for _, host := range hosts {
+		h, exists := b.httpHosts[host]
+		if b.isHostTaken(host) || b.tlsHosts[host] != nil || (exists && h.namespace != r.Namespace) {
+			conflicts = append(conflicts, host)
+			continue
+		}
+
+		if !exists {
+			h = &httpRouteHost{namespace: r.Namespace}
+			b.httpHosts[host] = h
+			b.httpHostNames = append(b.httpHostNames, host)
+		}
+
+		h.addRoute(r)
+
+		if l.Protocol == gateway_v1alpha1.HTTPSProtocolType {
+			if h.tlsSecret == "" {
+				h.tlsSecret = gw.Namespace + "/" + l.TLS.CertificateRef.Name
+			}
+		} else {
+			h.hasHTTPListener = true
+		}
+	}
+
+	setRouteAdmission(b.cfg.httpRouteAdmissions, routeKey, gwKey, generateRouteAdmissionForHosts(hosts, conflicts))
+}
+
+func (b *gatewayConfigurationBuilder) bindTLSRoute(gw *gateway_v1alpha1.Gateway, l *gateway_v1alpha1.Listener, r *gateway_v1alpha1.TLSRoute) {
+	gwKey := getResourceKey(&gw.ObjectMeta)
+	routeKey := getResourceKey(&r.ObjectMeta)
+
+	var allHosts []string
+	var conflicts []string
+
+	for i := range r.Spec.Rules {
+		rule := &r.Spec.Rules[i]
+
+		var snis []gateway_v1alpha1.Hostname
+		for _, m := range rule.Matches {
+			snis = append(snis, m.SNIs...)
+		}
+
+		for _, host := range getRouteHostnames(l.Hostname, snis) {
+			allHosts = append(allHosts, host)
+
+			h, exists := b.tlsHosts[host]
+			if b.isHostTaken(host) || b.httpHosts[host] != nil || (exists && h.route != r) {
+				conflicts = append(conflicts, host)
+				continue
+			}
+
+			if !exists {
+				b.tlsHosts[host] = &tlsRouteHost{
+					route: r,
+					rule:  rule,
+				}
+				b.tlsHostNames = append(b.tlsHostNames, host)
+			}
+		}
+	}
+
+	if len(allHosts) == 0 {
+		setRouteAdmission(b.cfg.tlsRouteAdmissions, routeKey, gwKey, routeAdmission{
+			reason:  routeReasonNoMatchingHostname,
+			message: "The route has no SNIs matching the listeners of the Gateway",
+		})
+		return
+	}
+
+	setRouteAdmission(b.cfg.tlsRouteAdmissions, routeKey, gwKey, generateRouteAdmissionForHosts(allHosts, conflicts))
+}
+
+func generateRouteAdmissionForHosts(hosts []string, conflicts []string) routeAdmission {
+	if len(conflicts) == len(hosts) {
+		return routeAdmission{
+			reason:  routeReasonHostnameConflict,
+			message: fmt.Sprintf("The hostnames %v are taken by other resources", strings.Join(conflicts, ", ")),
+		}
+	}
+
+	message := "The route is admitted"
+	if len(conflicts) > 0 {
+		message = fmt.Sprintf("The route is admitted, but the hostnames %v are taken by other resources", strings.Join(conflicts, ", "))
+	}
+
+	return routeAdmission{
+		admitted: true,
+		reason:   routeReasonAdmitted,
+		message:  message,
+	}
+}
+
+// setRouteAdmission sets the admission of the route for the Gateway. A route can be bound to several listeners of
+// a Gateway, so a route admitted by one of them stays admitted.
+func setRouteAdmission(admissions map[string]map[string]*routeAdmission, routeKey string, gwKey string, admission routeAdmission) {
+	gwAdmissions, exists := admissions[routeKey]
+	if !exists {
+		gwAdmissions = make(map[string]*routeAdmission)
+		admissions[routeKey] = gwAdmissions
+	}
+
+	if existing, exists := gwAdmissions[gwKey]; exists && existing.admitted {
+		return
+	}
+
+	gwAdmissions[gwKey] = &admission
+}
+
+// rejectRoute rejects the route for all its Gateways.
+func rejectRoute(gwAdmissions map[string]*routeAdmission, reason string, message string) {
+	for _, a := range gwAdmissions {
+		a.admitted = false
+		a.reason = reason
+		a.message = message
+		a.warnings = nil
+	}
+}
+
+// addRouteWarnings adds the warnings to the admissions of the route.
+func addRouteWarnings(gwAdmissions map[string]*routeAdmission, warnings []string) {
+	for _, a := range gwAdmissions {
+		if a.admitted {
+			a.warnings = append(a.warnings, warnings...)
+		}
+	}
+}
+
+func (b *gatewayConfigurationBuilder) generateVirtualServers() {
+	for _, host := range b.httpHostNames {
+		h := b.httpHosts[host]
+
+		vs, warnings := generateVirtualServerForHTTPRoutes(host, h)
+
+		var routeKeys []string
+		for _, r := range h.routes {
+			routeKey := getResourceKey(&r.ObjectMeta)
+			routeKeys = append(routeKeys, routeKey)
+			addRouteWarnings(b.cfg.httpRouteAdmissions[routeKey], warnings[routeKey])
+		}
+
+		err := b.virtualServerValidator.ValidateVirtualServer(vs)
+		if err != nil {
+			for _, routeKey := range routeKeys {
+				rejectRoute(b.cfg.httpRouteAdmissions[routeKey], routeReasonInvalid,
+					fmt.Sprintf("The configuration generated for the host %v is invalid: %v", host, err))
+			}
+			continue
+		}
+
+		b.cfg.virtualServers = append(b.cfg.virtualServers, &gatewayVirtualServer{
+			virtualServer: vs,
+			tlsSecret:     h.tlsSecret,
+			routes:        routeKeys,
+		})
+	}
+}
+
+func (b *gatewayConfigurationBuilder) generateTransportServers() {
+	for _, host := range b.tlsHostNames {
+		h := b.tlsHosts[host]
+		routeKey := getResourceKey(&h.route.ObjectMeta)
+
+		ts, warnings := generateTransportServerForTLSRoute(host, h)
+		if ts == nil {
+			rejectRoute(b.cfg.tlsRouteAdmissions[routeKey], routeReasonInvalid,
+				fmt.Sprintf("The route has no valid backends for the host %v: %v", host, strings.Join(warnings, "; ")))
+			continue
+		}
+
+		addRouteWarnings(b.cfg.tlsRouteAdmissions[routeKey], warnings)
+
+		err := b.transportServerValidator.ValidateTransportServer(ts)
+		if err != nil {
+			rejectRoute(b.cfg.tlsRouteAdmissions[routeKey], routeReasonInvalid,
+				fmt.Sprintf("The configuration generated for the host %v is invalid: %v", host, err))
+			continue
+		}
+
+		b.cfg.transportServers = append(b.cfg.transportServers, &gatewayTransportServer{
+			transportServer: ts,
+			route:           routeKey,
+		})
+	}
+}
+
+// validateGatewayListener validates the listener of a Gateway. It returns the condition that explains why the listener
+// is not supported or nil if the listener is valid.
+func validateGatewayListener(l *gateway_v1alpha1.Listener, isTLSPassthroughEnabled bool) *meta_v1.Condition {
+	var routeKind string
+
+	switch l.Protocol {
+	case gateway_v1alpha1.HTTPProtocolType:
+		if l.Port != 80 {
+			return newListenerDetachedCondition(gateway_v1alpha1.ListenerReasonPortUnavailable, "The port of an HTTP listener must be 80")
+		}
+		routeKind = httpRouteKind
+	case gateway_v1alpha1.HTTPSProtocolType:
+		if l.Port != 443 {
+			return newListenerDetachedCondition(gateway_v1alpha1.ListenerReasonPortUnavailable, "The port of an HTTPS listener must be 443")
+		}
+		if l.TLS == nil || (l.TLS.Mode != nil && *l.TLS.Mode != gateway_v1alpha1.TLSModeTerminate) {
+			return newListenerDetachedCondition(gateway_v1alpha1.ListenerReasonUnsupportedProtocol, "An HTTPS listener must terminate TLS")
+		}
+		ref := l.TLS.CertificateRef
+		if ref == nil || ref.Kind != "Secret" || (ref.Group != "core" && ref.Group != "") {
+			return newListenerResolvedRefsCondition(gateway_v1alpha1.ListenerReasonInvalidCertificateRef, "An HTTPS listener must reference a Secret as the certificate")
+		}
+		routeKind = httpRouteKind
+	case gateway_v1alpha1.TLSProtocolType:
+		if !isTLSPassthroughEnabled {
+			return newListenerDetachedCondition(gateway_v1alpha1.ListenerReasonUnsupportedProtocol, "A TLS listener requires TLS Passthrough to be enabled")
+		}
+		if l.Port != 443 {
+			return newListenerDetachedCondition(gateway_v1alpha1.ListenerReasonPortUnavailable, "The port of a TLS listener must be 443")
+		}
+		if l.TLS == nil || l.TLS.Mode == nil || *l.TLS.Mode != gateway_v1alpha1.TLSModePassthrough {
+			return newListenerDetachedCondition(gateway_v1alpha1.ListenerReasonUnsupportedProtocol, "A TLS listener must pass TLS through")
+		}
+		routeKind = tlsRouteKind
+	default:
+		return newListenerDetachedCondition(gateway_v1alpha1.ListenerReasonUnsupportedProtocol, fmt.Sprintf("The protocol %v is not supported", l.Protocol))
+	}
+
+	if l.Routes.Kind != routeKind || (l.Routes.Group != nil && *l.Routes.Group != gatewayAPIGroup) {
+		return newListenerResolvedRefsCondition(gateway_v1alpha1.ListenerReasonInvalidRoutesRef, fmt.Sprintf("A %v listener must select routes of the kind %v", l.Protocol, routeKind))
+	}
+
+	if l.Routes.Namespaces != nil && l.Routes.Namespaces.From != nil && *l.Routes.Namespaces.From == gateway_v1alpha1.RouteSelectSelector {
+		return newListenerResolvedRefsCondition(gateway_v1alpha1.ListenerReasonInvalidRoutesRef, "Selecting the namespaces of the routes by a selector is not supported")
+	}
+
+	if l.Routes.Selector != nil {
+		_, err := meta_v1.LabelSelectorAsSelector(l.Routes.Selector)
+		if err != nil {
+			return newListenerResolvedRefsCondition(gateway_v1alpha1.ListenerReasonInvalidRoutesRef, fmt.Sprintf("The selector of the routes is invalid: %v", err))
+		}
+	}
+
+	return nil
+}
+
+func newListenerDetachedCondition(reason gateway_v1alpha1.ListenerConditionReason, message string) *meta_v1.Condition {
+	return &meta_v1.Condition{
+		Type:    string(gateway_v1alpha1.ListenerConditionDetached),
+		Status:  meta_v1.ConditionTrue,
+		Reason:  string(reason),
+		Message: message,
+	}
+}
+
+func newListenerResolvedRefsCondition(reason gateway_v1alpha1.ListenerConditionReason, message string) *meta_v1.Condition {
+	return &meta_v1.Condition{
+		Type:    string(gateway_v1alpha1.ListenerConditionResolvedRefs),
+		Status:  meta_v1.ConditionFalse,
+		Reason:  string(reason),
+		Message: message,
+	}
+}
+
+// isRouteSelectedByListener checks if the listener selects the route by its namespace and labels.
+// The listener must be valid.
+func isRouteSelectedByListener(gw *gateway_v1alpha1.Gateway, l *gateway_v1alpha1.Listener, routeMeta *meta_v1.ObjectMeta) bool {
+	from := gateway_v1alpha1.RouteSelectSame
+	if l.Routes.Namespaces != nil && l.Routes.Namespaces.From != nil {
+		from = *l.Routes.Namespaces.From
+	}
+
+	if from == gateway_v1alpha1.RouteSelectSame && routeMeta.Namespace != gw.Namespace {
+		return false
+	}
+
+	if l.Routes.Selector == nil {
+		return true
+	}
+
+	selector, err := meta_v1.LabelSelectorAsSelector(l.Routes.Selector)
+	if err != nil {
+		return false
+	}
+
+	return selector.Matches(labels.Set(routeMeta.Labels))
+}
+
+// isGatewayAllowedByRoute checks if the gateways field of a route allows the Gateway.
+func isGatewayAllowedByRoute(gw *gateway_v1alpha1.Gateway, routeNamespace string, routeGateways *gateway_v1alpha1.RouteGateways) bool {
+	allow := gateway_v1alpha1.GatewayAllowSameNamespace
+	if routeGateways != nil && routeGateways.Allow != nil {
+		allow = *routeGateways.Allow
+	}
+
+	switch allow {
+	case gateway_v1alpha1.GatewayAllowAll:
+		return true
+	case gateway_v1alpha1.GatewayAllowFromList:
+		for _, ref := range routeGateways.GatewayRefs {
+			if ref.Name == gw.Name && ref.Namespace == gw.Namespace {
+				return true
+			}
+		}
+		return false
+	}
+
+	return routeNamespace == gw.Namespace
+}
+
+// matchesListenerHostname checks if the host matches the hostname of a listener. The hostname of a listener can have
+// a wildcard, which matches a single DNS label. An empty hostname matches all hosts.
+func matchesListenerHostname(listenerHostname string, host string) bool {
+	if listenerHostname == "" {
+		return true
+	}
+
+	if strings.HasPrefix(listenerHostname, "*.") {
+		suffix := listenerHostname[1:]
+		label := strings.TrimSuffix(host, suffix)
+		return strings.HasSuffix(host, suffix) && label != "" && !strings.Contains(label, ".")
+	}
+
+	return host == listenerHostname
+}
+
+// getRouteHostnames returns the hosts of a route bound to a listener, which are the hostnames of the route that match
+// the hostname of the listener. If the route doesn't have hostnames, the hostname of the listener is used.
+// A wildcard hostname of a route is supported only for a listener with a matching specific hostname.
+func getRouteHostnames(listenerHostname *gateway_v1alpha1.Hostname, routeHostnames []gateway_v1alpha1.Hostname) []string {
+	lh := ""
+	if listenerHostname != nil {
+		lh = string(*listenerHostname)
+	}
+	isWildcardListener := lh == "" || strings.HasPrefix(lh, "*")
+
+	if len(routeHostnames) == 0 {
+		if isWildcardListener {
+			return nil
+		}
+		return []string{lh}
+	}
+
+	var result []string
+	seen := make(map[string]bool)
+
+	for _, h := range routeHostnames {
+		host := string(h)
+
+		if strings.HasPrefix(host, "*") {
+			if isWildcardListener || !matchesListenerHostname(host, lh) {
+				continue
+			}
+			host = lh
+		} else if !matchesListenerHostname(lh, host) {
+			continue
+		}
+
+		if !seen[host] {
+			seen[host] = true
+			result = append(result, host)
+		}
+	}
+
+	return result
+}
+
+// generateVirtualServerForHTTPRoutes generates a VirtualServer for the HTTPRoutes of a host. The matches of the rules
+// with the same path are combined in a route of the VirtualServer. A route without a rule that matches only the path
+// returns 404. It returns the warnings for the HTTPRoutes keyed by the HTTPRoute.
+func generateVirtualServerForHTTPRoutes(host string, h *httpRouteHost) (*conf_v1.VirtualServer, map[string][]string) {
+	vs := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      httpRouteVirtualServerPrefix + host,
+			Namespace: h.namespace,
+		},
+		Spec: conf_v1.VirtualServerSpec{
+			Host: host,
+		},
+	}
+
+	if h.tlsSecret != "" {
+		_, secretName, _ := ParseNamespaceName(h.tlsSecret)
+		vs.Spec.TLS = &conf_v1.TLS{
+			Secret: secretName,
+		}
+
+		if !h.hasHTTPListener {
+			vs.Spec.TLS.Redirect = &conf_v1.TLSRedirect{
+				Enable: true,
+			}
+		}
+	}
+
+	warnings := make(map[string][]string)
+	upstreams := make(map[string]bool)
+	var routes []*conf_v1.Route
+	routesByPath := make(map[string]*conf_v1.Route)
+
+	for _, r := range h.routes {
+		routeKey := getResourceKey(&r.ObjectMeta)
+
+		for i, rule := range r.Spec.Rules {
+			action, splits, ruleUpstreams, ruleWarnings := generateActionForHTTPRouteRule(rule)
+			for _, w := range ruleWarnings {
+				warnings[routeKey] = append(warnings[routeKey], fmt.Sprintf("rule %d: %s", i, w))
+			}
+
+			for _, u := range ruleUpstreams {
+				if !upstreams[u.Name] {
+					upstreams[u.Name] = true
+					vs.Spec.Upstreams = append(vs.Spec.Upstreams, u)
+				}
+			}
+
+			matches := rule.Matches
+			if len(matches) == 0 {
+				matches = []gateway_v1alpha1.HTTPRouteMatch{{}}
+			}
+
+			for _, m := range matches {
+				path, conditions, err := generatePathAndConditionsForHTTPRouteMatch(m)
+				if err != nil {
+					warnings[routeKey] = append(warnings[routeKey], fmt.Sprintf("rule %d: %v", i, err))
+					continue
+				}
+
+				route, exists := routesByPath[path]
+				if !exists {
+					route = &conf_v1.Route{
+						Path: path,
+					}
+					routesByPath[path] = route
+					routes = append(routes, route)
+				}
+
+				if len(conditions) > 0 {
+					route.Matches = append(route.Matches, conf_v1.Match{
+						Conditions: conditions,
+						Action:     action,
+						Splits:     splits,
+					})
+					continue
+				}
+
+				if route.Action != nil || len(route.Splits) > 0 {
+					warnings[routeKey] = append(warnings[routeKey], fmt.Sprintf("rule %d: the path %v is already matched by another rule", i, path))
+					continue
+				}
+
+				route.Action = action
+				route.Splits = splits
+			}
+		}
+	}
+
+	for _, route := range routes {
+		if route.Action == nil && len(route.Splits) == 0 {
+			route.Action = &conf_v1.Action{
+				Return: &conf_v1.ActionReturn{
+					Code: 404,
+					Body: "Not Found",
+				},
+			}
+		}
+
+		vs.Spec.Routes = append(vs.Spec.Routes, *route)
+	}
+
+	return vs, warnings
+}
+
+// generatePathAndConditionsForHTTPRouteMatch generates the path of a route of a VirtualServer and the conditions of
+// a match of the route from a match of an HTTPRoute.
+func generatePathAndConditionsForHTTPRouteMatch(m gateway_v1alpha1.HTTPRouteMatch) (string, []conf_v1.Condition, error) {
+	if m.ExtensionRef != nil {
+		return "", nil, fmt.Errorf("extension references in matches are not supported")
+	}
+
+	pathType := gateway_v1alpha1.PathMatchPrefix
+	value := "/"
+	if m.Path != nil {
+		if m.Path.Type != nil {
+			pathType = *m.Path.Type
+		}
+		if m.Path.Value != nil {
+			value = *m.Path.Value
+		}
+	}
+
+	var path string
+
+	switch pathType {
+	case gateway_v1alpha1.PathMatchPrefix, gateway_v1alpha1.PathMatchImplementationSpecific:
+		path = value
+	case gateway_v1alpha1.PathMatchExact:
+		path = "=" + value
+	case gateway_v1alpha1.PathMatchRegularExpression:
+		path = "~" + value
+	default:
+		return "", nil, fmt.Errorf("the path match type %v is not supported", pathType)
+	}
+
+	var conditions []conf_v1.Condition
+
+	if m.Headers != nil {
+		if m.Headers.Type != nil && *m.Headers.Type != gateway_v1alpha1.HeaderMatchExact {
+			return "", nil, fmt.Errorf("the header match type %v is not supported", *m.Headers.Type)
+		}
+
+		for _, name := range getSortedStringMapKeys(m.Headers.Values) {
+			conditions = append(conditions, conf_v1.Condition{
+				Header: name,
+				Value:  m.Headers.Values[name],
+			})
+		}
+	}
+
+	if m.QueryParams != nil {
+		if m.QueryParams.Type != nil && *m.QueryParams.Type != gateway_v1alpha1.QueryParamMatchExact {
+			return "", nil, fmt.Errorf("the query param match type %v is not supported", *m.QueryParams.Type)
+		}
+
+		for _, name := range getSortedStringMapKeys(m.QueryParams.Values) {
+			conditions = append(conditions, conf_v1.Condition{
+				Argument: name,
+				Value:    m.QueryParams.Values[name],
+			})
+		}
+	}
+
+	return path, conditions, nil
+}
+
+// generateActionForHTTPRouteRule generates the action or the splits of a route of a VirtualServer from the backends
+// of a rule of an HTTPRoute, together with the upstreams for the backends. The weights of the backends are converted
+// into the weights of the splits. If the rule has no valid backends, the action returns 500.
+func generateActionForHTTPRouteRule(rule gateway_v1alpha1.HTTPRouteRule) (*conf_v1.Action, []conf_v1.Split, []conf_v1.Upstream, []string) {
+	ruleHeaders, warnings := generateRequestHeadersForHTTPRouteFilters(rule.Filters)
+
+	var upstreams []conf_v1.Upstream
+	var actions []*conf_v1.Action
+	var weights []int32
+
+	for _, f := range rule.ForwardTo {
+		if f.ServiceName == nil {
+			warnings = append(warnings, "only services are supported as backends")
+			continue
+		}
+
+		if f.Port == nil {
+			warnings = append(warnings, fmt.Sprintf("the port of the service %v is required", *f.ServiceName))
+			continue
+		}
+
+		weight := int32(1)
+		if f.Weight != nil {
+			weight = *f.Weight
+		}
+		if weight == 0 {
+			continue
+		}
+
+		upstream := conf_v1.Upstream{
+			Name:    fmt.Sprintf("%s-%d", *f.ServiceName, *f.Port),
+			Service: *f.ServiceName,
+			Port:    uint16(*f.Port),
+		}
+
+		backendHeaders, backendWarnings := generateRequestHeadersForHTTPRouteFilters(f.Filters)
+		warnings = append(warnings, backendWarnings...)
+
+		var headers []conf_v1.Header
+		headers = append(headers, ruleHeaders...)
+		headers = append(headers, backendHeaders...)
+
+		upstreams = append(upstreams, upstream)
+		actions = append(actions, generateActionForUpstream(upstream.Name, headers))
+		weights = append(weights, weight)
+	}
+
+	switch len(actions) {
+	case 0:
+		return &conf_v1.Action{
+			Return: &conf_v1.ActionReturn{
+				Code: 500,
+				Body: "No backends are available",
+			},
+		}, nil, upstreams, warnings
+	case 1:
+		return actions[0], nil, upstreams, warnings
+	}
+
+	var splits []conf_v1.Split
+	for i, w := range generateSplitWeights(weights) {
+		splits = append(splits, conf_v1.Split{
+			Weight: w,
+			Action: actions[i],
+		})
+	}
+
+	return nil, splits, upstreams, warnings
+}
+
+func generateActionForUpstream(upstream string, headers []conf_v1.Header) *conf_v1.Action {
+	if len(headers) == 0 {
+		return &conf_v1.Action{
+			Pass: upstream,
+		}
+	}
+
+	return &conf_v1.Action{
+		Proxy: &conf_v1.ActionProxy{
+			Upstream: upstream,
+			RequestHeaders: &conf_v1.ProxyRequestHeaders{
+				Set: headers,
+			},
+		},
+	}
+}
+
+// generateRequestHeadersForHTTPRouteFilters generates the request headers of an action of a VirtualServer from the
+// RequestHeaderModifier filters. NGINX replaces a header rather than adding a value to it, so the added headers are
+// set. The removed headers are set to an empty value.
+func generateRequestHeadersForHTTPRouteFilters(filters []gateway_v1alpha1.HTTPRouteFilter) ([]conf_v1.Header, []string) {
+	var headers []conf_v1.Header
+	var warnings []string
+
+	for _, f := range filters {
+		if f.Type != gateway_v1alpha1.HTTPRouteFilterRequestHeaderModifier || f.RequestHeaderModifier == nil {
+			warnings = append(warnings, fmt.Sprintf("the filter type %v is not supported", f.Type))
+			continue
+		}
+
+		m := f.RequestHeaderModifier
+
+		for _, name := range getSortedStringMapKeys(m.Set) {
+			headers = append(headers, conf_v1.Header{Name: name, Value: m.Set[name]})
+		}
+
+		for _, name := range getSortedStringMapKeys(m.Add) {
+			headers = append(headers, conf_v1.Header{Name: name, Value: m.Add[name]})
+		}
+
+		for _, name := range m.Remove {
+			headers = append(headers, conf_v1.Header{Name: name, Value: ""})
+		}
+	}
+
+	return headers, warnings
+}
+
+// generateSplitWeights converts the weights of the backends into the weights of the splits of a VirtualServer, which
+// must be between 1 and 99 and sum up to 100. There must be at least two backends with a positive weight.
+// The rounding error is added to the backend with the largest weight.
+func generateSplitWeights(weights []int32) []int {
+	var total int64
+	for _, w := range weights {
+		total += int64(w)
+	}
+
+	result := make([]int, len(weights))
+	sum := 0
+	largest := 0
+
+	for i, w := range weights {
+		result[i] = int(int64(w) * 100 / total)
+		if result[i] < 1 {
+			result[i] = 1
+		}
+
+		sum += result[i]
+
+		if w > weights[largest] {
+			largest = i
+		}
+	}
+
+	result[largest] += 100 - sum
+
+	return result
+}
+
+// generateTransportServerForTLSRoute generates a TLS Passthrough TransportServer for a host of a TLSRoute. TransportServers
+// don't support traffic splitting, so only the first backend of the rule is used. It returns nil if the rule has
+// no valid backends.
+func generateTransportServerForTLSRoute(host string, h *tlsRouteHost) (*conf_v1alpha1.TransportServer, []string) {
+	ts := &conf_v1alpha1.TransportServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      tlsRouteTransportServerPrefix + host,
+			Namespace: h.route.Namespace,
+		},
+		Spec: conf_v1alpha1.TransportServerSpec{
+			Listener: conf_v1alpha1.TransportServerListener{
+				Name:     conf_v1alpha1.TLSPassthroughListenerName,
+				Protocol: conf_v1alpha1.TLSPassthroughListenerProtocol,
+			},
+			Host: host,
+		},
+	}
+
+	var warnings []string
+
+	for _, f := range h.rule.ForwardTo {
+		if f.ServiceName == nil {
+			warnings = append(warnings, "only services are supported as backends")
+			continue
+		}
+
+		if f.Port == nil {
+			warnings = append(warnings, fmt.Sprintf("the port of the service %v is required", *f.ServiceName))
+			continue
+		}
+
+		if f.Weight != nil && *f.Weight == 0 {
+			continue
+		}
+
+		if ts.Spec.Action != nil {
+			warnings = append(warnings, "only the first backend is used, because traffic splitting is not supported")
+			break
+		}
+
+		name := fmt.Sprintf("%s-%d", *f.ServiceName, *f.Port)
+
+		ts.Spec.Upstreams = []conf_v1alpha1.Upstream{
+			{
+				Name:    name,
+				Service: *f.ServiceName,
+				Port:    int(*f.Port),
+			},
+		}
+		ts.Spec.Action = &conf_v1alpha1.Action{
+			Pass: name,
+		}
+	}
+
+	if ts.Spec.Action == nil {
+		return nil, warnings
+	}
+
+	return ts, warnings
+}
+
+func getSortedStringMapKeys(m map[string]string) []string {
+	var keys []string
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// AddSyncQueueForGatewayAPI enqueues the task to sync the Gateway API resources. The Gateway API resources are synced
+// all at once, because the routes of a host can be bound to several Gateways.
+func (lbc *LoadBalancerController) AddSyncQueueForGatewayAPI() {
+	lbc.syncQueue.EnqueueTask(task{Kind: gatewayAPI, Key: gatewayAPITaskKey})
+}
+
+func (lbc *LoadBalancerController) getGatewayResources() gatewayResources {
+	var resources gatewayResources
+
+	for _, obj := range lbc.gatewayClassLister.List() {
+		resources.gatewayClasses = append(resources.gatewayClasses, obj.(*gateway_v1alpha1.GatewayClass))
+	}
+
+	for _, obj := range lbc.gatewayLister.List() {
+		resources.gateways = append(resources.gateways, obj.(*gateway_v1alpha1.Gateway))
+	}
+
+	for _, obj := range lbc.httpRouteLister.List() {
+		resources.httpRoutes = append(resources.httpRoutes, obj.(*gateway_v1alpha1.HTTPRoute))
+	}
+
+	for _, obj := range lbc.tlsRouteLister.List() {
+		resources.tlsRoutes = append(resources.tlsRoutes, obj.(*gateway_v1alpha1.TLSRoute))
+	}
+
+	return resources
+}
+
+// syncGatewayAPI translates the Gateway API resources into VirtualServers and TransportServers, applies the changed
+// ones and reports the conditions of the Gateway API resources.
+func (lbc *LoadBalancerController) syncGatewayAPI() {
+	glog.V(3).Infof("Syncing Gateway API resources")
+
+	resources := lbc.getGatewayResources()
+
+	cfg := buildGatewayConfiguration(
+		resources,
+		lbc.gatewayControllerName,
+		lbc.configuration.isTLSPassthroughEnabled,
+		lbc.configuration.IsHostTaken,
+		lbc.configuration.virtualServerValidator,
+		lbc.configuration.transportServerValidator)
+
+	lbc.applyGatewayVirtualServers(cfg.virtualServers)
+	lbc.applyGatewayTransportServers(cfg.transportServers)
+
+	for _, gvs := range cfg.virtualServers {
+		for _, routeKey := range gvs.routes {
+			if gvs.err != nil {
+				rejectRoute(cfg.httpRouteAdmissions[routeKey], routeReasonAddedOrUpdatedWithError,
+					fmt.Sprintf("The configuration for the host %v was not applied: %v", gvs.virtualServer.Spec.Host, gvs.err))
+				continue
+			}
+
+			addRouteWarnings(cfg.httpRouteAdmissions[routeKey], gvs.warnings)
+		}
+	}
+
+	for _, gts := range cfg.transportServers {
+		if gts.err != nil {
+			rejectRoute(cfg.tlsRouteAdmissions[gts.route], routeReasonAddedOrUpdatedWithError,
+				fmt.Sprintf("The configuration for the host %v was not applied: %v", gts.transportServer.Spec.Host, gts.err))
+		}
+	}
+
+	if lbc.reportCustomResourceStatusEnabled() {
+		lbc.updateGatewayAPIStatuses(resources, cfg)
+	}
+}
+
+// applyGatewayVirtualServers deletes the configuration of the VirtualServers generated from HTTPRoutes that no longer
+// exist and adds or updates the configuration of the changed ones.
+func (lbc *LoadBalancerController) applyGatewayVirtualServers(virtualServers []*gatewayVirtualServer) {
+	applied := make(map[string]*gatewayVirtualServer)
+	for _, gvs := range virtualServers {
+		applied[getResourceKey(&gvs.virtualServer.ObjectMeta)] = gvs
+	}
+
+	for key := range lbc.gatewayVirtualServers {
+		if _, exists := applied[key]; exists {
+			continue
+		}
+
+		glog.V(2).Infof("Deleting the configuration generated from HTTPRoutes for %v", key)
+
+		err := lbc.configurator.DeleteVirtualServer(key)
+		if err != nil {
+			glog.Errorf("Error when deleting the configuration generated from HTTPRoutes for %v: %v", key, err)
+		}
+	}
+
+	for _, gvs := range virtualServers {
+		old, exists := lbc.gatewayVirtualServers[getResourceKey(&gvs.virtualServer.ObjectMeta)]
+		if exists && old.isEqual(gvs) {
+			gvs.warnings = old.warnings
+			gvs.err = old.err
+			continue
+		}
+
+		lbc.addOrUpdateGatewayVirtualServer(gvs)
+	}
+
+	lbc.gatewayVirtualServers = applied
+}
+
+func (lbc *LoadBalancerController) addOrUpdateGatewayVirtualServer(gvs *gatewayVirtualServer) {
+	vsEx := lbc.createGatewayVirtualServerEx(gvs)
+
+	warnings, err := lbc.configurator.AddOrUpdateVirtualServer(vsEx)
+	if err != nil {
+		glog.Errorf("Error when adding or updating the configuration generated from HTTPRoutes for the host %v: %v", gvs.virtualServer.Spec.Host, err)
+	}
+
+	gvs.warnings = warnings[vsEx.VirtualServer]
+	gvs.err = err
+}
+
+func (lbc *LoadBalancerController) createGatewayVirtualServerEx(gvs *gatewayVirtualServer) *configs.VirtualServerEx {
+	if gvs.tlsSecret == "" {
+		return lbc.createVirtualServerEx(gvs.virtualServer, nil)
+	}
+
+	// the TLS secret belongs to the namespace of the Gateway rather than to the namespace of the VirtualServer,
+	// so it is added to the extended VirtualServer separately
+	vs := gvs.virtualServer.DeepCopy()
+	tls := vs.Spec.TLS
+	vs.Spec.TLS = nil
+
+	vsEx := lbc.createVirtualServerEx(vs, nil)
+	vs.Spec.TLS = tls
+
+	secretRef := lbc.secretStore.GetSecret(gvs.tlsSecret)
+	if secretRef.Error != nil {
+		glog.Warningf("Error trying to get the secret %v for the host %v: %v", gvs.tlsSecret, vs.Spec.Host, secretRef.Error)
+	}
+
+	vsEx.SecretRefs[vs.Namespace+"/"+tls.Secret] = secretRef
+
+	return vsEx
+}
+
+// applyGatewayTransportServers deletes the configuration of the TransportServers generated from TLSRoutes that no
+// longer exist and adds or updates the configuration of the changed ones.
+func (lbc *LoadBalancerController) applyGatewayTransportServers(transportServers []*gatewayTransportServer) {
+	applied := make(map[string]*gatewayTransportServer)
+	for _, gts := range transportServers {
+		applied[getResourceKey(&gts.transportServer.ObjectMeta)] = gts
+	}
+
+	for key := range lbc.gatewayTransportServers {
+		if _, exists := applied[key]; exists {
+			continue
+		}
+
+		glog.V(2).Infof("Deleting the configuration generated from TLSRoutes for %v", key)
+
+		err := lbc.configurator.DeleteTransportServer(key)
+		if err != nil {
+			glog.Errorf("Error when deleting the configuration generated from TLSRoutes for %v: %v", key, err)
+		}
+	}
+
+	for _, gts := range transportServers {
+		old, exists := lbc.gatewayTransportServers[getResourceKey(&gts.transportServer.ObjectMeta)]
+		if exists && old.isEqual(gts) {
+			gts.err = old.err
+			continue
+		}
+
+		tsEx := lbc.createTransportServerEx(gts.transportServer, 0)
+
+		gts.err = lbc.configurator.AddOrUpdateTransportServer(tsEx)
+		if gts.err != nil {
+			glog.Errorf("Error when adding or updating the configuration generated from TLSRoutes for the host %v: %v", gts.transportServer.Spec.Host, gts.err)
+		}
+	}
+
+	lbc.gatewayTransportServers = applied
+}
+
+// updateGatewayVirtualServersForSecret updates the configuration of the VirtualServers generated from HTTPRoutes that
+// use the TLS secret.
+func (lbc *LoadBalancerController) updateGatewayVirtualServersForSecret(secretKey string) {
+	updated := false
+
+	for _, gvs := range lbc.gatewayVirtualServers {
+		if gvs.tlsSecret != secretKey {
+			continue
+		}
+
+		lbc.addOrUpdateGatewayVirtualServer(gvs)
+		updated = true
+	}
+
+	if updated {
+		// the statuses of the routes must reflect the result
+		lbc.AddSyncQueueForGatewayAPI()
+	}
+}
+
+// createGatewayResourceExesForService creates the extended VirtualServers and TransportServers generated from the
+// routes that reference the service. The ones that failed to be applied are skipped.
+func (lbc *LoadBalancerController) createGatewayResourceExesForService(namespace string, name string) ([]*configs.VirtualServerEx, []*configs.TransportServerEx) {
+	var vsExes []*configs.VirtualServerEx
+	var tsExes []*configs.TransportServerEx
+
+	for _, gvs := range lbc.gatewayVirtualServers {
+		if gvs.err != nil || gvs.virtualServer.Namespace != namespace {
+			continue
+		}
+
+		for _, u := range gvs.virtualServer.Spec.Upstreams {
+			if u.Service == name {
+				vsExes = append(vsExes, lbc.createGatewayVirtualServerEx(gvs))
+				break
+			}
+		}
+	}
+
+	for _, gts := range lbc.gatewayTransportServers {
+		if gts.err != nil || gts.transportServer.Namespace != namespace {
+			continue
+		}
+
+		for _, u := range gts.transportServer.Spec.Upstreams {
+			if u.Service == name {
+				tsExes = append(tsExes, lbc.createTransportServerEx(gts.transportServer, 0))
+				break
+			}
+		}
+	}
+
+	return vsExes, tsExes
+}
+
+// createGatewayVirtualServerExes creates the extended VirtualServers generated from HTTPRoutes. The ones that failed
+// to be applied are skipped.
+func (lbc *LoadBalancerController) createGatewayVirtualServerExes() []*configs.VirtualServerEx {
+	var result []*configs.VirtualServerEx
+
+	for _, gvs := range lbc.gatewayVirtualServers {
+		if gvs.err == nil {
+			result = append(result, lbc.createGatewayVirtualServerEx(gvs))
+		}
+	}
+
+	return result
+}
+
+func (lbc *LoadBalancerController) updateGatewayAPIStatuses(resources gatewayResources, cfg *gatewayConfiguration) {
+	for _, gc := range resources.gatewayClasses {
+		if !cfg.gatewayClasses[gc.Name] {
+			continue
+		}
+
+		err := lbc.statusUpdater.UpdateGatewayClassStatus(gc)
+		if err != nil {
+			glog.V(3).Infof("Error updating the status of GatewayClass %v: %v", gc.Name, err)
+		}
+	}
+
+	for _, gw := range resources.gateways {
+		key := getResourceKey(&gw.ObjectMeta)
+
+		problems, exists := cfg.listenerProblems[key]
+		if !exists {
+			continue
+		}
+
+		err := lbc.statusUpdater.UpdateGatewayStatus(gw, problems)
+		if err != nil {
+			glog.V(3).Infof("Error updating the status of Gateway %v: %v", key, err)
+		}
+	}
+
+	for _, r := range resources.httpRoutes {
+		key := getResourceKey(&r.ObjectMeta)
+
+		err := lbc.statusUpdater.UpdateHTTPRouteStatus(r, cfg.httpRouteAdmissions[key], lbc.gatewayControllerName)
+		if err != nil {
+			glog.V(3).Infof("Error updating the status of HTTPRoute %v: %v", key, err)
+		}
+	}
+
+	for _, r := range resources.tlsRoutes {
+		key := getResourceKey(&r.ObjectMeta)
+
+		err := lbc.statusUpdater.UpdateTLSRouteStatus(r, cfg.tlsRouteAdmissions[key], lbc.gatewayControllerName)
+		if err != nil {
+			glog.V(3).Infof("Error updating the status of TLSRoute %v: %v", key, err)
+		}
+	}
+}
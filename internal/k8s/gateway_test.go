@@ -0,0 +1,581 @@
+package k8s
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
+	"github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/validation"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gateway_v1alpha1 "sigs.k8s.io/gateway-api/apis/v1alpha1"
+)
+
+func createTestHostname(h string) *gateway_v1alpha1.Hostname {
+	hostname := gateway_v1alpha1.Hostname(h)
+	return &hostname
+}
+
+func TestGetRouteHostnames(t *testing.T) {
+	tests := []struct {
+		listenerHostname *gateway_v1alpha1.Hostname
+		routeHostnames   []gateway_v1alpha1.Hostname
+		expected         []string
+		msg              string
+	}{
+		{
+			listenerHostname: createTestHostname("cafe.example.com"),
+			routeHostnames:   nil,
+			expected:         []string{"cafe.example.com"},
+			msg:              "no route hostnames with a specific listener hostname",
+		},
+		{
+			listenerHostname: nil,
+			routeHostnames:   nil,
+			expected:         nil,
+			msg:              "no route hostnames with no listener hostname",
+		},
+		{
+			listenerHostname: nil,
+			routeHostnames:   []gateway_v1alpha1.Hostname{"cafe.example.com", "tea.example.com", "cafe.example.com"},
+			expected:         []string{"cafe.example.com", "tea.example.com"},
+			msg:              "route hostnames with no listener hostname",
+		},
+		{
+			listenerHostname: createTestHostname("*.example.com"),
+			routeHostnames:   []gateway_v1alpha1.Hostname{"cafe.example.com", "example.com", "a.cafe.example.com", "cafe.example.org"},
+			expected:         []string{"cafe.example.com"},
+			msg:              "route hostnames with a wildcard listener hostname",
+		},
+		{
+			listenerHostname: createTestHostname("cafe.example.com"),
+			routeHostnames:   []gateway_v1alpha1.Hostname{"*.example.com", "tea.example.com"},
+			expected:         []string{"cafe.example.com"},
+			msg:              "wildcard route hostname with a specific listener hostname",
+		},
+		{
+			listenerHostname: createTestHostname("*.example.com"),
+			routeHostnames:   []gateway_v1alpha1.Hostname{"*.example.com"},
+			expected:         nil,
+			msg:              "wildcard route hostname with a wildcard listener hostname",
+		},
+	}
+
+	for _, test := range tests {
+		result := getRouteHostnames(test.listenerHostname, test.routeHostnames)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("getRouteHostnames() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
+	}
+}
+
+func TestValidateGatewayListener(t *testing.T) {
+	httpRoutes := gateway_v1alpha1.RouteBindingSelector{Kind: httpRouteKind}
+	tlsRoutes := gateway_v1alpha1.RouteBindingSelector{Kind: tlsRouteKind}
+	terminate := gateway_v1alpha1.TLSModeTerminate
+	passthrough := gateway_v1alpha1.TLSModePassthrough
+	fromSelector := gateway_v1alpha1.RouteSelectSelector
+
+	tests := []struct {
+		listener                gateway_v1alpha1.Listener
+		isTLSPassthroughEnabled bool
+		expectedReason          string
+		msg                     string
+	}{
+		{
+			listener: gateway_v1alpha1.Listener{
+				Protocol: gateway_v1alpha1.HTTPProtocolType,
+				Port:     80,
+				Routes:   httpRoutes,
+			},
+			expectedReason: "",
+			msg:            "valid HTTP listener",
+		},
+		{
+			listener: gateway_v1alpha1.Listener{
+				Protocol: gateway_v1alpha1.HTTPSProtocolType,
+				Port:     443,
+				TLS: &gateway_v1alpha1.GatewayTLSConfig{
+					Mode:           &terminate,
+					CertificateRef: &gateway_v1alpha1.LocalObjectReference{Group: "core", Kind: "Secret", Name: "cafe-secret"},
+				},
+				Routes: httpRoutes,
+			},
+			expectedReason: "",
+			msg:            "valid HTTPS listener",
+		},
+		{
+			listener: gateway_v1alpha1.Listener{
+				Protocol: gateway_v1alpha1.TLSProtocolType,
+				Port:     443,
+				TLS:      &gateway_v1alpha1.GatewayTLSConfig{Mode: &passthrough},
+				Routes:   tlsRoutes,
+			},
+			isTLSPassthroughEnabled: true,
+			expectedReason:          "",
+			msg:                     "valid TLS listener",
+		},
+		{
+			listener: gateway_v1alpha1.Listener{
+				Protocol: gateway_v1alpha1.HTTPProtocolType,
+				Port:     8080,
+				Routes:   httpRoutes,
+			},
+			expectedReason: string(gateway_v1alpha1.ListenerReasonPortUnavailable),
+			msg:            "HTTP listener with an unsupported port",
+		},
+		{
+			listener: gateway_v1alpha1.Listener{
+				Protocol: gateway_v1alpha1.HTTPSProtocolType,
+				Port:     443,
+				TLS:      &gateway_v1alpha1.GatewayTLSConfig{Mode: &terminate},
+				Routes:   httpRoutes,
+			},
+			expectedReason: string(gateway_v1alpha1.ListenerReasonInvalidCertificateRef),
+			msg:            "HTTPS listener without a certificate",
+		},
+		{
+			listener: gateway_v1alpha1.Listener{
+				Protocol: gateway_v1alpha1.TLSProtocolType,
+				Port:     443,
+				TLS:      &gateway_v1alpha1.GatewayTLSConfig{Mode: &passthrough},
+				Routes:   tlsRoutes,
+			},
+			isTLSPassthroughEnabled: false,
+			expectedReason:          string(gateway_v1alpha1.ListenerReasonUnsupportedProtocol),
+			msg:                     "TLS listener with TLS Passthrough disabled",
+		},
+		{
+			listener: gateway_v1alpha1.Listener{
+				Protocol: gateway_v1alpha1.HTTPProtocolType,
+				Port:     80,
+				Routes:   tlsRoutes,
+			},
+			expectedReason: string(gateway_v1alpha1.ListenerReasonInvalidRoutesRef),
+			msg:            "HTTP listener with TLSRoutes",
+		},
+		{
+			listener: gateway_v1alpha1.Listener{
+				Protocol: gateway_v1alpha1.HTTPProtocolType,
+				Port:     80,
+				Routes: gateway_v1alpha1.RouteBindingSelector{
+					Kind:       httpRouteKind,
+					Namespaces: &gateway_v1alpha1.RouteNamespaces{From: &fromSelector},
+				},
+			},
+			expectedReason: string(gateway_v1alpha1.ListenerReasonInvalidRoutesRef),
+			msg:            "HTTP listener with a namespace selector",
+		},
+		{
+			listener: gateway_v1alpha1.Listener{
+				Protocol: gateway_v1alpha1.UDPProtocolType,
+				Port:     53,
+			},
+			expectedReason: string(gateway_v1alpha1.ListenerReasonUnsupportedProtocol),
+			msg:            "UDP listener",
+		},
+	}
+
+	for _, test := range tests {
+		result := validateGatewayListener(&test.listener, test.isTLSPassthroughEnabled)
+
+		reason := ""
+		if result != nil {
+			reason = result.Reason
+		}
+
+		if reason != test.expectedReason {
+			t.Errorf("validateGatewayListener() returned the reason %q but expected %q for the case of %s", reason, test.expectedReason, test.msg)
+		}
+	}
+}
+
+func TestGenerateSplitWeights(t *testing.T) {
+	tests := []struct {
+		weights  []int32
+		expected []int
+	}{
+		{
+			weights:  []int32{1, 1},
+			expected: []int{50, 50},
+		},
+		{
+			weights:  []int32{1, 2},
+			expected: []int{33, 67},
+		},
+		{
+			weights:  []int32{1, 1, 1},
+			expected: []int{34, 33, 33},
+		},
+		{
+			weights:  []int32{1, 1000},
+			expected: []int{1, 99},
+		},
+	}
+
+	for _, test := range tests {
+		result := generateSplitWeights(test.weights)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("generateSplitWeights(%v) returned %v but expected %v", test.weights, result, test.expected)
+		}
+	}
+}
+
+func TestGenerateVirtualServerForHTTPRoutes(t *testing.T) {
+	prefix := gateway_v1alpha1.PathMatchPrefix
+	exact := gateway_v1alpha1.PathMatchExact
+	teaPath := "/tea"
+	coffeePath := "/coffee"
+	teaSvc := "tea-svc"
+	coffeeSvc := "coffee-svc"
+	port := gateway_v1alpha1.PortNumber(80)
+	weight := int32(3)
+
+	route := &gateway_v1alpha1.HTTPRoute{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+		Spec: gateway_v1alpha1.HTTPRouteSpec{
+			Rules: []gateway_v1alpha1.HTTPRouteRule{
+				{
+					Matches: []gateway_v1alpha1.HTTPRouteMatch{
+						{
+							Path: &gateway_v1alpha1.HTTPPathMatch{Type: &prefix, Value: &teaPath},
+							Headers: &gateway_v1alpha1.HTTPHeaderMatch{
+								Values: map[string]string{"version": "v2"},
+							},
+						},
+					},
+					ForwardTo: []gateway_v1alpha1.HTTPRouteForwardTo{
+						{ServiceName: &teaSvc, Port: &port},
+					},
+				},
+				{
+					Matches: []gateway_v1alpha1.HTTPRouteMatch{
+						{
+							Path: &gateway_v1alpha1.HTTPPathMatch{Type: &exact, Value: &coffeePath},
+						},
+					},
+					ForwardTo: []gateway_v1alpha1.HTTPRouteForwardTo{
+						{ServiceName: &coffeeSvc, Port: &port, Weight: &weight},
+						{ServiceName: &teaSvc, Port: &port},
+					},
+				},
+				{
+					Matches: []gateway_v1alpha1.HTTPRouteMatch{
+						{
+							Path: &gateway_v1alpha1.HTTPPathMatch{Type: &exact, Value: &coffeePath},
+						},
+					},
+					ForwardTo: []gateway_v1alpha1.HTTPRouteForwardTo{
+						{ServiceName: &teaSvc},
+					},
+				},
+			},
+		},
+	}
+
+	host := &httpRouteHost{
+		namespace: "default",
+		routes:    []*gateway_v1alpha1.HTTPRoute{route},
+		tlsSecret: "gateway-ns/cafe-secret",
+	}
+
+	expected := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "httproute_cafe.example.com",
+			Namespace: "default",
+		},
+		Spec: conf_v1.VirtualServerSpec{
+			Host: "cafe.example.com",
+			TLS: &conf_v1.TLS{
+				Secret: "cafe-secret",
+				Redirect: &conf_v1.TLSRedirect{
+					Enable: true,
+				},
+			},
+			Upstreams: []conf_v1.Upstream{
+				{Name: "tea-svc-80", Service: "tea-svc", Port: 80},
+				{Name: "coffee-svc-80", Service: "coffee-svc", Port: 80},
+			},
+			Routes: []conf_v1.Route{
+				{
+					Path: "/tea",
+					Matches: []conf_v1.Match{
+						{
+							Conditions: []conf_v1.Condition{{Header: "version", Value: "v2"}},
+							Action:     &conf_v1.Action{Pass: "tea-svc-80"},
+						},
+					},
+					Action: &conf_v1.Action{
+						Return: &conf_v1.ActionReturn{Code: 404, Body: "Not Found"},
+					},
+				},
+				{
+					Path: "=/coffee",
+					Splits: []conf_v1.Split{
+						{Weight: 75, Action: &conf_v1.Action{Pass: "coffee-svc-80"}},
+						{Weight: 25, Action: &conf_v1.Action{Pass: "tea-svc-80"}},
+					},
+				},
+			},
+		},
+	}
+
+	expectedWarnings := map[string][]string{
+		"default/cafe": {
+			"rule 2: the port of the service tea-svc is required",
+			"rule 2: the path =/coffee is already matched by another rule",
+		},
+	}
+
+	vs, warnings := generateVirtualServerForHTTPRoutes("cafe.example.com", host)
+
+	if !reflect.DeepEqual(vs, expected) {
+		t.Errorf("generateVirtualServerForHTTPRoutes() returned \n%+v but expected \n%+v", vs, expected)
+	}
+	if !reflect.DeepEqual(warnings, expectedWarnings) {
+		t.Errorf("generateVirtualServerForHTTPRoutes() returned warnings %v but expected %v", warnings, expectedWarnings)
+	}
+}
+
+func TestBuildGatewayConfiguration(t *testing.T) {
+	svc := "cafe-svc"
+	port := gateway_v1alpha1.PortNumber(80)
+	allowAll := gateway_v1alpha1.GatewayAllowAll
+	fromAll := gateway_v1alpha1.RouteSelectAll
+
+	createRoute := func(namespace string, name string, created time.Time, hostnames ...gateway_v1alpha1.Hostname) *gateway_v1alpha1.HTTPRoute {
+		return &gateway_v1alpha1.HTTPRoute{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:              name,
+				Namespace:         namespace,
+				CreationTimestamp: meta_v1.NewTime(created),
+			},
+			Spec: gateway_v1alpha1.HTTPRouteSpec{
+				Gateways:  &gateway_v1alpha1.RouteGateways{Allow: &allowAll},
+				Hostnames: hostnames,
+				Rules: []gateway_v1alpha1.HTTPRouteRule{
+					{
+						ForwardTo: []gateway_v1alpha1.HTTPRouteForwardTo{
+							{ServiceName: &svc, Port: &port},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	now := time.Now()
+
+	resources := gatewayResources{
+		gatewayClasses: []*gateway_v1alpha1.GatewayClass{
+			{
+				ObjectMeta: meta_v1.ObjectMeta{Name: "nginx"},
+				Spec:       gateway_v1alpha1.GatewayClassSpec{Controller: "k8s.nginx.org/nginx-ingress-controller"},
+			},
+			{
+				ObjectMeta: meta_v1.ObjectMeta{Name: "other"},
+				Spec:       gateway_v1alpha1.GatewayClassSpec{Controller: "example.com/other-controller"},
+			},
+		},
+		gateways: []*gateway_v1alpha1.Gateway{
+			{
+				ObjectMeta: meta_v1.ObjectMeta{Name: "gateway", Namespace: "gateway-ns"},
+				Spec: gateway_v1alpha1.GatewaySpec{
+					GatewayClassName: "nginx",
+					Listeners: []gateway_v1alpha1.Listener{
+						{
+							Protocol: gateway_v1alpha1.HTTPProtocolType,
+							Port:     80,
+							Routes: gateway_v1alpha1.RouteBindingSelector{
+								Kind:       httpRouteKind,
+								Namespaces: &gateway_v1alpha1.RouteNamespaces{From: &fromAll},
+							},
+						},
+						{
+							Protocol: gateway_v1alpha1.HTTPProtocolType,
+							Port:     8080,
+							Routes:   gateway_v1alpha1.RouteBindingSelector{Kind: httpRouteKind},
+						},
+					},
+				},
+			},
+			{
+				ObjectMeta: meta_v1.ObjectMeta{Name: "other-gateway", Namespace: "gateway-ns"},
+				Spec: gateway_v1alpha1.GatewaySpec{
+					GatewayClassName: "other",
+				},
+			},
+		},
+		httpRoutes: []*gateway_v1alpha1.HTTPRoute{
+			createRoute("ns-2", "newer", now, "cafe.example.com", "tea.example.com"),
+			createRoute("ns-1", "older", now.Add(-time.Minute), "cafe.example.com"),
+			createRoute("ns-1", "taken", now, "taken.example.com"),
+		},
+	}
+
+	isHostTaken := func(host string) bool {
+		return host == "taken.example.com"
+	}
+
+	cfg := buildGatewayConfiguration(
+		resources,
+		"k8s.nginx.org/nginx-ingress-controller",
+		false,
+		isHostTaken,
+		validation.NewVirtualServerValidator(false),
+		validation.NewTransportServerValidator(false, false, false))
+
+	expectedGatewayClasses := map[string]bool{"nginx": true}
+	if !reflect.DeepEqual(cfg.gatewayClasses, expectedGatewayClasses) {
+		t.Errorf("buildGatewayConfiguration() returned GatewayClasses %v but expected %v", cfg.gatewayClasses, expectedGatewayClasses)
+	}
+
+	problems, exists := cfg.listenerProblems["gateway-ns/gateway"]
+	if !exists || len(problems) != 2 || problems[0] != nil || problems[1] == nil {
+		t.Errorf("buildGatewayConfiguration() returned unexpected listener problems %v", problems)
+	}
+	if _, exists := cfg.listenerProblems["gateway-ns/other-gateway"]; exists {
+		t.Errorf("buildGatewayConfiguration() returned listener problems for a Gateway of another controller")
+	}
+
+	var hosts []string
+	for _, gvs := range cfg.virtualServers {
+		hosts = append(hosts, gvs.virtualServer.Namespace+"/"+gvs.virtualServer.Spec.Host)
+	}
+
+	expectedHosts := []string{"ns-1/cafe.example.com", "ns-2/tea.example.com"}
+	if !reflect.DeepEqual(hosts, expectedHosts) {
+		t.Errorf("buildGatewayConfiguration() returned VirtualServers for %v but expected %v", hosts, expectedHosts)
+	}
+
+	expectedAdmissions := map[string]map[string]*routeAdmission{
+		"ns-1/older": {
+			"gateway-ns/gateway": {
+				admitted: true,
+				reason:   routeReasonAdmitted,
+				message:  "The route is admitted",
+			},
+		},
+		"ns-2/newer": {
+			"gateway-ns/gateway": {
+				admitted: true,
+				reason:   routeReasonAdmitted,
+				message:  "The route is admitted, but the hostnames cafe.example.com are taken by other resources",
+			},
+		},
+		"ns-1/taken": {
+			"gateway-ns/gateway": {
+				admitted: false,
+				reason:   routeReasonHostnameConflict,
+				message:  "The hostnames taken.example.com are taken by other resources",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(cfg.httpRouteAdmissions, expectedAdmissions) {
+		t.Errorf("buildGatewayConfiguration() returned admissions %+v but expected %+v", cfg.httpRouteAdmissions, expectedAdmissions)
+	}
+}
+
+func TestGenerateRouteGatewayStatuses(t *testing.T) {
+	controllerName := "k8s.nginx.org/nginx-ingress-controller"
+	otherControllerName := "example.com/other-controller"
+	before := meta_v1.NewTime(time.Now().Add(-time.Hour))
+	now := meta_v1.NewTime(time.Now())
+
+	otherStatus := gateway_v1alpha1.RouteGatewayStatus{
+		GatewayRef: gateway_v1alpha1.RouteStatusGatewayReference{
+			Name:       "other-gateway",
+			Namespace:  "default",
+			Controller: &otherControllerName,
+		},
+	}
+
+	existing := []gateway_v1alpha1.RouteGatewayStatus{
+		otherStatus,
+		{
+			GatewayRef: gateway_v1alpha1.RouteStatusGatewayReference{
+				Name:       "gateway-b",
+				Namespace:  "default",
+				Controller: &controllerName,
+			},
+			Conditions: []meta_v1.Condition{
+				{
+					Type:               string(gateway_v1alpha1.ConditionRouteAdmitted),
+					Status:             meta_v1.ConditionTrue,
+					Reason:             routeReasonAdmitted,
+					Message:            "The route is admitted",
+					ObservedGeneration: 1,
+					LastTransitionTime: before,
+				},
+			},
+		},
+		{
+			GatewayRef: gateway_v1alpha1.RouteStatusGatewayReference{
+				Name:       "removed-gateway",
+				Namespace:  "default",
+				Controller: &controllerName,
+			},
+		},
+	}
+
+	admissions := map[string]*routeAdmission{
+		"default/gateway-b": {
+			admitted: true,
+			reason:   routeReasonAdmitted,
+			message:  "The route is admitted",
+			warnings: []string{"rule 0: the filter type RequestMirror is not supported"},
+		},
+		"default/gateway-a": {
+			admitted: false,
+			reason:   routeReasonNotAllowed,
+			message:  "The route doesn't allow the Gateway",
+		},
+	}
+
+	expected := []gateway_v1alpha1.RouteGatewayStatus{
+		otherStatus,
+		{
+			GatewayRef: gateway_v1alpha1.RouteStatusGatewayReference{
+				Name:       "gateway-a",
+				Namespace:  "default",
+				Controller: &controllerName,
+			},
+			Conditions: []meta_v1.Condition{
+				{
+					Type:               string(gateway_v1alpha1.ConditionRouteAdmitted),
+					Status:             meta_v1.ConditionFalse,
+					Reason:             routeReasonNotAllowed,
+					Message:            "The route doesn't allow the Gateway",
+					ObservedGeneration: 2,
+					LastTransitionTime: now,
+				},
+			},
+		},
+		{
+			GatewayRef: gateway_v1alpha1.RouteStatusGatewayReference{
+				Name:       "gateway-b",
+				Namespace:  "default",
+				Controller: &controllerName,
+			},
+			Conditions: []meta_v1.Condition{
+				{
+					Type:               string(gateway_v1alpha1.ConditionRouteAdmitted),
+					Status:             meta_v1.ConditionTrue,
+					Reason:             routeReasonAdmitted,
+					Message:            "The route is admitted with warnings: rule 0: the filter type RequestMirror is not supported",
+					ObservedGeneration: 2,
+					LastTransitionTime: before,
+				},
+			},
+		},
+	}
+
+	result := generateRouteGatewayStatuses(existing, admissions, controllerName, 2, now)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("generateRouteGatewayStatuses() returned \n%+v but expected \n%+v", result, expected)
+	}
+}
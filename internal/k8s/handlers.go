@@ -7,7 +7,8 @@ import (
 	"github.com/golang/glog"
 	"github.com/nginxinc/kubernetes-ingress/internal/k8s/secrets"
 	v1 "k8s.io/api/core/v1"
-	networking "k8s.io/api/networking/v1beta1"
+	discovery_v1 "k8s.io/api/discovery/v1"
+	networking "k8s.io/api/networking/v1"
 	"k8s.io/client-go/tools/cache"
 
 	"fmt"
@@ -15,6 +16,7 @@ import (
 	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
 	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
@@ -59,35 +61,36 @@ func createConfigMapHandlers(lbc *LoadBalancerController, name string) cache.Res
 	}
 }
 
-// createEndpointHandlers builds the handler funcs for endpoints
-func createEndpointHandlers(lbc *LoadBalancerController) cache.ResourceEventHandlerFuncs {
+// createEndpointSliceHandlers builds the handler funcs for endpoint slices
+func createEndpointSliceHandlers(lbc *LoadBalancerController) cache.ResourceEventHandlerFuncs {
 	return cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
-			endpoint := obj.(*v1.Endpoints)
-			glog.V(3).Infof("Adding endpoints: %v", endpoint.Name)
-			lbc.AddSyncQueue(obj)
+			endpointSlice := obj.(*discovery_v1.EndpointSlice)
+			glog.V(3).Infof("Adding EndpointSlice: %v", endpointSlice.Name)
+			lbc.AddSyncQueueForEndpointSlice(endpointSlice)
 		},
 		DeleteFunc: func(obj interface{}) {
-			endpoint, isEndpoint := obj.(*v1.Endpoints)
-			if !isEndpoint {
+			endpointSlice, isEndpointSlice := obj.(*discovery_v1.EndpointSlice)
+			if !isEndpointSlice {
 				deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
 				if !ok {
 					glog.V(3).Infof("Error received unexpected object: %v", obj)
 					return
 				}
-				endpoint, ok = deletedState.Obj.(*v1.Endpoints)
+				endpointSlice, ok = deletedState.Obj.(*discovery_v1.EndpointSlice)
 				if !ok {
-					glog.V(3).Infof("Error DeletedFinalStateUnknown contained non-Endpoints object: %v", deletedState.Obj)
+					glog.V(3).Infof("Error DeletedFinalStateUnknown contained non-EndpointSlice object: %v", deletedState.Obj)
 					return
 				}
 			}
-			glog.V(3).Infof("Removing endpoints: %v", endpoint.Name)
-			lbc.AddSyncQueue(obj)
+			glog.V(3).Infof("Removing EndpointSlice: %v", endpointSlice.Name)
+			lbc.AddSyncQueueForEndpointSlice(endpointSlice)
 		},
 		UpdateFunc: func(old, cur interface{}) {
 			if !reflect.DeepEqual(old, cur) {
-				glog.V(3).Infof("Endpoints %v changed, syncing", cur.(*v1.Endpoints).Name)
-				lbc.AddSyncQueue(cur)
+				endpointSlice := cur.(*discovery_v1.EndpointSlice)
+				glog.V(3).Infof("EndpointSlice %v changed, syncing", endpointSlice.Name)
+				lbc.AddSyncQueueForEndpointSlice(endpointSlice)
 			}
 		},
 	}
@@ -129,6 +132,51 @@ func createIngressHandlers(lbc *LoadBalancerController) cache.ResourceEventHandl
 	}
 }
 
+// createIngressClassHandlers builds the handler funcs for ingress classes
+func createIngressClassHandlers(lbc *LoadBalancerController) cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			ingressClass := obj.(*networking.IngressClass)
+			if ingressClass.Name != lbc.ingressClass {
+				return
+			}
+			glog.V(3).Infof("Adding IngressClass: %v", ingressClass.Name)
+			lbc.syncIngressesForIngressClass()
+		},
+		DeleteFunc: func(obj interface{}) {
+			ingressClass, isIngressClass := obj.(*networking.IngressClass)
+			if !isIngressClass {
+				deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					glog.V(3).Infof("Error received unexpected object: %v", obj)
+					return
+				}
+				ingressClass, ok = deletedState.Obj.(*networking.IngressClass)
+				if !ok {
+					glog.V(3).Infof("Error DeletedFinalStateUnknown contained non-IngressClass object: %v", deletedState.Obj)
+					return
+				}
+			}
+			if ingressClass.Name != lbc.ingressClass {
+				return
+			}
+			glog.V(3).Infof("Removing IngressClass: %v", ingressClass.Name)
+			lbc.syncIngressesForIngressClass()
+		},
+		UpdateFunc: func(old, current interface{}) {
+			c := current.(*networking.IngressClass)
+			o := old.(*networking.IngressClass)
+			if c.Name != lbc.ingressClass {
+				return
+			}
+			if o.Annotations[isDefaultIngressClassKey] != c.Annotations[isDefaultIngressClassKey] {
+				glog.V(3).Infof("IngressClass %v changed, syncing", c.Name)
+				lbc.syncIngressesForIngressClass()
+			}
+		},
+	}
+}
+
 // createSecretHandlers builds the handler funcs for secrets
 func createSecretHandlers(lbc *LoadBalancerController) cache.ResourceEventHandlerFuncs {
 	return cache.ResourceEventHandlerFuncs{
@@ -454,6 +502,48 @@ func createPolicyHandlers(lbc *LoadBalancerController) cache.ResourceEventHandle
 	}
 }
 
+// createGatewayAPIHandlers builds the handler funcs for the Gateway API resources. All resources share the same
+// handlers, because any change triggers the sync of all Gateway API resources.
+func createGatewayAPIHandlers(lbc *LoadBalancerController) cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			key, err := keyFunc(obj)
+			if err != nil {
+				glog.V(3).Infof("Error getting the key of the Gateway API resource: %v", err)
+				return
+			}
+			glog.V(3).Infof("Adding Gateway API resource: %v", key)
+			lbc.AddSyncQueueForGatewayAPI()
+		},
+		DeleteFunc: func(obj interface{}) {
+			key, err := keyFunc(obj)
+			if err != nil {
+				glog.V(3).Infof("Error getting the key of the Gateway API resource: %v", err)
+				return
+			}
+			glog.V(3).Infof("Removing Gateway API resource: %v", key)
+			lbc.AddSyncQueueForGatewayAPI()
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			oldMeta, err := meta.Accessor(old)
+			if err != nil {
+				glog.V(3).Infof("Error received unexpected object: %v", old)
+				return
+			}
+			curMeta, err := meta.Accessor(cur)
+			if err != nil {
+				glog.V(3).Infof("Error received unexpected object: %v", cur)
+				return
+			}
+			// the status updates of the resources don't change the generation
+			if oldMeta.GetGeneration() != curMeta.GetGeneration() || !reflect.DeepEqual(oldMeta.GetLabels(), curMeta.GetLabels()) {
+				glog.V(3).Infof("Gateway API resource %v/%v changed, syncing", curMeta.GetNamespace(), curMeta.GetName())
+				lbc.AddSyncQueueForGatewayAPI()
+			}
+		},
+	}
+}
+
 func createIngressLinkHandlers(lbc *LoadBalancerController) cache.ResourceEventHandlerFuncs {
 	return cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
@@ -84,6 +84,11 @@ func createLeaderHandler(lbc *LoadBalancerController) leaderelection.LeaderCallb
 					glog.V(3).Infof("error updating TransportServers status when starting leading: %v", err)
 				}
 			}
+
+			if lbc.isGatewayAPIEnabled {
+				glog.V(3).Info("updating Gateway API resources status")
+				lbc.AddSyncQueueForGatewayAPI()
+			}
 		},
 		OnStoppedLeading: func() {
 			glog.V(3).Info("stopped leading")
@@ -0,0 +1,412 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/nginxinc/kubernetes-ingress/internal/configs"
+	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
+	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
+	"github.com/nginxinc/nginx-plus-go-client/client"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// outlierDetectionInterval is how often the Ingress Controller checks the peers of the upstreams with an outlier detection.
+	outlierDetectionInterval = 2 * time.Second
+	// outlierDetectionTaskKey is the key of the task that checks the peers of the upstreams with an outlier detection.
+	outlierDetectionTaskKey = "outlier-detection"
+
+	defaultOutlierEjectionTime       = 30 * time.Second
+	defaultOutlierMaxEjectionPercent = 10
+)
+
+// upstreamPeersClient gets the peers of the upstreams and updates them via the NGINX Plus API.
+type upstreamPeersClient interface {
+	GetUpstreams() (*client.Upstreams, error)
+	GetStreamUpstreams() (*client.StreamUpstreams, error)
+	UpdateHTTPServer(upstream string, server client.UpstreamServer) error
+	UpdateStreamServer(upstream string, server client.StreamUpstreamServer) error
+}
+
+// outlierDetectionSettings holds the thresholds of an outlier detection. A zero threshold is disabled.
+type outlierDetectionSettings struct {
+	consecutive5xx     int
+	consecutiveFails   int
+	ejectionTime       time.Duration
+	maxEjectionPercent int
+}
+
+func newOutlierDetectionSettings(od *conf_v1.OutlierDetection) outlierDetectionSettings {
+	settings := outlierDetectionSettings{
+		ejectionTime:       getOutlierEjectionTime(od.EjectionTime),
+		maxEjectionPercent: getOutlierMaxEjectionPercent(od.MaxEjectionPercent),
+	}
+
+	if od.Consecutive5xx != nil {
+		settings.consecutive5xx = *od.Consecutive5xx
+	}
+
+	if od.ConsecutiveGatewayErrors != nil {
+		settings.consecutiveFails = *od.ConsecutiveGatewayErrors
+	}
+
+	return settings
+}
+
+func newStreamOutlierDetectionSettings(od *conf_v1alpha1.OutlierDetection) outlierDetectionSettings {
+	settings := outlierDetectionSettings{
+		ejectionTime:       getOutlierEjectionTime(od.EjectionTime),
+		maxEjectionPercent: getOutlierMaxEjectionPercent(od.MaxEjectionPercent),
+	}
+
+	if od.ConsecutiveErrors != nil {
+		settings.consecutiveFails = *od.ConsecutiveErrors
+	}
+
+	return settings
+}
+
+func getOutlierEjectionTime(ejectionTime string) time.Duration {
+	return parseDurationOrDefault(ejectionTime, defaultOutlierEjectionTime, "ejection time of the outlier detection")
+}
+
+func getOutlierMaxEjectionPercent(maxEjectionPercent *int) int {
+	if maxEjectionPercent == nil {
+		return defaultOutlierMaxEjectionPercent
+	}
+
+	return *maxEjectionPercent
+}
+
+// outlierUpstream is an NGINX upstream with an outlier detection.
+type outlierUpstream struct {
+	settings outlierDetectionSettings
+	stream   bool
+	// resource is the VirtualServer, VirtualServerRoute or TransportServer that defines the upstream.
+	resource runtime.Object
+}
+
+// getUpstreamsWithOutlierDetection returns the NGINX upstreams with an outlier detection of the resources keyed by the
+// name of the upstream.
+func getUpstreamsWithOutlierDetection(resources []Resource) map[string]outlierUpstream {
+	upstreams := make(map[string]outlierUpstream)
+
+	for _, r := range resources {
+		switch impl := r.(type) {
+		case *VirtualServerConfiguration:
+			vs := impl.VirtualServer
+
+			for _, u := range vs.Spec.Upstreams {
+				if u.OutlierDetection != nil {
+					upstreams[configs.GetUpstreamNameForVirtualServer(vs, u.Name)] = outlierUpstream{
+						settings: newOutlierDetectionSettings(u.OutlierDetection),
+						resource: vs,
+					}
+				}
+			}
+
+			for _, vsr := range impl.VirtualServerRoutes {
+				for _, u := range vsr.Spec.Upstreams {
+					if u.OutlierDetection != nil {
+						upstreams[configs.GetUpstreamNameForVirtualServerRoute(vs, vsr, u.Name)] = outlierUpstream{
+							settings: newOutlierDetectionSettings(u.OutlierDetection),
+							resource: vsr,
+						}
+					}
+				}
+			}
+		case *TransportServerConfiguration:
+			ts := impl.TransportServer
+
+			for _, u := range ts.Spec.Upstreams {
+				if u.OutlierDetection != nil {
+					upstreams[configs.GetUpstreamNameForTransportServer(ts, u.Name)] = outlierUpstream{
+						settings: newStreamOutlierDetectionSettings(u.OutlierDetection),
+						stream:   true,
+						resource: ts,
+					}
+				}
+			}
+		}
+	}
+
+	return upstreams
+}
+
+// peerCounters holds the counters of a peer reported by the NGINX Plus API.
+type peerCounters struct {
+	id     int
+	server string
+	state  string
+	// successes is the number of the responses other than 5xx for HTTP peers
+	// or the number of the successful connections for stream peers.
+	successes uint64
+	errors5xx uint64
+	fails     uint64
+}
+
+func getPeerCounters(peers []client.Peer) []peerCounters {
+	var counters []peerCounters
+
+	for _, p := range peers {
+		counters = append(counters, peerCounters{
+			id:        p.ID,
+			server:    p.Server,
+			state:     p.State,
+			successes: p.Responses.Total - p.Responses.Responses5xx,
+			errors5xx: p.Responses.Responses5xx,
+			fails:     p.Fails,
+		})
+	}
+
+	return counters
+}
+
+func getStreamPeerCounters(peers []client.StreamPeer) []peerCounters {
+	var counters []peerCounters
+
+	for _, p := range peers {
+		counters = append(counters, peerCounters{
+			id:        p.ID,
+			server:    p.Server,
+			state:     p.State,
+			successes: p.Connections - p.Fails,
+			fails:     p.Fails,
+		})
+	}
+
+	return counters
+}
+
+// peerOutlierState holds the consecutive errors of a peer and its ejection.
+type peerOutlierState struct {
+	last             peerCounters
+	consecutive5xx   uint64
+	consecutiveFails uint64
+	ejectedUntil     time.Time
+}
+
+func (p *peerOutlierState) isEjected() bool {
+	return !p.ejectedUntil.IsZero()
+}
+
+func (p *peerOutlierState) reset(counters peerCounters) {
+	p.last = counters
+	p.consecutive5xx = 0
+	p.consecutiveFails = 0
+}
+
+type upstreamOutliers struct {
+	stream bool
+	peers  map[string]*peerOutlierState
+}
+
+type outlierActionKind int
+
+const (
+	// ejectPeer marks the peer as down because it exceeded a threshold.
+	ejectPeer outlierActionKind = iota
+	// reejectPeer marks the ejected peer as down again, for example, after a reload of NGINX.
+	reejectPeer
+	// restorePeer marks the ejected peer as up after the ejection time.
+	restorePeer
+	// forgetPeer tells that the peer no longer exists.
+	forgetPeer
+)
+
+// outlierAction is an update of a peer of an upstream.
+type outlierAction struct {
+	kind     outlierActionKind
+	upstream string
+	stream   bool
+	id       int
+	server   string
+	reason   string
+}
+
+// outlierDetector keeps track of the consecutive errors of the peers of the upstreams with an outlier detection.
+// Because the NGINX Plus API only reports the counters of the peers, an error is consecutive if the peer
+// has no successful responses or connections since the previous check.
+type outlierDetector struct {
+	upstreams map[string]*upstreamOutliers
+}
+
+func newOutlierDetector() *outlierDetector {
+	return &outlierDetector{
+		upstreams: make(map[string]*upstreamOutliers),
+	}
+}
+
+// detect checks the peers of the upstream and returns the peers to eject, re-eject, restore or forget.
+func (d *outlierDetector) detect(upstream string, stream bool, settings outlierDetectionSettings, peers []peerCounters, now time.Time) []outlierAction {
+	u, exists := d.upstreams[upstream]
+	if !exists {
+		u = &upstreamOutliers{
+			stream: stream,
+			peers:  make(map[string]*peerOutlierState),
+		}
+		d.upstreams[upstream] = u
+	}
+
+	newAction := func(kind outlierActionKind, p peerCounters, reason string) outlierAction {
+		return outlierAction{
+			kind:     kind,
+			upstream: upstream,
+			stream:   stream,
+			id:       p.id,
+			server:   p.server,
+			reason:   reason,
+		}
+	}
+
+	var actions []outlierAction
+
+	current := make(map[string]bool)
+	for _, p := range peers {
+		current[p.server] = true
+	}
+
+	for _, server := range getSortedPeerServers(u.peers) {
+		if !current[server] {
+			actions = append(actions, outlierAction{kind: forgetPeer, upstream: upstream, stream: stream, server: server})
+			delete(u.peers, server)
+		}
+	}
+
+	ejected := 0
+	for _, p := range u.peers {
+		if p.isEjected() && now.Before(p.ejectedUntil) {
+			ejected++
+		}
+	}
+
+	maxEjected := len(peers) * settings.maxEjectionPercent / 100
+	if maxEjected < 1 {
+		maxEjected = 1
+	}
+
+	for _, p := range peers {
+		state, exists := u.peers[p.server]
+		if !exists {
+			state = &peerOutlierState{}
+			state.reset(p)
+			u.peers[p.server] = state
+			continue
+		}
+
+		if state.isEjected() {
+			if !now.Before(state.ejectedUntil) {
+				state.ejectedUntil = time.Time{}
+				state.reset(p)
+				actions = append(actions, newAction(restorePeer, p, ""))
+				continue
+			}
+
+			if p.state != "down" {
+				actions = append(actions, newAction(reejectPeer, p, ""))
+			}
+
+			state.reset(p)
+			continue
+		}
+
+		// the counters of a peer decrease after a reload of NGINX or if the peer was re-added
+		if p.id != state.last.id || p.successes < state.last.successes || p.errors5xx < state.last.errors5xx || p.fails < state.last.fails {
+			state.reset(p)
+			continue
+		}
+
+		if p.successes > state.last.successes {
+			state.consecutive5xx = 0
+			state.consecutiveFails = 0
+		}
+
+		state.consecutive5xx += p.errors5xx - state.last.errors5xx
+		state.consecutiveFails += p.fails - state.last.fails
+		state.last = p
+
+		reason := checkOutlier(settings, state)
+		if reason == "" {
+			continue
+		}
+
+		if ejected >= maxEjected {
+			glog.V(3).Infof("Cannot eject peer %v of upstream %v: %v, the maximum of %v%% of the peers are ejected", p.server, upstream, reason, settings.maxEjectionPercent)
+			continue
+		}
+
+		state.ejectedUntil = now.Add(settings.ejectionTime)
+		state.reset(p)
+		ejected++
+
+		actions = append(actions, newAction(ejectPeer, p, reason))
+	}
+
+	return actions
+}
+
+// checkOutlier returns the reason for the ejection of the peer or an empty string if the peer is healthy.
+func checkOutlier(settings outlierDetectionSettings, state *peerOutlierState) string {
+	if settings.consecutive5xx > 0 && state.consecutive5xx >= uint64(settings.consecutive5xx) {
+		return fmt.Sprintf("%d consecutive 5xx responses", state.consecutive5xx)
+	}
+
+	if settings.consecutiveFails > 0 && state.consecutiveFails >= uint64(settings.consecutiveFails) {
+		return fmt.Sprintf("%d consecutive errors", state.consecutiveFails)
+	}
+
+	return ""
+}
+
+// retain removes the upstreams that no longer have an outlier detection and returns the actions to restore their
+// ejected peers and forget all their peers.
+func (d *outlierDetector) retain(upstreams map[string]outlierUpstream) []outlierAction {
+	var names []string
+	for name := range d.upstreams {
+		if _, exists := upstreams[name]; !exists {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	var actions []outlierAction
+
+	for _, name := range names {
+		u := d.upstreams[name]
+
+		for _, server := range getSortedPeerServers(u.peers) {
+			state := u.peers[server]
+
+			if state.isEjected() {
+				actions = append(actions, outlierAction{
+					kind:     restorePeer,
+					upstream: name,
+					stream:   u.stream,
+					id:       state.last.id,
+					server:   server,
+				})
+			}
+
+			actions = append(actions, outlierAction{kind: forgetPeer, upstream: name, stream: u.stream, server: server})
+		}
+
+		delete(d.upstreams, name)
+	}
+
+	return actions
+}
+
+func getSortedPeerServers(peers map[string]*peerOutlierState) []string {
+	var servers []string
+	for server := range peers {
+		servers = append(servers, server)
+	}
+
+	sort.Strings(servers)
+
+	return servers
+}
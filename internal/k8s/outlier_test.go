@@ -0,0 +1,271 @@
+package k8s
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
+	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestOutlierDetectorDetect(t *testing.T) {
+	settings := outlierDetectionSettings{
+		consecutive5xx:     3,
+		consecutiveFails:   2,
+		ejectionTime:       30 * time.Second,
+		maxEjectionPercent: 50,
+	}
+	now := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	detector := newOutlierDetector()
+
+	peers := []peerCounters{
+		{id: 0, server: "10.0.0.1:80", state: "up", successes: 10},
+		{id: 1, server: "10.0.0.2:80", state: "up", successes: 10},
+	}
+
+	actions := detector.detect("vs_default_cafe_coffee", false, settings, peers, now)
+	if len(actions) != 0 {
+		t.Errorf("detect() returned %v for new peers but expected no actions", actions)
+	}
+
+	// the first peer returns 2 errors and a success, the second peer returns 3 errors
+	peers = []peerCounters{
+		{id: 0, server: "10.0.0.1:80", state: "up", successes: 11, errors5xx: 2},
+		{id: 1, server: "10.0.0.2:80", state: "up", successes: 10, errors5xx: 3},
+	}
+	now = now.Add(outlierDetectionInterval)
+
+	actions = detector.detect("vs_default_cafe_coffee", false, settings, peers, now)
+	expected := []outlierAction{
+		{kind: ejectPeer, upstream: "vs_default_cafe_coffee", id: 1, server: "10.0.0.2:80", reason: "3 consecutive 5xx responses"},
+	}
+	if !reflect.DeepEqual(actions, expected) {
+		t.Errorf("detect() returned %v but expected %v", actions, expected)
+	}
+
+	// the first peer returns 2 errors; the maximum of the ejected peers is reached
+	peers = []peerCounters{
+		{id: 0, server: "10.0.0.1:80", state: "up", successes: 11, errors5xx: 2, fails: 2},
+		{id: 1, server: "10.0.0.2:80", state: "down", successes: 10, errors5xx: 3},
+	}
+	now = now.Add(outlierDetectionInterval)
+
+	actions = detector.detect("vs_default_cafe_coffee", false, settings, peers, now)
+	if len(actions) != 0 {
+		t.Errorf("detect() returned %v when the maximum of the ejected peers is reached but expected no actions", actions)
+	}
+
+	// NGINX was reloaded: the counters are reset and the ejected peer is up
+	peers = []peerCounters{
+		{id: 0, server: "10.0.0.1:80", state: "up"},
+		{id: 1, server: "10.0.0.2:80", state: "up"},
+	}
+	now = now.Add(outlierDetectionInterval)
+
+	actions = detector.detect("vs_default_cafe_coffee", false, settings, peers, now)
+	expected = []outlierAction{
+		{kind: reejectPeer, upstream: "vs_default_cafe_coffee", id: 1, server: "10.0.0.2:80"},
+	}
+	if !reflect.DeepEqual(actions, expected) {
+		t.Errorf("detect() returned %v after a reload but expected %v", actions, expected)
+	}
+
+	// the ejection time of the second peer is over and the first peer is removed
+	peers = []peerCounters{
+		{id: 1, server: "10.0.0.2:80", state: "down"},
+	}
+	now = now.Add(settings.ejectionTime)
+
+	actions = detector.detect("vs_default_cafe_coffee", false, settings, peers, now)
+	expected = []outlierAction{
+		{kind: forgetPeer, upstream: "vs_default_cafe_coffee", server: "10.0.0.1:80"},
+		{kind: restorePeer, upstream: "vs_default_cafe_coffee", id: 1, server: "10.0.0.2:80"},
+	}
+	if !reflect.DeepEqual(actions, expected) {
+		t.Errorf("detect() returned %v after the ejection time but expected %v", actions, expected)
+	}
+}
+
+func TestOutlierDetectorDetectConsecutiveErrors(t *testing.T) {
+	settings := outlierDetectionSettings{
+		consecutiveFails:   3,
+		ejectionTime:       30 * time.Second,
+		maxEjectionPercent: 10,
+	}
+	now := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	detector := newOutlierDetector()
+
+	checks := []struct {
+		peer     peerCounters
+		expected []outlierAction
+		msg      string
+	}{
+		{
+			peer:     peerCounters{id: 0, server: "10.0.0.1:5353", state: "up", successes: 5},
+			expected: nil,
+			msg:      "new peer",
+		},
+		{
+			peer:     peerCounters{id: 0, server: "10.0.0.1:5353", state: "up", successes: 5, fails: 2},
+			expected: nil,
+			msg:      "2 consecutive errors",
+		},
+		{
+			peer:     peerCounters{id: 0, server: "10.0.0.1:5353", state: "up", successes: 6, fails: 3},
+			expected: nil,
+			msg:      "an error and a success",
+		},
+		{
+			peer: peerCounters{id: 0, server: "10.0.0.1:5353", state: "up", successes: 6, fails: 5},
+			expected: []outlierAction{
+				{kind: ejectPeer, upstream: "ts_default_dns_dns", stream: true, id: 0, server: "10.0.0.1:5353", reason: "3 consecutive errors"},
+			},
+			msg: "3 consecutive errors",
+		},
+	}
+
+	for _, c := range checks {
+		actions := detector.detect("ts_default_dns_dns", true, settings, []peerCounters{c.peer}, now)
+		if !reflect.DeepEqual(actions, c.expected) {
+			t.Errorf("detect() returned %v but expected %v for the case of %s", actions, c.expected, c.msg)
+		}
+		now = now.Add(outlierDetectionInterval)
+	}
+}
+
+func TestOutlierDetectorRetain(t *testing.T) {
+	settings := outlierDetectionSettings{
+		consecutive5xx:     1,
+		ejectionTime:       30 * time.Second,
+		maxEjectionPercent: 100,
+	}
+	now := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	detector := newOutlierDetector()
+
+	detector.detect("vs_default_cafe_tea", false, settings, []peerCounters{{id: 0, server: "10.0.0.3:80"}}, now)
+	detector.detect("vs_default_cafe_coffee", false, settings, []peerCounters{{id: 0, server: "10.0.0.1:80"}, {id: 1, server: "10.0.0.2:80"}}, now)
+	detector.detect("vs_default_cafe_coffee", false, settings, []peerCounters{{id: 0, server: "10.0.0.1:80", errors5xx: 1}, {id: 1, server: "10.0.0.2:80"}}, now)
+
+	actions := detector.retain(map[string]outlierUpstream{
+		"vs_default_cafe_tea": {settings: settings},
+	})
+	expected := []outlierAction{
+		{kind: restorePeer, upstream: "vs_default_cafe_coffee", id: 0, server: "10.0.0.1:80"},
+		{kind: forgetPeer, upstream: "vs_default_cafe_coffee", server: "10.0.0.1:80"},
+		{kind: forgetPeer, upstream: "vs_default_cafe_coffee", server: "10.0.0.2:80"},
+	}
+	if !reflect.DeepEqual(actions, expected) {
+		t.Errorf("retain() returned %v but expected %v", actions, expected)
+	}
+
+	if _, exists := detector.upstreams["vs_default_cafe_coffee"]; exists {
+		t.Errorf("retain() didn't remove the upstream vs_default_cafe_coffee")
+	}
+	if _, exists := detector.upstreams["vs_default_cafe_tea"]; !exists {
+		t.Errorf("retain() removed the upstream vs_default_cafe_tea")
+	}
+}
+
+func TestGetUpstreamsWithOutlierDetection(t *testing.T) {
+	vs := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+		Spec: conf_v1.VirtualServerSpec{
+			Upstreams: []conf_v1.Upstream{
+				{
+					Name: "tea",
+				},
+				{
+					Name: "coffee",
+					OutlierDetection: &conf_v1.OutlierDetection{
+						Consecutive5xx: createPointerFromInt(5),
+					},
+				},
+			},
+		},
+	}
+	vsr := &conf_v1.VirtualServerRoute{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "juice",
+			Namespace: "default",
+		},
+		Spec: conf_v1.VirtualServerRouteSpec{
+			Upstreams: []conf_v1.Upstream{
+				{
+					Name: "orange",
+					OutlierDetection: &conf_v1.OutlierDetection{
+						ConsecutiveGatewayErrors: createPointerFromInt(2),
+						EjectionTime:             "1m",
+						MaxEjectionPercent:       createPointerFromInt(50),
+					},
+				},
+			},
+		},
+	}
+	ts := &conf_v1alpha1.TransportServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "dns",
+			Namespace: "default",
+		},
+		Spec: conf_v1alpha1.TransportServerSpec{
+			Upstreams: []conf_v1alpha1.Upstream{
+				{
+					Name: "dns",
+					OutlierDetection: &conf_v1alpha1.OutlierDetection{
+						ConsecutiveErrors: createPointerFromInt(3),
+					},
+				},
+			},
+		},
+	}
+
+	resources := []Resource{
+		&VirtualServerConfiguration{
+			VirtualServer:       vs,
+			VirtualServerRoutes: []*conf_v1.VirtualServerRoute{vsr},
+		},
+		&TransportServerConfiguration{
+			TransportServer: ts,
+		},
+	}
+
+	expected := map[string]outlierUpstream{
+		"vs_default_cafe_coffee": {
+			settings: outlierDetectionSettings{
+				consecutive5xx:     5,
+				ejectionTime:       defaultOutlierEjectionTime,
+				maxEjectionPercent: defaultOutlierMaxEjectionPercent,
+			},
+			resource: vs,
+		},
+		"vs_default_cafe_vsr_default_juice_orange": {
+			settings: outlierDetectionSettings{
+				consecutiveFails:   2,
+				ejectionTime:       time.Minute,
+				maxEjectionPercent: 50,
+			},
+			resource: vsr,
+		},
+		"ts_default_dns_dns": {
+			settings: outlierDetectionSettings{
+				consecutiveFails:   3,
+				ejectionTime:       defaultOutlierEjectionTime,
+				maxEjectionPercent: defaultOutlierMaxEjectionPercent,
+			},
+			stream:   true,
+			resource: ts,
+		},
+	}
+
+	result := getUpstreamsWithOutlierDetection(resources)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("getUpstreamsWithOutlierDetection() returned %v but expected %v", result, expected)
+	}
+}
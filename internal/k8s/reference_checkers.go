@@ -4,7 +4,7 @@ import (
 	"github.com/nginxinc/kubernetes-ingress/internal/configs"
 	v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
 	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
-	networking "k8s.io/api/networking/v1beta1"
+	networking "k8s.io/api/networking/v1"
 )
 
 type resourceReferenceChecker interface {
@@ -94,8 +94,8 @@ func (rc *serviceReferenceChecker) IsReferencedByIngress(svcNamespace string, sv
 		return false
 	}
 
-	if ing.Spec.Backend != nil {
-		if ing.Spec.Backend.ServiceName == svcName {
+	if ing.Spec.DefaultBackend != nil && ing.Spec.DefaultBackend.Service != nil {
+		if ing.Spec.DefaultBackend.Service.Name == svcName {
 			return true
 		}
 	}
@@ -104,7 +104,7 @@ func (rc *serviceReferenceChecker) IsReferencedByIngress(svcNamespace string, sv
 			continue
 		}
 		for _, p := range rules.IngressRuleValue.HTTP.Paths {
-			if p.Backend.ServiceName == svcName {
+			if p.Backend.Service != nil && p.Backend.Service.Name == svcName {
 				return true
 			}
 		}
@@ -0,0 +1,253 @@
+package k8s
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/nginxinc/kubernetes-ingress/internal/metrics/collectors"
+	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// upstreamAnalysisInterval is how often the Ingress Controller checks the upstreams of the routes with a rollback.
+	upstreamAnalysisInterval = 10 * time.Second
+	// upstreamAnalysisTaskKey is the key of the task that checks the upstreams of the routes with a rollback.
+	upstreamAnalysisTaskKey = "upstream-analysis"
+
+	defaultRollbackWindow            = time.Minute
+	defaultRollbackMinRequests       = 10
+	defaultRollbackLatencyPercentile = 99
+)
+
+// rollbackSpec holds the parts of a route that define its rollback targets.
+// A change of any of them cancels the rollbacks of the route.
+type rollbackSpec struct {
+	splits   []conf_v1.Split
+	canary   *conf_v1.Canary
+	rollback *conf_v1.Rollback
+}
+
+// routeRollback holds the rolled back upstreams of a route.
+type routeRollback struct {
+	spec      rollbackSpec
+	upstreams map[string]conf_v1.RollbackStatus
+}
+
+// upstreamRollbacks keeps the rolled back upstreams of the routes of VirtualServers and VirtualServerRoutes.
+// The rollbacks of a VirtualServer are keyed by the path of the route.
+type upstreamRollbacks struct {
+	rollbacks map[string]map[string]*routeRollback
+}
+
+func newUpstreamRollbacks() *upstreamRollbacks {
+	return &upstreamRollbacks{
+		rollbacks: make(map[string]map[string]*routeRollback),
+	}
+}
+
+// update updates the rollbacks of the routes of the VirtualServer and its VirtualServerRoutes and returns
+// the rolled back upstreams keyed by the path of the route.
+// If the Ingress Controller doesn't know about a route yet, it resumes the rollbacks from the status of the VirtualServer.
+func (u *upstreamRollbacks) update(vs *conf_v1.VirtualServer, vsrs []*conf_v1.VirtualServerRoute) map[string]map[string]bool {
+	key := getResourceKey(&vs.ObjectMeta)
+
+	oldRollbacks := u.rollbacks[key]
+	newRollbacks := make(map[string]*routeRollback)
+	rolledBack := make(map[string]map[string]bool)
+
+	for _, r := range getRoutesWithRollback(vs, vsrs) {
+		route := r.DeepCopy()
+		spec := rollbackSpec{
+			splits:   route.Splits,
+			canary:   route.Canary,
+			rollback: route.Rollback,
+		}
+
+		rr, exists := oldRollbacks[r.Path]
+		if !exists {
+			rr = newRouteRollbackFromStatus(r.Path, spec, vs.Status.Rollbacks)
+		} else if !reflect.DeepEqual(rr.spec, spec) {
+			if len(rr.upstreams) > 0 {
+				glog.V(2).Infof("The route %v of VirtualServer %v was updated, cancelling its rollbacks", r.Path, key)
+			}
+			rr = &routeRollback{
+				spec:      spec,
+				upstreams: make(map[string]conf_v1.RollbackStatus),
+			}
+		}
+
+		newRollbacks[r.Path] = rr
+
+		if len(rr.upstreams) > 0 {
+			rolledBack[r.Path] = make(map[string]bool)
+			for upstream := range rr.upstreams {
+				rolledBack[r.Path][upstream] = true
+			}
+		}
+	}
+
+	if len(newRollbacks) == 0 {
+		delete(u.rollbacks, key)
+	} else {
+		u.rollbacks[key] = newRollbacks
+	}
+
+	return rolledBack
+}
+
+func newRouteRollbackFromStatus(path string, spec rollbackSpec, statuses []conf_v1.RollbackStatus) *routeRollback {
+	rr := &routeRollback{
+		spec:      spec,
+		upstreams: make(map[string]conf_v1.RollbackStatus),
+	}
+
+	targets := make(map[string]bool)
+	for _, t := range getRollbackTargets(spec.splits, spec.canary) {
+		targets[t] = true
+	}
+
+	for _, s := range statuses {
+		if s.Path == path && targets[s.Upstream] {
+			rr.upstreams[s.Upstream] = s
+		}
+	}
+
+	return rr
+}
+
+// isRolledBack tells if the upstream of the route of the VirtualServer is rolled back.
+func (u *upstreamRollbacks) isRolledBack(vsKey string, path string, upstream string) bool {
+	rr, exists := u.rollbacks[vsKey][path]
+	if !exists {
+		return false
+	}
+
+	_, rolledBack := rr.upstreams[upstream]
+
+	return rolledBack
+}
+
+// rollBack rolls back the upstream of the route of the VirtualServer.
+func (u *upstreamRollbacks) rollBack(vsKey string, path string, upstream string, reason string, now time.Time) {
+	rr, exists := u.rollbacks[vsKey][path]
+	if !exists {
+		glog.Warningf("Cannot roll back upstream %v of unknown route %v of VirtualServer %v", upstream, path, vsKey)
+		return
+	}
+
+	rr.upstreams[upstream] = conf_v1.RollbackStatus{
+		Path:     path,
+		Upstream: upstream,
+		Reason:   reason,
+		Time:     meta_v1.NewTime(now),
+	}
+}
+
+// getStatuses returns the statuses of the rolled back upstreams of the VirtualServer.
+func (u *upstreamRollbacks) getStatuses(vsKey string) []conf_v1.RollbackStatus {
+	var statuses []conf_v1.RollbackStatus
+
+	for _, rr := range u.rollbacks[vsKey] {
+		for _, s := range rr.upstreams {
+			statuses = append(statuses, s)
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Path != statuses[j].Path {
+			return statuses[i].Path < statuses[j].Path
+		}
+		return statuses[i].Upstream < statuses[j].Upstream
+	})
+
+	return statuses
+}
+
+// delete removes the rollbacks of the VirtualServer.
+func (u *upstreamRollbacks) delete(vsKey string) {
+	delete(u.rollbacks, vsKey)
+}
+
+func getRoutesWithRollback(vs *conf_v1.VirtualServer, vsrs []*conf_v1.VirtualServerRoute) []conf_v1.Route {
+	var routes []conf_v1.Route
+
+	for _, r := range vs.Spec.Routes {
+		if r.Rollback != nil {
+			routes = append(routes, r)
+		}
+	}
+
+	for _, vsr := range vsrs {
+		for _, r := range vsr.Spec.Subroutes {
+			if r.Rollback != nil {
+				routes = append(routes, r)
+			}
+		}
+	}
+
+	return routes
+}
+
+// getRollbackTargets returns the upstreams of a route that can be rolled back: the canary upstream of a canary release
+// or the upstreams of all splits except the first one.
+func getRollbackTargets(splits []conf_v1.Split, canary *conf_v1.Canary) []string {
+	if canary != nil {
+		return []string{canary.Canary}
+	}
+
+	var targets []string
+
+	for i, s := range splits {
+		if i == 0 || s.Action == nil {
+			continue
+		}
+
+		if s.Action.Proxy != nil && s.Action.Proxy.Upstream != "" {
+			targets = append(targets, s.Action.Proxy.Upstream)
+		} else if s.Action.Pass != "" {
+			targets = append(targets, s.Action.Pass)
+		}
+	}
+
+	return targets
+}
+
+// checkRollback checks the statistics of an upstream against the thresholds of the rollback.
+// It returns the reason for the rollback or an empty string if the upstream is healthy.
+func checkRollback(rollback *conf_v1.Rollback, stats collectors.UpstreamStatsSnapshot) string {
+	minRequests := defaultRollbackMinRequests
+	if rollback.MinRequests != nil {
+		minRequests = *rollback.MinRequests
+	}
+
+	if stats.Requests == 0 || stats.Requests < minRequests {
+		return ""
+	}
+
+	if rollback.MaxErrorRate != nil {
+		if rate := stats.ErrorRate(); rate > float64(*rollback.MaxErrorRate) {
+			return fmt.Sprintf("error rate %.1f%% exceeds %d%%", rate, *rollback.MaxErrorRate)
+		}
+	}
+
+	if maxLatency := parseDurationOrDefault(rollback.MaxLatency, 0, "max latency of the rollback"); maxLatency > 0 {
+		percentile := defaultRollbackLatencyPercentile
+		if rollback.LatencyPercentile != nil {
+			percentile = *rollback.LatencyPercentile
+		}
+
+		if latency := stats.LatencyPercentile(percentile); latency > maxLatency {
+			return fmt.Sprintf("latency p%d exceeds %v", percentile, maxLatency)
+		}
+	}
+
+	return ""
+}
+
+func getRollbackWindow(rollback *conf_v1.Rollback) time.Duration {
+	return parseDurationOrDefault(rollback.Window, defaultRollbackWindow, "window of the rollback")
+}
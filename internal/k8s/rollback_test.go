@@ -0,0 +1,188 @@
+package k8s
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nginxinc/kubernetes-ingress/internal/metrics/collectors"
+	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func createPointerFromInt(n int) *int {
+	return &n
+}
+
+func createTestVirtualServerWithRollback() *conf_v1.VirtualServer {
+	return &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+		Spec: conf_v1.VirtualServerSpec{
+			Routes: []conf_v1.Route{
+				{
+					Path: "/",
+					Splits: []conf_v1.Split{
+						{
+							Weight: 80,
+							Action: &conf_v1.Action{
+								Pass: "coffee-v1",
+							},
+						},
+						{
+							Weight: 20,
+							Action: &conf_v1.Action{
+								Pass: "coffee-v2",
+							},
+						},
+					},
+					Rollback: &conf_v1.Rollback{
+						MaxErrorRate: createPointerFromInt(5),
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestUpstreamRollbacks(t *testing.T) {
+	vs := createTestVirtualServerWithRollback()
+	now := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	rollbacks := newUpstreamRollbacks()
+
+	rolledBack := rollbacks.update(vs, nil)
+	if len(rolledBack) != 0 {
+		t.Errorf("update() returned %v but expected no rolled back upstreams", rolledBack)
+	}
+
+	rollbacks.rollBack("default/cafe", "/", "coffee-v2", "error rate 10.0% exceeds 5%", now)
+
+	rolledBack = rollbacks.update(vs, nil)
+	expected := map[string]map[string]bool{
+		"/": {
+			"coffee-v2": true,
+		},
+	}
+	if !reflect.DeepEqual(rolledBack, expected) {
+		t.Errorf("update() returned %v but expected %v", rolledBack, expected)
+	}
+
+	statuses := rollbacks.getStatuses("default/cafe")
+	if len(statuses) != 1 || statuses[0].Upstream != "coffee-v2" {
+		t.Errorf("getStatuses() returned %+v but expected a single status for coffee-v2", statuses)
+	}
+
+	// the rollbacks survive a restart
+	resumed := newUpstreamRollbacks()
+	vs.Status.Rollbacks = statuses
+	rolledBack = resumed.update(vs, nil)
+	if !reflect.DeepEqual(rolledBack, expected) {
+		t.Errorf("update() after a restart returned %v but expected %v", rolledBack, expected)
+	}
+
+	// a change of the splits cancels the rollbacks
+	vs.Spec.Routes[0].Splits[1].Action.Pass = "coffee-v3"
+	rolledBack = resumed.update(vs, nil)
+	if len(rolledBack) != 0 {
+		t.Errorf("update() returned %v after the splits changed but expected no rolled back upstreams", rolledBack)
+	}
+}
+
+func TestGetRollbackTargets(t *testing.T) {
+	splits := []conf_v1.Split{
+		{
+			Weight: 50,
+			Action: &conf_v1.Action{
+				Pass: "coffee-v1",
+			},
+		},
+		{
+			Weight: 30,
+			Action: &conf_v1.Action{
+				Proxy: &conf_v1.ActionProxy{
+					Upstream: "coffee-v2",
+				},
+			},
+		},
+		{
+			Weight: 20,
+			Action: &conf_v1.Action{
+				Return: &conf_v1.ActionReturn{
+					Body: "hello",
+				},
+			},
+		},
+	}
+
+	targets := getRollbackTargets(splits, nil)
+	expected := []string{"coffee-v2"}
+	if !reflect.DeepEqual(targets, expected) {
+		t.Errorf("getRollbackTargets() returned %v but expected %v for splits", targets, expected)
+	}
+
+	canary := &conf_v1.Canary{
+		Stable: "coffee-v1",
+		Canary: "coffee-v2",
+	}
+	targets = getRollbackTargets(nil, canary)
+	if !reflect.DeepEqual(targets, expected) {
+		t.Errorf("getRollbackTargets() returned %v but expected %v for canary", targets, expected)
+	}
+}
+
+func TestCheckRollback(t *testing.T) {
+	now := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	stats := collectors.NewUpstreamStats()
+	for i := 0; i < 18; i++ {
+		stats.Record("healthy", "200", 0.01, now)
+		stats.Record("failing", "200", 0.01, now)
+		stats.Record("slow", "200", 0.5, now)
+	}
+	for i := 0; i < 2; i++ {
+		stats.Record("healthy", "200", 0.01, now)
+		stats.Record("failing", "503", 0.01, now)
+		stats.Record("slow", "200", 0.5, now)
+	}
+	stats.Record("idle", "500", 0.01, now)
+
+	rollback := &conf_v1.Rollback{
+		MaxErrorRate: createPointerFromInt(5),
+		MaxLatency:   "100ms",
+	}
+
+	tests := []struct {
+		upstream   string
+		shouldFail bool
+	}{
+		{
+			upstream:   "healthy",
+			shouldFail: false,
+		},
+		{
+			upstream:   "failing",
+			shouldFail: true,
+		},
+		{
+			upstream:   "slow",
+			shouldFail: true,
+		},
+		{
+			upstream:   "idle",
+			shouldFail: false,
+		},
+	}
+
+	for _, test := range tests {
+		reason := checkRollback(rollback, stats.Get(test.upstream, time.Minute, now))
+		if test.shouldFail && reason == "" {
+			t.Errorf("checkRollback() returned no reason for %v", test.upstream)
+		}
+		if !test.shouldFail && reason != "" {
+			t.Errorf("checkRollback() returned reason %q for %v", reason, test.upstream)
+		}
+	}
+}
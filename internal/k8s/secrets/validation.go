@@ -6,6 +6,7 @@ import (
 	"encoding/pem"
 	"fmt"
 	"regexp"
+	"strings"
 
 	api_v1 "k8s.io/api/core/v1"
 )
@@ -19,6 +20,9 @@ const CAKey = "ca.crt"
 // ClientSecretKey is the key of the data field of a Secret where the OIDC client secret must be stored.
 const ClientSecretKey = "client-secret"
 
+// HtpasswdKey is the key of the data field of a Secret where the htpasswd file must be stored.
+const HtpasswdKey = "htpasswd"
+
 // SecretTypeCA contains a certificate authority for TLS certificate verification.
 const SecretTypeCA api_v1.SecretType = "nginx.org/ca"
 
@@ -28,6 +32,9 @@ const SecretTypeJWK api_v1.SecretType = "nginx.org/jwk"
 // SecretTypeOIDC contains an OIDC client secret for use in oauth flows.
 const SecretTypeOIDC api_v1.SecretType = "nginx.org/oidc"
 
+// SecretTypeHtpasswd contains an htpasswd file for use in basic authentication.
+const SecretTypeHtpasswd api_v1.SecretType = "nginx.org/htpasswd"
+
 // ValidateTLSSecret validates the secret. If it is valid, the function returns nil.
 func ValidateTLSSecret(secret *api_v1.Secret) error {
 	if secret.Type != api_v1.SecretTypeTLS {
@@ -103,12 +110,47 @@ func ValidateOIDCSecret(secret *api_v1.Secret) error {
 	return nil
 }
 
+// ValidateHtpasswdSecret validates the secret. If it is valid, the function returns nil.
+func ValidateHtpasswdSecret(secret *api_v1.Secret) error {
+	if secret.Type != SecretTypeHtpasswd {
+		return fmt.Errorf("Htpasswd secret must be of the type %v", SecretTypeHtpasswd)
+	}
+
+	htpasswd, exists := secret.Data[HtpasswdKey]
+	if !exists {
+		return fmt.Errorf("Htpasswd secret must have the data field %v", HtpasswdKey)
+	}
+
+	// we only validate the format of the lines: invalid password hashes will not make NGINX fail to reload,
+	// NGINX will reject the affected users.
+	users := 0
+	for i, line := range strings.Split(string(htpasswd), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("The line %d of the data field %v must be in the format user:password", i+1, HtpasswdKey)
+		}
+		users++
+	}
+
+	if users == 0 {
+		return fmt.Errorf("The data field %v must include at least one user", HtpasswdKey)
+	}
+
+	return nil
+}
+
 // IsSupportedSecretType checks if the secret type is supported.
 func IsSupportedSecretType(secretType api_v1.SecretType) bool {
 	return secretType == api_v1.SecretTypeTLS ||
 		secretType == SecretTypeCA ||
 		secretType == SecretTypeJWK ||
-		secretType == SecretTypeOIDC
+		secretType == SecretTypeOIDC ||
+		secretType == SecretTypeHtpasswd
 }
 
 // ValidateSecret validates the secret. If it is valid, the function returns nil.
@@ -122,6 +164,8 @@ func ValidateSecret(secret *api_v1.Secret) error {
 		return ValidateCASecret(secret)
 	case SecretTypeOIDC:
 		return ValidateOIDCSecret(secret)
+	case SecretTypeHtpasswd:
+		return ValidateHtpasswdSecret(secret)
 	}
 
 	return fmt.Errorf("Secret is of the unsupported type %v", secret.Type)
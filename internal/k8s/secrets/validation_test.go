@@ -312,6 +312,101 @@ func TestValidateOIDCSecretFails(t *testing.T) {
 	}
 }
 
+func TestValidateHtpasswdSecret(t *testing.T) {
+	secret := &v1.Secret{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "htpasswd-secret",
+			Namespace: "default",
+		},
+		Type: SecretTypeHtpasswd,
+		Data: map[string][]byte{
+			"htpasswd": []byte("# users\nfoo:$apr1$Eh8BXZbm$QlNLIrwJnb2LyFU4rwfmg0\n\nbar:{SHA}C+7Hteo/D9vJXQ3UfzxbwnXaijM=\n"),
+		},
+	}
+
+	err := ValidateHtpasswdSecret(secret)
+	if err != nil {
+		t.Errorf("ValidateHtpasswdSecret() returned error %v", err)
+	}
+}
+
+func TestValidateHtpasswdSecretFails(t *testing.T) {
+	tests := []struct {
+		secret *v1.Secret
+		msg    string
+	}{
+		{
+			secret: &v1.Secret{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "htpasswd-secret",
+					Namespace: "default",
+				},
+				Type: "some-type",
+				Data: map[string][]byte{
+					"htpasswd": []byte("foo:bar"),
+				},
+			},
+			msg: "Incorrect type for htpasswd secret",
+		},
+		{
+			secret: &v1.Secret{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "htpasswd-secret",
+					Namespace: "default",
+				},
+				Type: SecretTypeHtpasswd,
+			},
+			msg: "Missing htpasswd for htpasswd secret",
+		},
+		{
+			secret: &v1.Secret{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "htpasswd-secret",
+					Namespace: "default",
+				},
+				Type: SecretTypeHtpasswd,
+				Data: map[string][]byte{
+					"htpasswd": []byte("# no users\n"),
+				},
+			},
+			msg: "No users in htpasswd secret",
+		},
+		{
+			secret: &v1.Secret{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "htpasswd-secret",
+					Namespace: "default",
+				},
+				Type: SecretTypeHtpasswd,
+				Data: map[string][]byte{
+					"htpasswd": []byte("foo:bar\nbaz"),
+				},
+			},
+			msg: "Missing password in htpasswd secret",
+		},
+		{
+			secret: &v1.Secret{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "htpasswd-secret",
+					Namespace: "default",
+				},
+				Type: SecretTypeHtpasswd,
+				Data: map[string][]byte{
+					"htpasswd": []byte(":bar"),
+				},
+			},
+			msg: "Missing user in htpasswd secret",
+		},
+	}
+
+	for _, test := range tests {
+		err := ValidateHtpasswdSecret(test.secret)
+		if err == nil {
+			t.Errorf("ValidateHtpasswdSecret() returned no error for the case of %s", test.msg)
+		}
+	}
+}
+
 func TestValidateSecret(t *testing.T) {
 	tests := []struct {
 		secret *v1.Secret
@@ -370,6 +465,19 @@ func TestValidateSecret(t *testing.T) {
 			},
 			msg: "Valid OIDC secret",
 		},
+		{
+			secret: &v1.Secret{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "htpasswd-secret",
+					Namespace: "default",
+				},
+				Type: SecretTypeHtpasswd,
+				Data: map[string][]byte{
+					"htpasswd": []byte("foo:bar"),
+				},
+			},
+			msg: "Valid htpasswd secret",
+		},
 	}
 
 	for _, test := range tests {
@@ -449,6 +557,10 @@ func TestHasCorrectSecretType(t *testing.T) {
 			secretType: SecretTypeOIDC,
 			expected:   true,
 		},
+		{
+			secretType: SecretTypeHtpasswd,
+			expected:   true,
+		},
 		{
 			secretType: "some-type",
 			expected:   false,
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -14,16 +15,19 @@ import (
 	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
 	k8s_nginx "github.com/nginxinc/kubernetes-ingress/pkg/client/clientset/versioned"
 	api_v1 "k8s.io/api/core/v1"
-	networking "k8s.io/api/networking/v1beta1"
+	networking "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	typednetworking "k8s.io/client-go/kubernetes/typed/networking/v1beta1"
+	typednetworking "k8s.io/client-go/kubernetes/typed/networking/v1"
+	gateway_v1alpha1 "sigs.k8s.io/gateway-api/apis/v1alpha1"
+	gateway_versioned "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
 
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 )
 
-// statusUpdater reports Ingress, VirtualServer and VirtualServerRoute status information via the kubernetes
+// statusUpdater reports Ingress, VirtualServer, VirtualServerRoute and Gateway API status information via the kubernetes
 // API. For external information, it primarily reports the IP or host of the LoadBalancer Service exposing the
 // Ingress Controller, or an external IP specified in the ConfigMap.
 type statusUpdater struct {
@@ -44,6 +48,7 @@ type statusUpdater struct {
 	transportServerLister    cache.Store
 	policyLister             cache.Store
 	confClient               k8s_nginx.Interface
+	gatewayClient            gateway_versioned.Interface
 }
 
 func (su *statusUpdater) UpdateExternalEndpointsForResources(resource []Resource) error {
@@ -131,7 +136,7 @@ func (su *statusUpdater) updateIngressWithStatus(ing networking.Ingress, status
 	}
 
 	ingCopy.Status.LoadBalancer.Ingress = status
-	clientIngress := su.client.NetworkingV1beta1().Ingresses(ingCopy.Namespace)
+	clientIngress := su.client.NetworkingV1().Ingresses(ingCopy.Namespace)
 	_, err = clientIngress.UpdateStatus(context.TODO(), ingCopy, metav1.UpdateOptions{})
 	if err != nil {
 		glog.V(3).Infof("error setting ingress status: %v", err)
@@ -469,6 +474,78 @@ func (su *statusUpdater) UpdateVirtualServerStatus(vs *conf_v1.VirtualServer, st
 	return err
 }
 
+// UpdateVirtualServerRolloutStatus updates the status of the canary releases and the rolled back upstreams of a VirtualServer.
+func (su *statusUpdater) UpdateVirtualServerRolloutStatus(vs *conf_v1.VirtualServer, canaries []conf_v1.CanaryStatus, rollbacks []conf_v1.RollbackStatus) error {
+	// Get an up-to-date VirtualServer from the Store
+	vsLatest, exists, err := su.virtualServerLister.Get(vs)
+	if err != nil {
+		glog.V(3).Infof("error getting VirtualServer from Store: %v", err)
+		return err
+	}
+	if !exists {
+		glog.V(3).Infof("VirtualServer doesn't exist in Store")
+		return nil
+	}
+
+	vsCopy := vsLatest.(*conf_v1.VirtualServer).DeepCopy()
+
+	if !haveCanaryStatusesChanged(vsCopy.Status.Canaries, canaries) && !haveRollbackStatusesChanged(vsCopy.Status.Rollbacks, rollbacks) {
+		return nil
+	}
+
+	vsCopy.Status.Canaries = canaries
+	vsCopy.Status.Rollbacks = rollbacks
+
+	_, err = su.confClient.K8sV1().VirtualServers(vsCopy.Namespace).UpdateStatus(context.TODO(), vsCopy, metav1.UpdateOptions{})
+	if err != nil {
+		glog.V(3).Infof("error setting VirtualServer %v/%v status, retrying: %v", vsCopy.Namespace, vsCopy.Name, err)
+		return su.retryUpdateVirtualServerStatus(vsCopy)
+	}
+	return err
+}
+
+func haveCanaryStatusesChanged(old []conf_v1.CanaryStatus, new []conf_v1.CanaryStatus) bool {
+	if len(old) != len(new) {
+		return true
+	}
+
+	for i := range old {
+		o, n := old[i], new[i]
+		if o.Path != n.Path || o.Stable != n.Stable || o.Canary != n.Canary || o.Step != n.Step || o.Weight != n.Weight || o.State != n.State {
+			return true
+		}
+
+		// the API server stores the time with the precision of seconds
+		oldTime, newTime := o.StepStartTime.Rfc3339Copy(), n.StepStartTime.Rfc3339Copy()
+		if !oldTime.Equal(&newTime) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func haveRollbackStatusesChanged(old []conf_v1.RollbackStatus, new []conf_v1.RollbackStatus) bool {
+	if len(old) != len(new) {
+		return true
+	}
+
+	for i := range old {
+		o, n := old[i], new[i]
+		if o.Path != n.Path || o.Upstream != n.Upstream || o.Reason != n.Reason {
+			return true
+		}
+
+		// the API server stores the time with the precision of seconds
+		oldTime, newTime := o.Time.Rfc3339Copy(), n.Time.Rfc3339Copy()
+		if !oldTime.Equal(&newTime) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func hasVsrStatusChanged(vsr *conf_v1.VirtualServerRoute, state string, reason string, message string, referencedByString string) bool {
 	if vsr.Status.State != state {
 		return true
@@ -669,3 +746,316 @@ func (su *statusUpdater) retryUpdatePolicyStatus(polCopy *v1.Policy) error {
 
 	return nil
 }
+
+// mergeConditions returns the conditions with the transition times of the existing conditions of the same type and
+// status preserved, so that an unchanged condition stays unchanged.
+func mergeConditions(existing []metav1.Condition, conditions []metav1.Condition) []metav1.Condition {
+	result := make([]metav1.Condition, 0, len(conditions))
+
+	for _, c := range conditions {
+		if e := meta.FindStatusCondition(existing, c.Type); e != nil && e.Status == c.Status {
+			c.LastTransitionTime = e.LastTransitionTime
+		}
+		result = append(result, c)
+	}
+
+	return result
+}
+
+// UpdateGatewayClassStatus reports that a GatewayClass of the Ingress Controller is admitted.
+func (su *statusUpdater) UpdateGatewayClassStatus(gc *gateway_v1alpha1.GatewayClass) error {
+	conditions := mergeConditions(gc.Status.Conditions, []metav1.Condition{
+		{
+			Type:               string(gateway_v1alpha1.GatewayClassConditionStatusAdmitted),
+			Status:             metav1.ConditionTrue,
+			Reason:             string(gateway_v1alpha1.GatewayClassConditionStatusAdmitted),
+			Message:            "The GatewayClass is admitted by the Ingress Controller",
+			ObservedGeneration: gc.Generation,
+			LastTransitionTime: metav1.Now(),
+		},
+	})
+
+	if reflect.DeepEqual(gc.Status.Conditions, conditions) {
+		return nil
+	}
+
+	gcCopy := gc.DeepCopy()
+	gcCopy.Status.Conditions = conditions
+
+	_, err := su.gatewayClient.NetworkingV1alpha1().GatewayClasses().UpdateStatus(context.TODO(), gcCopy, metav1.UpdateOptions{})
+	if err != nil {
+		glog.V(3).Infof("error setting GatewayClass %v status, retrying: %v", gcCopy.Name, err)
+		return su.retryUpdateGatewayClassStatus(gcCopy)
+	}
+
+	return nil
+}
+
+func (su *statusUpdater) retryUpdateGatewayClassStatus(gcCopy *gateway_v1alpha1.GatewayClass) error {
+	gc, err := su.gatewayClient.NetworkingV1alpha1().GatewayClasses().Get(context.TODO(), gcCopy.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	gc.Status = gcCopy.Status
+	_, err = su.gatewayClient.NetworkingV1alpha1().GatewayClasses().UpdateStatus(context.TODO(), gc, metav1.UpdateOptions{})
+
+	return err
+}
+
+// UpdateGatewayStatus updates the conditions of a Gateway of the Ingress Controller and its listeners, and the
+// addresses of the Gateway. The listener problems are the problems of the listeners of the Gateway.
+func (su *statusUpdater) UpdateGatewayStatus(gw *gateway_v1alpha1.Gateway, listenerProblems []*metav1.Condition) error {
+	status := generateGatewayStatus(gw, listenerProblems, su.generateGatewayAddresses(), metav1.Now())
+
+	if reflect.DeepEqual(gw.Status, status) {
+		return nil
+	}
+
+	gwCopy := gw.DeepCopy()
+	gwCopy.Status = status
+
+	_, err := su.gatewayClient.NetworkingV1alpha1().Gateways(gwCopy.Namespace).UpdateStatus(context.TODO(), gwCopy, metav1.UpdateOptions{})
+	if err != nil {
+		glog.V(3).Infof("error setting Gateway %v/%v status, retrying: %v", gwCopy.Namespace, gwCopy.Name, err)
+		return su.retryUpdateGatewayStatus(gwCopy)
+	}
+
+	return nil
+}
+
+func (su *statusUpdater) retryUpdateGatewayStatus(gwCopy *gateway_v1alpha1.Gateway) error {
+	gw, err := su.gatewayClient.NetworkingV1alpha1().Gateways(gwCopy.Namespace).Get(context.TODO(), gwCopy.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	gw.Status = gwCopy.Status
+	_, err = su.gatewayClient.NetworkingV1alpha1().Gateways(gw.Namespace).UpdateStatus(context.TODO(), gw, metav1.UpdateOptions{})
+
+	return err
+}
+
+func (su *statusUpdater) generateGatewayAddresses() []gateway_v1alpha1.GatewayAddress {
+	var addresses []gateway_v1alpha1.GatewayAddress
+
+	for _, lb := range su.status {
+		addressType := gateway_v1alpha1.IPAddressType
+		value := lb.IP
+
+		if lb.IP == "" {
+			addressType = gateway_v1alpha1.NamedAddressType
+			value = lb.Hostname
+		}
+
+		addresses = append(addresses, gateway_v1alpha1.GatewayAddress{
+			Type:  &addressType,
+			Value: value,
+		})
+	}
+
+	return addresses
+}
+
+func generateGatewayStatus(
+	gw *gateway_v1alpha1.Gateway,
+	listenerProblems []*metav1.Condition,
+	addresses []gateway_v1alpha1.GatewayAddress,
+	now metav1.Time,
+) gateway_v1alpha1.GatewayStatus {
+	ready := metav1.Condition{
+		Type:               string(gateway_v1alpha1.GatewayConditionReady),
+		Status:             metav1.ConditionTrue,
+		Reason:             string(gateway_v1alpha1.GatewayConditionReady),
+		Message:            "The Gateway is ready",
+		ObservedGeneration: gw.Generation,
+		LastTransitionTime: now,
+	}
+
+	var listeners []gateway_v1alpha1.ListenerStatus
+
+	for i, l := range gw.Spec.Listeners {
+		var existing []metav1.Condition
+		if i < len(gw.Status.Listeners) && gw.Status.Listeners[i].Port == l.Port && gw.Status.Listeners[i].Protocol == l.Protocol {
+			existing = gw.Status.Listeners[i].Conditions
+		}
+
+		var conditions []metav1.Condition
+
+		if i < len(listenerProblems) && listenerProblems[i] != nil {
+			problem := *listenerProblems[i]
+			problem.ObservedGeneration = gw.Generation
+			problem.LastTransitionTime = now
+
+			conditions = append(conditions, problem, metav1.Condition{
+				Type:               string(gateway_v1alpha1.ListenerConditionReady),
+				Status:             metav1.ConditionFalse,
+				Reason:             string(gateway_v1alpha1.ListenerReasonInvalid),
+				Message:            problem.Message,
+				ObservedGeneration: gw.Generation,
+				LastTransitionTime: now,
+			})
+
+			ready.Status = metav1.ConditionFalse
+			ready.Reason = string(gateway_v1alpha1.GatewayReasonListenersNotValid)
+			ready.Message = "Some listeners of the Gateway are not valid"
+		} else {
+			conditions = append(conditions, metav1.Condition{
+				Type:               string(gateway_v1alpha1.ListenerConditionReady),
+				Status:             metav1.ConditionTrue,
+				Reason:             string(gateway_v1alpha1.ListenerConditionReady),
+				Message:            "The listener is ready",
+				ObservedGeneration: gw.Generation,
+				LastTransitionTime: now,
+			})
+		}
+
+		listeners = append(listeners, gateway_v1alpha1.ListenerStatus{
+			Port:       l.Port,
+			Protocol:   l.Protocol,
+			Hostname:   l.Hostname,
+			Conditions: mergeConditions(existing, conditions),
+		})
+	}
+
+	return gateway_v1alpha1.GatewayStatus{
+		Addresses: addresses,
+		Conditions: mergeConditions(gw.Status.Conditions, []metav1.Condition{
+			{
+				Type:               string(gateway_v1alpha1.GatewayConditionScheduled),
+				Status:             metav1.ConditionTrue,
+				Reason:             string(gateway_v1alpha1.GatewayConditionScheduled),
+				Message:            "The Gateway is scheduled by the Ingress Controller",
+				ObservedGeneration: gw.Generation,
+				LastTransitionTime: now,
+			},
+			ready,
+		}),
+		Listeners: listeners,
+	}
+}
+
+// UpdateHTTPRouteStatus updates the Admitted conditions of an HTTPRoute for the Gateways of the Ingress Controller.
+// The statuses for the Gateways of other controllers are preserved.
+func (su *statusUpdater) UpdateHTTPRouteStatus(route *gateway_v1alpha1.HTTPRoute, admissions map[string]*routeAdmission, controllerName string) error {
+	gateways := generateRouteGatewayStatuses(route.Status.Gateways, admissions, controllerName, route.Generation, metav1.Now())
+
+	if len(gateways) == 0 && len(route.Status.Gateways) == 0 || reflect.DeepEqual(route.Status.Gateways, gateways) {
+		return nil
+	}
+
+	routeCopy := route.DeepCopy()
+	routeCopy.Status.Gateways = gateways
+
+	_, err := su.gatewayClient.NetworkingV1alpha1().HTTPRoutes(routeCopy.Namespace).UpdateStatus(context.TODO(), routeCopy, metav1.UpdateOptions{})
+	if err != nil {
+		glog.V(3).Infof("error setting HTTPRoute %v/%v status, retrying: %v", routeCopy.Namespace, routeCopy.Name, err)
+		return su.retryUpdateHTTPRouteStatus(routeCopy)
+	}
+
+	return nil
+}
+
+func (su *statusUpdater) retryUpdateHTTPRouteStatus(routeCopy *gateway_v1alpha1.HTTPRoute) error {
+	route, err := su.gatewayClient.NetworkingV1alpha1().HTTPRoutes(routeCopy.Namespace).Get(context.TODO(), routeCopy.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	route.Status = routeCopy.Status
+	_, err = su.gatewayClient.NetworkingV1alpha1().HTTPRoutes(route.Namespace).UpdateStatus(context.TODO(), route, metav1.UpdateOptions{})
+
+	return err
+}
+
+// UpdateTLSRouteStatus updates the Admitted conditions of a TLSRoute for the Gateways of the Ingress Controller.
+// The statuses for the Gateways of other controllers are preserved.
+func (su *statusUpdater) UpdateTLSRouteStatus(route *gateway_v1alpha1.TLSRoute, admissions map[string]*routeAdmission, controllerName string) error {
+	gateways := generateRouteGatewayStatuses(route.Status.Gateways, admissions, controllerName, route.Generation, metav1.Now())
+
+	if len(gateways) == 0 && len(route.Status.Gateways) == 0 || reflect.DeepEqual(route.Status.Gateways, gateways) {
+		return nil
+	}
+
+	routeCopy := route.DeepCopy()
+	routeCopy.Status.Gateways = gateways
+
+	_, err := su.gatewayClient.NetworkingV1alpha1().TLSRoutes(routeCopy.Namespace).UpdateStatus(context.TODO(), routeCopy, metav1.UpdateOptions{})
+	if err != nil {
+		glog.V(3).Infof("error setting TLSRoute %v/%v status, retrying: %v", routeCopy.Namespace, routeCopy.Name, err)
+		return su.retryUpdateTLSRouteStatus(routeCopy)
+	}
+
+	return nil
+}
+
+func (su *statusUpdater) retryUpdateTLSRouteStatus(routeCopy *gateway_v1alpha1.TLSRoute) error {
+	route, err := su.gatewayClient.NetworkingV1alpha1().TLSRoutes(routeCopy.Namespace).Get(context.TODO(), routeCopy.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	route.Status = routeCopy.Status
+	_, err = su.gatewayClient.NetworkingV1alpha1().TLSRoutes(route.Namespace).UpdateStatus(context.TODO(), route, metav1.UpdateOptions{})
+
+	return err
+}
+
+// generateRouteGatewayStatuses generates the statuses of a route for the Gateways. The statuses for the Gateways of
+// other controllers are preserved, while the statuses for the Gateways of the Ingress Controller are replaced
+// with the admissions.
+func generateRouteGatewayStatuses(
+	existing []gateway_v1alpha1.RouteGatewayStatus,
+	admissions map[string]*routeAdmission,
+	controllerName string,
+	generation int64,
+	now metav1.Time,
+) []gateway_v1alpha1.RouteGatewayStatus {
+	var result []gateway_v1alpha1.RouteGatewayStatus
+	existingConditions := make(map[string][]metav1.Condition)
+
+	for _, s := range existing {
+		if s.GatewayRef.Controller == nil || *s.GatewayRef.Controller != controllerName {
+			result = append(result, s)
+			continue
+		}
+
+		existingConditions[s.GatewayRef.Namespace+"/"+s.GatewayRef.Name] = s.Conditions
+	}
+
+	var gwKeys []string
+	for k := range admissions {
+		gwKeys = append(gwKeys, k)
+	}
+	sort.Strings(gwKeys)
+
+	for _, gwKey := range gwKeys {
+		a := admissions[gwKey]
+		namespace, name, _ := ParseNamespaceName(gwKey)
+
+		status := metav1.ConditionFalse
+		if a.admitted {
+			status = metav1.ConditionTrue
+		}
+
+		result = append(result, gateway_v1alpha1.RouteGatewayStatus{
+			GatewayRef: gateway_v1alpha1.RouteStatusGatewayReference{
+				Name:       name,
+				Namespace:  namespace,
+				Controller: &controllerName,
+			},
+			Conditions: mergeConditions(existingConditions[gwKey], []metav1.Condition{
+				{
+					Type:               string(gateway_v1alpha1.ConditionRouteAdmitted),
+					Status:             status,
+					Reason:             a.reason,
+					Message:            a.getMessage(),
+					ObservedGeneration: generation,
+					LastTransitionTime: now,
+				},
+			}),
+		})
+	}
+
+	return result
+}
@@ -9,7 +9,7 @@ import (
 	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
 	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
 	v1 "k8s.io/api/core/v1"
-	networking "k8s.io/api/networking/v1beta1"
+	networking "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/workqueue"
@@ -59,6 +59,21 @@ func (tq *taskQueue) Enqueue(obj interface{}) {
 	tq.queue.Add(task)
 }
 
+// EnqueueTask enqueues the given task in the task queue.
+func (tq *taskQueue) EnqueueTask(t task) {
+	glog.V(3).Infof("Adding an element with a key: %v", t.Key)
+	tq.queue.Add(t)
+}
+
+// EnqueueTaskAfter enqueues the given task in the task queue after the given duration.
+func (tq *taskQueue) EnqueueTaskAfter(t task, after time.Duration) {
+	glog.V(3).Infof("Adding an element with a key: %v after %s", t.Key, after.String())
+	go func(t task, after time.Duration) {
+		time.Sleep(after)
+		tq.queue.Add(t)
+	}(t, after)
+}
+
 // Requeue adds the task to the queue again and logs the given error
 func (tq *taskQueue) Requeue(task task, err error) {
 	glog.Errorf("Requeuing %v, err %v", task.Key, err)
@@ -106,7 +121,7 @@ type kind int
 // resources
 const (
 	ingress = iota
-	endpoints
+	endpointSlice
 	configMap
 	secret
 	service
@@ -119,8 +134,16 @@ const (
 	appProtectLogConf
 	appProtectUserSig
 	ingressLink
+	nginxReload
+	canaryStep
+	upstreamAnalysis
+	outlierDetection
+	gatewayAPI
 )
 
+// nginxReloadTaskKey is the key of the task that applies the pending NGINX reload
+const nginxReloadTaskKey = "nginx-reload"
+
 // task is an element of a taskQueue
 type task struct {
 	Kind kind
@@ -133,8 +156,6 @@ func newTask(key string, obj interface{}) (task, error) {
 	switch t := obj.(type) {
 	case *networking.Ingress:
 		k = ingress
-	case *v1.Endpoints:
-		k = endpoints
 	case *v1.ConfigMap:
 		k = configMap
 	case *v1.Secret:
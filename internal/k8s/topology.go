@@ -0,0 +1,125 @@
+package k8s
+
+import (
+	"github.com/golang/glog"
+	api_v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// zoneLabel is the well-known label of the zone of a node.
+	zoneLabel = "topology.kubernetes.io/zone"
+	// deprecatedZoneLabel is the label of the zone of a node used before Kubernetes 1.17.
+	deprecatedZoneLabel = "failure-domain.beta.kubernetes.io/zone"
+)
+
+// getZoneForNode returns the zone of the node or an empty string if the node or its zone is unknown.
+func (lbc *LoadBalancerController) getZoneForNode(nodeName string) string {
+	if nodeName == "" {
+		return ""
+	}
+
+	obj, exists, err := lbc.nodeLister.GetByKey(nodeName)
+	if err != nil {
+		glog.Warningf("Error getting node %v from the cache: %v", nodeName, err)
+		return ""
+	}
+	if !exists {
+		return ""
+	}
+
+	return getZoneFromNodeLabels(obj.(*api_v1.Node).Labels)
+}
+
+// getZoneForEndpoint returns the zone of the endpoint from its EndpointSlice or, if the EndpointSlice doesn't have it,
+// from the node of the endpoint.
+func (lbc *LoadBalancerController) getZoneForEndpoint(e podEndpoint) string {
+	if e.Zone != "" {
+		return e.Zone
+	}
+
+	return lbc.getZoneForNode(e.NodeName)
+}
+
+func getZoneFromNodeLabels(labels map[string]string) string {
+	if zone, exists := labels[zoneLabel]; exists {
+		return zone
+	}
+
+	return labels[deprecatedZoneLabel]
+}
+
+// selectEndpointsInZone returns the endpoints for the zone of the Ingress Controller. If the endpoints have
+// topology hints, the hints are used. Otherwise, it returns the endpoints in the zone if the zone has enough
+// healthy capacity or all endpoints if it doesn't.
+func (lbc *LoadBalancerController) selectEndpointsInZone(endps []podEndpoint, minHealthyPercent int) []podEndpoint {
+	zone := lbc.getZoneForNode(lbc.nodeName)
+	if zone == "" {
+		glog.Warningf("The zone of the node %v of the Ingress Controller is unknown, using the endpoints in all zones", lbc.nodeName)
+		return endps
+	}
+
+	if hinted, ok := selectEndpointsByHints(endps, zone); ok {
+		return hinted
+	}
+
+	return selectEndpointsByZone(endps, lbc.getZoneForEndpoint, zone, minHealthyPercent)
+}
+
+// selectEndpointsByHints returns the endpoints with the zone in their topology hints. Kubernetes sets the hints for
+// the Services with the topology aware hints enabled. Like kube-proxy, it uses the hints only if all endpoints have them
+// and at least one endpoint is hinted for the zone. The second return value tells if the hints were used.
+func selectEndpointsByHints(endps []podEndpoint, zone string) ([]podEndpoint, bool) {
+	var result []podEndpoint
+
+	for _, e := range endps {
+		if len(e.ZoneHints) == 0 {
+			return nil, false
+		}
+
+		for _, z := range e.ZoneHints {
+			if z == zone {
+				result = append(result, e)
+				break
+			}
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, false
+	}
+
+	return result, true
+}
+
+// selectEndpointsByZone returns the endpoints in the zone if the zone has enough healthy capacity. Otherwise, it returns all
+// endpoints. The healthy capacity of the zone is the number of the ready endpoints in the zone as a percentage of
+// an even share of the ready endpoints across all zones. For example, if there are 6 endpoints in 3 zones, the even
+// share is 2 endpoints, so a zone with a single endpoint has 50% of the healthy capacity.
+func selectEndpointsByZone(endps []podEndpoint, getZone func(e podEndpoint) string, zone string, minHealthyPercent int) []podEndpoint {
+	zones := make(map[string]bool)
+	var inZone []podEndpoint
+
+	for _, e := range endps {
+		z := getZone(e)
+		if z == "" {
+			continue
+		}
+
+		zones[z] = true
+		if z == zone {
+			inZone = append(inZone, e)
+		}
+	}
+
+	if len(inZone) == 0 {
+		return endps
+	}
+
+	// inZone / (len(endps) / len(zones)) * 100 < minHealthyPercent
+	if len(inZone)*len(zones)*100 < minHealthyPercent*len(endps) {
+		glog.V(3).Infof("The zone %v has %v of %v endpoints in %v zones, using the endpoints in all zones", zone, len(inZone), len(endps), len(zones))
+		return endps
+	}
+
+	return inZone
+}
@@ -0,0 +1,160 @@
+package k8s
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetZoneFromNodeLabels(t *testing.T) {
+	tests := []struct {
+		labels   map[string]string
+		expected string
+	}{
+		{
+			labels:   map[string]string{"topology.kubernetes.io/zone": "zone-a"},
+			expected: "zone-a",
+		},
+		{
+			labels:   map[string]string{"failure-domain.beta.kubernetes.io/zone": "zone-b"},
+			expected: "zone-b",
+		},
+		{
+			labels: map[string]string{
+				"topology.kubernetes.io/zone":            "zone-a",
+				"failure-domain.beta.kubernetes.io/zone": "zone-b",
+			},
+			expected: "zone-a",
+		},
+		{
+			labels:   nil,
+			expected: "",
+		},
+	}
+
+	for _, test := range tests {
+		result := getZoneFromNodeLabels(test.labels)
+		if result != test.expected {
+			t.Errorf("getZoneFromNodeLabels(%v) returned %q but expected %q", test.labels, result, test.expected)
+		}
+	}
+}
+
+func TestSelectEndpointsByHints(t *testing.T) {
+	endpointA := podEndpoint{Address: "10.0.0.1:80", ZoneHints: []string{"zone-a"}}
+	endpointAB := podEndpoint{Address: "10.0.0.2:80", ZoneHints: []string{"zone-a", "zone-b"}}
+	endpointC := podEndpoint{Address: "10.0.0.3:80", ZoneHints: []string{"zone-c"}}
+	endpointNoHints := podEndpoint{Address: "10.0.0.4:80", Zone: "zone-a"}
+
+	tests := []struct {
+		endps          []podEndpoint
+		zone           string
+		expected       []podEndpoint
+		expectedHinted bool
+		msg            string
+	}{
+		{
+			endps:          []podEndpoint{endpointA, endpointAB, endpointC},
+			zone:           "zone-b",
+			expected:       []podEndpoint{endpointAB},
+			expectedHinted: true,
+			msg:            "endpoints hinted for the zone",
+		},
+		{
+			endps:          []podEndpoint{endpointA, endpointC},
+			zone:           "zone-b",
+			expected:       nil,
+			expectedHinted: false,
+			msg:            "no endpoints hinted for the zone",
+		},
+		{
+			endps:          []podEndpoint{endpointA, endpointNoHints},
+			zone:           "zone-a",
+			expected:       nil,
+			expectedHinted: false,
+			msg:            "endpoint without hints",
+		},
+		{
+			endps:          nil,
+			zone:           "zone-a",
+			expected:       nil,
+			expectedHinted: false,
+			msg:            "no endpoints",
+		},
+	}
+
+	for _, test := range tests {
+		result, hinted := selectEndpointsByHints(test.endps, test.zone)
+		if !reflect.DeepEqual(result, test.expected) || hinted != test.expectedHinted {
+			t.Errorf("selectEndpointsByHints() returned %v, %v but expected %v, %v for the case of %s", result, hinted, test.expected, test.expectedHinted, test.msg)
+		}
+	}
+}
+
+func TestSelectEndpointsByZone(t *testing.T) {
+	zones := map[string]string{
+		"node-1": "zone-a",
+		"node-2": "zone-a",
+		"node-3": "zone-b",
+		"node-4": "zone-c",
+	}
+	getZone := func(e podEndpoint) string {
+		return zones[e.NodeName]
+	}
+
+	endpointA1 := podEndpoint{Address: "10.0.0.1:80", NodeName: "node-1"}
+	endpointA2 := podEndpoint{Address: "10.0.0.2:80", NodeName: "node-2"}
+	endpointB := podEndpoint{Address: "10.0.0.3:80", NodeName: "node-3"}
+	endpointC := podEndpoint{Address: "10.0.0.4:80", NodeName: "node-4"}
+	endpointUnknown := podEndpoint{Address: "10.0.0.5:80", NodeName: "node-5"}
+
+	tests := []struct {
+		endps             []podEndpoint
+		zone              string
+		minHealthyPercent int
+		expected          []podEndpoint
+		msg               string
+	}{
+		{
+			endps:             []podEndpoint{endpointA1, endpointA2, endpointB, endpointC},
+			zone:              "zone-a",
+			minHealthyPercent: 50,
+			expected:          []podEndpoint{endpointA1, endpointA2},
+			msg:               "enough endpoints in the zone",
+		},
+		{
+			endps:             []podEndpoint{endpointA1, endpointA2, endpointB, endpointC},
+			zone:              "zone-b",
+			minHealthyPercent: 50,
+			expected:          []podEndpoint{endpointB},
+			msg:               "exactly the minimum of the endpoints in the zone",
+		},
+		{
+			endps:             []podEndpoint{endpointA1, endpointA2, endpointB, endpointC},
+			zone:              "zone-b",
+			minHealthyPercent: 80,
+			expected:          []podEndpoint{endpointA1, endpointA2, endpointB, endpointC},
+			msg:               "not enough endpoints in the zone",
+		},
+		{
+			endps:             []podEndpoint{endpointA1, endpointB},
+			zone:              "zone-c",
+			minHealthyPercent: 0,
+			expected:          []podEndpoint{endpointA1, endpointB},
+			msg:               "no endpoints in the zone",
+		},
+		{
+			endps:             []podEndpoint{endpointC, endpointUnknown},
+			zone:              "zone-c",
+			minHealthyPercent: 50,
+			expected:          []podEndpoint{endpointC},
+			msg:               "endpoint in an unknown zone",
+		},
+	}
+
+	for _, test := range tests {
+		result := selectEndpointsByZone(test.endps, getZone, test.zone, test.minHealthyPercent)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("selectEndpointsByZone() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
+	}
+}
@@ -20,10 +20,12 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/golang/glog"
 	v1 "k8s.io/api/core/v1"
-	networking "k8s.io/api/networking/v1beta1"
+	discovery_v1 "k8s.io/api/discovery/v1"
+	networking "k8s.io/api/networking/v1"
 
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -65,8 +67,8 @@ func (s *storeToIngressLister) GetServiceIngress(svc *v1.Service) (ings []networ
 		if ing.Namespace != svc.Namespace {
 			continue
 		}
-		if ing.Spec.Backend != nil {
-			if ing.Spec.Backend.ServiceName == svc.Name {
+		if ing.Spec.DefaultBackend != nil && ing.Spec.DefaultBackend.Service != nil {
+			if ing.Spec.DefaultBackend.Service.Name == svc.Name {
 				ings = append(ings, ing)
 			}
 		}
@@ -75,7 +77,7 @@ func (s *storeToIngressLister) GetServiceIngress(svc *v1.Service) (ings []networ
 				continue
 			}
 			for _, p := range rules.IngressRuleValue.HTTP.Paths {
-				if p.Backend.ServiceName == svc.Name {
+				if p.Backend.Service != nil && p.Backend.Service.Name == svc.Name {
 					ings = append(ings, ing)
 				}
 			}
@@ -113,20 +115,24 @@ func (ipl indexerToPodLister) ListByNamespace(ns string, selector labels.Selecto
 	return pods, err
 }
 
-// storeToEndpointLister makes a Store that lists Endponts
-type storeToEndpointLister struct {
-	cache.Store
+// indexerToEndpointSliceLister makes an Indexer that lists EndpointSlices.
+type indexerToEndpointSliceLister struct {
+	cache.Indexer
 }
 
-// GetServiceEndpoints returns the endpoints of a service, matched on service name.
-func (s *storeToEndpointLister) GetServiceEndpoints(svc *v1.Service) (ep v1.Endpoints, err error) {
-	for _, m := range s.Store.List() {
-		ep = *m.(*v1.Endpoints)
-		if svc.Name == ep.Name && svc.Namespace == ep.Namespace {
-			return ep, nil
-		}
+// GetServiceEndpointSlices returns the EndpointSlices of a service, matched on the service name label.
+func (s *indexerToEndpointSliceLister) GetServiceEndpointSlices(svc *v1.Service) (slices []discovery_v1.EndpointSlice, err error) {
+	selector := labels.SelectorFromSet(labels.Set{discovery_v1.LabelServiceName: svc.Name})
+	err = cache.ListAllByNamespace(s.Indexer, svc.Namespace, selector, func(m interface{}) {
+		slices = append(slices, *m.(*discovery_v1.EndpointSlice))
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(slices) == 0 {
+		return nil, fmt.Errorf("could not find endpoint slices for service: %v", svc.Name)
 	}
-	return ep, fmt.Errorf("could not find endpoints for service: %v", svc.Name)
+	return slices, nil
 }
 
 // findPort locates the container port for the given pod and portName.  If the
@@ -194,3 +200,20 @@ func GetK8sVersion(client kubernetes.Interface) (v *version.Version, err error)
 
 	return runningVersion, nil
 }
+
+// parseDurationOrDefault parses the duration of a field of a resource. An empty duration means the default duration.
+// The validation of the resource ensures the duration is valid, so if parsing fails, the error is only logged and
+// the default duration is used.
+func parseDurationOrDefault(duration string, defaultDuration time.Duration, field string) time.Duration {
+	if duration == "" {
+		return defaultDuration
+	}
+
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		glog.Errorf("Invalid %v %q: %v", field, duration, err)
+		return defaultDuration
+	}
+
+	return d
+}
@@ -7,7 +7,7 @@ import (
 	"strings"
 
 	"github.com/nginxinc/kubernetes-ingress/internal/configs"
-	networking "k8s.io/api/networking/v1beta1"
+	networking "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
@@ -547,6 +547,10 @@ func validateIngressSpec(spec *networking.IngressSpec, fieldPath *field.Path) fi
 		return append(allErrs, field.Required(fieldPath.Child("rules"), ""))
 	}
 
+	if spec.DefaultBackend != nil {
+		allErrs = append(allErrs, validateIngressBackend(spec.DefaultBackend, fieldPath.Child("defaultBackend"))...)
+	}
+
 	for i, r := range spec.Rules {
 		idxPath := fieldPath.Child("rules").Index(i)
 
@@ -557,6 +561,27 @@ func validateIngressSpec(spec *networking.IngressSpec, fieldPath *field.Path) fi
 		} else {
 			allHosts.Insert(r.Host)
 		}
+
+		if r.HTTP != nil {
+			for j, path := range r.HTTP.Paths {
+				allErrs = append(allErrs, validateIngressBackend(&path.Backend, idxPath.Child("http").Child("paths").Index(j).Child("backend"))...)
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateIngressBackend(backend *networking.IngressBackend, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	// the resource backends are not supported, but they are only ignored, so that the other paths of the Ingress work
+	if backend.Resource != nil {
+		return allErrs
+	}
+
+	if backend.Service == nil {
+		return append(allErrs, field.Required(fieldPath.Child("service"), ""))
 	}
 
 	return allErrs
@@ -600,13 +625,15 @@ func validateMinionSpec(spec *networking.IngressSpec, fieldPath *field.Path) fie
 
 func getSpecServices(ingressSpec networking.IngressSpec) map[string]bool {
 	services := make(map[string]bool)
-	if ingressSpec.Backend != nil {
-		services[ingressSpec.Backend.ServiceName] = true
+	if ingressSpec.DefaultBackend != nil && ingressSpec.DefaultBackend.Service != nil {
+		services[ingressSpec.DefaultBackend.Service.Name] = true
 	}
 	for _, rule := range ingressSpec.Rules {
 		if rule.HTTP != nil {
 			for _, path := range rule.HTTP.Paths {
-				services[path.Backend.ServiceName] = true
+				if path.Backend.Service != nil {
+					services[path.Backend.Service.Name] = true
+				}
 			}
 		}
 	}
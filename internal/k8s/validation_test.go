@@ -6,7 +6,8 @@ import (
 	"strings"
 	"testing"
 
-	networking "k8s.io/api/networking/v1beta1"
+	api_v1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
@@ -76,6 +77,14 @@ func TestValidateIngress(t *testing.T) {
 									Paths: []networking.HTTPIngressPath{
 										{
 											Path: "/",
+											Backend: networking.IngressBackend{
+												Service: &networking.IngressServiceBackend{
+													Name: "test",
+													Port: networking.ServiceBackendPort{
+														Number: 80,
+													},
+												},
+											},
 										},
 									},
 								},
@@ -1725,6 +1734,80 @@ func TestValidateIngressSpec(t *testing.T) {
 			},
 			msg: "duplicated host",
 		},
+		{
+			spec: &networking.IngressSpec{
+				DefaultBackend: &networking.IngressBackend{
+					Service: &networking.IngressServiceBackend{
+						Name: "default-svc",
+						Port: networking.ServiceBackendPort{
+							Name: "http",
+						},
+					},
+				},
+				Rules: []networking.IngressRule{
+					{
+						Host: "foo.example.com",
+						IngressRuleValue: networking.IngressRuleValue{
+							HTTP: &networking.HTTPIngressRuleValue{
+								Paths: []networking.HTTPIngressPath{
+									{
+										Path: "/",
+										Backend: networking.IngressBackend{
+											Service: &networking.IngressServiceBackend{
+												Name: "foo-svc",
+												Port: networking.ServiceBackendPort{
+													Number: 80,
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedErrors: nil,
+			msg:            "valid service backends",
+		},
+		{
+			spec: &networking.IngressSpec{
+				DefaultBackend: &networking.IngressBackend{
+					Resource: &api_v1.TypedLocalObjectReference{
+						Kind: "StorageBucket",
+						Name: "static-assets",
+					},
+				},
+				Rules: []networking.IngressRule{
+					{
+						Host: "foo.example.com",
+						IngressRuleValue: networking.IngressRuleValue{
+							HTTP: &networking.HTTPIngressRuleValue{
+								Paths: []networking.HTTPIngressPath{
+									{
+										Path: "/",
+										Backend: networking.IngressBackend{
+											Resource: &api_v1.TypedLocalObjectReference{
+												Kind: "StorageBucket",
+												Name: "static-assets",
+											},
+										},
+									},
+									{
+										Path:    "/foo",
+										Backend: networking.IngressBackend{},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedErrors: []string{
+				"spec.rules[0].http.paths[1].backend.service: Required value",
+			},
+			msg: "resource and missing service backends",
+		},
 	}
 
 	for _, test := range tests {
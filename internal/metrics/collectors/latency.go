@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/prometheus/client_golang/prometheus"
@@ -73,13 +74,16 @@ type LatencyMetricsCollector struct {
 	metricsPublishedMap          metricsPublishedMap
 	metricsPublishedMutex        sync.Mutex
 	variableLabelsMutex          sync.RWMutex
+	upstreamStats                *UpstreamStats
 }
 
-// NewLatencyMetricsCollector creates a new LatencyMetricsCollector
+// NewLatencyMetricsCollector creates a new LatencyMetricsCollector.
+// If upstreamStats is not nil, the collector also records the responses of the upstreams in it.
 func NewLatencyMetricsCollector(
 	constLabels map[string]string,
 	upstreamServerLabelNames []string,
 	upstreamServerPeerLabelNames []string,
+	upstreamStats *UpstreamStats,
 ) *LatencyMetricsCollector {
 	return &LatencyMetricsCollector{
 		httpLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
@@ -96,6 +100,7 @@ func NewLatencyMetricsCollector(
 		metricsPublishedMap:          make(metricsPublishedMap),
 		upstreamServerLabelNames:     upstreamServerLabelNames,
 		upstreamServerPeerLabelNames: upstreamServerPeerLabelNames,
+		upstreamStats:                upstreamStats,
 	}
 }
 
@@ -133,6 +138,10 @@ func (l *LatencyMetricsCollector) DeleteUpstreamServerLabels(upstreamNames []str
 		delete(l.upstreamServerLabels, k)
 	}
 	l.variableLabelsMutex.Unlock()
+
+	if l.upstreamStats != nil {
+		l.upstreamStats.Delete(upstreamNames)
+	}
 }
 
 // DeleteMetrics deletes all metrics published associated with the given upstream server peer names.
@@ -181,6 +190,9 @@ func (l *LatencyMetricsCollector) RecordLatency(syslogMsg string) {
 		glog.V(3).Infof("could not parse syslog message: %v", err)
 		return
 	}
+	if l.upstreamStats != nil {
+		l.upstreamStats.Record(lm.Upstream, lm.Code, lm.Latency, time.Now())
+	}
 	labelValues, err := l.createLatencyLabelValues(lm)
 	if err != nil {
 		glog.Errorf("cannot record latency for upstream %s and server %s: %v", lm.Upstream, lm.Server, err)
@@ -11,6 +11,7 @@ type ManagerCollector interface {
 	IncNginxReloadCount(isEndPointUpdate bool)
 	IncNginxReloadErrors()
 	UpdateLastReloadTime(ms time.Duration)
+	ObserveReloadCoalescedChanges(changes int)
 	Register(registry *prometheus.Registry) error
 }
 
@@ -21,6 +22,7 @@ type LocalManagerMetricsCollector struct {
 	reloadsError     prometheus.Counter
 	lastReloadStatus prometheus.Gauge
 	lastReloadTime   prometheus.Gauge
+	coalescedChanges prometheus.Histogram
 }
 
 // NewLocalManagerMetricsCollector creates a new LocalManagerMetricsCollector
@@ -59,6 +61,15 @@ func NewLocalManagerMetricsCollector(constLabels map[string]string) *LocalManage
 				ConstLabels: constLabels,
 			},
 		),
+		coalescedChanges: prometheus.NewHistogram(
+			prometheus.HistogramOpts{
+				Name:        "nginx_reload_coalesced_changes",
+				Namespace:   metricsNamespace,
+				Help:        "Number of configuration changes applied by a single NGINX reload",
+				ConstLabels: constLabels,
+				Buckets:     []float64{1, 2, 5, 10, 20, 50, 100, 200, 500},
+			},
+		),
 	}
 	nc.reloadsTotal.WithLabelValues("other")
 	nc.reloadsTotal.WithLabelValues("endpoints")
@@ -97,12 +108,18 @@ func (nc *LocalManagerMetricsCollector) UpdateLastReloadTime(duration time.Durat
 	nc.lastReloadTime.Set(float64(duration / time.Millisecond))
 }
 
+// ObserveReloadCoalescedChanges records the number of configuration changes applied by a single NGINX reload
+func (nc *LocalManagerMetricsCollector) ObserveReloadCoalescedChanges(changes int) {
+	nc.coalescedChanges.Observe(float64(changes))
+}
+
 // Describe implements prometheus.Collector interface Describe method
 func (nc *LocalManagerMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
 	nc.reloadsTotal.Describe(ch)
 	nc.reloadsError.Describe(ch)
 	nc.lastReloadStatus.Describe(ch)
 	nc.lastReloadTime.Describe(ch)
+	nc.coalescedChanges.Describe(ch)
 }
 
 // Collect implements the prometheus.Collector interface Collect method
@@ -111,6 +128,7 @@ func (nc *LocalManagerMetricsCollector) Collect(ch chan<- prometheus.Metric) {
 	nc.reloadsError.Collect(ch)
 	nc.lastReloadStatus.Collect(ch)
 	nc.lastReloadTime.Collect(ch)
+	nc.coalescedChanges.Collect(ch)
 }
 
 // Register registers all the metrics of the collector
@@ -137,3 +155,6 @@ func (nc *ManagerFakeCollector) IncNginxReloadErrors() {}
 
 // UpdateLastReloadTime implements a fake UpdateLastReloadTime
 func (nc *ManagerFakeCollector) UpdateLastReloadTime(ms time.Duration) {}
+
+// ObserveReloadCoalescedChanges implements a fake ObserveReloadCoalescedChanges
+func (nc *ManagerFakeCollector) ObserveReloadCoalescedChanges(changes int) {}
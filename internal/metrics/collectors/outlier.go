@@ -0,0 +1,101 @@
+package collectors
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var labelNamesOutlierDetection = []string{"upstream", "server"}
+
+// OutlierDetectionCollector is an interface for the metrics of the outlier detection of upstreams
+type OutlierDetectionCollector interface {
+	SetPeerEjected(upstream string, server string, ejected bool)
+	IncPeerEjections(upstream string, server string)
+	DeletePeer(upstream string, server string)
+	Register(registry *prometheus.Registry) error
+}
+
+// OutlierDetectionMetricsCollector implements the OutlierDetectionCollector interface and prometheus.Collector interface
+type OutlierDetectionMetricsCollector struct {
+	peerEjected        *prometheus.GaugeVec
+	peerEjectionsTotal *prometheus.CounterVec
+}
+
+// NewOutlierDetectionMetricsCollector creates a new OutlierDetectionMetricsCollector
+func NewOutlierDetectionMetricsCollector(constLabels map[string]string) *OutlierDetectionMetricsCollector {
+	return &OutlierDetectionMetricsCollector{
+		peerEjected: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "controller_upstream_server_peer_ejected",
+				Namespace:   metricsNamespace,
+				Help:        "Whether the upstream server peer is ejected by the outlier detection (1 for ejected, 0 for not ejected)",
+				ConstLabels: constLabels,
+			},
+			labelNamesOutlierDetection,
+		),
+		peerEjectionsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "controller_upstream_server_peer_ejections_total",
+				Namespace:   metricsNamespace,
+				Help:        "Total number of ejections of the upstream server peer by the outlier detection",
+				ConstLabels: constLabels,
+			},
+			labelNamesOutlierDetection,
+		),
+	}
+}
+
+// SetPeerEjected sets the value of the ejected gauge of the peer
+func (oc *OutlierDetectionMetricsCollector) SetPeerEjected(upstream string, server string, ejected bool) {
+	value := 0.0
+	if ejected {
+		value = 1
+	}
+	oc.peerEjected.WithLabelValues(upstream, server).Set(value)
+}
+
+// IncPeerEjections increments the ejections counter of the peer
+func (oc *OutlierDetectionMetricsCollector) IncPeerEjections(upstream string, server string) {
+	oc.peerEjectionsTotal.WithLabelValues(upstream, server).Inc()
+}
+
+// DeletePeer deletes the metrics of the peer
+func (oc *OutlierDetectionMetricsCollector) DeletePeer(upstream string, server string) {
+	oc.peerEjected.DeleteLabelValues(upstream, server)
+	oc.peerEjectionsTotal.DeleteLabelValues(upstream, server)
+}
+
+// Describe implements prometheus.Collector interface Describe method
+func (oc *OutlierDetectionMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	oc.peerEjected.Describe(ch)
+	oc.peerEjectionsTotal.Describe(ch)
+}
+
+// Collect implements the prometheus.Collector interface Collect method
+func (oc *OutlierDetectionMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	oc.peerEjected.Collect(ch)
+	oc.peerEjectionsTotal.Collect(ch)
+}
+
+// Register registers all the metrics of the collector
+func (oc *OutlierDetectionMetricsCollector) Register(registry *prometheus.Registry) error {
+	return registry.Register(oc)
+}
+
+// OutlierDetectionFakeCollector is a fake collector that implements the OutlierDetectionCollector interface
+type OutlierDetectionFakeCollector struct{}
+
+// NewOutlierDetectionFakeCollector creates a fake collector that implements the OutlierDetectionCollector interface
+func NewOutlierDetectionFakeCollector() *OutlierDetectionFakeCollector {
+	return &OutlierDetectionFakeCollector{}
+}
+
+// Register implements a fake Register
+func (oc *OutlierDetectionFakeCollector) Register(registry *prometheus.Registry) error { return nil }
+
+// SetPeerEjected implements a fake SetPeerEjected
+func (oc *OutlierDetectionFakeCollector) SetPeerEjected(upstream string, server string, ejected bool) {
+}
+
+// IncPeerEjections implements a fake IncPeerEjections
+func (oc *OutlierDetectionFakeCollector) IncPeerEjections(upstream string, server string) {}
+
+// DeletePeer implements a fake DeletePeer
+func (oc *OutlierDetectionFakeCollector) DeletePeer(upstream string, server string) {}
@@ -0,0 +1,158 @@
+package collectors
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// upstreamStatsBucketWidth is the time resolution of the UpstreamStats.
+	upstreamStatsBucketWidth = 10 * time.Second
+	// MaxUpstreamStatsWindow is the longest window the UpstreamStats can report.
+	MaxUpstreamStatsWindow   = 10 * time.Minute
+	upstreamStatsBucketCount = int(MaxUpstreamStatsWindow / upstreamStatsBucketWidth)
+)
+
+// upstreamStatsBucket holds the statistics of the responses received within upstreamStatsBucketWidth.
+type upstreamStatsBucket struct {
+	start     int64
+	requests  int
+	errors    int
+	latencies []int
+}
+
+// UpstreamStats keeps the statistics of the responses of upstreams over a sliding window.
+// The statistics are kept in buckets, so that the memory used per upstream is constant.
+type UpstreamStats struct {
+	mu        sync.Mutex
+	upstreams map[string][]upstreamStatsBucket
+}
+
+// NewUpstreamStats creates an UpstreamStats.
+func NewUpstreamStats() *UpstreamStats {
+	return &UpstreamStats{
+		upstreams: make(map[string][]upstreamStatsBucket),
+	}
+}
+
+// Record records a response of an upstream with the status code and the latency in seconds.
+// Responses with a 5xx status code are counted as errors.
+func (us *UpstreamStats) Record(upstream string, code string, latency float64, t time.Time) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	buckets, exists := us.upstreams[upstream]
+	if !exists {
+		buckets = make([]upstreamStatsBucket, upstreamStatsBucketCount)
+		us.upstreams[upstream] = buckets
+	}
+
+	start := t.Truncate(upstreamStatsBucketWidth).Unix()
+	b := &buckets[(start/int64(upstreamStatsBucketWidth/time.Second))%int64(upstreamStatsBucketCount)]
+	if b.start != start {
+		*b = upstreamStatsBucket{
+			start:     start,
+			latencies: make([]int, len(latencyBucketsMilliSeconds)+1),
+		}
+	}
+
+	b.requests++
+	if strings.HasPrefix(code, "5") {
+		b.errors++
+	}
+	b.latencies[findLatencyBucket(latency*1000)]++
+}
+
+func findLatencyBucket(latencyMs float64) int {
+	for i, upper := range latencyBucketsMilliSeconds {
+		if latencyMs <= upper {
+			return i
+		}
+	}
+	return len(latencyBucketsMilliSeconds)
+}
+
+// Get returns the statistics of the responses of the upstream received within the window before now.
+// The window is rounded up to the time resolution of the UpstreamStats and can't exceed MaxUpstreamStatsWindow.
+func (us *UpstreamStats) Get(upstream string, window time.Duration, now time.Time) UpstreamStatsSnapshot {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	snapshot := UpstreamStatsSnapshot{
+		latencies: make([]int, len(latencyBucketsMilliSeconds)+1),
+	}
+
+	if window > MaxUpstreamStatsWindow {
+		window = MaxUpstreamStatsWindow
+	}
+
+	newest := now.Truncate(upstreamStatsBucketWidth)
+	oldest := newest.Add(-window).Add(upstreamStatsBucketWidth)
+	if oldest.After(newest) {
+		oldest = newest
+	}
+
+	for _, b := range us.upstreams[upstream] {
+		if b.requests == 0 || b.start < oldest.Unix() || b.start > newest.Unix() {
+			continue
+		}
+
+		snapshot.Requests += b.requests
+		snapshot.Errors += b.errors
+		for i, count := range b.latencies {
+			snapshot.latencies[i] += count
+		}
+	}
+
+	return snapshot
+}
+
+// Delete removes the statistics of the upstreams.
+func (us *UpstreamStats) Delete(upstreams []string) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	for _, u := range upstreams {
+		delete(us.upstreams, u)
+	}
+}
+
+// UpstreamStatsSnapshot holds the statistics of the responses of an upstream over a window.
+type UpstreamStatsSnapshot struct {
+	Requests  int
+	Errors    int
+	latencies []int
+}
+
+// ErrorRate returns the percentage of the responses with a 5xx status code.
+func (s UpstreamStatsSnapshot) ErrorRate() float64 {
+	if s.Requests == 0 {
+		return 0
+	}
+	return float64(s.Errors) * 100 / float64(s.Requests)
+}
+
+// LatencyPercentile returns the upper bound of the latency bucket where the percentile of the responses falls.
+// For the responses slower than the largest bucket, it returns the maximum duration.
+func (s UpstreamStatsSnapshot) LatencyPercentile(percentile int) time.Duration {
+	if s.Requests == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(float64(s.Requests) * float64(percentile) / 100))
+
+	count := 0
+	for i, c := range s.latencies {
+		count += c
+		if count >= rank {
+			if i == len(latencyBucketsMilliSeconds) {
+				break
+			}
+			return time.Duration(latencyBucketsMilliSeconds[i] * float64(time.Millisecond))
+		}
+	}
+
+	return time.Duration(math.MaxInt64)
+}
@@ -0,0 +1,83 @@
+package collectors
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestUpstreamStats(t *testing.T) {
+	us := NewUpstreamStats()
+	now := time.Date(2021, time.January, 1, 0, 10, 0, 0, time.UTC)
+
+	// outside of the 1 minute window
+	us.Record("upstream-1", "500", 0.001, now.Add(-2*time.Minute))
+
+	for i := 0; i < 90; i++ {
+		us.Record("upstream-1", "200", 0.003, now.Add(-30*time.Second))
+	}
+	for i := 0; i < 10; i++ {
+		us.Record("upstream-1", "502", 0.150, now)
+	}
+
+	us.Record("upstream-2", "200", 100, now)
+
+	s := us.Get("upstream-1", time.Minute, now)
+
+	if s.Requests != 100 {
+		t.Errorf("Get() returned %d requests but expected 100", s.Requests)
+	}
+	if s.Errors != 10 {
+		t.Errorf("Get() returned %d errors but expected 10", s.Errors)
+	}
+	if rate := s.ErrorRate(); rate != 10 {
+		t.Errorf("ErrorRate() returned %v but expected 10", rate)
+	}
+
+	tests := []struct {
+		percentile int
+		expected   time.Duration
+	}{
+		{
+			percentile: 50,
+			expected:   3 * time.Millisecond,
+		},
+		{
+			percentile: 90,
+			expected:   3 * time.Millisecond,
+		},
+		{
+			percentile: 95,
+			expected:   200 * time.Millisecond,
+		},
+	}
+	for _, test := range tests {
+		if p := s.LatencyPercentile(test.percentile); p != test.expected {
+			t.Errorf("LatencyPercentile(%d) returned %v but expected %v", test.percentile, p, test.expected)
+		}
+	}
+
+	s = us.Get("upstream-2", time.Minute, now)
+	if p := s.LatencyPercentile(99); p != time.Duration(math.MaxInt64) {
+		t.Errorf("LatencyPercentile(99) returned %v for the latency beyond the largest bucket", p)
+	}
+
+	us.Delete([]string{"upstream-1"})
+	s = us.Get("upstream-1", time.Minute, now)
+	if s.Requests != 0 {
+		t.Errorf("Get() returned %d requests after Delete()", s.Requests)
+	}
+}
+
+func TestUpstreamStatsReusesBuckets(t *testing.T) {
+	us := NewUpstreamStats()
+	now := time.Date(2021, time.January, 1, 0, 10, 0, 0, time.UTC)
+
+	us.Record("upstream", "500", 0.001, now.Add(-MaxUpstreamStatsWindow))
+	us.Record("upstream", "200", 0.001, now)
+
+	s := us.Get("upstream", MaxUpstreamStatsWindow, now)
+	if s.Requests != 1 || s.Errors != 0 {
+		t.Errorf("Get() returned %d requests and %d errors but expected 1 request and no errors", s.Requests, s.Errors)
+	}
+}
@@ -0,0 +1,191 @@
+package nginx
+
+import (
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/nginxinc/kubernetes-ingress/internal/metrics/collectors"
+)
+
+// ReloadCoalescer wraps a Manager and coalesces the reloads requested within a time window into a single reload.
+//
+// Reload only tests the configuration, so that an invalid configuration is still reported to the caller and rolled
+// back right away, and schedules a reload. When the window expires without new reload requests, or when the
+// first pending change has waited for the max latency, the ReloadCoalescer invokes the notifier. The notifier must
+// make sure that ApplyPendingReload is called from the goroutine that makes the configuration changes, so that
+// NGINX never reloads a partially written configuration.
+type ReloadCoalescer struct {
+	Manager
+
+	window           time.Duration
+	maxLatency       time.Duration
+	metricsCollector collectors.ManagerCollector
+
+	mu                 sync.Mutex
+	notifier           func()
+	timer              *time.Timer
+	pendingChanges     int
+	pendingOtherUpdate bool
+	firstChangeTime    time.Time
+	afterReload        []func()
+}
+
+// NewReloadCoalescer creates a ReloadCoalescer.
+func NewReloadCoalescer(manager Manager, window time.Duration, maxLatency time.Duration, mc collectors.ManagerCollector) *ReloadCoalescer {
+	if maxLatency < window {
+		maxLatency = window
+	}
+
+	return &ReloadCoalescer{
+		Manager:          manager,
+		window:           window,
+		maxLatency:       maxLatency,
+		metricsCollector: mc,
+	}
+}
+
+// SetReloadNotifier sets the function which is invoked when the pending reload must be applied.
+func (rc *ReloadCoalescer) SetReloadNotifier(notifier func()) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.notifier = notifier
+}
+
+// Reload tests the NGINX configuration and schedules a reload.
+func (rc *ReloadCoalescer) Reload(isEndpointsUpdate bool) error {
+	if err := rc.Manager.TestConfig(); err != nil {
+		return err
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	now := time.Now()
+	if rc.pendingChanges == 0 {
+		rc.firstChangeTime = now
+	}
+	rc.pendingChanges++
+	if !isEndpointsUpdate {
+		rc.pendingOtherUpdate = true
+	}
+
+	delay := rc.window
+	if remaining := rc.maxLatency - now.Sub(rc.firstChangeTime); remaining < delay {
+		delay = remaining
+	}
+	if delay < 0 {
+		delay = 0
+	}
+
+	if rc.timer != nil {
+		rc.timer.Stop()
+	}
+	rc.timer = time.AfterFunc(delay, rc.notify)
+
+	glog.V(3).Infof("Scheduled an NGINX reload in %v with %v pending change(s)", delay, rc.pendingChanges)
+
+	return nil
+}
+
+func (rc *ReloadCoalescer) notify() {
+	rc.mu.Lock()
+	notifier := rc.notifier
+	rc.mu.Unlock()
+
+	if notifier == nil {
+		glog.Warningf("No notifier is set for the pending NGINX reload")
+		return
+	}
+
+	notifier()
+}
+
+// HasPendingReload checks if there are configuration changes not yet applied by a reload.
+func (rc *ReloadCoalescer) HasPendingReload() bool {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	return rc.pendingChanges > 0
+}
+
+// RunAfterReload runs the callback after NGINX applies the pending configuration changes.
+// If there are no pending changes, the callback runs right away.
+func (rc *ReloadCoalescer) RunAfterReload(callback func()) {
+	rc.mu.Lock()
+	if rc.pendingChanges > 0 {
+		rc.afterReload = append(rc.afterReload, callback)
+		rc.mu.Unlock()
+		return
+	}
+	rc.mu.Unlock()
+
+	callback()
+}
+
+// ApplyPendingReload reloads NGINX if there are pending configuration changes. Reload has already tested
+// the configuration, so NGINX is reloaded without another test. If the reload fails, the changes stay pending
+// and the reload is retried after the window.
+func (rc *ReloadCoalescer) ApplyPendingReload() error {
+	rc.mu.Lock()
+	changes := rc.pendingChanges
+	isEndpointsUpdate := !rc.pendingOtherUpdate
+	rc.mu.Unlock()
+
+	if changes == 0 {
+		return nil
+	}
+
+	glog.V(3).Infof("Reloading NGINX to apply %v coalesced change(s)", changes)
+
+	if err := rc.Manager.ReloadTestedConfig(isEndpointsUpdate); err != nil {
+		rc.mu.Lock()
+		if rc.timer != nil {
+			rc.timer.Stop()
+		}
+		rc.timer = time.AfterFunc(rc.window, rc.notify)
+		rc.mu.Unlock()
+
+		return err
+	}
+
+	rc.metricsCollector.ObserveReloadCoalescedChanges(changes)
+
+	rc.mu.Lock()
+	rc.pendingChanges = 0
+	rc.pendingOtherUpdate = false
+	if rc.timer != nil {
+		rc.timer.Stop()
+		rc.timer = nil
+	}
+	callbacks := rc.afterReload
+	rc.afterReload = nil
+	rc.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback()
+	}
+
+	return nil
+}
+
+// UpdateServersInPlus applies the pending reload, so that the upstream exists in NGINX Plus,
+// and updates NGINX Plus servers of the given upstream.
+func (rc *ReloadCoalescer) UpdateServersInPlus(upstream string, servers []string, config ServerConfig) error {
+	if err := rc.ApplyPendingReload(); err != nil {
+		return err
+	}
+
+	return rc.Manager.UpdateServersInPlus(upstream, servers, config)
+}
+
+// UpdateStreamServersInPlus applies the pending reload, so that the upstream exists in NGINX Plus,
+// and updates NGINX Plus stream servers of the given upstream.
+func (rc *ReloadCoalescer) UpdateStreamServersInPlus(upstream string, servers []string) error {
+	if err := rc.ApplyPendingReload(); err != nil {
+		return err
+	}
+
+	return rc.Manager.UpdateStreamServersInPlus(upstream, servers)
+}
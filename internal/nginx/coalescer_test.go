@@ -0,0 +1,161 @@
+package nginx
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nginxinc/kubernetes-ingress/internal/metrics/collectors"
+)
+
+type countingManager struct {
+	*FakeManager
+	reloads           int
+	isEndpointsUpdate bool
+	testErr           error
+	reloadErr         error
+}
+
+func (m *countingManager) ReloadTestedConfig(isEndpointsUpdate bool) error {
+	if m.reloadErr != nil {
+		return m.reloadErr
+	}
+	m.reloads++
+	m.isEndpointsUpdate = isEndpointsUpdate
+	return nil
+}
+
+func (m *countingManager) TestConfig() error {
+	return m.testErr
+}
+
+func TestReloadCoalescerCoalescesReloads(t *testing.T) {
+	manager := &countingManager{FakeManager: NewFakeManager("/etc/nginx")}
+	rc := NewReloadCoalescer(manager, time.Hour, time.Hour, collectors.NewManagerFakeCollector())
+
+	notified := make(chan struct{}, 10)
+	rc.SetReloadNotifier(func() {
+		notified <- struct{}{}
+	})
+
+	for i := 0; i < 3; i++ {
+		if err := rc.Reload(ReloadForEndpointsUpdate); err != nil {
+			t.Fatalf("Reload() returned unexpected error: %v", err)
+		}
+	}
+	if err := rc.Reload(ReloadForOtherUpdate); err != nil {
+		t.Fatalf("Reload() returned unexpected error: %v", err)
+	}
+
+	if manager.reloads != 0 {
+		t.Errorf("Reload() reloaded NGINX %v times before the window expired", manager.reloads)
+	}
+	if !rc.HasPendingReload() {
+		t.Errorf("HasPendingReload() returned false after Reload()")
+	}
+
+	if err := rc.ApplyPendingReload(); err != nil {
+		t.Fatalf("ApplyPendingReload() returned unexpected error: %v", err)
+	}
+	if manager.reloads != 1 {
+		t.Errorf("ApplyPendingReload() reloaded NGINX %v times but expected 1", manager.reloads)
+	}
+	if manager.isEndpointsUpdate != ReloadForOtherUpdate {
+		t.Errorf("ApplyPendingReload() reloaded NGINX for endpoints update, but one of the changes was other update")
+	}
+	if rc.HasPendingReload() {
+		t.Errorf("HasPendingReload() returned true after ApplyPendingReload()")
+	}
+
+	if err := rc.ApplyPendingReload(); err != nil {
+		t.Fatalf("ApplyPendingReload() returned unexpected error: %v", err)
+	}
+	if manager.reloads != 1 {
+		t.Errorf("ApplyPendingReload() reloaded NGINX without pending changes")
+	}
+}
+
+func TestReloadCoalescerNotifiesAfterMaxLatency(t *testing.T) {
+	manager := &countingManager{FakeManager: NewFakeManager("/etc/nginx")}
+	rc := NewReloadCoalescer(manager, 100*time.Millisecond, 200*time.Millisecond, collectors.NewManagerFakeCollector())
+
+	notified := make(chan struct{}, 10)
+	rc.SetReloadNotifier(func() {
+		notified <- struct{}{}
+	})
+
+	// every Reload restarts the window, so only the max latency can trigger the notifier
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if err := rc.Reload(ReloadForOtherUpdate); err != nil {
+			t.Fatalf("Reload() returned unexpected error: %v", err)
+		}
+
+		select {
+		case <-notified:
+			return
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+
+	t.Errorf("ReloadCoalescer didn't invoke the notifier after the max latency")
+}
+
+func TestReloadCoalescerReturnsTestError(t *testing.T) {
+	manager := &countingManager{
+		FakeManager: NewFakeManager("/etc/nginx"),
+		testErr:     errors.New("test failed"),
+	}
+	rc := NewReloadCoalescer(manager, time.Hour, time.Hour, collectors.NewManagerFakeCollector())
+
+	if err := rc.Reload(ReloadForOtherUpdate); err == nil {
+		t.Errorf("Reload() returned no error for invalid config")
+	}
+	if rc.HasPendingReload() {
+		t.Errorf("HasPendingReload() returned true for invalid config")
+	}
+}
+
+func TestReloadCoalescerRetriesFailedReload(t *testing.T) {
+	manager := &countingManager{
+		FakeManager: NewFakeManager("/etc/nginx"),
+		reloadErr:   errors.New("reload failed"),
+	}
+	rc := NewReloadCoalescer(manager, 50*time.Millisecond, time.Hour, collectors.NewManagerFakeCollector())
+
+	if err := rc.Reload(ReloadForOtherUpdate); err != nil {
+		t.Fatalf("Reload() returned unexpected error: %v", err)
+	}
+
+	if err := rc.ApplyPendingReload(); err == nil {
+		t.Errorf("ApplyPendingReload() returned no error for failed reload")
+	}
+	if !rc.HasPendingReload() {
+		t.Errorf("HasPendingReload() returned false after failed reload")
+	}
+
+	notified := make(chan struct{}, 10)
+	rc.SetReloadNotifier(func() {
+		notified <- struct{}{}
+	})
+
+	select {
+	case <-notified:
+	case <-time.After(time.Second):
+		t.Fatalf("ReloadCoalescer didn't invoke the notifier to retry the failed reload")
+	}
+
+	manager.reloadErr = nil
+	if err := rc.ApplyPendingReload(); err != nil {
+		t.Fatalf("ApplyPendingReload() returned unexpected error: %v", err)
+	}
+	if manager.reloads != 1 {
+		t.Errorf("ApplyPendingReload() reloaded NGINX %v times but expected 1", manager.reloads)
+	}
+	if manager.isEndpointsUpdate != ReloadForOtherUpdate {
+		t.Errorf("ApplyPendingReload() lost the other update of the failed reload")
+	}
+	if rc.HasPendingReload() {
+		t.Errorf("HasPendingReload() returned true after successful reload")
+	}
+}
@@ -53,6 +53,11 @@ func (*FakeManager) ClearAppProtectFolder(name string) {
 	glog.V(3).Infof("Deleting Ap Resource folder %v", name)
 }
 
+// DeleteCacheFolder provides a fake implementation of DeleteCacheFolder
+func (*FakeManager) DeleteCacheFolder(path string) {
+	glog.V(3).Infof("Deleting cache folder %v", path)
+}
+
 // DeleteConfig provides a fake implementation of DeleteConfig.
 func (*FakeManager) DeleteConfig(name string) {
 	glog.V(3).Infof("Deleting config %v", name)
@@ -113,6 +118,23 @@ func (*FakeManager) Reload(isEndpointsUpdate bool) error {
 	return nil
 }
 
+// ReloadTestedConfig provides a fake implementation of ReloadTestedConfig.
+func (*FakeManager) ReloadTestedConfig(isEndpointsUpdate bool) error {
+	glog.V(3).Infof("Reloading nginx")
+	return nil
+}
+
+// RunAfterReload provides a fake implementation of RunAfterReload.
+func (*FakeManager) RunAfterReload(callback func()) {
+	callback()
+}
+
+// TestConfig provides a fake implementation of TestConfig.
+func (*FakeManager) TestConfig() error {
+	glog.V(3).Infof("Testing nginx config")
+	return nil
+}
+
 // Quit provides a fake implementation of Quit.
 func (*FakeManager) Quit() {
 	glog.V(3).Info("Quitting nginx")
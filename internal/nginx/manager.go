@@ -1,6 +1,7 @@
 package nginx
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -21,10 +22,15 @@ const (
 	ReloadForOtherUpdate         = false // ReloadForOtherUpdate means that a reload is caused by an update for a resource(s) other than endpoints.
 	TLSSecretFileMode            = 0600  // TLSSecretFileMode defines the default filemode for files with TLS Secrets.
 	JWKSecretFileMode            = 0644  // JWKSecretFileMode defines the default filemode for files with JWK Secrets.
+	HtpasswdSecretFileMode       = 0644  // HtpasswdSecretFileMode defines the default filemode for files with Htpasswd Secrets.
 	configFileMode               = 0644
 	jsonFileForOpenTracingTracer = "/var/lib/nginx/tracer-config.json"
 )
 
+// ErrConfigTestFailed is returned when NGINX rejects the configuration. In that case, the files changed since
+// the previous successful test are restored.
+var ErrConfigTestFailed = errors.New("nginx config test failed")
+
 // appPluginParams is the configuration of App-Protect plugin
 const appPluginParams = "tmm_count 4 proc_cpuinfo_cpu_mhz 2000000 total_xml_memory 307200000 total_umu_max_size 3129344 sys_max_account_id 1024 no_static_config"
 
@@ -39,6 +45,10 @@ type ServerConfig struct {
 	MaxConns    int
 	FailTimeout string
 	SlowStart   string
+	// Weights holds the weights of the servers keyed by the address. A server without a weight has the default weight.
+	Weights map[string]int
+	// Draining holds the addresses of the servers in the draining mode.
+	Draining map[string]bool
 }
 
 // appProtectDebugLogConfigFileContent holds the content of the file to be written when nginx debug is enabled. It will enable NGINX App Protect debug logs
@@ -61,12 +71,16 @@ type Manager interface {
 	CreateAppProtectResourceFile(name string, content []byte)
 	DeleteAppProtectResourceFile(name string)
 	ClearAppProtectFolder(name string)
+	DeleteCacheFolder(path string)
 	GetFilenameForSecret(name string) string
 	CreateDHParam(content string) (string, error)
 	CreateOpenTracingTracerConfig(content string) error
 	Start(done chan error)
 	Version() string
 	Reload(isEndpointsUpdate bool) error
+	ReloadTestedConfig(isEndpointsUpdate bool) error
+	RunAfterReload(callback func())
+	TestConfig() error
 	Quit()
 	UpdateConfigVersionFile(openTracing bool)
 	SetPlusClients(plusClient *client.NginxClient, plusConfigVersionCheckClient *http.Client)
@@ -95,6 +109,8 @@ type LocalManager struct {
 	configVersion                int
 	reloadCmd                    string
 	quitCmd                      string
+	testCmd                      string
+	backups                      *configBackups
 	plusClient                   *client.NginxClient
 	plusConfigVersionCheckClient *http.Client
 	metricsCollector             collectors.ManagerCollector
@@ -124,6 +140,8 @@ func NewLocalManager(confPath string, binaryFilename string, mc collectors.Manag
 		verifyClient:                newVerifyClient(timeout),
 		reloadCmd:                   fmt.Sprintf("%v -s %v", binaryFilename, "reload"),
 		quitCmd:                     fmt.Sprintf("%v -s %v", binaryFilename, "quit"),
+		testCmd:                     fmt.Sprintf("%v -t", binaryFilename),
+		backups:                     newConfigBackups(),
 		metricsCollector:            mc,
 	}
 
@@ -135,6 +153,7 @@ func (lm *LocalManager) CreateMainConfig(content []byte) {
 	glog.V(3).Infof("Writing main config to %v", lm.mainConfFilename)
 	glog.V(3).Infof(string(content))
 
+	lm.backups.save(lm.mainConfFilename)
 	err := createFileAndWrite(lm.mainConfFilename, content)
 	if err != nil {
 		glog.Fatalf("Failed to write main config: %v", err)
@@ -143,7 +162,9 @@ func (lm *LocalManager) CreateMainConfig(content []byte) {
 
 // CreateConfig creates a configuration file. If the file already exists, it will be overridden.
 func (lm *LocalManager) CreateConfig(name string, content []byte) {
-	createConfig(lm.getFilenameForConfig(name), content)
+	filename := lm.getFilenameForConfig(name)
+	lm.backups.save(filename)
+	createConfig(filename, content)
 }
 
 func createConfig(filename string, content []byte) {
@@ -158,7 +179,9 @@ func createConfig(filename string, content []byte) {
 
 // DeleteConfig deletes the configuration file from the conf.d folder.
 func (lm *LocalManager) DeleteConfig(name string) {
-	deleteConfig(lm.getFilenameForConfig(name))
+	filename := lm.getFilenameForConfig(name)
+	lm.backups.save(filename)
+	deleteConfig(filename)
 }
 
 func deleteConfig(filename string) {
@@ -176,12 +199,16 @@ func (lm *LocalManager) getFilenameForConfig(name string) string {
 // CreateStreamConfig creates a configuration file for stream module.
 // If the file already exists, it will be overridden.
 func (lm *LocalManager) CreateStreamConfig(name string, content []byte) {
-	createConfig(lm.getFilenameForStreamConfig(name), content)
+	filename := lm.getFilenameForStreamConfig(name)
+	lm.backups.save(filename)
+	createConfig(filename, content)
 }
 
 // DeleteStreamConfig deletes the configuration file from the stream-conf.d folder.
 func (lm *LocalManager) DeleteStreamConfig(name string) {
-	deleteConfig(lm.getFilenameForStreamConfig(name))
+	filename := lm.getFilenameForStreamConfig(name)
+	lm.backups.save(filename)
+	deleteConfig(filename)
 }
 
 func (lm *LocalManager) getFilenameForStreamConfig(name string) string {
@@ -193,6 +220,7 @@ func (lm *LocalManager) getFilenameForStreamConfig(name string) string {
 // If the file already exists, it will be overridden.
 func (lm *LocalManager) CreateTLSPassthroughHostsConfig(content []byte) {
 	glog.V(3).Infof("Writing TLS Passthrough Hosts config file to %v", lm.tlsPassthroughHostsFilename)
+	lm.backups.save(lm.tlsPassthroughHostsFilename)
 	createConfig(lm.tlsPassthroughHostsFilename, content)
 }
 
@@ -203,6 +231,7 @@ func (lm *LocalManager) CreateSecret(name string, content []byte, mode os.FileMo
 
 	glog.V(3).Infof("Writing secret to %v", filename)
 
+	lm.backups.save(filename)
 	createFileAndWriteAtomically(filename, lm.secretsPath, mode, content)
 
 	return filename
@@ -214,6 +243,7 @@ func (lm *LocalManager) DeleteSecret(name string) {
 
 	glog.V(3).Infof("Deleting secret from %v", filename)
 
+	lm.backups.save(filename)
 	if err := os.Remove(filename); err != nil {
 		glog.Warningf("Failed to delete secret from %v: %v", filename, err)
 	}
@@ -228,6 +258,7 @@ func (lm *LocalManager) GetFilenameForSecret(name string) string {
 func (lm *LocalManager) CreateDHParam(content string) (string, error) {
 	glog.V(3).Infof("Writing dhparam file to %v", lm.dhparamFilename)
 
+	lm.backups.save(lm.dhparamFilename)
 	err := createFileAndWrite(lm.dhparamFilename, []byte(content))
 	if err != nil {
 		return lm.dhparamFilename, fmt.Errorf("Failed to write dhparam file from %v: %v", lm.dhparamFilename, err)
@@ -239,6 +270,7 @@ func (lm *LocalManager) CreateDHParam(content string) (string, error) {
 // CreateAppProtectResourceFile writes contents of An App Protect resource to a file
 func (lm *LocalManager) CreateAppProtectResourceFile(name string, content []byte) {
 	glog.V(3).Infof("Writing App Protect Resource to %v", name)
+	lm.backups.save(name)
 	err := createFileAndWrite(name, content)
 	if err != nil {
 		glog.Fatalf("Failed to write App Protect Resource to %v: %v", name, err)
@@ -249,6 +281,7 @@ func (lm *LocalManager) CreateAppProtectResourceFile(name string, content []byte
 func (lm *LocalManager) DeleteAppProtectResourceFile(name string) {
 	// This check is done to avoid errors in case eg. a policy is referenced, but it never became valid.
 	if _, err := os.Stat(name); !os.IsNotExist(err) {
+		lm.backups.save(name)
 		if err := os.Remove(name); err != nil {
 			glog.Fatalf("Failed to delete App Protect Resource from %v: %v", name, err)
 		}
@@ -266,6 +299,15 @@ func (lm *LocalManager) ClearAppProtectFolder(name string) {
 	}
 }
 
+// DeleteCacheFolder deletes the folder of a proxy cache zone, which is no longer used, together with the cached responses.
+func (lm *LocalManager) DeleteCacheFolder(path string) {
+	glog.V(3).Infof("Deleting cache folder %v", path)
+
+	if err := os.RemoveAll(path); err != nil {
+		glog.Warningf("Failed to delete cache folder %v: %v", path, err)
+	}
+}
+
 // Start starts NGINX.
 func (lm *LocalManager) Start(done chan error) {
 	glog.V(3).Info("Starting nginx")
@@ -277,6 +319,9 @@ func (lm *LocalManager) Start(done chan error) {
 		glog.Fatalf("Failed to start nginx: %v", err)
 	}
 
+	// the config files written before the start are not subject to rollback
+	lm.backups.commit()
+
 	go func() {
 		done <- cmd.Wait()
 	}()
@@ -286,8 +331,34 @@ func (lm *LocalManager) Start(done chan error) {
 	}
 }
 
-// Reload reloads NGINX.
+// TestConfig tests the NGINX configuration. If the test fails, all files changed since the previous successful test
+// are restored, so that the broken configuration doesn't affect the subsequent reloads.
+func (lm *LocalManager) TestConfig() error {
+	if err := shellOut(lm.testCmd); err != nil {
+		lm.metricsCollector.IncNginxReloadErrors()
+		if rbErr := lm.backups.rollback(); rbErr != nil {
+			return fmt.Errorf("%w: %v; rollback failed: %v", ErrConfigTestFailed, err, rbErr)
+		}
+		return fmt.Errorf("%w, changes were rolled back: %v", ErrConfigTestFailed, err)
+	}
+
+	lm.backups.commit()
+
+	return nil
+}
+
+// Reload tests the NGINX configuration and reloads NGINX.
 func (lm *LocalManager) Reload(isEndpointsUpdate bool) error {
+	if err := lm.TestConfig(); err != nil {
+		return err
+	}
+
+	return lm.ReloadTestedConfig(isEndpointsUpdate)
+}
+
+// ReloadTestedConfig reloads NGINX without testing the configuration. The configuration must be already tested
+// by TestConfig.
+func (lm *LocalManager) ReloadTestedConfig(isEndpointsUpdate bool) error {
 	// write a new config version
 	lm.configVersion++
 	lm.UpdateConfigVersionFile(lm.OpenTracing)
@@ -313,6 +384,12 @@ func (lm *LocalManager) Reload(isEndpointsUpdate bool) error {
 	return nil
 }
 
+// RunAfterReload runs the callback. Reload is synchronous, so NGINX has already applied the configuration
+// of the successful Reload calls.
+func (lm *LocalManager) RunAfterReload(callback func()) {
+	callback()
+}
+
 // Quit shutdowns NGINX gracefully.
 func (lm *LocalManager) Quit() {
 	glog.V(3).Info("Quitting nginx")
@@ -362,13 +439,18 @@ func (lm *LocalManager) UpdateServersInPlus(upstream string, servers []string, c
 
 	var upsServers []client.UpstreamServer
 	for _, s := range servers {
-		upsServers = append(upsServers, client.UpstreamServer{
+		upsServer := client.UpstreamServer{
 			Server:      s,
 			MaxFails:    &config.MaxFails,
 			MaxConns:    &config.MaxConns,
 			FailTimeout: config.FailTimeout,
 			SlowStart:   config.SlowStart,
-		})
+		}
+		if weight, exists := config.Weights[s]; exists {
+			upsServer.Weight = &weight
+		}
+		upsServer.Drain = config.Draining[s]
+		upsServers = append(upsServers, upsServer)
 	}
 
 	added, removed, updated, err := lm.plusClient.UpdateHTTPServers(upstream, upsServers)
@@ -412,6 +494,7 @@ func (lm *LocalManager) UpdateStreamServersInPlus(upstream string, servers []str
 // CreateOpenTracingTracerConfig creates a json configuration file for the OpenTracing tracer with the content of the string.
 func (lm *LocalManager) CreateOpenTracingTracerConfig(content string) error {
 	glog.V(3).Infof("Writing OpenTracing tracer config file to %v", jsonFileForOpenTracingTracer)
+	lm.backups.save(jsonFileForOpenTracingTracer)
 	err := createFileAndWrite(jsonFileForOpenTracingTracer, []byte(content))
 	if err != nil {
 		return fmt.Errorf("Failed to write config file: %v", err)
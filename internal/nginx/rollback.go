@@ -0,0 +1,94 @@
+package nginx
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/golang/glog"
+)
+
+// fileBackup holds the content of a file before it was changed.
+type fileBackup struct {
+	existed bool
+	content []byte
+	mode    os.FileMode
+}
+
+// configBackups keeps the previous state of the files changed since the last successful config test,
+// so that the changes can be rolled back if the new configuration is invalid.
+type configBackups struct {
+	files map[string]*fileBackup
+}
+
+func newConfigBackups() *configBackups {
+	return &configBackups{
+		files: make(map[string]*fileBackup),
+	}
+}
+
+// save remembers the current state of the file unless it has already been saved since the last commit.
+func (b *configBackups) save(filename string) {
+	if _, exists := b.files[filename]; exists {
+		return
+	}
+
+	info, err := os.Stat(filename)
+	if os.IsNotExist(err) {
+		b.files[filename] = &fileBackup{existed: false}
+		return
+	}
+	if err != nil {
+		glog.Warningf("Failed to stat %v for backup: %v", filename, err)
+		return
+	}
+
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		glog.Warningf("Failed to read %v for backup: %v", filename, err)
+		return
+	}
+
+	b.files[filename] = &fileBackup{
+		existed: true,
+		content: content,
+		mode:    info.Mode(),
+	}
+}
+
+// commit discards the saved state of the files.
+func (b *configBackups) commit() {
+	b.files = make(map[string]*fileBackup)
+}
+
+// rollback restores the saved state of the files.
+func (b *configBackups) rollback() error {
+	var failed []string
+
+	for filename, backup := range b.files {
+		glog.V(3).Infof("Rolling back %v", filename)
+
+		if !backup.existed {
+			if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+				glog.Errorf("Failed to remove %v during rollback: %v", filename, err)
+				failed = append(failed, filename)
+			}
+			continue
+		}
+
+		err := writeFileAtomically(filename, path.Dir(filename), backup.mode, backup.content)
+		if err != nil {
+			glog.Errorf("Failed to restore %v during rollback: %v", filename, err)
+			failed = append(failed, filename)
+		}
+	}
+
+	b.commit()
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to restore files %v", failed)
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package nginx
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestConfigBackupsRollback(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nginx-rollback")
+	if err != nil {
+		t.Fatalf("error creating a temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	existing := path.Join(dir, "existing.conf")
+	deleted := path.Join(dir, "deleted.conf")
+	created := path.Join(dir, "created.conf")
+
+	for _, f := range []string{existing, deleted} {
+		if err := ioutil.WriteFile(f, []byte("old"), 0644); err != nil {
+			t.Fatalf("error writing %v: %v", f, err)
+		}
+	}
+
+	b := newConfigBackups()
+
+	b.save(existing)
+	if err := ioutil.WriteFile(existing, []byte("new"), 0644); err != nil {
+		t.Fatalf("error writing %v: %v", existing, err)
+	}
+	// the second save must keep the original content
+	b.save(existing)
+	if err := ioutil.WriteFile(existing, []byte("newer"), 0644); err != nil {
+		t.Fatalf("error writing %v: %v", existing, err)
+	}
+
+	b.save(deleted)
+	if err := os.Remove(deleted); err != nil {
+		t.Fatalf("error removing %v: %v", deleted, err)
+	}
+
+	b.save(created)
+	if err := ioutil.WriteFile(created, []byte("new"), 0644); err != nil {
+		t.Fatalf("error writing %v: %v", created, err)
+	}
+
+	if err := b.rollback(); err != nil {
+		t.Fatalf("rollback() returned unexpected error: %v", err)
+	}
+
+	for _, f := range []string{existing, deleted} {
+		content, err := ioutil.ReadFile(f)
+		if err != nil {
+			t.Errorf("error reading %v after rollback: %v", f, err)
+			continue
+		}
+		if string(content) != "old" {
+			t.Errorf("rollback() restored %v with %q but expected %q", f, content, "old")
+		}
+	}
+
+	if _, err := os.Stat(created); !os.IsNotExist(err) {
+		t.Errorf("rollback() didn't remove %v", created)
+	}
+
+	if len(b.files) != 0 {
+		t.Errorf("rollback() didn't clear the backups: %v", b.files)
+	}
+}
+
+func TestConfigBackupsCommit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nginx-rollback")
+	if err != nil {
+		t.Fatalf("error creating a temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	f := path.Join(dir, "test.conf")
+
+	b := newConfigBackups()
+
+	b.save(f)
+	if err := ioutil.WriteFile(f, []byte("new"), 0644); err != nil {
+		t.Fatalf("error writing %v: %v", f, err)
+	}
+
+	b.commit()
+
+	if err := b.rollback(); err != nil {
+		t.Fatalf("rollback() returned unexpected error: %v", err)
+	}
+
+	content, err := ioutil.ReadFile(f)
+	if err != nil {
+		t.Fatalf("error reading %v: %v", f, err)
+	}
+	if string(content) != "new" {
+		t.Errorf("rollback() after commit() changed %v to %q", f, content)
+	}
+}
@@ -51,28 +51,50 @@ func createFileAndWrite(name string, b []byte) error {
 }
 
 func createFileAndWriteAtomically(filename string, tempPath string, mode os.FileMode, content []byte) {
+	err := writeFileAtomically(filename, tempPath, mode, content)
+	if err != nil {
+		glog.Fatal(err)
+	}
+}
+
+func writeFileAtomically(filename string, tempPath string, mode os.FileMode, content []byte) error {
 	file, err := ioutil.TempFile(tempPath, path.Base(filename))
 	if err != nil {
-		glog.Fatalf("Couldn't create a temp file for the file %v: %v", filename, err)
+		return fmt.Errorf("Couldn't create a temp file for the file %v: %v", filename, err)
 	}
 
+	renamed := false
+	defer func() {
+		if renamed {
+			return
+		}
+		// the file might be already closed, so the error is ignored
+		_ = file.Close()
+		if err := os.Remove(file.Name()); err != nil {
+			glog.Warningf("Couldn't remove the temp file %v: %v", file.Name(), err)
+		}
+	}()
+
 	err = file.Chmod(mode)
 	if err != nil {
-		glog.Fatalf("Couldn't change the mode of the temp file %v: %v", file.Name(), err)
+		return fmt.Errorf("Couldn't change the mode of the temp file %v: %v", file.Name(), err)
 	}
 
 	_, err = file.Write(content)
 	if err != nil {
-		glog.Fatalf("Couldn't write to the temp file %v: %v", file.Name(), err)
+		return fmt.Errorf("Couldn't write to the temp file %v: %v", file.Name(), err)
 	}
 
 	err = file.Close()
 	if err != nil {
-		glog.Fatalf("Couldn't close the temp file %v: %v", file.Name(), err)
+		return fmt.Errorf("Couldn't close the temp file %v: %v", file.Name(), err)
 	}
 
 	err = os.Rename(file.Name(), filename)
 	if err != nil {
-		glog.Fatalf("Couldn't rename the temp file %v to %v: %v", file.Name(), filename, err)
+		return fmt.Errorf("Couldn't rename the temp file %v to %v: %v", file.Name(), filename, err)
 	}
+	renamed = true
+
+	return nil
 }
@@ -0,0 +1,64 @@
+package nginx
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestWriteFileAtomically(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nginx-write")
+	if err != nil {
+		t.Fatalf("error creating a temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	tempDir := path.Join(dir, "tmp")
+	if err := os.Mkdir(tempDir, 0755); err != nil {
+		t.Fatalf("error creating %v: %v", tempDir, err)
+	}
+
+	filename := path.Join(dir, "default.conf")
+	if err := writeFileAtomically(filename, tempDir, 0644, []byte("content")); err != nil {
+		t.Fatalf("writeFileAtomically() returned unexpected error: %v", err)
+	}
+
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("error reading %v: %v", filename, err)
+	}
+	if string(content) != "content" {
+		t.Errorf("writeFileAtomically() wrote %q but expected %q", content, "content")
+	}
+
+	files, err := ioutil.ReadDir(tempDir)
+	if err != nil {
+		t.Fatalf("error reading %v: %v", tempDir, err)
+	}
+	if len(files) != 0 {
+		t.Errorf("writeFileAtomically() left %v files in the temp dir", len(files))
+	}
+}
+
+func TestWriteFileAtomicallyRemovesTempFileOnError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nginx-write")
+	if err != nil {
+		t.Fatalf("error creating a temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// the rename fails, because the directory of the file doesn't exist
+	filename := path.Join(dir, "missing", "default.conf")
+	if err := writeFileAtomically(filename, dir, 0644, []byte("content")); err == nil {
+		t.Fatalf("writeFileAtomically() returned no error for a file in a missing directory")
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("error reading %v: %v", dir, err)
+	}
+	if len(files) != 0 {
+		t.Errorf("writeFileAtomically() left %v files in the temp dir after the error", len(files))
+	}
+}
@@ -0,0 +1,63 @@
+package nginx
+
+import (
+	"regexp"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+var (
+	nginxVersionRegexp     = regexp.MustCompile(`nginx/(\d+\.\d+\.\d+)`)
+	nginxPlusReleaseRegexp = regexp.MustCompile(`nginx-plus-r(\d+)`)
+)
+
+var (
+	// minHTTP3Version is the first version of NGINX that supports HTTP/3.
+	minHTTP3Version = version.MustParseGeneric("1.25.0")
+	// minHTTP3PlusRelease is the first release of NGINX Plus that supports HTTP/3.
+	minHTTP3PlusRelease = 30
+	// minJWTRequireVersion is the first version of NGINX Plus that supports the auth_jwt_require directive.
+	minJWTRequireVersion = version.MustParseGeneric("1.21.2")
+	// minJWTKeyCacheVersion is the first version of NGINX Plus that supports the auth_jwt_key_cache directive.
+	minJWTKeyCacheVersion = version.MustParseGeneric("1.21.4")
+)
+
+// IsHTTP3Supported checks if NGINX supports HTTP/3 based on the output of the Version method of the Manager.
+// Note that the version doesn't tell if NGINX was built with the ngx_http_v3_module, which is the case for
+// the official images. The support can't be checked with verifyConfigVersion either: it only confirms that
+// the workers of NGINX Plus run the latest config version and requires the NGINX Plus API.
+func IsHTTP3Supported(nginxVersion string) bool {
+	if match := nginxPlusReleaseRegexp.FindStringSubmatch(nginxVersion); match != nil {
+		release, err := strconv.Atoi(match[1])
+		return err == nil && release >= minHTTP3PlusRelease
+	}
+
+	return isVersionAtLeast(nginxVersion, minHTTP3Version)
+}
+
+// IsJWTRequireSupported checks if NGINX Plus supports the auth_jwt_require directive based on the output
+// of the Version method of the Manager.
+func IsJWTRequireSupported(nginxVersion string) bool {
+	return isVersionAtLeast(nginxVersion, minJWTRequireVersion)
+}
+
+// IsJWTKeyCacheSupported checks if NGINX Plus supports the auth_jwt_key_cache directive based on the output
+// of the Version method of the Manager.
+func IsJWTKeyCacheSupported(nginxVersion string) bool {
+	return isVersionAtLeast(nginxVersion, minJWTKeyCacheVersion)
+}
+
+func isVersionAtLeast(nginxVersion string, min *version.Version) bool {
+	match := nginxVersionRegexp.FindStringSubmatch(nginxVersion)
+	if match == nil {
+		return false
+	}
+
+	v, err := version.ParseGeneric(match[1])
+	if err != nil {
+		return false
+	}
+
+	return v.AtLeast(min)
+}
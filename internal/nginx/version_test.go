@@ -0,0 +1,96 @@
+package nginx
+
+import "testing"
+
+func TestIsHTTP3Supported(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected bool
+	}{
+		{
+			version:  "nginx version: nginx/1.25.3\n",
+			expected: true,
+		},
+		{
+			version:  "nginx version: nginx/1.27.0\n",
+			expected: true,
+		},
+		{
+			version:  "nginx version: nginx/1.21.3\n",
+			expected: false,
+		},
+		{
+			version:  "nginx version: nginx/1.25.3 (nginx-plus-r30)\n",
+			expected: true,
+		},
+		{
+			version:  "nginx version: nginx/1.25.1 (nginx-plus-r29)\n",
+			expected: false,
+		},
+		{
+			version:  "fake version",
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		result := IsHTTP3Supported(test.version)
+		if result != test.expected {
+			t.Errorf("IsHTTP3Supported(%q) returned %v but expected %v", test.version, result, test.expected)
+		}
+	}
+}
+
+func TestIsJWTRequireSupported(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected bool
+	}{
+		{
+			version:  "nginx version: nginx/1.21.3 (nginx-plus-r25)\n",
+			expected: true,
+		},
+		{
+			version:  "nginx version: nginx/1.19.5 (nginx-plus-r23)\n",
+			expected: false,
+		},
+		{
+			version:  "fake version",
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		result := IsJWTRequireSupported(test.version)
+		if result != test.expected {
+			t.Errorf("IsJWTRequireSupported(%q) returned %v but expected %v", test.version, result, test.expected)
+		}
+	}
+}
+
+func TestIsJWTKeyCacheSupported(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected bool
+	}{
+		{
+			version:  "nginx version: nginx/1.21.5 (nginx-plus-r26)\n",
+			expected: true,
+		},
+		{
+			version:  "nginx version: nginx/1.21.3 (nginx-plus-r25)\n",
+			expected: false,
+		},
+		{
+			version:  "fake version",
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		result := IsJWTKeyCacheSupported(test.version)
+		if result != test.expected {
+			t.Errorf("IsJWTKeyCacheSupported(%q) returned %v but expected %v", test.version, result, test.expected)
+		}
+	}
+}
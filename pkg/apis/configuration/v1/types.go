@@ -53,31 +53,52 @@ type PolicyReference struct {
 
 // Upstream defines an upstream.
 type Upstream struct {
-	Name                     string            `json:"name"`
-	Service                  string            `json:"service"`
-	Subselector              map[string]string `json:"subselector"`
-	Port                     uint16            `json:"port"`
-	LBMethod                 string            `json:"lb-method"`
-	FailTimeout              string            `json:"fail-timeout"`
-	MaxFails                 *int              `json:"max-fails"`
-	MaxConns                 *int              `json:"max-conns"`
-	Keepalive                *int              `json:"keepalive"`
-	ProxyConnectTimeout      string            `json:"connect-timeout"`
-	ProxyReadTimeout         string            `json:"read-timeout"`
-	ProxySendTimeout         string            `json:"send-timeout"`
-	ProxyNextUpstream        string            `json:"next-upstream"`
-	ProxyNextUpstreamTimeout string            `json:"next-upstream-timeout"`
-	ProxyNextUpstreamTries   int               `json:"next-upstream-tries"`
-	ProxyBuffering           *bool             `json:"buffering"`
-	ProxyBuffers             *UpstreamBuffers  `json:"buffers"`
-	ProxyBufferSize          string            `json:"buffer-size"`
-	ClientMaxBodySize        string            `json:"client-max-body-size"`
-	TLS                      UpstreamTLS       `json:"tls"`
-	HealthCheck              *HealthCheck      `json:"healthCheck"`
-	SlowStart                string            `json:"slow-start"`
-	Queue                    *UpstreamQueue    `json:"queue"`
-	SessionCookie            *SessionCookie    `json:"sessionCookie"`
-	UseClusterIP             bool              `json:"use-cluster-ip"`
+	Name                     string                `json:"name"`
+	Service                  string                `json:"service"`
+	Subselector              map[string]string     `json:"subselector"`
+	Port                     uint16                `json:"port"`
+	Type                     string                `json:"type"`
+	LBMethod                 string                `json:"lb-method"`
+	FailTimeout              string                `json:"fail-timeout"`
+	MaxFails                 *int                  `json:"max-fails"`
+	MaxConns                 *int                  `json:"max-conns"`
+	Keepalive                *int                  `json:"keepalive"`
+	ProxyConnectTimeout      string                `json:"connect-timeout"`
+	ProxyReadTimeout         string                `json:"read-timeout"`
+	ProxySendTimeout         string                `json:"send-timeout"`
+	ProxyNextUpstream        string                `json:"next-upstream"`
+	ProxyNextUpstreamTimeout string                `json:"next-upstream-timeout"`
+	ProxyNextUpstreamTries   int                   `json:"next-upstream-tries"`
+	ProxyBuffering           *bool                 `json:"buffering"`
+	ProxyBuffers             *UpstreamBuffers      `json:"buffers"`
+	ProxyBufferSize          string                `json:"buffer-size"`
+	ClientMaxBodySize        string                `json:"client-max-body-size"`
+	TLS                      UpstreamTLS           `json:"tls"`
+	HealthCheck              *HealthCheck          `json:"healthCheck"`
+	SlowStart                string                `json:"slow-start"`
+	Queue                    *UpstreamQueue        `json:"queue"`
+	SessionCookie            *SessionCookie        `json:"sessionCookie"`
+	UseClusterIP             bool                  `json:"use-cluster-ip"`
+	OutlierDetection         *OutlierDetection     `json:"outlierDetection"`
+	Subsets                  []UpstreamSubset      `json:"subsets"`
+	TopologyAwareRouting     *TopologyAwareRouting `json:"topologyAwareRouting"`
+	DrainTimeout             string                `json:"drain-timeout"`
+	Host                     string                `json:"host"`
+	Resolver                 *UpstreamResolver     `json:"resolver"`
+	WebSocket                *UpstreamWebSocket    `json:"websocket"`
+}
+
+// UpstreamWebSocket defines the handling of the WebSocket connections of an Upstream.
+type UpstreamWebSocket struct {
+	Enable      bool   `json:"enable"`
+	IdleTimeout string `json:"idle-timeout"`
+}
+
+// UpstreamResolver defines the resolver settings of an Upstream with a DNS name.
+type UpstreamResolver struct {
+	Valid   string `json:"valid"`
+	Timeout string `json:"timeout"`
+	IPv6    *bool  `json:"ipv6"`
 }
 
 // UpstreamBuffers defines Buffer Configuration for an Upstream.
@@ -106,6 +127,8 @@ type HealthCheck struct {
 	SendTimeout    string       `json:"send-timeout"`
 	Headers        []Header     `json:"headers"`
 	StatusMatch    string       `json:"statusMatch"`
+	GRPCStatus     *int         `json:"grpcStatus"`
+	GRPCService    string       `json:"grpcService"`
 }
 
 // Header defines an HTTP Header.
@@ -125,6 +148,18 @@ type SessionCookie struct {
 	Secure   bool   `json:"secure"`
 }
 
+// UpstreamSubset defines a subset of the pods of an Upstream with the weight of its endpoints.
+type UpstreamSubset struct {
+	Subselector map[string]string `json:"subselector"`
+	Weight      int               `json:"weight"`
+}
+
+// TopologyAwareRouting defines the preference of the endpoints of an Upstream in the zone of the Ingress Controller.
+type TopologyAwareRouting struct {
+	Enable            bool `json:"enable"`
+	MinHealthyPercent *int `json:"minHealthyPercent"`
+}
+
 // Route defines a route.
 type Route struct {
 	Path             string            `json:"path"`
@@ -135,6 +170,50 @@ type Route struct {
 	Matches          []Match           `json:"matches"`
 	ErrorPages       []ErrorPage       `json:"errorPages"`
 	LocationSnippets string            `json:"location-snippets"`
+	Canary           *Canary           `json:"canary"`
+	Rollback         *Rollback         `json:"rollback"`
+	Mirror           *Mirror           `json:"mirror"`
+}
+
+// Canary defines a canary release in a Route. The traffic is split between the stable and the canary upstreams
+// according to the weight of the current step. The Ingress Controller advances the steps automatically.
+type Canary struct {
+	Stable   string          `json:"stable"`
+	Canary   string          `json:"canary"`
+	Override *CanaryOverride `json:"override"`
+	Steps    []CanaryStep    `json:"steps"`
+}
+
+// CanaryOverride defines a header or a cookie that sends a request to the canary upstream regardless of the weight.
+type CanaryOverride struct {
+	Header string `json:"header"`
+	Cookie string `json:"cookie"`
+	Value  string `json:"value"`
+}
+
+// CanaryStep defines a step of a canary release.
+type CanaryStep struct {
+	Weight   int    `json:"weight"`
+	Duration string `json:"duration"`
+}
+
+// Rollback defines the thresholds for the automatic rollback of the splits or the canary release of a Route.
+// When the responses of a target upstream exceed a threshold over the window, the Ingress Controller sets the weight
+// of the upstream to 0.
+type Rollback struct {
+	Window            string `json:"window"`
+	MinRequests       *int   `json:"minRequests"`
+	MaxErrorRate      *int   `json:"maxErrorRate"`
+	MaxLatency        string `json:"maxLatency"`
+	LatencyPercentile *int   `json:"latencyPercentile"`
+}
+
+// Mirror defines the mirroring of the requests of a Route to one or more upstreams.
+// The responses of the mirrored requests are ignored.
+type Mirror struct {
+	Upstreams   []string `json:"upstreams"`
+	Percentage  *int     `json:"percentage"`
+	RequestBody *bool    `json:"requestBody"`
 }
 
 // Action defines an action.
@@ -230,6 +309,7 @@ type ErrorPageRedirect struct {
 type TLS struct {
 	Secret   string       `json:"secret"`
 	Redirect *TLSRedirect `json:"redirect"`
+	HTTP3    *bool        `json:"http3"`
 }
 
 // TLSRedirect defines a redirect for a TLS.
@@ -245,6 +325,36 @@ type VirtualServerStatus struct {
 	Reason            string             `json:"reason"`
 	Message           string             `json:"message"`
 	ExternalEndpoints []ExternalEndpoint `json:"externalEndpoints,omitempty"`
+	Canaries          []CanaryStatus     `json:"canaries,omitempty"`
+	Rollbacks         []RollbackStatus   `json:"rollbacks,omitempty"`
+}
+
+// CanaryStatus defines the progress of a canary release of a Route.
+type CanaryStatus struct {
+	Path          string      `json:"path"`
+	Stable        string      `json:"stable"`
+	Canary        string      `json:"canary"`
+	Step          int         `json:"step"`
+	Weight        int         `json:"weight"`
+	State         string      `json:"state"`
+	StepStartTime metav1.Time `json:"stepStartTime"`
+}
+
+const (
+	// CanaryStateProgressing is used when the canary release has steps left.
+	CanaryStateProgressing = "Progressing"
+	// CanaryStateCompleted is used when the canary release has reached the last step.
+	CanaryStateCompleted = "Completed"
+	// CanaryStateRolledBack is used when the canary upstream was rolled back.
+	CanaryStateRolledBack = "RolledBack"
+)
+
+// RollbackStatus defines an upstream of a Route that was rolled back.
+type RollbackStatus struct {
+	Path     string      `json:"path"`
+	Upstream string      `json:"upstream"`
+	Reason   string      `json:"reason"`
+	Time     metav1.Time `json:"time"`
 }
 
 // ExternalEndpoint defines the IP and ports used to connect to this resource.
@@ -306,6 +416,15 @@ type UpstreamQueue struct {
 	Timeout string `json:"timeout"`
 }
 
+// OutlierDetection defines the ejection of the peers of an upstream that return consecutive errors.
+// The peers are ejected via the NGINX Plus API and restored after the ejection time.
+type OutlierDetection struct {
+	Consecutive5xx           *int   `json:"consecutive5xx"`
+	ConsecutiveGatewayErrors *int   `json:"consecutiveGatewayErrors"`
+	EjectionTime             string `json:"ejectionTime"`
+	MaxEjectionPercent       *int   `json:"maxEjectionPercent"`
+}
+
 // VirtualServerRouteStatus defines the status for the VirtualServerRoute resource.
 type VirtualServerRouteStatus struct {
 	State             string             `json:"state"`
@@ -343,13 +462,21 @@ type PolicyStatus struct {
 // The spec includes multiple fields, where each field represents a different policy.
 // Only one policy (field) is allowed.
 type PolicySpec struct {
-	AccessControl *AccessControl `json:"accessControl"`
-	RateLimit     *RateLimit     `json:"rateLimit"`
-	JWTAuth       *JWTAuth       `json:"jwt"`
-	IngressMTLS   *IngressMTLS   `json:"ingressMTLS"`
-	EgressMTLS    *EgressMTLS    `json:"egressMTLS"`
-	OIDC          *OIDC          `json:"oidc"`
-	WAF           *WAF           `json:"waf"`
+	AccessControl   *AccessControl   `json:"accessControl"`
+	RateLimit       *RateLimit       `json:"rateLimit"`
+	JWTAuth         *JWTAuth         `json:"jwt"`
+	IngressMTLS     *IngressMTLS     `json:"ingressMTLS"`
+	EgressMTLS      *EgressMTLS      `json:"egressMTLS"`
+	OIDC            *OIDC            `json:"oidc"`
+	WAF             *WAF             `json:"waf"`
+	Retry           *Retry           `json:"retry"`
+	CORS            *CORS            `json:"cors"`
+	BasicAuth       *BasicAuth       `json:"basicAuth"`
+	ExternalAuth    *ExternalAuth    `json:"externalAuth"`
+	Cache           *Cache           `json:"cache"`
+	Compression     *Compression     `json:"compression"`
+	RequestLimits   *RequestLimits   `json:"requestLimits"`
+	IPAccessControl *IPAccessControl `json:"ipAccessControl"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -369,26 +496,119 @@ type AccessControl struct {
 	Deny  []string `json:"deny"`
 }
 
+// IPAccessControl defines an access control policy that matches the address, the country or
+// the autonomous system of the client. The country and the autonomous system are looked up in the MaxMind GeoIP2 databases.
+// The client IP settings are only applied in the spec of a VirtualServer.
+// policy status: preview
+type IPAccessControl struct {
+	Allow        *IPAccessRule `json:"allow"`
+	Deny         *IPAccessRule `json:"deny"`
+	ClientIP     *ClientIP     `json:"clientIP"`
+	DenyResponse *DenyResponse `json:"denyResponse"`
+}
+
+// IPAccessRule defines the addresses, the countries and the autonomous systems of the clients.
+// A client matches the rule if it matches any of them.
+type IPAccessRule struct {
+	Addresses []string `json:"addresses"`
+	Countries []string `json:"countries"`
+	ASNs      []int    `json:"asns"`
+}
+
+// ClientIP defines the header that carries the address of the client when the request comes from a trusted address.
+type ClientIP struct {
+	Header           string   `json:"header"`
+	TrustedAddresses []string `json:"trustedAddresses"`
+	Recursive        bool     `json:"recursive"`
+}
+
+// DenyResponse defines the response to the denied requests.
+type DenyResponse struct {
+	Code int    `json:"code"`
+	Body string `json:"body"`
+}
+
 // RateLimit defines a rate limit policy.
 // policy status: preview
 type RateLimit struct {
-	Rate       string `json:"rate"`
-	Key        string `json:"key"`
-	Delay      *int   `json:"delay"`
-	NoDelay    *bool  `json:"noDelay"`
-	Burst      *int   `json:"burst"`
-	ZoneSize   string `json:"zoneSize"`
-	DryRun     *bool  `json:"dryRun"`
-	LogLevel   string `json:"logLevel"`
-	RejectCode *int   `json:"rejectCode"`
+	Rate       string          `json:"rate"`
+	Key        string          `json:"key"`
+	Delay      *int            `json:"delay"`
+	NoDelay    *bool           `json:"noDelay"`
+	Burst      *int            `json:"burst"`
+	ZoneSize   string          `json:"zoneSize"`
+	DryRun     *bool           `json:"dryRun"`
+	LogLevel   string          `json:"logLevel"`
+	RejectCode *int            `json:"rejectCode"`
+	Condition  *Condition      `json:"condition"`
+	Limits     []RateLimitTier `json:"limits"`
+	LimitConn  *LimitConn      `json:"limitConn"`
+}
+
+// RateLimitTier defines an additional rate limit of a rate limit policy.
+// If the condition is set, the limit applies only to the requests that match it.
+type RateLimitTier struct {
+	Rate      string     `json:"rate"`
+	Key       string     `json:"key"`
+	Delay     *int       `json:"delay"`
+	NoDelay   *bool      `json:"noDelay"`
+	Burst     *int       `json:"burst"`
+	ZoneSize  string     `json:"zoneSize"`
+	Condition *Condition `json:"condition"`
+}
+
+// LimitConn defines a limit of the number of concurrent connections per key.
+type LimitConn struct {
+	Key         string `json:"key"`
+	Connections int    `json:"connections"`
+	ZoneSize    string `json:"zoneSize"`
 }
 
 // JWTAuth holds JWT authentication configuration.
+// The keys are either stored in a Secret or fetched from a JWKS URI.
 // policy status: preview
 type JWTAuth struct {
+	Realm         string                `json:"realm"`
+	Secret        string                `json:"secret"`
+	Token         string                `json:"token"`
+	JWKSURI       string                `json:"jwksURI"`
+	KeyCache      string                `json:"keyCache"`
+	RequireClaims []JWTClaimRequirement `json:"requireClaims"`
+	ClaimHeaders  []JWTClaimHeader      `json:"claimHeaders"`
+}
+
+// JWTClaimRequirement defines a requirement for a claim of a JWT.
+// The claim is a dot-separated path to the claim, for example, realm_access.roles.
+type JWTClaimRequirement struct {
+	Claim    string `json:"claim"`
+	Equals   string `json:"equals"`
+	Contains string `json:"contains"`
+}
+
+// JWTClaimHeader defines a header that passes a claim of a JWT to the upstream.
+type JWTClaimHeader struct {
+	Claim  string `json:"claim"`
+	Header string `json:"header"`
+}
+
+// BasicAuth holds HTTP Basic authentication configuration.
+// policy status: preview
+type BasicAuth struct {
 	Realm  string `json:"realm"`
 	Secret string `json:"secret"`
-	Token  string `json:"token"`
+}
+
+// ExternalAuth defines an external authentication policy.
+// The auth service is either a Service in the namespace of the policy or a URL.
+// policy status: preview
+type ExternalAuth struct {
+	AuthServiceName string   `json:"authServiceName"`
+	AuthServicePort uint16   `json:"authServicePort"`
+	AuthPath        string   `json:"authPath"`
+	AuthURL         string   `json:"authURL"`
+	ResponseHeaders []string `json:"responseHeaders"`
+	SigninURL       string   `json:"signinURL"`
+	CacheTTL        string   `json:"cacheTTL"`
 }
 
 // IngressMTLS defines an Ingress MTLS policy.
@@ -414,14 +634,22 @@ type EgressMTLS struct {
 }
 
 // OIDC defines an Open ID Connect policy.
+// With PKCE enabled, the client is a public client and doesn't use a client secret.
+// The forwarded token is either the ID token (id), the access token (access) or none.
 type OIDC struct {
-	AuthEndpoint  string `json:"authEndpoint"`
-	TokenEndpoint string `json:"tokenEndpoint"`
-	JWKSURI       string `json:"jwksURI"`
-	ClientID      string `json:"clientID"`
-	ClientSecret  string `json:"clientSecret"`
-	Scope         string `json:"scope"`
-	RedirectURI   string `json:"redirectURI"`
+	AuthEndpoint          string   `json:"authEndpoint"`
+	TokenEndpoint         string   `json:"tokenEndpoint"`
+	JWKSURI               string   `json:"jwksURI"`
+	EndSessionEndpoint    string   `json:"endSessionEndpoint"`
+	ClientID              string   `json:"clientID"`
+	ClientSecret          string   `json:"clientSecret"`
+	Scope                 string   `json:"scope"`
+	RedirectURI           string   `json:"redirectURI"`
+	PostLogoutRedirectURI string   `json:"postLogoutRedirectURI"`
+	AuthExtraArgs         []string `json:"authExtraArgs"`
+	PKCEEnable            bool     `json:"pkceEnable"`
+	ForwardToken          string   `json:"forwardToken"`
+	ForwardTokenHeader    string   `json:"forwardTokenHeader"`
 }
 
 // WAF defines an WAF policy.
@@ -432,6 +660,88 @@ type WAF struct {
 	SecurityLog *SecurityLog `json:"securityLog"`
 }
 
+// Retry defines a retry policy. It overrides the next upstream settings of the upstreams.
+// policy status: preview
+type Retry struct {
+	Conditions    []string `json:"conditions"`
+	Tries         *int     `json:"tries"`
+	PerTryTimeout string   `json:"perTryTimeout"`
+	Timeout       string   `json:"timeout"`
+	NonIdempotent bool     `json:"nonIdempotent"`
+}
+
+// CORS defines a Cross-Origin Resource Sharing policy.
+// An allowed origin that starts with `~` is a regular expression.
+// policy status: preview
+type CORS struct {
+	AllowOrigins     []string `json:"allowOrigins"`
+	AllowMethods     []string `json:"allowMethods"`
+	AllowHeaders     []string `json:"allowHeaders"`
+	ExposeHeaders    []string `json:"exposeHeaders"`
+	AllowCredentials bool     `json:"allowCredentials"`
+	MaxAge           *int     `json:"maxAge"`
+}
+
+// Cache defines a response caching policy.
+// Every VirtualServer that references the policy gets its own cache zone in a subdirectory of the path.
+// policy status: preview
+type Cache struct {
+	ZoneSize         string       `json:"zoneSize"`
+	Path             string       `json:"path"`
+	MaxSize          string       `json:"maxSize"`
+	Inactive         string       `json:"inactive"`
+	Key              string       `json:"key"`
+	Methods          []string     `json:"methods"`
+	Valid            []CacheValid `json:"valid"`
+	MinUses          *int         `json:"minUses"`
+	UseStale         []string     `json:"useStale"`
+	BackgroundUpdate bool         `json:"backgroundUpdate"`
+	Lock             bool         `json:"lock"`
+	Bypass           []string     `json:"bypass"`
+	StatusHeader     string       `json:"statusHeader"`
+}
+
+// CacheValid defines the caching time of the responses with the status codes.
+// Without status codes, only the responses with the codes 200, 301 and 302 are cached.
+type CacheValid struct {
+	Codes []int  `json:"codes"`
+	Time  string `json:"time"`
+}
+
+// Compression defines a response compression policy.
+// policy status: preview
+type Compression struct {
+	Gzip   *Gzip   `json:"gzip"`
+	Brotli *Brotli `json:"brotli"`
+}
+
+// Gzip defines the gzip compression of the responses.
+type Gzip struct {
+	Types     []string `json:"types"`
+	Level     *int     `json:"level"`
+	MinLength *int     `json:"minLength"`
+	Proxied   []string `json:"proxied"`
+	Vary      bool     `json:"vary"`
+}
+
+// Brotli defines the brotli compression of the responses. Brotli is only supported in NGINX Plus.
+type Brotli struct {
+	Types     []string `json:"types"`
+	Level     *int     `json:"level"`
+	MinLength *int     `json:"minLength"`
+}
+
+// RequestLimits defines a policy that limits the size and configures the buffering of client requests.
+// The header buffers are only applied in the spec of a VirtualServer.
+// policy status: preview
+type RequestLimits struct {
+	ClientMaxBodySize        string           `json:"clientMaxBodySize"`
+	ClientBodyBufferSize     string           `json:"clientBodyBufferSize"`
+	RequestBuffering         *bool            `json:"requestBuffering"`
+	ClientHeaderBufferSize   string           `json:"clientHeaderBufferSize"`
+	LargeClientHeaderBuffers *UpstreamBuffers `json:"largeClientHeaderBuffers"`
+}
+
 // SecurityLog defines the security log of a WAF policy.
 type SecurityLog struct {
 	Enable    bool   `json:"enable"`
@@ -140,6 +140,280 @@ func (in *AddHeader) DeepCopy() *AddHeader {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BasicAuth) DeepCopyInto(out *BasicAuth) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BasicAuth.
+func (in *BasicAuth) DeepCopy() *BasicAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(BasicAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Brotli) DeepCopyInto(out *Brotli) {
+	*out = *in
+	if in.Types != nil {
+		in, out := &in.Types, &out.Types
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Level != nil {
+		in, out := &in.Level, &out.Level
+		*out = new(int)
+		**out = **in
+	}
+	if in.MinLength != nil {
+		in, out := &in.MinLength, &out.MinLength
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Brotli.
+func (in *Brotli) DeepCopy() *Brotli {
+	if in == nil {
+		return nil
+	}
+	out := new(Brotli)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CORS) DeepCopyInto(out *CORS) {
+	*out = *in
+	if in.AllowOrigins != nil {
+		in, out := &in.AllowOrigins, &out.AllowOrigins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowMethods != nil {
+		in, out := &in.AllowMethods, &out.AllowMethods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowHeaders != nil {
+		in, out := &in.AllowHeaders, &out.AllowHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExposeHeaders != nil {
+		in, out := &in.ExposeHeaders, &out.ExposeHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CORS.
+func (in *CORS) DeepCopy() *CORS {
+	if in == nil {
+		return nil
+	}
+	out := new(CORS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Cache) DeepCopyInto(out *Cache) {
+	*out = *in
+	if in.Methods != nil {
+		in, out := &in.Methods, &out.Methods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Valid != nil {
+		in, out := &in.Valid, &out.Valid
+		*out = make([]CacheValid, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MinUses != nil {
+		in, out := &in.MinUses, &out.MinUses
+		*out = new(int)
+		**out = **in
+	}
+	if in.UseStale != nil {
+		in, out := &in.UseStale, &out.UseStale
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Bypass != nil {
+		in, out := &in.Bypass, &out.Bypass
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Cache.
+func (in *Cache) DeepCopy() *Cache {
+	if in == nil {
+		return nil
+	}
+	out := new(Cache)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CacheValid) DeepCopyInto(out *CacheValid) {
+	*out = *in
+	if in.Codes != nil {
+		in, out := &in.Codes, &out.Codes
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheValid.
+func (in *CacheValid) DeepCopy() *CacheValid {
+	if in == nil {
+		return nil
+	}
+	out := new(CacheValid)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Canary) DeepCopyInto(out *Canary) {
+	*out = *in
+	if in.Override != nil {
+		in, out := &in.Override, &out.Override
+		*out = new(CanaryOverride)
+		**out = **in
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]CanaryStep, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Canary.
+func (in *Canary) DeepCopy() *Canary {
+	if in == nil {
+		return nil
+	}
+	out := new(Canary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryOverride) DeepCopyInto(out *CanaryOverride) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryOverride.
+func (in *CanaryOverride) DeepCopy() *CanaryOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryStatus) DeepCopyInto(out *CanaryStatus) {
+	*out = *in
+	in.StepStartTime.DeepCopyInto(&out.StepStartTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStatus.
+func (in *CanaryStatus) DeepCopy() *CanaryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryStep) DeepCopyInto(out *CanaryStep) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStep.
+func (in *CanaryStep) DeepCopy() *CanaryStep {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientIP) DeepCopyInto(out *ClientIP) {
+	*out = *in
+	if in.TrustedAddresses != nil {
+		in, out := &in.TrustedAddresses, &out.TrustedAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientIP.
+func (in *ClientIP) DeepCopy() *ClientIP {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientIP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Compression) DeepCopyInto(out *Compression) {
+	*out = *in
+	if in.Gzip != nil {
+		in, out := &in.Gzip, &out.Gzip
+		*out = new(Gzip)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Brotli != nil {
+		in, out := &in.Brotli, &out.Brotli
+		*out = new(Brotli)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Compression.
+func (in *Compression) DeepCopy() *Compression {
+	if in == nil {
+		return nil
+	}
+	out := new(Compression)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Condition) DeepCopyInto(out *Condition) {
 	*out = *in
@@ -156,6 +430,22 @@ func (in *Condition) DeepCopy() *Condition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DenyResponse) DeepCopyInto(out *DenyResponse) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DenyResponse.
+func (in *DenyResponse) DeepCopy() *DenyResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(DenyResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EgressMTLS) DeepCopyInto(out *EgressMTLS) {
 	*out = *in
@@ -252,6 +542,27 @@ func (in *ErrorPageReturn) DeepCopy() *ErrorPageReturn {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalAuth) DeepCopyInto(out *ExternalAuth) {
+	*out = *in
+	if in.ResponseHeaders != nil {
+		in, out := &in.ResponseHeaders, &out.ResponseHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalAuth.
+func (in *ExternalAuth) DeepCopy() *ExternalAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalEndpoint) DeepCopyInto(out *ExternalEndpoint) {
 	*out = *in
@@ -268,6 +579,42 @@ func (in *ExternalEndpoint) DeepCopy() *ExternalEndpoint {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Gzip) DeepCopyInto(out *Gzip) {
+	*out = *in
+	if in.Types != nil {
+		in, out := &in.Types, &out.Types
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Level != nil {
+		in, out := &in.Level, &out.Level
+		*out = new(int)
+		**out = **in
+	}
+	if in.MinLength != nil {
+		in, out := &in.MinLength, &out.MinLength
+		*out = new(int)
+		**out = **in
+	}
+	if in.Proxied != nil {
+		in, out := &in.Proxied, &out.Proxied
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Gzip.
+func (in *Gzip) DeepCopy() *Gzip {
+	if in == nil {
+		return nil
+	}
+	out := new(Gzip)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Header) DeepCopyInto(out *Header) {
 	*out = *in
@@ -297,6 +644,11 @@ func (in *HealthCheck) DeepCopyInto(out *HealthCheck) {
 		*out = make([]Header, len(*in))
 		copy(*out, *in)
 	}
+	if in.GRPCStatus != nil {
+		in, out := &in.GRPCStatus, &out.GRPCStatus
+		*out = new(int)
+		**out = **in
+	}
 	return
 }
 
@@ -310,6 +662,73 @@ func (in *HealthCheck) DeepCopy() *HealthCheck {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPAccessControl) DeepCopyInto(out *IPAccessControl) {
+	*out = *in
+	if in.Allow != nil {
+		in, out := &in.Allow, &out.Allow
+		*out = new(IPAccessRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Deny != nil {
+		in, out := &in.Deny, &out.Deny
+		*out = new(IPAccessRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientIP != nil {
+		in, out := &in.ClientIP, &out.ClientIP
+		*out = new(ClientIP)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DenyResponse != nil {
+		in, out := &in.DenyResponse, &out.DenyResponse
+		*out = new(DenyResponse)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPAccessControl.
+func (in *IPAccessControl) DeepCopy() *IPAccessControl {
+	if in == nil {
+		return nil
+	}
+	out := new(IPAccessControl)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPAccessRule) DeepCopyInto(out *IPAccessRule) {
+	*out = *in
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Countries != nil {
+		in, out := &in.Countries, &out.Countries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ASNs != nil {
+		in, out := &in.ASNs, &out.ASNs
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPAccessRule.
+func (in *IPAccessRule) DeepCopy() *IPAccessRule {
+	if in == nil {
+		return nil
+	}
+	out := new(IPAccessRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IngressMTLS) DeepCopyInto(out *IngressMTLS) {
 	*out = *in
@@ -334,6 +753,16 @@ func (in *IngressMTLS) DeepCopy() *IngressMTLS {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JWTAuth) DeepCopyInto(out *JWTAuth) {
 	*out = *in
+	if in.RequireClaims != nil {
+		in, out := &in.RequireClaims, &out.RequireClaims
+		*out = make([]JWTClaimRequirement, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClaimHeaders != nil {
+		in, out := &in.ClaimHeaders, &out.ClaimHeaders
+		*out = make([]JWTClaimHeader, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -342,56 +771,171 @@ func (in *JWTAuth) DeepCopy() *JWTAuth {
 	if in == nil {
 		return nil
 	}
-	out := new(JWTAuth)
+	out := new(JWTAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JWTClaimHeader) DeepCopyInto(out *JWTClaimHeader) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JWTClaimHeader.
+func (in *JWTClaimHeader) DeepCopy() *JWTClaimHeader {
+	if in == nil {
+		return nil
+	}
+	out := new(JWTClaimHeader)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JWTClaimRequirement) DeepCopyInto(out *JWTClaimRequirement) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JWTClaimRequirement.
+func (in *JWTClaimRequirement) DeepCopy() *JWTClaimRequirement {
+	if in == nil {
+		return nil
+	}
+	out := new(JWTClaimRequirement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LimitConn) DeepCopyInto(out *LimitConn) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LimitConn.
+func (in *LimitConn) DeepCopy() *LimitConn {
+	if in == nil {
+		return nil
+	}
+	out := new(LimitConn)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Match) DeepCopyInto(out *Match) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		copy(*out, *in)
+	}
+	if in.Action != nil {
+		in, out := &in.Action, &out.Action
+		*out = new(Action)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Splits != nil {
+		in, out := &in.Splits, &out.Splits
+		*out = make([]Split, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Match.
+func (in *Match) DeepCopy() *Match {
+	if in == nil {
+		return nil
+	}
+	out := new(Match)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Mirror) DeepCopyInto(out *Mirror) {
+	*out = *in
+	if in.Upstreams != nil {
+		in, out := &in.Upstreams, &out.Upstreams
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Percentage != nil {
+		in, out := &in.Percentage, &out.Percentage
+		*out = new(int)
+		**out = **in
+	}
+	if in.RequestBody != nil {
+		in, out := &in.RequestBody, &out.RequestBody
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Mirror.
+func (in *Mirror) DeepCopy() *Mirror {
+	if in == nil {
+		return nil
+	}
+	out := new(Mirror)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Match) DeepCopyInto(out *Match) {
+func (in *OIDC) DeepCopyInto(out *OIDC) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]Condition, len(*in))
+	if in.AuthExtraArgs != nil {
+		in, out := &in.AuthExtraArgs, &out.AuthExtraArgs
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.Action != nil {
-		in, out := &in.Action, &out.Action
-		*out = new(Action)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Splits != nil {
-		in, out := &in.Splits, &out.Splits
-		*out = make([]Split, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Match.
-func (in *Match) DeepCopy() *Match {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDC.
+func (in *OIDC) DeepCopy() *OIDC {
 	if in == nil {
 		return nil
 	}
-	out := new(Match)
+	out := new(OIDC)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OIDC) DeepCopyInto(out *OIDC) {
+func (in *OutlierDetection) DeepCopyInto(out *OutlierDetection) {
 	*out = *in
+	if in.Consecutive5xx != nil {
+		in, out := &in.Consecutive5xx, &out.Consecutive5xx
+		*out = new(int)
+		**out = **in
+	}
+	if in.ConsecutiveGatewayErrors != nil {
+		in, out := &in.ConsecutiveGatewayErrors, &out.ConsecutiveGatewayErrors
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxEjectionPercent != nil {
+		in, out := &in.MaxEjectionPercent, &out.MaxEjectionPercent
+		*out = new(int)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDC.
-func (in *OIDC) DeepCopy() *OIDC {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutlierDetection.
+func (in *OutlierDetection) DeepCopy() *OutlierDetection {
 	if in == nil {
 		return nil
 	}
-	out := new(OIDC)
+	out := new(OutlierDetection)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -489,7 +1033,7 @@ func (in *PolicySpec) DeepCopyInto(out *PolicySpec) {
 	if in.JWTAuth != nil {
 		in, out := &in.JWTAuth, &out.JWTAuth
 		*out = new(JWTAuth)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.IngressMTLS != nil {
 		in, out := &in.IngressMTLS, &out.IngressMTLS
@@ -504,13 +1048,53 @@ func (in *PolicySpec) DeepCopyInto(out *PolicySpec) {
 	if in.OIDC != nil {
 		in, out := &in.OIDC, &out.OIDC
 		*out = new(OIDC)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.WAF != nil {
 		in, out := &in.WAF, &out.WAF
 		*out = new(WAF)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(Retry)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CORS != nil {
+		in, out := &in.CORS, &out.CORS
+		*out = new(CORS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BasicAuth != nil {
+		in, out := &in.BasicAuth, &out.BasicAuth
+		*out = new(BasicAuth)
+		**out = **in
+	}
+	if in.ExternalAuth != nil {
+		in, out := &in.ExternalAuth, &out.ExternalAuth
+		*out = new(ExternalAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(Cache)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Compression != nil {
+		in, out := &in.Compression, &out.Compression
+		*out = new(Compression)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RequestLimits != nil {
+		in, out := &in.RequestLimits, &out.RequestLimits
+		*out = new(RequestLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IPAccessControl != nil {
+		in, out := &in.IPAccessControl, &out.IPAccessControl
+		*out = new(IPAccessControl)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -630,6 +1214,23 @@ func (in *RateLimit) DeepCopyInto(out *RateLimit) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.Condition != nil {
+		in, out := &in.Condition, &out.Condition
+		*out = new(Condition)
+		**out = **in
+	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = make([]RateLimitTier, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LimitConn != nil {
+		in, out := &in.LimitConn, &out.LimitConn
+		*out = new(LimitConn)
+		**out = **in
+	}
 	return
 }
 
@@ -643,6 +1244,142 @@ func (in *RateLimit) DeepCopy() *RateLimit {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitTier) DeepCopyInto(out *RateLimitTier) {
+	*out = *in
+	if in.Delay != nil {
+		in, out := &in.Delay, &out.Delay
+		*out = new(int)
+		**out = **in
+	}
+	if in.NoDelay != nil {
+		in, out := &in.NoDelay, &out.NoDelay
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Burst != nil {
+		in, out := &in.Burst, &out.Burst
+		*out = new(int)
+		**out = **in
+	}
+	if in.Condition != nil {
+		in, out := &in.Condition, &out.Condition
+		*out = new(Condition)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitTier.
+func (in *RateLimitTier) DeepCopy() *RateLimitTier {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitTier)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequestLimits) DeepCopyInto(out *RequestLimits) {
+	*out = *in
+	if in.RequestBuffering != nil {
+		in, out := &in.RequestBuffering, &out.RequestBuffering
+		*out = new(bool)
+		**out = **in
+	}
+	if in.LargeClientHeaderBuffers != nil {
+		in, out := &in.LargeClientHeaderBuffers, &out.LargeClientHeaderBuffers
+		*out = new(UpstreamBuffers)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequestLimits.
+func (in *RequestLimits) DeepCopy() *RequestLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(RequestLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Retry) DeepCopyInto(out *Retry) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tries != nil {
+		in, out := &in.Tries, &out.Tries
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Retry.
+func (in *Retry) DeepCopy() *Retry {
+	if in == nil {
+		return nil
+	}
+	out := new(Retry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Rollback) DeepCopyInto(out *Rollback) {
+	*out = *in
+	if in.MinRequests != nil {
+		in, out := &in.MinRequests, &out.MinRequests
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxErrorRate != nil {
+		in, out := &in.MaxErrorRate, &out.MaxErrorRate
+		*out = new(int)
+		**out = **in
+	}
+	if in.LatencyPercentile != nil {
+		in, out := &in.LatencyPercentile, &out.LatencyPercentile
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Rollback.
+func (in *Rollback) DeepCopy() *Rollback {
+	if in == nil {
+		return nil
+	}
+	out := new(Rollback)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollbackStatus) DeepCopyInto(out *RollbackStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollbackStatus.
+func (in *RollbackStatus) DeepCopy() *RollbackStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RollbackStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Route) DeepCopyInto(out *Route) {
 	*out = *in
@@ -677,6 +1414,21 @@ func (in *Route) DeepCopyInto(out *Route) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(Canary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollback != nil {
+		in, out := &in.Rollback, &out.Rollback
+		*out = new(Rollback)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Mirror != nil {
+		in, out := &in.Mirror, &out.Mirror
+		*out = new(Mirror)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -751,6 +1503,11 @@ func (in *TLS) DeepCopyInto(out *TLS) {
 		*out = new(TLSRedirect)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HTTP3 != nil {
+		in, out := &in.HTTP3, &out.HTTP3
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -785,6 +1542,27 @@ func (in *TLSRedirect) DeepCopy() *TLSRedirect {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologyAwareRouting) DeepCopyInto(out *TopologyAwareRouting) {
+	*out = *in
+	if in.MinHealthyPercent != nil {
+		in, out := &in.MinHealthyPercent, &out.MinHealthyPercent
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyAwareRouting.
+func (in *TopologyAwareRouting) DeepCopy() *TopologyAwareRouting {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologyAwareRouting)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Upstream) DeepCopyInto(out *Upstream) {
 	*out = *in
@@ -836,6 +1614,33 @@ func (in *Upstream) DeepCopyInto(out *Upstream) {
 		*out = new(SessionCookie)
 		**out = **in
 	}
+	if in.OutlierDetection != nil {
+		in, out := &in.OutlierDetection, &out.OutlierDetection
+		*out = new(OutlierDetection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Subsets != nil {
+		in, out := &in.Subsets, &out.Subsets
+		*out = make([]UpstreamSubset, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TopologyAwareRouting != nil {
+		in, out := &in.TopologyAwareRouting, &out.TopologyAwareRouting
+		*out = new(TopologyAwareRouting)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resolver != nil {
+		in, out := &in.Resolver, &out.Resolver
+		*out = new(UpstreamResolver)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WebSocket != nil {
+		in, out := &in.WebSocket, &out.WebSocket
+		*out = new(UpstreamWebSocket)
+		**out = **in
+	}
 	return
 }
 
@@ -881,6 +1686,50 @@ func (in *UpstreamQueue) DeepCopy() *UpstreamQueue {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpstreamResolver) DeepCopyInto(out *UpstreamResolver) {
+	*out = *in
+	if in.IPv6 != nil {
+		in, out := &in.IPv6, &out.IPv6
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpstreamResolver.
+func (in *UpstreamResolver) DeepCopy() *UpstreamResolver {
+	if in == nil {
+		return nil
+	}
+	out := new(UpstreamResolver)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpstreamSubset) DeepCopyInto(out *UpstreamSubset) {
+	*out = *in
+	if in.Subselector != nil {
+		in, out := &in.Subselector, &out.Subselector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpstreamSubset.
+func (in *UpstreamSubset) DeepCopy() *UpstreamSubset {
+	if in == nil {
+		return nil
+	}
+	out := new(UpstreamSubset)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UpstreamTLS) DeepCopyInto(out *UpstreamTLS) {
 	*out = *in
@@ -897,6 +1746,22 @@ func (in *UpstreamTLS) DeepCopy() *UpstreamTLS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpstreamWebSocket) DeepCopyInto(out *UpstreamWebSocket) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpstreamWebSocket.
+func (in *UpstreamWebSocket) DeepCopy() *UpstreamWebSocket {
+	if in == nil {
+		return nil
+	}
+	out := new(UpstreamWebSocket)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualServer) DeepCopyInto(out *VirtualServer) {
 	*out = *in
@@ -1118,6 +1983,20 @@ func (in *VirtualServerStatus) DeepCopyInto(out *VirtualServerStatus) {
 		*out = make([]ExternalEndpoint, len(*in))
 		copy(*out, *in)
 	}
+	if in.Canaries != nil {
+		in, out := &in.Canaries, &out.Canaries
+		*out = make([]CanaryStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Rollbacks != nil {
+		in, out := &in.Rollbacks, &out.Rollbacks
+		*out = make([]RollbackStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -85,13 +85,22 @@ type TransportServerListener struct {
 
 // Upstream defines an upstream.
 type Upstream struct {
-	Name        string       `json:"name"`
-	Service     string       `json:"service"`
-	Port        int          `json:"port"`
-	FailTimeout string       `json:"failTimeout"`
-	MaxFails    *int         `json:"maxFails"`
-	MaxConns    *int         `json:"maxConns"`
-	HealthCheck *HealthCheck `json:"healthCheck"`
+	Name             string            `json:"name"`
+	Service          string            `json:"service"`
+	Port             int               `json:"port"`
+	FailTimeout      string            `json:"failTimeout"`
+	MaxFails         *int              `json:"maxFails"`
+	MaxConns         *int              `json:"maxConns"`
+	HealthCheck      *HealthCheck      `json:"healthCheck"`
+	OutlierDetection *OutlierDetection `json:"outlierDetection"`
+}
+
+// OutlierDetection defines the ejection of the peers of an upstream that fail consecutive connections.
+// The peers are ejected via the NGINX Plus API and restored after the ejection time.
+type OutlierDetection struct {
+	ConsecutiveErrors  *int   `json:"consecutiveErrors"`
+	EjectionTime       string `json:"ejectionTime"`
+	MaxEjectionPercent *int   `json:"maxEjectionPercent"`
 }
 
 // HealthCheck defines the parameters for active Upstream HealthChecks.
@@ -137,6 +137,32 @@ func (in *Listener) DeepCopy() *Listener {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutlierDetection) DeepCopyInto(out *OutlierDetection) {
+	*out = *in
+	if in.ConsecutiveErrors != nil {
+		in, out := &in.ConsecutiveErrors, &out.ConsecutiveErrors
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxEjectionPercent != nil {
+		in, out := &in.MaxEjectionPercent, &out.MaxEjectionPercent
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutlierDetection.
+func (in *OutlierDetection) DeepCopy() *OutlierDetection {
+	if in == nil {
+		return nil
+	}
+	out := new(OutlierDetection)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SessionParameters) DeepCopyInto(out *SessionParameters) {
 	*out = *in
@@ -303,6 +329,11 @@ func (in *Upstream) DeepCopyInto(out *Upstream) {
 		*out = new(HealthCheck)
 		**out = **in
 	}
+	if in.OutlierDetection != nil {
+		in, out := &in.OutlierDetection, &out.OutlierDetection
+		*out = new(OutlierDetection)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
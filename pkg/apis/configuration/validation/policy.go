@@ -10,17 +10,18 @@ import (
 
 	"github.com/nginxinc/kubernetes-ingress/internal/k8s/appprotect"
 	v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 // ValidatePolicy validates a Policy.
-func ValidatePolicy(policy *v1.Policy, isPlus, enablePreviewPolicies, enableAppProtect bool) error {
-	allErrs := validatePolicySpec(&policy.Spec, field.NewPath("spec"), isPlus, enablePreviewPolicies, enableAppProtect)
+func ValidatePolicy(policy *v1.Policy, isPlus, enablePreviewPolicies, enableAppProtect, enableBrotli bool) error {
+	allErrs := validatePolicySpec(&policy.Spec, field.NewPath("spec"), isPlus, enablePreviewPolicies, enableAppProtect, enableBrotli)
 	return allErrs.ToAggregate()
 }
 
-func validatePolicySpec(spec *v1.PolicySpec, fieldPath *field.Path, isPlus, enablePreviewPolicies, enableAppProtect bool) field.ErrorList {
+func validatePolicySpec(spec *v1.PolicySpec, fieldPath *field.Path, isPlus, enablePreviewPolicies, enableAppProtect, enableBrotli bool) field.ErrorList {
 	allErrs := field.ErrorList{}
 
 	fieldCount := 0
@@ -100,8 +101,80 @@ func validatePolicySpec(spec *v1.PolicySpec, fieldPath *field.Path, isPlus, enab
 		fieldCount++
 	}
 
+	if spec.Retry != nil {
+		if !enablePreviewPolicies {
+			return append(allErrs, field.Forbidden(fieldPath.Child("retry"),
+				"retry is a preview policy. Preview policies must be enabled to use via cli argument -enable-preview-policies"))
+		}
+		allErrs = append(allErrs, validateRetry(spec.Retry, fieldPath.Child("retry"))...)
+		fieldCount++
+	}
+
+	if spec.CORS != nil {
+		if !enablePreviewPolicies {
+			return append(allErrs, field.Forbidden(fieldPath.Child("cors"),
+				"cors is a preview policy. Preview policies must be enabled to use via cli argument -enable-preview-policies"))
+		}
+		allErrs = append(allErrs, validateCORS(spec.CORS, fieldPath.Child("cors"))...)
+		fieldCount++
+	}
+
+	if spec.BasicAuth != nil {
+		if !enablePreviewPolicies {
+			return append(allErrs, field.Forbidden(fieldPath.Child("basicAuth"),
+				"basicAuth is a preview policy. Preview policies must be enabled to use via cli argument -enable-preview-policies"))
+		}
+		allErrs = append(allErrs, validateBasicAuth(spec.BasicAuth, fieldPath.Child("basicAuth"))...)
+		fieldCount++
+	}
+
+	if spec.ExternalAuth != nil {
+		if !enablePreviewPolicies {
+			return append(allErrs, field.Forbidden(fieldPath.Child("externalAuth"),
+				"externalAuth is a preview policy. Preview policies must be enabled to use via cli argument -enable-preview-policies"))
+		}
+		allErrs = append(allErrs, validateExternalAuth(spec.ExternalAuth, fieldPath.Child("externalAuth"))...)
+		fieldCount++
+	}
+
+	if spec.Cache != nil {
+		if !enablePreviewPolicies {
+			return append(allErrs, field.Forbidden(fieldPath.Child("cache"),
+				"cache is a preview policy. Preview policies must be enabled to use via cli argument -enable-preview-policies"))
+		}
+		allErrs = append(allErrs, validateCache(spec.Cache, fieldPath.Child("cache"))...)
+		fieldCount++
+	}
+
+	if spec.Compression != nil {
+		if !enablePreviewPolicies {
+			return append(allErrs, field.Forbidden(fieldPath.Child("compression"),
+				"compression is a preview policy. Preview policies must be enabled to use via cli argument -enable-preview-policies"))
+		}
+		allErrs = append(allErrs, validateCompression(spec.Compression, fieldPath.Child("compression"), isPlus, enableBrotli)...)
+		fieldCount++
+	}
+
+	if spec.RequestLimits != nil {
+		if !enablePreviewPolicies {
+			return append(allErrs, field.Forbidden(fieldPath.Child("requestLimits"),
+				"requestLimits is a preview policy. Preview policies must be enabled to use via cli argument -enable-preview-policies"))
+		}
+		allErrs = append(allErrs, validateRequestLimits(spec.RequestLimits, fieldPath.Child("requestLimits"))...)
+		fieldCount++
+	}
+
+	if spec.IPAccessControl != nil {
+		if !enablePreviewPolicies {
+			return append(allErrs, field.Forbidden(fieldPath.Child("ipAccessControl"),
+				"ipAccessControl is a preview policy. Preview policies must be enabled to use via cli argument -enable-preview-policies"))
+		}
+		allErrs = append(allErrs, validateIPAccessControl(spec.IPAccessControl, fieldPath.Child("ipAccessControl"))...)
+		fieldCount++
+	}
+
 	if fieldCount != 1 {
-		msg := "must specify exactly one of: `accessControl`, `rateLimit`, `ingressMTLS`, `egressMTLS`"
+		msg := "must specify exactly one of: `accessControl`, `rateLimit`, `ingressMTLS`, `egressMTLS`, `retry`, `cors`, `basicAuth`, `externalAuth`, `cache`, `compression`, `requestLimits`, `ipAccessControl`"
 		if isPlus {
 			msg = fmt.Sprint(msg, ", `jwt`, `oidc`, `waf`")
 		}
@@ -137,6 +210,126 @@ func validateAccessControl(accessControl *v1.AccessControl, fieldPath *field.Pat
 	return allErrs
 }
 
+func validateIPAccessControl(ipAccessControl *v1.IPAccessControl, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	fieldCount := 0
+
+	if ipAccessControl.Allow != nil {
+		allErrs = append(allErrs, validateIPAccessRule(ipAccessControl.Allow, fieldPath.Child("allow"))...)
+		fieldCount++
+	}
+
+	if ipAccessControl.Deny != nil {
+		allErrs = append(allErrs, validateIPAccessRule(ipAccessControl.Deny, fieldPath.Child("deny"))...)
+		fieldCount++
+	}
+
+	if fieldCount != 1 {
+		allErrs = append(allErrs, field.Invalid(fieldPath, "", "must specify exactly one of: `allow` or `deny`"))
+	}
+
+	if ipAccessControl.ClientIP != nil {
+		allErrs = append(allErrs, validateClientIP(ipAccessControl.ClientIP, fieldPath.Child("clientIP"))...)
+	}
+
+	if ipAccessControl.DenyResponse != nil {
+		allErrs = append(allErrs, validateDenyResponse(ipAccessControl.DenyResponse, fieldPath.Child("denyResponse"))...)
+	}
+
+	return allErrs
+}
+
+const (
+	countryCodeFmt    = `[A-Z]{2}`
+	countryCodeErrMsg = "must be an ISO 3166-1 alpha-2 country code"
+)
+
+var countryCodeRegexp = regexp.MustCompile("^" + countryCodeFmt + "$")
+
+func validateIPAccessRule(rule *v1.IPAccessRule, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(rule.Addresses) == 0 && len(rule.Countries) == 0 && len(rule.ASNs) == 0 {
+		return append(allErrs, field.Required(fieldPath, "must specify at least one of: `addresses`, `countries`, `asns`"))
+	}
+
+	for i, a := range rule.Addresses {
+		allErrs = append(allErrs, validateIPorCIDR(a, fieldPath.Child("addresses").Index(i))...)
+	}
+
+	allCountries := sets.String{}
+	for i, c := range rule.Countries {
+		idxPath := fieldPath.Child("countries").Index(i)
+
+		if !countryCodeRegexp.MatchString(c) {
+			msg := validation.RegexError(countryCodeErrMsg, countryCodeFmt, "US", "DE")
+			allErrs = append(allErrs, field.Invalid(idxPath, c, msg))
+		} else if allCountries.Has(c) {
+			allErrs = append(allErrs, field.Duplicate(idxPath, c))
+		} else {
+			allCountries.Insert(c)
+		}
+	}
+
+	allASNs := sets.NewInt()
+	for i, asn := range rule.ASNs {
+		idxPath := fieldPath.Child("asns").Index(i)
+
+		if asn <= 0 {
+			allErrs = append(allErrs, field.Invalid(idxPath, asn, "must be positive"))
+		} else if allASNs.Has(asn) {
+			allErrs = append(allErrs, field.Duplicate(idxPath, asn))
+		} else {
+			allASNs.Insert(asn)
+		}
+	}
+
+	return allErrs
+}
+
+func validateClientIP(clientIP *v1.ClientIP, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if clientIP.Header == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("header"), ""))
+	} else {
+		for _, msg := range validation.IsHTTPHeaderName(clientIP.Header) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("header"), clientIP.Header, msg))
+		}
+	}
+
+	if len(clientIP.TrustedAddresses) == 0 {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("trustedAddresses"), "must include at least one address"))
+	}
+
+	for i, a := range clientIP.TrustedAddresses {
+		allErrs = append(allErrs, validateIPorCIDR(a, fieldPath.Child("trustedAddresses").Index(i))...)
+	}
+
+	return allErrs
+}
+
+func validateDenyResponse(denyResponse *v1.DenyResponse, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if denyResponse.Code != 0 && (denyResponse.Code < 400 || denyResponse.Code > 599) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("code"), denyResponse.Code,
+			"must be a valid status code 4XX or 5XX, for example, 403"))
+	}
+
+	if denyResponse.Body == "" {
+		return allErrs
+	}
+
+	if !escapedStringsFmtRegexp.MatchString(denyResponse.Body) {
+		msg := validation.RegexError(escapedStringsErrMsg, escapedStringsFmt, "Access denied", `{\"error\": \"forbidden\"}`)
+		return append(allErrs, field.Invalid(fieldPath.Child("body"), denyResponse.Body, msg))
+	}
+
+	return append(allErrs, validateStringWithVariables(denyResponse.Body, fieldPath.Child("body"), nil, map[string]bool{}, false)...)
+}
+
 func validateRateLimit(rateLimit *v1.RateLimit, fieldPath *field.Path, isPlus bool) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -152,6 +345,18 @@ func validateRateLimit(rateLimit *v1.RateLimit, fieldPath *field.Path, isPlus bo
 		allErrs = append(allErrs, validatePositiveInt(*rateLimit.Burst, fieldPath.Child("burst"))...)
 	}
 
+	if rateLimit.Condition != nil {
+		allErrs = append(allErrs, validateCondition(*rateLimit.Condition, fieldPath.Child("condition"))...)
+	}
+
+	for i, l := range rateLimit.Limits {
+		allErrs = append(allErrs, validateRateLimitTier(l, fieldPath.Child("limits").Index(i), isPlus)...)
+	}
+
+	if rateLimit.LimitConn != nil {
+		allErrs = append(allErrs, validateLimitConn(rateLimit.LimitConn, fieldPath.Child("limitConn"), isPlus)...)
+	}
+
 	if rateLimit.LogLevel != "" {
 		allErrs = append(allErrs, validateRateLimitLogLevel(rateLimit.LogLevel, fieldPath.Child("logLevel"))...)
 	}
@@ -166,18 +371,274 @@ func validateRateLimit(rateLimit *v1.RateLimit, fieldPath *field.Path, isPlus bo
 	return allErrs
 }
 
+func validateRateLimitTier(tier v1.RateLimitTier, fieldPath *field.Path, isPlus bool) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	allErrs = append(allErrs, validateRateLimitZoneSize(tier.ZoneSize, fieldPath.Child("zoneSize"))...)
+	allErrs = append(allErrs, validateRate(tier.Rate, fieldPath.Child("rate"))...)
+	allErrs = append(allErrs, validateRateLimitKey(tier.Key, fieldPath.Child("key"), isPlus)...)
+
+	if tier.Delay != nil {
+		allErrs = append(allErrs, validatePositiveInt(*tier.Delay, fieldPath.Child("delay"))...)
+	}
+
+	if tier.Burst != nil {
+		allErrs = append(allErrs, validatePositiveInt(*tier.Burst, fieldPath.Child("burst"))...)
+	}
+
+	if tier.Condition != nil {
+		allErrs = append(allErrs, validateCondition(*tier.Condition, fieldPath.Child("condition"))...)
+	}
+
+	return allErrs
+}
+
+func validateLimitConn(limitConn *v1.LimitConn, fieldPath *field.Path, isPlus bool) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	allErrs = append(allErrs, validateRateLimitZoneSize(limitConn.ZoneSize, fieldPath.Child("zoneSize"))...)
+	allErrs = append(allErrs, validateRateLimitKey(limitConn.Key, fieldPath.Child("key"), isPlus)...)
+	allErrs = append(allErrs, validatePositiveInt(limitConn.Connections, fieldPath.Child("connections"))...)
+
+	return allErrs
+}
+
 func validateJWT(jwt *v1.JWTAuth, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
 	allErrs = append(allErrs, validateJWTRealm(jwt.Realm, fieldPath.Child("realm"))...)
 
-	if jwt.Secret == "" {
-		return append(allErrs, field.Required(fieldPath.Child("secret"), ""))
+	if (jwt.Secret == "") == (jwt.JWKSURI == "") {
+		return append(allErrs, field.Required(fieldPath, "must specify exactly one of: `secret`, `jwksURI`"))
+	}
+
+	if jwt.Secret != "" {
+		allErrs = append(allErrs, validateSecretName(jwt.Secret, fieldPath.Child("secret"))...)
+	} else {
+		allErrs = append(allErrs, validateProxyPassURL(jwt.JWKSURI, fieldPath.Child("jwksURI"))...)
 	}
-	allErrs = append(allErrs, validateSecretName(jwt.Secret, fieldPath.Child("secret"))...)
 
 	allErrs = append(allErrs, validateJWTToken(jwt.Token, fieldPath.Child("token"))...)
 
+	if jwt.KeyCache != "" {
+		allErrs = append(allErrs, validateTime(jwt.KeyCache, fieldPath.Child("keyCache"))...)
+	}
+
+	for i, r := range jwt.RequireClaims {
+		allErrs = append(allErrs, validateJWTClaimRequirement(r, fieldPath.Child("requireClaims").Index(i))...)
+	}
+
+	allErrs = append(allErrs, validateJWTClaimHeaders(jwt.ClaimHeaders, fieldPath.Child("claimHeaders"))...)
+
+	return allErrs
+}
+
+const (
+	jwtClaimFmt    = `[a-zA-Z0-9_:-]+(\.[a-zA-Z0-9_:-]+)*`
+	jwtClaimErrMsg = "must be a dot-separated path of claim names that consist of alphanumeric characters, '_', ':' or '-'"
+)
+
+var jwtClaimRegexp = regexp.MustCompile("^" + jwtClaimFmt + "$")
+
+func validateJWTClaim(claim string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if claim == "" {
+		return append(allErrs, field.Required(fieldPath, ""))
+	}
+
+	if !jwtClaimRegexp.MatchString(claim) {
+		msg := validation.RegexError(jwtClaimErrMsg, jwtClaimFmt, "sub", "realm_access.roles")
+		return append(allErrs, field.Invalid(fieldPath, claim, msg))
+	}
+
+	return allErrs
+}
+
+func validateJWTClaimRequirement(requirement v1.JWTClaimRequirement, fieldPath *field.Path) field.ErrorList {
+	allErrs := validateJWTClaim(requirement.Claim, fieldPath.Child("claim"))
+
+	if (requirement.Equals == "") == (requirement.Contains == "") {
+		return append(allErrs, field.Required(fieldPath, "must specify exactly one of: `equals`, `contains`"))
+	}
+
+	if requirement.Equals != "" {
+		allErrs = append(allErrs, validateJWTClaimValue(requirement.Equals, fieldPath.Child("equals"))...)
+	} else {
+		allErrs = append(allErrs, validateJWTClaimValue(requirement.Contains, fieldPath.Child("contains"))...)
+
+		// the values of an array claim are separated by commas
+		if strings.Contains(requirement.Contains, ",") {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("contains"), requirement.Contains, "must not contain commas"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateJWTClaimValue(value string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if strings.ContainsAny(value, "\"\\$") {
+		return append(allErrs, field.Invalid(fieldPath, value, "must not contain double quotes, backslashes or variables"))
+	}
+
+	return allErrs
+}
+
+func validateJWTClaimHeaders(headers []v1.JWTClaimHeader, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	allHeaders := sets.String{}
+	for i, h := range headers {
+		idxPath := fieldPath.Index(i)
+
+		allErrs = append(allErrs, validateJWTClaim(h.Claim, idxPath.Child("claim"))...)
+
+		if h.Header == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("header"), ""))
+			continue
+		}
+
+		for _, msg := range validation.IsHTTPHeaderName(h.Header) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("header"), h.Header, msg))
+		}
+
+		if allHeaders.Has(strings.ToLower(h.Header)) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("header"), h.Header))
+		} else {
+			allHeaders.Insert(strings.ToLower(h.Header))
+		}
+	}
+
+	return allErrs
+}
+
+func validateBasicAuth(basicAuth *v1.BasicAuth, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if basicAuth.Realm != "" {
+		allErrs = append(allErrs, validateJWTRealm(basicAuth.Realm, fieldPath.Child("realm"))...)
+	}
+
+	if basicAuth.Secret == "" {
+		return append(allErrs, field.Required(fieldPath.Child("secret"), ""))
+	}
+	allErrs = append(allErrs, validateSecretName(basicAuth.Secret, fieldPath.Child("secret"))...)
+
+	return allErrs
+}
+
+func validateExternalAuth(externalAuth *v1.ExternalAuth, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if (externalAuth.AuthServiceName == "") == (externalAuth.AuthURL == "") {
+		return append(allErrs, field.Required(fieldPath, "must specify exactly one of: `authServiceName`, `authURL`"))
+	}
+
+	if externalAuth.AuthServiceName != "" {
+		allErrs = append(allErrs, validateServiceName(externalAuth.AuthServiceName, fieldPath.Child("authServiceName"))...)
+
+		if externalAuth.AuthServicePort == 0 {
+			allErrs = append(allErrs, field.Required(fieldPath.Child("authServicePort"), ""))
+		}
+
+		if externalAuth.AuthPath != "" {
+			allErrs = append(allErrs, validateExternalAuthPath(externalAuth.AuthPath, fieldPath.Child("authPath"))...)
+		}
+	} else {
+		if externalAuth.AuthServicePort != 0 {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("authServicePort"), "can only be used with `authServiceName`"))
+		}
+
+		if externalAuth.AuthPath != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("authPath"), "can only be used with `authServiceName`"))
+		}
+
+		allErrs = append(allErrs, validateProxyPassURL(externalAuth.AuthURL, fieldPath.Child("authURL"))...)
+	}
+
+	allErrs = append(allErrs, validateExternalAuthResponseHeaders(externalAuth.ResponseHeaders, fieldPath.Child("responseHeaders"))...)
+
+	if externalAuth.SigninURL != "" {
+		allErrs = append(allErrs, validateExternalAuthSigninURL(externalAuth.SigninURL, fieldPath.Child("signinURL"))...)
+	}
+
+	if externalAuth.CacheTTL != "" {
+		allErrs = append(allErrs, validateTime(externalAuth.CacheTTL, fieldPath.Child("cacheTTL"))...)
+	}
+
+	return allErrs
+}
+
+func validateExternalAuthPath(path string, fieldPath *field.Path) field.ErrorList {
+	allErrs := validatePath(path, fieldPath)
+
+	if strings.Contains(path, "$") {
+		allErrs = append(allErrs, field.Invalid(fieldPath, path, "must not contain variables"))
+	}
+
+	return allErrs
+}
+
+// validateProxyPassURL validates a URL that is used as is in a proxy_pass directive.
+func validateProxyPassURL(proxyURL string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if strings.ContainsAny(proxyURL, " \t\"'{};$\\") {
+		return append(allErrs, field.Invalid(fieldPath, proxyURL, "must not contain whitespace, quotes, braces, semicolons, backslashes or variables"))
+	}
+
+	u, err := url.Parse(proxyURL)
+	if err == nil && u.Scheme != "http" && u.Scheme != "https" {
+		return append(allErrs, field.Invalid(fieldPath, proxyURL, "scheme must be http or https"))
+	}
+
+	return append(allErrs, validateURL(proxyURL, fieldPath)...)
+}
+
+func validateExternalAuthResponseHeaders(headers []string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	allHeaders := sets.String{}
+	for i, h := range headers {
+		idxPath := fieldPath.Index(i)
+
+		for _, msg := range validation.IsHTTPHeaderName(h) {
+			allErrs = append(allErrs, field.Invalid(idxPath, h, msg))
+		}
+
+		if allHeaders.Has(strings.ToLower(h)) {
+			allErrs = append(allErrs, field.Duplicate(idxPath, h))
+		} else {
+			allHeaders.Insert(strings.ToLower(h))
+		}
+	}
+
+	return allErrs
+}
+
+func validateExternalAuthSigninURL(signinURL string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if !strings.HasPrefix(signinURL, "http://") && !strings.HasPrefix(signinURL, "https://") {
+		return append(allErrs, field.Invalid(fieldPath, signinURL, "must start with http:// or https://"))
+	}
+
+	if !escapedStringsFmtRegexp.MatchString(signinURL) {
+		msg := validation.RegexError(escapedStringsErrMsg, escapedStringsFmt, "https://auth.example.com/signin",
+			"https://auth.example.com/signin?rd=${scheme}://${host}${request_uri}")
+		return append(allErrs, field.Invalid(fieldPath, signinURL, msg))
+	}
+
+	if strings.ContainsAny(signinURL, " \t;") {
+		return append(allErrs, field.Invalid(fieldPath, signinURL, "must not contain whitespace or semicolons"))
+	}
+
+	// the URL is used in the error_page directive, which supports variables.
+	isPlus := false
+	allErrs = append(allErrs, validateStringWithVariables(signinURL, fieldPath, nil, validRedirectVariableNames, isPlus)...)
+
 	return allErrs
 }
 
@@ -231,7 +692,11 @@ func validateOIDC(oidc *v1.OIDC, fieldPath *field.Path) field.ErrorList {
 	if oidc.ClientID == "" {
 		return append(allErrs, field.Required(fieldPath.Child("clientID"), ""))
 	}
-	if oidc.ClientSecret == "" {
+	if oidc.PKCEEnable {
+		if oidc.ClientSecret != "" {
+			return append(allErrs, field.Forbidden(fieldPath.Child("clientSecret"), "clientSecret is not used when PKCE is enabled"))
+		}
+	} else if oidc.ClientSecret == "" {
 		return append(allErrs, field.Required(fieldPath.Child("clientSecret"), ""))
 	}
 
@@ -243,11 +708,91 @@ func validateOIDC(oidc *v1.OIDC, fieldPath *field.Path) field.ErrorList {
 		allErrs = append(allErrs, validatePath(oidc.RedirectURI, fieldPath.Child("redirectURI"))...)
 	}
 
-	allErrs = append(allErrs, validateURL(oidc.AuthEndpoint, fieldPath.Child("authEndpoint"))...)
+	if oidc.PostLogoutRedirectURI != "" {
+		allErrs = append(allErrs, validatePath(oidc.PostLogoutRedirectURI, fieldPath.Child("postLogoutRedirectURI"))...)
+	}
+
+	allErrs = append(allErrs, validateURL(oidc.AuthEndpoint, fieldPath.Child("authEndpoint"))...)
 	allErrs = append(allErrs, validateURL(oidc.TokenEndpoint, fieldPath.Child("tokenEndpoint"))...)
 	allErrs = append(allErrs, validateURL(oidc.JWKSURI, fieldPath.Child("jwksURI"))...)
-	allErrs = append(allErrs, validateSecretName(oidc.ClientSecret, fieldPath.Child("clientSecret"))...)
+	if oidc.EndSessionEndpoint != "" {
+		allErrs = append(allErrs, validateURL(oidc.EndSessionEndpoint, fieldPath.Child("endSessionEndpoint"))...)
+	}
+	if oidc.ClientSecret != "" {
+		allErrs = append(allErrs, validateSecretName(oidc.ClientSecret, fieldPath.Child("clientSecret"))...)
+	}
 	allErrs = append(allErrs, validateClientID(oidc.ClientID, fieldPath.Child("clientID"))...)
+	allErrs = append(allErrs, validateOIDCAuthExtraArgs(oidc.AuthExtraArgs, fieldPath.Child("authExtraArgs"))...)
+	allErrs = append(allErrs, validateOIDCForwardToken(oidc.ForwardToken, oidc.ForwardTokenHeader, fieldPath)...)
+
+	return allErrs
+}
+
+const (
+	oidcAuthExtraArgFmt    = `[a-zA-Z0-9_.~-]+=[a-zA-Z0-9_.~%:/+,-]*`
+	oidcAuthExtraArgErrMsg = "must be a URL-encoded query parameter in the format key=value"
+)
+
+var oidcAuthExtraArgRegexp = regexp.MustCompile("^" + oidcAuthExtraArgFmt + "$")
+
+// oidcReservedAuthArgs are the arguments of the authorization request that the Ingress Controller sets itself.
+var oidcReservedAuthArgs = map[string]bool{
+	"response_type":         true,
+	"scope":                 true,
+	"client_id":             true,
+	"redirect_uri":          true,
+	"nonce":                 true,
+	"state":                 true,
+	"code_challenge":        true,
+	"code_challenge_method": true,
+}
+
+func validateOIDCAuthExtraArgs(args []string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, arg := range args {
+		idxPath := fieldPath.Index(i)
+
+		if !oidcAuthExtraArgRegexp.MatchString(arg) {
+			msg := validation.RegexError(oidcAuthExtraArgErrMsg, oidcAuthExtraArgFmt, "prompt=login", "kc_idp_hint=github")
+			allErrs = append(allErrs, field.Invalid(idxPath, arg, msg))
+			continue
+		}
+
+		key := strings.SplitN(arg, "=", 2)[0]
+		if oidcReservedAuthArgs[key] {
+			allErrs = append(allErrs, field.Forbidden(idxPath, fmt.Sprintf("argument %v is set by the Ingress Controller", key)))
+		}
+	}
+
+	return allErrs
+}
+
+var validOIDCForwardTokens = map[string]bool{
+	"id":     true,
+	"access": true,
+	"none":   true,
+}
+
+func validateOIDCForwardToken(token string, header string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if token != "" && !validOIDCForwardTokens[token] {
+		msg := fmt.Sprintf("must be one of: %v", mapToPrettyString(validOIDCForwardTokens))
+		return append(allErrs, field.Invalid(fieldPath.Child("forwardToken"), token, msg))
+	}
+
+	if header == "" {
+		return allErrs
+	}
+
+	if token == "" || token == "none" {
+		return append(allErrs, field.Forbidden(fieldPath.Child("forwardTokenHeader"), "forwardTokenHeader requires forwardToken id or access"))
+	}
+
+	for _, msg := range validation.IsHTTPHeaderName(header) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("forwardTokenHeader"), header, msg))
+	}
 
 	return allErrs
 }
@@ -268,6 +813,485 @@ func validateWAF(waf *v1.WAF, fieldPath *field.Path) field.ErrorList {
 	return allErrs
 }
 
+func validateRetry(retry *v1.Retry, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	allErrs = append(allErrs, validateRetryConditions(retry.Conditions, fieldPath.Child("conditions"))...)
+
+	if retry.Tries != nil {
+		allErrs = append(allErrs, validatePositiveIntOrZero(*retry.Tries, fieldPath.Child("tries"))...)
+	}
+
+	allErrs = append(allErrs, validateTime(retry.PerTryTimeout, fieldPath.Child("perTryTimeout"))...)
+	allErrs = append(allErrs, validateTime(retry.Timeout, fieldPath.Child("timeout"))...)
+
+	return allErrs
+}
+
+var validRetryConditions = map[string]bool{
+	"error":          true,
+	"timeout":        true,
+	"invalid_header": true,
+	"http_500":       true,
+	"http_502":       true,
+	"http_503":       true,
+	"http_504":       true,
+	"http_403":       true,
+	"http_404":       true,
+	"http_429":       true,
+}
+
+func validateRetryConditions(conditions []string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	allConditions := sets.String{}
+
+	for i, c := range conditions {
+		idxPath := fieldPath.Index(i)
+
+		if !validRetryConditions[c] {
+			allErrs = append(allErrs, field.Invalid(idxPath, c, fmt.Sprintf("Accepted values: %s",
+				mapToPrettyString(validRetryConditions))))
+		} else if allConditions.Has(c) {
+			allErrs = append(allErrs, field.Duplicate(idxPath, c))
+		} else {
+			allConditions.Insert(c)
+		}
+	}
+
+	return allErrs
+}
+
+func validateCache(cache *v1.Cache, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if cache.ZoneSize == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("zoneSize"), ""))
+	} else {
+		allErrs = append(allErrs, validateSize(cache.ZoneSize, fieldPath.Child("zoneSize"))...)
+	}
+
+	if cache.Path != "" {
+		allErrs = append(allErrs, validateCachePath(cache.Path, fieldPath.Child("path"))...)
+	}
+
+	allErrs = append(allErrs, validateOffset(cache.MaxSize, fieldPath.Child("maxSize"))...)
+	allErrs = append(allErrs, validateTime(cache.Inactive, fieldPath.Child("inactive"))...)
+
+	if cache.Key != "" {
+		allErrs = append(allErrs, validateCacheKey(cache.Key, fieldPath.Child("key"))...)
+	}
+
+	allErrs = append(allErrs, validateCacheMethods(cache.Methods, fieldPath.Child("methods"))...)
+
+	for i, v := range cache.Valid {
+		allErrs = append(allErrs, validateCacheValid(v, fieldPath.Child("valid").Index(i))...)
+	}
+
+	if cache.MinUses != nil {
+		allErrs = append(allErrs, validatePositiveInt(*cache.MinUses, fieldPath.Child("minUses"))...)
+	}
+
+	allErrs = append(allErrs, validateCacheUseStale(cache.UseStale, fieldPath.Child("useStale"))...)
+
+	if cache.BackgroundUpdate && !sets.NewString(cache.UseStale...).Has("updating") {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("backgroundUpdate"), cache.BackgroundUpdate,
+			"requires `updating` in `useStale`"))
+	}
+
+	for i, b := range cache.Bypass {
+		allErrs = append(allErrs, validateCacheBypass(b, fieldPath.Child("bypass").Index(i))...)
+	}
+
+	if cache.StatusHeader != "" {
+		for _, msg := range validation.IsHTTPHeaderName(cache.StatusHeader) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("statusHeader"), cache.StatusHeader, msg))
+		}
+	}
+
+	return allErrs
+}
+
+var cacheKeySpecialVariables = []string{"arg_", "http_", "cookie_"}
+
+const (
+	cachePathFmt    = `/[a-zA-Z0-9_./-]*`
+	cachePathErrMsg = "must be an absolute path"
+)
+
+var cachePathRegexp = regexp.MustCompile("^" + cachePathFmt + "$")
+
+func validateCachePath(path string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if !cachePathRegexp.MatchString(path) {
+		msg := validation.RegexError(cachePathErrMsg, cachePathFmt, "/var/cache/nginx", "/data/cache")
+		return append(allErrs, field.Invalid(fieldPath, path, msg))
+	}
+
+	for _, segment := range strings.Split(path, "/") {
+		if segment == ".." {
+			return append(allErrs, field.Invalid(fieldPath, path, "must not include `..`"))
+		}
+	}
+
+	return allErrs
+}
+
+// cacheKeyVariables includes NGINX variables allowed to be used in a cache policy key.
+var cacheKeyVariables = map[string]bool{
+	"scheme":         true,
+	"host":           true,
+	"proxy_host":     true,
+	"request_method": true,
+	"request_uri":    true,
+	"uri":            true,
+	"args":           true,
+}
+
+func validateCacheKey(key string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if !escapedStringsFmtRegexp.MatchString(key) {
+		msg := validation.RegexError(escapedStringsErrMsg, escapedStringsFmt, `${scheme}${host}${request_uri}`)
+		return append(allErrs, field.Invalid(fieldPath, key, msg))
+	}
+
+	return append(allErrs, validateStringWithVariables(key, fieldPath, cacheKeySpecialVariables, cacheKeyVariables, false)...)
+}
+
+var validCacheMethods = map[string]bool{
+	"GET":  true,
+	"HEAD": true,
+	"POST": true,
+}
+
+func validateCacheMethods(methods []string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	allMethods := sets.String{}
+
+	for i, m := range methods {
+		idxPath := fieldPath.Index(i)
+
+		if !validCacheMethods[m] {
+			allErrs = append(allErrs, field.Invalid(idxPath, m, fmt.Sprintf("Accepted values: %s",
+				mapToPrettyString(validCacheMethods))))
+		} else if allMethods.Has(m) {
+			allErrs = append(allErrs, field.Duplicate(idxPath, m))
+		} else {
+			allMethods.Insert(m)
+		}
+	}
+
+	return allErrs
+}
+
+func validateCacheValid(valid v1.CacheValid, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, c := range valid.Codes {
+		if c < 100 || c > 599 {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("codes").Index(i), c, "must be within the range [100-599]"))
+		}
+	}
+
+	if valid.Time == "" {
+		return append(allErrs, field.Required(fieldPath.Child("time"), ""))
+	}
+
+	return append(allErrs, validateTime(valid.Time, fieldPath.Child("time"))...)
+}
+
+var validCacheUseStaleConditions = map[string]bool{
+	"error":          true,
+	"timeout":        true,
+	"invalid_header": true,
+	"updating":       true,
+	"http_500":       true,
+	"http_502":       true,
+	"http_503":       true,
+	"http_504":       true,
+	"http_403":       true,
+	"http_404":       true,
+	"http_429":       true,
+}
+
+func validateCacheUseStale(conditions []string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	allConditions := sets.String{}
+
+	for i, c := range conditions {
+		idxPath := fieldPath.Index(i)
+
+		if !validCacheUseStaleConditions[c] {
+			allErrs = append(allErrs, field.Invalid(idxPath, c, fmt.Sprintf("Accepted values: %s",
+				mapToPrettyString(validCacheUseStaleConditions))))
+		} else if allConditions.Has(c) {
+			allErrs = append(allErrs, field.Duplicate(idxPath, c))
+		} else {
+			allConditions.Insert(c)
+		}
+	}
+
+	return allErrs
+}
+
+var cacheBypassSpecialVariables = []string{"arg_", "http_", "cookie_"}
+
+func validateCacheBypass(bypass string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if bypass == "" {
+		return append(allErrs, field.Required(fieldPath, ""))
+	}
+
+	if !escapedStringsFmtRegexp.MatchString(bypass) {
+		msg := validation.RegexError(escapedStringsErrMsg, escapedStringsFmt, `${http_pragma}`, `${cookie_nocache}`)
+		return append(allErrs, field.Invalid(fieldPath, bypass, msg))
+	}
+
+	return append(allErrs, validateStringWithVariables(bypass, fieldPath, cacheBypassSpecialVariables, map[string]bool{}, false)...)
+}
+
+func validateCompression(compression *v1.Compression, fieldPath *field.Path, isPlus, enableBrotli bool) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if compression.Gzip == nil && compression.Brotli == nil {
+		return append(allErrs, field.Required(fieldPath, "must specify at least one of: `gzip`, `brotli`"))
+	}
+
+	if compression.Gzip != nil {
+		allErrs = append(allErrs, validateGzip(compression.Gzip, fieldPath.Child("gzip"))...)
+	}
+
+	if compression.Brotli != nil {
+		if !isPlus {
+			return append(allErrs, field.Forbidden(fieldPath.Child("brotli"), "brotli is only supported in NGINX Plus"))
+		}
+		if !enableBrotli {
+			return append(allErrs, field.Forbidden(fieldPath.Child("brotli"), "brotli must be enabled via cli argument -enable-brotli"))
+		}
+		allErrs = append(allErrs, validateBrotli(compression.Brotli, fieldPath.Child("brotli"))...)
+	}
+
+	return allErrs
+}
+
+func validateGzip(gzip *v1.Gzip, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	allErrs = append(allErrs, validateCompressionTypes(gzip.Types, fieldPath.Child("types"))...)
+
+	if gzip.Level != nil {
+		allErrs = append(allErrs, validateCompressionLevel(*gzip.Level, 9, fieldPath.Child("level"))...)
+	}
+
+	if gzip.MinLength != nil {
+		allErrs = append(allErrs, validatePositiveInt(*gzip.MinLength, fieldPath.Child("minLength"))...)
+	}
+
+	return append(allErrs, validateGzipProxied(gzip.Proxied, fieldPath.Child("proxied"))...)
+}
+
+func validateBrotli(brotli *v1.Brotli, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	allErrs = append(allErrs, validateCompressionTypes(brotli.Types, fieldPath.Child("types"))...)
+
+	if brotli.Level != nil {
+		allErrs = append(allErrs, validateCompressionLevel(*brotli.Level, 11, fieldPath.Child("level"))...)
+	}
+
+	if brotli.MinLength != nil {
+		allErrs = append(allErrs, validatePositiveInt(*brotli.MinLength, fieldPath.Child("minLength"))...)
+	}
+
+	return allErrs
+}
+
+func validateCompressionLevel(level int, maxLevel int, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if level < 1 || level > maxLevel {
+		allErrs = append(allErrs, field.Invalid(fieldPath, level, fmt.Sprintf("must be within the range [1-%d]", maxLevel)))
+	}
+
+	return allErrs
+}
+
+const (
+	mimeTypeFmt    = `[a-z0-9][a-z0-9!#$&^_.+-]*/[a-z0-9][a-z0-9!#$&^_.+-]*`
+	mimeTypeErrMsg = "must be a MIME type in the format type/subtype or '*'"
+)
+
+var mimeTypeRegexp = regexp.MustCompile("^" + mimeTypeFmt + "$")
+
+func validateCompressionTypes(types []string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	allTypes := sets.String{}
+
+	for i, t := range types {
+		idxPath := fieldPath.Index(i)
+
+		if t != "*" && !mimeTypeRegexp.MatchString(t) {
+			msg := validation.RegexError(mimeTypeErrMsg, mimeTypeFmt, "application/json", "image/svg+xml")
+			allErrs = append(allErrs, field.Invalid(idxPath, t, msg))
+		} else if allTypes.Has(t) {
+			allErrs = append(allErrs, field.Duplicate(idxPath, t))
+		} else {
+			allTypes.Insert(t)
+		}
+	}
+
+	if allTypes.Has("*") && allTypes.Len() > 1 {
+		allErrs = append(allErrs, field.Invalid(fieldPath, types, "'*' cannot be combined with other types"))
+	}
+
+	return allErrs
+}
+
+var validGzipProxiedConditions = map[string]bool{
+	"off":              true,
+	"expired":          true,
+	"no-cache":         true,
+	"no-store":         true,
+	"private":          true,
+	"no_last_modified": true,
+	"no_etag":          true,
+	"auth":             true,
+	"any":              true,
+}
+
+func validateGzipProxied(conditions []string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	allConditions := sets.String{}
+
+	for i, c := range conditions {
+		idxPath := fieldPath.Index(i)
+
+		if !validGzipProxiedConditions[c] {
+			allErrs = append(allErrs, field.Invalid(idxPath, c, fmt.Sprintf("Accepted values: %s",
+				mapToPrettyString(validGzipProxiedConditions))))
+		} else if allConditions.Has(c) {
+			allErrs = append(allErrs, field.Duplicate(idxPath, c))
+		} else {
+			allConditions.Insert(c)
+		}
+	}
+
+	for _, c := range []string{"off", "any"} {
+		if allConditions.Has(c) && allConditions.Len() > 1 {
+			allErrs = append(allErrs, field.Invalid(fieldPath, conditions, fmt.Sprintf("'%s' cannot be combined with other values", c)))
+		}
+	}
+
+	return allErrs
+}
+
+func validateRequestLimits(requestLimits *v1.RequestLimits, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	allErrs = append(allErrs, validateOffset(requestLimits.ClientMaxBodySize, fieldPath.Child("clientMaxBodySize"))...)
+	allErrs = append(allErrs, validateSize(requestLimits.ClientBodyBufferSize, fieldPath.Child("clientBodyBufferSize"))...)
+	allErrs = append(allErrs, validateSize(requestLimits.ClientHeaderBufferSize, fieldPath.Child("clientHeaderBufferSize"))...)
+
+	return append(allErrs, validateBuffer(requestLimits.LargeClientHeaderBuffers, fieldPath.Child("largeClientHeaderBuffers"))...)
+}
+
+func validateCORS(cors *v1.CORS, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(cors.AllowOrigins) == 0 {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("allowOrigins"), "must include at least one origin"))
+	}
+
+	for i, o := range cors.AllowOrigins {
+		allErrs = append(allErrs, validateCORSOrigin(o, fieldPath.Child("allowOrigins").Index(i))...)
+
+		// NGINX returns the origin of the request instead of `*`, so any website could make credentialed requests
+		if o == "*" && cors.AllowCredentials {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("allowOrigins").Index(i), "`*` is not allowed when allowCredentials is true"))
+		}
+	}
+
+	for i, m := range cors.AllowMethods {
+		if !validCORSMethods[m] {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("allowMethods").Index(i), m, fmt.Sprintf("Accepted values: %s",
+				mapToPrettyString(validCORSMethods))))
+		}
+	}
+
+	allErrs = append(allErrs, validateCORSHeaders(cors.AllowHeaders, fieldPath.Child("allowHeaders"))...)
+	allErrs = append(allErrs, validateCORSHeaders(cors.ExposeHeaders, fieldPath.Child("exposeHeaders"))...)
+
+	if cors.MaxAge != nil {
+		allErrs = append(allErrs, validatePositiveIntOrZero(*cors.MaxAge, fieldPath.Child("maxAge"))...)
+	}
+
+	return allErrs
+}
+
+const (
+	corsOriginFmt    = `https?://[a-zA-Z0-9.-]+(:[0-9]+)?`
+	corsOriginErrMsg = "must be a scheme, a host and an optional port"
+)
+
+var corsOriginRegexp = regexp.MustCompile("^" + corsOriginFmt + "$")
+
+func validateCORSOrigin(origin string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if origin == "*" {
+		return allErrs
+	}
+
+	if strings.HasPrefix(origin, "~") {
+		regex := strings.TrimPrefix(strings.TrimPrefix(origin, "~"), "*")
+		if regex == "" {
+			return append(allErrs, field.Invalid(fieldPath, origin, "must include a regular expression after `~`"))
+		}
+		if _, err := regexp.Compile(regex); err != nil {
+			return append(allErrs, field.Invalid(fieldPath, origin, fmt.Sprintf("must be a valid regular expression: %v", err)))
+		}
+		if !escapedStringsFmtRegexp.MatchString(regex) {
+			msg := validation.RegexError(escapedStringsErrMsg, escapedStringsFmt, `~^https://.*\.example\.com$`)
+			return append(allErrs, field.Invalid(fieldPath, origin, msg))
+		}
+		return allErrs
+	}
+
+	if !corsOriginRegexp.MatchString(origin) {
+		msg := validation.RegexError(corsOriginErrMsg, corsOriginFmt, "https://example.com", "http://example.com:8080")
+		allErrs = append(allErrs, field.Invalid(fieldPath, origin, msg))
+	}
+
+	return allErrs
+}
+
+var validCORSMethods = map[string]bool{
+	"GET":     true,
+	"HEAD":    true,
+	"POST":    true,
+	"PUT":     true,
+	"DELETE":  true,
+	"PATCH":   true,
+	"OPTIONS": true,
+}
+
+func validateCORSHeaders(headers []string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, h := range headers {
+		if h == "*" {
+			continue
+		}
+		for _, msg := range validation.IsHTTPHeaderName(h) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Index(i), h, msg))
+		}
+	}
+
+	return allErrs
+}
+
 func validateLogConf(logConf, logDest string, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -440,14 +1464,20 @@ func validateRateLimitZoneSize(zoneSize string, fieldPath *field.Path) field.Err
 	return allErrs
 }
 
-var rateLimitKeySpecialVariables = []string{"arg_", "http_", "cookie_"}
+var rateLimitKeySpecialVariables = []string{"arg_", "http_", "cookie_", "jwt_claim_"}
 
 // rateLimitKeyVariables includes NGINX variables allowed to be used in a rateLimit policy key.
 var rateLimitKeyVariables = map[string]bool{
 	"binary_remote_addr": true,
+	"remote_addr":        true,
 	"request_uri":        true,
+	"request_method":     true,
 	"uri":                true,
 	"args":               true,
+	"host":               true,
+	"server_name":        true,
+	"scheme":             true,
+	"ssl_client_s_dn":    true,
 }
 
 func validateRateLimitKey(key string, fieldPath *field.Path, isPlus bool) field.ErrorList {
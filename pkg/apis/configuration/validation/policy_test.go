@@ -71,9 +71,109 @@ func TestValidatePolicy(t *testing.T) {
 			enableAppProtect:      true,
 			msg:                   "use WAF(plus only) policy",
 		},
+		{
+			policy: &v1.Policy{
+				Spec: v1.PolicySpec{
+					Retry: &v1.Retry{
+						Conditions: []string{"error", "timeout"},
+					},
+				},
+			},
+			isPlus:                false,
+			enablePreviewPolicies: true,
+			msg:                   "use retry policy",
+		},
+		{
+			policy: &v1.Policy{
+				Spec: v1.PolicySpec{
+					CORS: &v1.CORS{
+						AllowOrigins: []string{"https://example.com"},
+					},
+				},
+			},
+			isPlus:                false,
+			enablePreviewPolicies: true,
+			msg:                   "use cors policy",
+		},
+		{
+			policy: &v1.Policy{
+				Spec: v1.PolicySpec{
+					BasicAuth: &v1.BasicAuth{
+						Realm:  "My Internal Tool",
+						Secret: "my-htpasswd",
+					},
+				},
+			},
+			isPlus:                false,
+			enablePreviewPolicies: true,
+			msg:                   "use basicAuth policy",
+		},
+		{
+			policy: &v1.Policy{
+				Spec: v1.PolicySpec{
+					ExternalAuth: &v1.ExternalAuth{
+						AuthServiceName: "oauth2-proxy",
+						AuthServicePort: 4180,
+					},
+				},
+			},
+			isPlus:                false,
+			enablePreviewPolicies: true,
+			msg:                   "use externalAuth policy",
+		},
+		{
+			policy: &v1.Policy{
+				Spec: v1.PolicySpec{
+					Cache: &v1.Cache{
+						ZoneSize: "10m",
+					},
+				},
+			},
+			isPlus:                false,
+			enablePreviewPolicies: true,
+			msg:                   "use cache policy",
+		},
+		{
+			policy: &v1.Policy{
+				Spec: v1.PolicySpec{
+					Compression: &v1.Compression{
+						Gzip: &v1.Gzip{},
+					},
+				},
+			},
+			isPlus:                false,
+			enablePreviewPolicies: true,
+			msg:                   "use compression policy",
+		},
+		{
+			policy: &v1.Policy{
+				Spec: v1.PolicySpec{
+					RequestLimits: &v1.RequestLimits{
+						ClientMaxBodySize: "10m",
+					},
+				},
+			},
+			isPlus:                false,
+			enablePreviewPolicies: true,
+			msg:                   "use requestLimits policy",
+		},
+		{
+			policy: &v1.Policy{
+				Spec: v1.PolicySpec{
+					IPAccessControl: &v1.IPAccessControl{
+						Deny: &v1.IPAccessRule{
+							Addresses: []string{"10.0.0.0/8"},
+						},
+					},
+				},
+			},
+			isPlus:                false,
+			enablePreviewPolicies: true,
+			msg:                   "use ipAccessControl policy",
+		},
 	}
 	for _, test := range tests {
-		err := ValidatePolicy(test.policy, test.isPlus, test.enablePreviewPolicies, test.enableAppProtect)
+		err := ValidatePolicy(test.policy, test.isPlus, test.enablePreviewPolicies, test.enableAppProtect, false)
 		if err != nil {
 			t.Errorf("ValidatePolicy() returned error %v for valid input for the case of %v", err, test.msg)
 		}
@@ -259,7 +359,7 @@ func TestValidatePolicyFails(t *testing.T) {
 		},
 	}
 	for _, test := range tests {
-		err := ValidatePolicy(test.policy, test.isPlus, test.enablePreviewPolicies, test.enableAppProtect)
+		err := ValidatePolicy(test.policy, test.isPlus, test.enablePreviewPolicies, test.enableAppProtect, false)
 		if err == nil {
 			t.Errorf("ValidatePolicy() returned no error for invalid input")
 		}
@@ -361,6 +461,35 @@ func TestValidateRateLimit(t *testing.T) {
 			},
 			msg: "ratelimit all fields set",
 		},
+		{
+			rateLimit: &v1.RateLimit{
+				Rate:     "10r/s",
+				ZoneSize: "10M",
+				Key:      "${binary_remote_addr}",
+				Condition: &v1.Condition{
+					Header: "X-API-Key",
+					Value:  "",
+				},
+				Limits: []v1.RateLimitTier{
+					{
+						Rate:     "100r/s",
+						ZoneSize: "10M",
+						Key:      "${http_x_api_key}",
+						Burst:    createPointerFromInt(50),
+						Condition: &v1.Condition{
+							Header: "X-API-Key",
+							Value:  "!",
+						},
+					},
+				},
+				LimitConn: &v1.LimitConn{
+					Key:         "${remote_addr}",
+					Connections: 10,
+					ZoneSize:    "10M",
+				},
+			},
+			msg: "ratelimit with conditional limits and a connection limit",
+		},
 	}
 
 	isPlus := false
@@ -413,122 +542,1094 @@ func TestValidateRateLimitFails(t *testing.T) {
 			msg: "invalid rateLimit burst",
 		},
 		{
-			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
-				r.ZoneSize = "31k"
-			}),
-			msg: "invalid rateLimit zoneSize",
+			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
+				r.ZoneSize = "31k"
+			}),
+			msg: "invalid rateLimit zoneSize",
+		},
+		{
+			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
+				r.RejectCode = createPointerFromInt(600)
+			}),
+			msg: "invalid rateLimit rejectCode",
+		},
+		{
+			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
+				r.LogLevel = "invalid"
+			}),
+			msg: "invalid rateLimit logLevel",
+		},
+		{
+			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
+				r.Key = "${jwt_claim_sub}"
+			}),
+			msg: "jwt claim in rateLimit key without NGINX Plus",
+		},
+		{
+			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
+				r.Condition = &v1.Condition{
+					Header:   "X-API-Key",
+					Argument: "key",
+				}
+			}),
+			msg: "invalid rateLimit condition",
+		},
+		{
+			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
+				r.Limits = []v1.RateLimitTier{
+					{
+						Rate:     "0r/s",
+						ZoneSize: "10M",
+						Key:      "${http_x_api_key}",
+					},
+				}
+			}),
+			msg: "invalid rateLimit limits rate",
+		},
+		{
+			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
+				r.Limits = []v1.RateLimitTier{
+					{
+						Rate:     "100r/s",
+						ZoneSize: "10M",
+					},
+				}
+			}),
+			msg: "missing rateLimit limits key",
+		},
+		{
+			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
+				r.LimitConn = &v1.LimitConn{
+					Key:         "${remote_addr}",
+					Connections: 0,
+					ZoneSize:    "10M",
+				}
+			}),
+			msg: "invalid rateLimit limitConn connections",
+		},
+		{
+			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
+				r.LimitConn = &v1.LimitConn{
+					Key:         "${remote_addr}",
+					Connections: 10,
+				}
+			}),
+			msg: "missing rateLimit limitConn zoneSize",
+		},
+	}
+
+	isPlus := false
+
+	for _, test := range tests {
+		allErrs := validateRateLimit(test.rateLimit, field.NewPath("rateLimit"), isPlus)
+		if len(allErrs) == 0 {
+			t.Errorf("validateRateLimit() returned no errors for invalid input for the case of %v", test.msg)
+		}
+	}
+}
+
+func TestValidateJWT(t *testing.T) {
+	tests := []struct {
+		jwt *v1.JWTAuth
+		msg string
+	}{
+		{
+			jwt: &v1.JWTAuth{
+				Realm:  "My Product API",
+				Secret: "my-jwk",
+			},
+			msg: "basic",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:  "My Product API",
+				Secret: "my-jwk",
+				Token:  "$cookie_auth_token",
+			},
+			msg: "jwt with token",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:    "My Product API",
+				JWKSURI:  "https://idp.example.com/.well-known/jwks.json",
+				KeyCache: "1h",
+				RequireClaims: []v1.JWTClaimRequirement{
+					{
+						Claim:  "iss",
+						Equals: "https://idp.example.com/",
+					},
+					{
+						Claim:    "realm_access.roles",
+						Contains: "admin",
+					},
+				},
+				ClaimHeaders: []v1.JWTClaimHeader{
+					{
+						Claim:  "sub",
+						Header: "X-User",
+					},
+				},
+			},
+			msg: "jwt with jwks uri, claim requirements and claim headers",
+		},
+	}
+	for _, test := range tests {
+		allErrs := validateJWT(test.jwt, field.NewPath("jwt"))
+		if len(allErrs) != 0 {
+			t.Errorf("validateJWT() returned errors %v for valid input for the case of %v", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateJWTFails(t *testing.T) {
+	tests := []struct {
+		msg string
+		jwt *v1.JWTAuth
+	}{
+		{
+			jwt: &v1.JWTAuth{
+				Realm: "My Product API",
+			},
+			msg: "missing secret",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Secret: "my-jwk",
+			},
+			msg: "missing realm",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:  "My Product API",
+				Secret: "my-jwk",
+				Token:  "$uri",
+			},
+			msg: "invalid variable use in token",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:  "My Product API",
+				Secret: "my-\"jwk",
+			},
+			msg: "invalid secret name",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:  "My \"Product API",
+				Secret: "my-jwk",
+			},
+			msg: "invalid realm due to escaped string",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:  "My Product ${api}",
+				Secret: "my-jwk",
+			},
+			msg: "invalid variable use in realm with curly braces",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:  "My Product $api",
+				Secret: "my-jwk",
+			},
+			msg: "invalid variable use in realm without curly braces",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:   "My Product API",
+				Secret:  "my-jwk",
+				JWKSURI: "https://idp.example.com/.well-known/jwks.json",
+			},
+			msg: "both secret and jwks uri",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:   "My Product API",
+				JWKSURI: "https://idp.example.com/jwks;",
+			},
+			msg: "invalid jwks uri",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:    "My Product API",
+				Secret:   "my-jwk",
+				KeyCache: "1 hour",
+			},
+			msg: "invalid key cache",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:  "My Product API",
+				Secret: "my-jwk",
+				RequireClaims: []v1.JWTClaimRequirement{
+					{
+						Claim: "aud",
+					},
+				},
+			},
+			msg: "claim requirement without a value",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:  "My Product API",
+				Secret: "my-jwk",
+				RequireClaims: []v1.JWTClaimRequirement{
+					{
+						Claim:    "aud",
+						Equals:   "api",
+						Contains: "api",
+					},
+				},
+			},
+			msg: "claim requirement with both equals and contains",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:  "My Product API",
+				Secret: "my-jwk",
+				RequireClaims: []v1.JWTClaimRequirement{
+					{
+						Claim:    "groups",
+						Contains: "admin,dev",
+					},
+				},
+			},
+			msg: "claim requirement contains with a comma",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:  "My Product API",
+				Secret: "my-jwk",
+				RequireClaims: []v1.JWTClaimRequirement{
+					{
+						Claim:  "aud",
+						Equals: "${api}",
+					},
+				},
+			},
+			msg: "claim requirement with a variable",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:  "My Product API",
+				Secret: "my-jwk",
+				RequireClaims: []v1.JWTClaimRequirement{
+					{
+						Claim:  "roles..admin",
+						Equals: "true",
+					},
+				},
+			},
+			msg: "invalid claim",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:  "My Product API",
+				Secret: "my-jwk",
+				ClaimHeaders: []v1.JWTClaimHeader{
+					{
+						Claim:  "sub",
+						Header: "X-User",
+					},
+					{
+						Claim:  "email",
+						Header: "x-user",
+					},
+				},
+			},
+			msg: "duplicate claim headers",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:  "My Product API",
+				Secret: "my-jwk",
+				ClaimHeaders: []v1.JWTClaimHeader{
+					{
+						Claim:  "sub",
+						Header: "X User",
+					},
+				},
+			},
+			msg: "invalid claim header",
+		},
+	}
+	for _, test := range tests {
+		allErrs := validateJWT(test.jwt, field.NewPath("jwt"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateJWT() returned no errors for invalid input for the case of %v", test.msg)
+		}
+	}
+}
+
+func TestValidateBasicAuth(t *testing.T) {
+	tests := []struct {
+		basicAuth *v1.BasicAuth
+		msg       string
+	}{
+		{
+			basicAuth: &v1.BasicAuth{
+				Realm:  "My Internal Tool",
+				Secret: "my-htpasswd",
+			},
+			msg: "basic",
+		},
+		{
+			basicAuth: &v1.BasicAuth{
+				Secret: "my-htpasswd",
+			},
+			msg: "basic auth without realm",
+		},
+	}
+	for _, test := range tests {
+		allErrs := validateBasicAuth(test.basicAuth, field.NewPath("basicAuth"))
+		if len(allErrs) != 0 {
+			t.Errorf("validateBasicAuth() returned errors %v for valid input for the case of %v", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateBasicAuthFails(t *testing.T) {
+	tests := []struct {
+		basicAuth *v1.BasicAuth
+		msg       string
+	}{
+		{
+			basicAuth: &v1.BasicAuth{
+				Realm: "My Internal Tool",
+			},
+			msg: "missing secret",
+		},
+		{
+			basicAuth: &v1.BasicAuth{
+				Realm:  "My Internal Tool",
+				Secret: "my-\"htpasswd",
+			},
+			msg: "invalid secret name",
+		},
+		{
+			basicAuth: &v1.BasicAuth{
+				Realm:  "My \"Internal Tool",
+				Secret: "my-htpasswd",
+			},
+			msg: "invalid realm due to escaped string",
+		},
+		{
+			basicAuth: &v1.BasicAuth{
+				Realm:  "My Internal ${tool}",
+				Secret: "my-htpasswd",
+			},
+			msg: "invalid variable use in realm",
+		},
+	}
+	for _, test := range tests {
+		allErrs := validateBasicAuth(test.basicAuth, field.NewPath("basicAuth"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateBasicAuth() returned no errors for invalid input for the case of %v", test.msg)
+		}
+	}
+}
+
+func TestValidateExternalAuth(t *testing.T) {
+	tests := []struct {
+		externalAuth *v1.ExternalAuth
+		msg          string
+	}{
+		{
+			externalAuth: &v1.ExternalAuth{
+				AuthServiceName: "oauth2-proxy",
+				AuthServicePort: 4180,
+			},
+			msg: "auth service",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				AuthServiceName: "oauth2-proxy",
+				AuthServicePort: 4180,
+				AuthPath:        "/oauth2/auth",
+				ResponseHeaders: []string{"X-Auth-Request-User", "X-Auth-Request-Email"},
+				SigninURL:       "https://auth.example.com/oauth2/start?rd=${scheme}://${host}${request_uri}",
+				CacheTTL:        "30s",
+			},
+			msg: "auth service with all fields",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				AuthURL: "https://authz.example.com/verify",
+			},
+			msg: "auth url",
+		},
+	}
+	for _, test := range tests {
+		allErrs := validateExternalAuth(test.externalAuth, field.NewPath("externalAuth"))
+		if len(allErrs) != 0 {
+			t.Errorf("validateExternalAuth() returned errors %v for valid input for the case of %v", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateExternalAuthFails(t *testing.T) {
+	tests := []struct {
+		externalAuth *v1.ExternalAuth
+		msg          string
+	}{
+		{
+			externalAuth: &v1.ExternalAuth{},
+			msg:          "missing auth service and url",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				AuthServiceName: "oauth2-proxy",
+				AuthServicePort: 4180,
+				AuthURL:         "https://authz.example.com/verify",
+			},
+			msg: "both auth service and url",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				AuthServiceName: "oauth2-proxy",
+			},
+			msg: "missing auth service port",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				AuthServiceName: "oauth2_proxy",
+				AuthServicePort: 4180,
+			},
+			msg: "invalid auth service name",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				AuthServiceName: "oauth2-proxy",
+				AuthServicePort: 4180,
+				AuthPath:        "/auth/$uri",
+			},
+			msg: "variable in auth path",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				AuthURL:  "https://authz.example.com/verify",
+				AuthPath: "/verify",
+			},
+			msg: "auth path with auth url",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				AuthURL: "https://authz.example.com",
+			},
+			msg: "auth url without path",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				AuthURL: "ftp://authz.example.com/verify",
+			},
+			msg: "invalid auth url scheme",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				AuthURL: "https://authz.example.com/verify;",
+			},
+			msg: "invalid character in auth url",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				AuthURL:         "https://authz.example.com/verify",
+				ResponseHeaders: []string{"X-User", "x-user"},
+			},
+			msg: "duplicated response headers",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				AuthURL:         "https://authz.example.com/verify",
+				ResponseHeaders: []string{"X User"},
+			},
+			msg: "invalid response header",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				AuthURL:   "https://authz.example.com/verify",
+				SigninURL: "/signin",
+			},
+			msg: "relative signin url",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				AuthURL:   "https://authz.example.com/verify",
+				SigninURL: "https://auth.example.com/signin?rd=${http_referer}",
+			},
+			msg: "invalid variable in signin url",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				AuthURL:  "https://authz.example.com/verify",
+				CacheTTL: "1 minute",
+			},
+			msg: "invalid cache ttl",
+		},
+	}
+	for _, test := range tests {
+		allErrs := validateExternalAuth(test.externalAuth, field.NewPath("externalAuth"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateExternalAuth() returned no errors for invalid input for the case of %v", test.msg)
+		}
+	}
+}
+
+func TestValidateCache(t *testing.T) {
+	tests := []struct {
+		cache *v1.Cache
+		msg   string
+	}{
+		{
+			cache: &v1.Cache{
+				ZoneSize: "10m",
+			},
+			msg: "zone size only",
+		},
+		{
+			cache: &v1.Cache{
+				ZoneSize: "10m",
+				Path:     "/data/cache",
+				MaxSize:  "1g",
+				Inactive: "1h",
+				Key:      "${scheme}${host}${request_uri}${cookie_user}",
+				Methods:  []string{"GET", "HEAD"},
+				Valid: []v1.CacheValid{
+					{
+						Codes: []int{200, 302},
+						Time:  "10m",
+					},
+					{
+						Time: "1m",
+					},
+				},
+				MinUses:          createPointerFromInt(2),
+				UseStale:         []string{"error", "timeout", "updating"},
+				BackgroundUpdate: true,
+				Lock:             true,
+				Bypass:           []string{"${cookie_nocache}", "${arg_nocache}"},
+				StatusHeader:     "X-Cache-Status",
+			},
+			msg: "all fields",
+		},
+	}
+	for _, test := range tests {
+		allErrs := validateCache(test.cache, field.NewPath("cache"))
+		if len(allErrs) != 0 {
+			t.Errorf("validateCache() returned errors %v for valid input for the case of %v", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateCacheFails(t *testing.T) {
+	tests := []struct {
+		cache *v1.Cache
+		msg   string
+	}{
+		{
+			cache: &v1.Cache{},
+			msg:   "missing zone size",
+		},
+		{
+			cache: &v1.Cache{
+				ZoneSize: "10 MB",
+			},
+			msg: "invalid zone size",
+		},
+		{
+			cache: &v1.Cache{
+				ZoneSize: "10m",
+				Path:     "data/cache",
+			},
+			msg: "relative path",
+		},
+		{
+			cache: &v1.Cache{
+				ZoneSize: "10m",
+				Path:     "/var/cache/nginx/../../etc",
+			},
+			msg: "path with parent directory",
+		},
+		{
+			cache: &v1.Cache{
+				ZoneSize: "10m",
+				Path:     "/var/cache/nginx;",
+			},
+			msg: "path with invalid characters",
+		},
+		{
+			cache: &v1.Cache{
+				ZoneSize: "10m",
+				Inactive: "1 hour",
+			},
+			msg: "invalid inactive",
+		},
+		{
+			cache: &v1.Cache{
+				ZoneSize: "10m",
+				Key:      "${scheme}${remote_addr}",
+			},
+			msg: "invalid variable in key",
+		},
+		{
+			cache: &v1.Cache{
+				ZoneSize: "10m",
+				Methods:  []string{"PUT"},
+			},
+			msg: "invalid method",
+		},
+		{
+			cache: &v1.Cache{
+				ZoneSize: "10m",
+				Methods:  []string{"GET", "GET"},
+			},
+			msg: "duplicated method",
+		},
+		{
+			cache: &v1.Cache{
+				ZoneSize: "10m",
+				Valid: []v1.CacheValid{
+					{
+						Codes: []int{700},
+						Time:  "10m",
+					},
+				},
+			},
+			msg: "invalid status code in valid",
+		},
+		{
+			cache: &v1.Cache{
+				ZoneSize: "10m",
+				Valid: []v1.CacheValid{
+					{
+						Codes: []int{200},
+					},
+				},
+			},
+			msg: "missing time in valid",
+		},
+		{
+			cache: &v1.Cache{
+				ZoneSize: "10m",
+				MinUses:  createPointerFromInt(0),
+			},
+			msg: "invalid min uses",
+		},
+		{
+			cache: &v1.Cache{
+				ZoneSize: "10m",
+				UseStale: []string{"http_501"},
+			},
+			msg: "invalid use stale condition",
+		},
+		{
+			cache: &v1.Cache{
+				ZoneSize:         "10m",
+				UseStale:         []string{"error"},
+				BackgroundUpdate: true,
+			},
+			msg: "background update without updating in use stale",
+		},
+		{
+			cache: &v1.Cache{
+				ZoneSize: "10m",
+				Bypass:   []string{"${remote_addr}"},
+			},
+			msg: "invalid variable in bypass",
+		},
+		{
+			cache: &v1.Cache{
+				ZoneSize:     "10m",
+				StatusHeader: "X Cache",
+			},
+			msg: "invalid status header",
+		},
+	}
+	for _, test := range tests {
+		allErrs := validateCache(test.cache, field.NewPath("cache"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateCache() returned no errors for invalid input for the case of %v", test.msg)
+		}
+	}
+}
+
+func TestValidateCompression(t *testing.T) {
+	tests := []struct {
+		compression  *v1.Compression
+		isPlus       bool
+		enableBrotli bool
+		msg          string
+	}{
+		{
+			compression: &v1.Compression{
+				Gzip: &v1.Gzip{},
+			},
+			isPlus: false,
+			msg:    "gzip with defaults",
+		},
+		{
+			compression: &v1.Compression{
+				Gzip: &v1.Gzip{
+					Types:     []string{"application/json", "image/svg+xml"},
+					Level:     createPointerFromInt(5),
+					MinLength: createPointerFromInt(1000),
+					Proxied:   []string{"expired", "no-cache", "auth"},
+					Vary:      true,
+				},
+			},
+			isPlus: false,
+			msg:    "gzip with all fields",
+		},
+		{
+			compression: &v1.Compression{
+				Gzip: &v1.Gzip{
+					Types: []string{"*"},
+				},
+				Brotli: &v1.Brotli{
+					Types:     []string{"*"},
+					Level:     createPointerFromInt(11),
+					MinLength: createPointerFromInt(1000),
+				},
+			},
+			isPlus:       true,
+			enableBrotli: true,
+			msg:          "gzip and brotli",
+		},
+	}
+	for _, test := range tests {
+		allErrs := validateCompression(test.compression, field.NewPath("compression"), test.isPlus, test.enableBrotli)
+		if len(allErrs) != 0 {
+			t.Errorf("validateCompression() returned errors %v for valid input for the case of %v", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateCompressionFails(t *testing.T) {
+	tests := []struct {
+		compression  *v1.Compression
+		isPlus       bool
+		enableBrotli bool
+		msg          string
+	}{
+		{
+			compression: &v1.Compression{},
+			isPlus:      true,
+			msg:         "missing gzip and brotli",
+		},
+		{
+			compression: &v1.Compression{
+				Brotli: &v1.Brotli{},
+			},
+			isPlus: false,
+			msg:    "brotli in NGINX",
+		},
+		{
+			compression: &v1.Compression{
+				Brotli: &v1.Brotli{},
+			},
+			isPlus: true,
+			msg:    "brotli not enabled",
+		},
+		{
+			compression: &v1.Compression{
+				Gzip: &v1.Gzip{
+					Types: []string{"application"},
+				},
+			},
+			msg: "invalid type",
+		},
+		{
+			compression: &v1.Compression{
+				Gzip: &v1.Gzip{
+					Types: []string{"application/json", "application/json"},
+				},
+			},
+			msg: "duplicated type",
+		},
+		{
+			compression: &v1.Compression{
+				Gzip: &v1.Gzip{
+					Types: []string{"*", "application/json"},
+				},
+			},
+			msg: "any type combined with other types",
+		},
+		{
+			compression: &v1.Compression{
+				Gzip: &v1.Gzip{
+					Level: createPointerFromInt(10),
+				},
+			},
+			msg: "invalid gzip level",
+		},
+		{
+			compression: &v1.Compression{
+				Gzip: &v1.Gzip{
+					MinLength: createPointerFromInt(0),
+				},
+			},
+			msg: "invalid min length",
+		},
+		{
+			compression: &v1.Compression{
+				Gzip: &v1.Gzip{
+					Proxied: []string{"no-transform"},
+				},
+			},
+			msg: "invalid proxied condition",
+		},
+		{
+			compression: &v1.Compression{
+				Gzip: &v1.Gzip{
+					Proxied: []string{"any", "expired"},
+				},
+			},
+			msg: "any proxied condition combined with other conditions",
+		},
+		{
+			compression: &v1.Compression{
+				Brotli: &v1.Brotli{
+					Level: createPointerFromInt(12),
+				},
+			},
+			isPlus:       true,
+			enableBrotli: true,
+			msg:          "invalid brotli level",
+		},
+	}
+	for _, test := range tests {
+		allErrs := validateCompression(test.compression, field.NewPath("compression"), test.isPlus, test.enableBrotli)
+		if len(allErrs) == 0 {
+			t.Errorf("validateCompression() returned no errors for invalid input for the case of %v", test.msg)
+		}
+	}
+}
+
+func TestValidateRequestLimits(t *testing.T) {
+	requestBuffering := false
+	requestLimits := &v1.RequestLimits{
+		ClientMaxBodySize:      "1g",
+		ClientBodyBufferSize:   "16k",
+		RequestBuffering:       &requestBuffering,
+		ClientHeaderBufferSize: "2k",
+		LargeClientHeaderBuffers: &v1.UpstreamBuffers{
+			Number: 4,
+			Size:   "16k",
+		},
+	}
+
+	allErrs := validateRequestLimits(requestLimits, field.NewPath("requestLimits"))
+	if len(allErrs) != 0 {
+		t.Errorf("validateRequestLimits() returned errors %v for valid input", allErrs)
+	}
+}
+
+func TestValidateRequestLimitsFails(t *testing.T) {
+	tests := []struct {
+		requestLimits *v1.RequestLimits
+		msg           string
+	}{
+		{
+			requestLimits: &v1.RequestLimits{
+				ClientMaxBodySize: "10 MB",
+			},
+			msg: "invalid client max body size",
+		},
+		{
+			requestLimits: &v1.RequestLimits{
+				ClientBodyBufferSize: "1g",
+			},
+			msg: "invalid client body buffer size",
 		},
 		{
-			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
-				r.RejectCode = createPointerFromInt(600)
-			}),
-			msg: "invalid rateLimit rejectCode",
+			requestLimits: &v1.RequestLimits{
+				ClientHeaderBufferSize: "1k1",
+			},
+			msg: "invalid client header buffer size",
 		},
 		{
-			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
-				r.LogLevel = "invalid"
-			}),
-			msg: "invalid rateLimit logLevel",
+			requestLimits: &v1.RequestLimits{
+				LargeClientHeaderBuffers: &v1.UpstreamBuffers{
+					Number: 0,
+					Size:   "8k",
+				},
+			},
+			msg: "invalid large client header buffers",
 		},
 	}
-
-	isPlus := false
-
 	for _, test := range tests {
-		allErrs := validateRateLimit(test.rateLimit, field.NewPath("rateLimit"), isPlus)
+		allErrs := validateRequestLimits(test.requestLimits, field.NewPath("requestLimits"))
 		if len(allErrs) == 0 {
-			t.Errorf("validateRateLimit() returned no errors for invalid input for the case of %v", test.msg)
+			t.Errorf("validateRequestLimits() returned no errors for invalid input for the case of %v", test.msg)
 		}
 	}
 }
 
-func TestValidateJWT(t *testing.T) {
+func TestValidateIPAccessControl(t *testing.T) {
 	tests := []struct {
-		jwt *v1.JWTAuth
-		msg string
+		ipAccessControl *v1.IPAccessControl
+		msg             string
 	}{
 		{
-			jwt: &v1.JWTAuth{
-				Realm:  "My Product API",
-				Secret: "my-jwk",
+			ipAccessControl: &v1.IPAccessControl{
+				Allow: &v1.IPAccessRule{
+					Countries: []string{"US", "CA"},
+				},
 			},
-			msg: "basic",
+			msg: "allow countries",
 		},
 		{
-			jwt: &v1.JWTAuth{
-				Realm:  "My Product API",
-				Secret: "my-jwk",
-				Token:  "$cookie_auth_token",
+			ipAccessControl: &v1.IPAccessControl{
+				Deny: &v1.IPAccessRule{
+					Addresses: []string{"10.0.0.0/8", "2001:0db8::1"},
+					ASNs:      []int{64496},
+				},
+				ClientIP: &v1.ClientIP{
+					Header:           "X-Forwarded-For",
+					TrustedAddresses: []string{"192.168.0.0/16"},
+					Recursive:        true,
+				},
+				DenyResponse: &v1.DenyResponse{
+					Code: 451,
+					Body: `{\"error\": \"unavailable\"}`,
+				},
 			},
-			msg: "jwt with token",
+			msg: "deny addresses and asns with client ip and deny response",
 		},
 	}
 	for _, test := range tests {
-		allErrs := validateJWT(test.jwt, field.NewPath("jwt"))
+		allErrs := validateIPAccessControl(test.ipAccessControl, field.NewPath("ipAccessControl"))
 		if len(allErrs) != 0 {
-			t.Errorf("validateJWT() returned errors %v for valid input for the case of %v", allErrs, test.msg)
+			t.Errorf("validateIPAccessControl() returned errors %v for valid input for the case of %v", allErrs, test.msg)
 		}
 	}
 }
 
-func TestValidateJWTFails(t *testing.T) {
+func TestValidateIPAccessControlFails(t *testing.T) {
 	tests := []struct {
-		msg string
-		jwt *v1.JWTAuth
+		ipAccessControl *v1.IPAccessControl
+		msg             string
 	}{
 		{
-			jwt: &v1.JWTAuth{
-				Realm: "My Product API",
+			ipAccessControl: &v1.IPAccessControl{},
+			msg:             "no allow or deny",
+		},
+		{
+			ipAccessControl: &v1.IPAccessControl{
+				Allow: &v1.IPAccessRule{
+					Addresses: []string{"10.0.0.0/8"},
+				},
+				Deny: &v1.IPAccessRule{
+					Addresses: []string{"10.0.0.1"},
+				},
 			},
-			msg: "missing secret",
+			msg: "both allow and deny",
 		},
 		{
-			jwt: &v1.JWTAuth{
-				Secret: "my-jwk",
+			ipAccessControl: &v1.IPAccessControl{
+				Allow: &v1.IPAccessRule{},
 			},
-			msg: "missing realm",
+			msg: "empty rule",
 		},
 		{
-			jwt: &v1.JWTAuth{
-				Realm:  "My Product API",
-				Secret: "my-jwk",
-				Token:  "$uri",
+			ipAccessControl: &v1.IPAccessControl{
+				Deny: &v1.IPAccessRule{
+					Addresses: []string{"localhost"},
+				},
 			},
-			msg: "invalid variable use in token",
+			msg: "invalid address",
 		},
 		{
-			jwt: &v1.JWTAuth{
-				Realm:  "My Product API",
-				Secret: "my-\"jwk",
+			ipAccessControl: &v1.IPAccessControl{
+				Deny: &v1.IPAccessRule{
+					Countries: []string{"usa"},
+				},
 			},
-			msg: "invalid secret name",
+			msg: "invalid country",
 		},
 		{
-			jwt: &v1.JWTAuth{
-				Realm:  "My \"Product API",
-				Secret: "my-jwk",
+			ipAccessControl: &v1.IPAccessControl{
+				Deny: &v1.IPAccessRule{
+					Countries: []string{"US", "US"},
+				},
 			},
-			msg: "invalid realm due to escaped string",
+			msg: "duplicated country",
 		},
 		{
-			jwt: &v1.JWTAuth{
-				Realm:  "My Product ${api}",
-				Secret: "my-jwk",
+			ipAccessControl: &v1.IPAccessControl{
+				Deny: &v1.IPAccessRule{
+					ASNs: []int{0},
+				},
 			},
-			msg: "invalid variable use in realm with curly braces",
+			msg: "invalid asn",
 		},
 		{
-			jwt: &v1.JWTAuth{
-				Realm:  "My Product $api",
-				Secret: "my-jwk",
+			ipAccessControl: &v1.IPAccessControl{
+				Deny: &v1.IPAccessRule{
+					ASNs: []int{64496, 64496},
+				},
 			},
-			msg: "invalid variable use in realm without curly braces",
+			msg: "duplicated asn",
+		},
+		{
+			ipAccessControl: &v1.IPAccessControl{
+				Deny: &v1.IPAccessRule{
+					Addresses: []string{"10.0.0.0/8"},
+				},
+				ClientIP: &v1.ClientIP{
+					TrustedAddresses: []string{"192.168.0.0/16"},
+				},
+			},
+			msg: "missing client ip header",
+		},
+		{
+			ipAccessControl: &v1.IPAccessControl{
+				Deny: &v1.IPAccessRule{
+					Addresses: []string{"10.0.0.0/8"},
+				},
+				ClientIP: &v1.ClientIP{
+					Header: "X-Forwarded-For",
+				},
+			},
+			msg: "missing client ip trusted addresses",
+		},
+		{
+			ipAccessControl: &v1.IPAccessControl{
+				Deny: &v1.IPAccessRule{
+					Addresses: []string{"10.0.0.0/8"},
+				},
+				DenyResponse: &v1.DenyResponse{
+					Code: 302,
+				},
+			},
+			msg: "invalid deny response code",
+		},
+		{
+			ipAccessControl: &v1.IPAccessControl{
+				Deny: &v1.IPAccessRule{
+					Addresses: []string{"10.0.0.0/8"},
+				},
+				DenyResponse: &v1.DenyResponse{
+					Body: "Access denied to ${remote_addr}",
+				},
+			},
+			msg: "variable in deny response body",
+		},
+		{
+			ipAccessControl: &v1.IPAccessControl{
+				Deny: &v1.IPAccessRule{
+					Addresses: []string{"10.0.0.0/8"},
+				},
+				DenyResponse: &v1.DenyResponse{
+					Body: `Access "denied"`,
+				},
+			},
+			msg: "unescaped quotes in deny response body",
 		},
 	}
 	for _, test := range tests {
-		allErrs := validateJWT(test.jwt, field.NewPath("jwt"))
+		allErrs := validateIPAccessControl(test.ipAccessControl, field.NewPath("ipAccessControl"))
 		if len(allErrs) == 0 {
-			t.Errorf("validateJWT() returned no errors for invalid input for the case of %v", test.msg)
+			t.Errorf("validateIPAccessControl() returned no errors for invalid input for the case of %v", test.msg)
 		}
 	}
 }
@@ -893,6 +1994,213 @@ func TestValidateEgressMTLSInvalid(t *testing.T) {
 	}
 }
 
+func TestValidateRetry(t *testing.T) {
+	tests := []struct {
+		retry *v1.Retry
+		msg   string
+	}{
+		{
+			retry: &v1.Retry{},
+			msg:   "empty retry",
+		},
+		{
+			retry: &v1.Retry{
+				Conditions:    []string{"error", "timeout", "http_503"},
+				Tries:         createPointerFromInt(3),
+				PerTryTimeout: "5s",
+				Timeout:       "20s",
+				NonIdempotent: true,
+			},
+			msg: "all fields",
+		},
+		{
+			retry: &v1.Retry{
+				Tries: createPointerFromInt(0),
+			},
+			msg: "unlimited tries",
+		},
+	}
+	for _, test := range tests {
+		allErrs := validateRetry(test.retry, field.NewPath("retry"))
+		if len(allErrs) != 0 {
+			t.Errorf("validateRetry() returned errors %v for valid input for the case of %v", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateRetryInvalid(t *testing.T) {
+	tests := []struct {
+		retry *v1.Retry
+		msg   string
+	}{
+		{
+			retry: &v1.Retry{
+				Conditions: []string{"http_418"},
+			},
+			msg: "invalid condition",
+		},
+		{
+			retry: &v1.Retry{
+				Conditions: []string{"non_idempotent"},
+			},
+			msg: "non_idempotent condition",
+		},
+		{
+			retry: &v1.Retry{
+				Conditions: []string{"error", "error"},
+			},
+			msg: "duplicated condition",
+		},
+		{
+			retry: &v1.Retry{
+				Tries: createPointerFromInt(-1),
+			},
+			msg: "negative tries",
+		},
+		{
+			retry: &v1.Retry{
+				PerTryTimeout: "5 seconds",
+			},
+			msg: "invalid per try timeout",
+		},
+		{
+			retry: &v1.Retry{
+				Timeout: "-1s",
+			},
+			msg: "invalid timeout",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateRetry(test.retry, field.NewPath("retry"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateRetry() returned no errors for invalid input for the case of %v", test.msg)
+		}
+	}
+}
+
+func TestValidateCORS(t *testing.T) {
+	tests := []struct {
+		cors *v1.CORS
+		msg  string
+	}{
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"*"},
+			},
+			msg: "any origin",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins:     []string{"https://example.com", "http://localhost:8080", `~^https://.*\.example\.com$`, `~*^https://FOO\.com$`},
+				AllowMethods:     []string{"GET", "POST"},
+				AllowHeaders:     []string{"Content-Type", "Authorization"},
+				ExposeHeaders:    []string{"X-Request-ID"},
+				AllowCredentials: true,
+				MaxAge:           createPointerFromInt(3600),
+			},
+			msg: "all fields",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"https://example.com"},
+				AllowHeaders: []string{"*"},
+			},
+			msg: "any header",
+		},
+	}
+	for _, test := range tests {
+		allErrs := validateCORS(test.cors, field.NewPath("cors"))
+		if len(allErrs) != 0 {
+			t.Errorf("validateCORS() returned errors %v for valid input for the case of %v", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateCORSInvalid(t *testing.T) {
+	tests := []struct {
+		cors *v1.CORS
+		msg  string
+	}{
+		{
+			cors: &v1.CORS{},
+			msg:  "no origins",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"example.com"},
+			},
+			msg: "origin without scheme",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"https://example.com/path"},
+			},
+			msg: "origin with path",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"~^https://(.*$"},
+			},
+			msg: "invalid regex",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{`~^https://"foo"$`},
+			},
+			msg: "regex with unescaped quotes",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"~"},
+			},
+			msg: "empty regex",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins:     []string{"https://example.com", "*"},
+				AllowCredentials: true,
+			},
+			msg: "any origin with credentials",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"*"},
+				AllowMethods: []string{"get"},
+			},
+			msg: "invalid method",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"*"},
+				AllowHeaders: []string{"Content Type"},
+			},
+			msg: "invalid header",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins:  []string{"*"},
+				ExposeHeaders: []string{"X-Header;"},
+			},
+			msg: "invalid exposed header",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"*"},
+				MaxAge:       createPointerFromInt(-1),
+			},
+			msg: "negative max age",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateCORS(test.cors, field.NewPath("cors"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateCORS() returned no errors for invalid input for the case of %v", test.msg)
+		}
+	}
+}
+
 func TestValidateOIDCValid(t *testing.T) {
 	tests := []struct {
 		oidc *v1.OIDC
@@ -944,6 +2252,21 @@ func TestValidateOIDCValid(t *testing.T) {
 			},
 			msg: "ip address",
 		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:          "https://keycloak.example.com/auth/realms/master/protocol/openid-connect/auth",
+				TokenEndpoint:         "https://keycloak.example.com/auth/realms/master/protocol/openid-connect/token",
+				JWKSURI:               "https://keycloak.example.com/auth/realms/master/protocol/openid-connect/certs",
+				EndSessionEndpoint:    "https://keycloak.example.com/auth/realms/master/protocol/openid-connect/logout",
+				ClientID:              "public-client",
+				PostLogoutRedirectURI: "/logged-out",
+				AuthExtraArgs:         []string{"kc_idp_hint=github", "prompt=login"},
+				PKCEEnable:            true,
+				ForwardToken:          "access",
+				ForwardTokenHeader:    "X-Access-Token",
+			},
+			msg: "pkce, logout, extra args and token forwarding",
+		},
 	}
 
 	for _, test := range tests {
@@ -1039,6 +2362,95 @@ func TestValidateOIDCInvalid(t *testing.T) {
 			},
 			msg: "invalid chars in clientID",
 		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:  "https://keycloak.example.com/auth",
+				TokenEndpoint: "https://keycloak.example.com/token",
+				JWKSURI:       "https://keycloak.example.com/certs",
+				ClientID:      "client",
+				ClientSecret:  "secret",
+				PKCEEnable:    true,
+			},
+			msg: "client secret with pkce",
+		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:       "https://keycloak.example.com/auth",
+				TokenEndpoint:      "https://keycloak.example.com/token",
+				JWKSURI:            "https://keycloak.example.com/certs",
+				EndSessionEndpoint: "keycloak.example.com/logout",
+				ClientID:           "client",
+				ClientSecret:       "secret",
+			},
+			msg: "invalid end session endpoint",
+		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:          "https://keycloak.example.com/auth",
+				TokenEndpoint:         "https://keycloak.example.com/token",
+				JWKSURI:               "https://keycloak.example.com/certs",
+				ClientID:              "client",
+				ClientSecret:          "secret",
+				PostLogoutRedirectURI: "logged-out",
+			},
+			msg: "invalid post logout redirect uri",
+		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:  "https://keycloak.example.com/auth",
+				TokenEndpoint: "https://keycloak.example.com/token",
+				JWKSURI:       "https://keycloak.example.com/certs",
+				ClientID:      "client",
+				ClientSecret:  "secret",
+				AuthExtraArgs: []string{"prompt=\"login\""},
+			},
+			msg: "invalid auth extra arg",
+		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:  "https://keycloak.example.com/auth",
+				TokenEndpoint: "https://keycloak.example.com/token",
+				JWKSURI:       "https://keycloak.example.com/certs",
+				ClientID:      "client",
+				ClientSecret:  "secret",
+				AuthExtraArgs: []string{"state=foo"},
+			},
+			msg: "reserved auth extra arg",
+		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:  "https://keycloak.example.com/auth",
+				TokenEndpoint: "https://keycloak.example.com/token",
+				JWKSURI:       "https://keycloak.example.com/certs",
+				ClientID:      "client",
+				ClientSecret:  "secret",
+				ForwardToken:  "refresh",
+			},
+			msg: "invalid forward token",
+		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:       "https://keycloak.example.com/auth",
+				TokenEndpoint:      "https://keycloak.example.com/token",
+				JWKSURI:            "https://keycloak.example.com/certs",
+				ClientID:           "client",
+				ClientSecret:       "secret",
+				ForwardTokenHeader: "X-Token",
+			},
+			msg: "forward token header without forward token",
+		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:       "https://keycloak.example.com/auth",
+				TokenEndpoint:      "https://keycloak.example.com/token",
+				JWKSURI:            "https://keycloak.example.com/certs",
+				ClientID:           "client",
+				ClientSecret:       "secret",
+				ForwardToken:       "id",
+				ForwardTokenHeader: "X Token",
+			},
+			msg: "invalid forward token header",
+		},
 	}
 
 	for _, test := range tests {
@@ -13,13 +13,15 @@ import (
 type TransportServerValidator struct {
 	tlsPassthrough  bool
 	snippetsEnabled bool
+	isPlus          bool
 }
 
 // NewTransportServerValidator creates a new TransportServerValidator.
-func NewTransportServerValidator(tlsPassthrough bool, snippetsEnabled bool) *TransportServerValidator {
+func NewTransportServerValidator(tlsPassthrough bool, snippetsEnabled bool, isPlus bool) *TransportServerValidator {
 	return &TransportServerValidator{
 		tlsPassthrough:  tlsPassthrough,
 		snippetsEnabled: snippetsEnabled,
+		isPlus:          isPlus,
 	}
 }
 
@@ -37,7 +39,7 @@ func (tsv *TransportServerValidator) validateTransportServerSpec(spec *v1alpha1.
 	isTLSPassthroughListener := isPotentialTLSPassthroughListener(&spec.Listener)
 	allErrs = append(allErrs, validateTransportServerHost(spec.Host, fieldPath.Child("host"), isTLSPassthroughListener)...)
 
-	upstreamErrs, upstreamNames := validateTransportServerUpstreams(spec.Upstreams, fieldPath.Child("upstreams"))
+	upstreamErrs, upstreamNames := validateTransportServerUpstreams(spec.Upstreams, fieldPath.Child("upstreams"), tsv.isPlus)
 	allErrs = append(allErrs, upstreamErrs...)
 
 	allErrs = append(allErrs, validateTransportServerUpstreamParameters(spec.UpstreamParameters, fieldPath.Child("upstreamParameters"), spec.Listener.Protocol)...)
@@ -146,7 +148,7 @@ func validateListenerProtocol(protocol string, fieldPath *field.Path) field.Erro
 	return allErrs
 }
 
-func validateTransportServerUpstreams(upstreams []v1alpha1.Upstream, fieldPath *field.Path) (allErrs field.ErrorList, upstreamNames sets.String) {
+func validateTransportServerUpstreams(upstreams []v1alpha1.Upstream, fieldPath *field.Path, isPlus bool) (allErrs field.ErrorList, upstreamNames sets.String) {
 	allErrs = field.ErrorList{}
 	upstreamNames = sets.String{}
 
@@ -172,6 +174,12 @@ func validateTransportServerUpstreams(upstreams []v1alpha1.Upstream, fieldPath *
 		}
 
 		allErrs = append(allErrs, validateTSUpstreamHealthChecks(u.HealthCheck, idxPath.Child("healthChecks"))...)
+
+		if isPlus {
+			allErrs = append(allErrs, validateTSUpstreamOutlierDetection(u.OutlierDetection, idxPath.Child("outlierDetection"))...)
+		} else if u.OutlierDetection != nil {
+			allErrs = append(allErrs, field.Forbidden(idxPath.Child("outlierDetection"), "outlier detection is only supported in NGINX Plus"))
+		}
 	}
 
 	return allErrs, upstreamNames
@@ -199,6 +207,24 @@ func validateTSUpstreamHealthChecks(hc *v1alpha1.HealthCheck, fieldPath *field.P
 	return allErrs
 }
 
+func validateTSUpstreamOutlierDetection(outlierDetection *v1alpha1.OutlierDetection, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if outlierDetection == nil {
+		return allErrs
+	}
+
+	if outlierDetection.ConsecutiveErrors == nil {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("consecutiveErrors"), ""))
+	} else {
+		allErrs = append(allErrs, validatePositiveInt(*outlierDetection.ConsecutiveErrors, fieldPath.Child("consecutiveErrors"))...)
+	}
+
+	allErrs = append(allErrs, validateEjection(outlierDetection.EjectionTime, outlierDetection.MaxEjectionPercent, fieldPath)...)
+
+	return allErrs
+}
+
 func validateTransportServerUpstreamParameters(upstreamParameters *v1alpha1.UpstreamParameters, fieldPath *field.Path, protocol string) field.ErrorList {
 	allErrs := field.ErrorList{}
 
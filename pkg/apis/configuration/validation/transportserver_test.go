@@ -99,7 +99,7 @@ func TestValidateTransportServerUpstreams(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		allErrs, resultUpstreamNames := validateTransportServerUpstreams(test.upstreams, field.NewPath("upstreams"))
+		allErrs, resultUpstreamNames := validateTransportServerUpstreams(test.upstreams, field.NewPath("upstreams"), true)
 		if len(allErrs) > 0 {
 			t.Errorf("validateTransportServerUpstreams() returned errors %v for valid input for the case of %s", allErrs, test.msg)
 		}
@@ -112,6 +112,7 @@ func TestValidateTransportServerUpstreams(t *testing.T) {
 func TestValidateTransportServerUpstreamsFails(t *testing.T) {
 	tests := []struct {
 		upstreams             []v1alpha1.Upstream
+		isPlus                bool
 		expectedUpstreamNames sets.String
 		msg                   string
 	}{
@@ -170,10 +171,27 @@ func TestValidateTransportServerUpstreamsFails(t *testing.T) {
 			},
 			msg: "duplicated upstreams",
 		},
+		{
+			upstreams: []v1alpha1.Upstream{
+				{
+					Name:    "upstream1",
+					Service: "test-1",
+					Port:    80,
+					OutlierDetection: &v1alpha1.OutlierDetection{
+						ConsecutiveErrors: createPointerFromInt(3),
+					},
+				},
+			},
+			isPlus: false,
+			expectedUpstreamNames: map[string]sets.Empty{
+				"upstream1": {},
+			},
+			msg: "outlier detection in NGINX",
+		},
 	}
 
 	for _, test := range tests {
-		allErrs, resultUpstreamNames := validateTransportServerUpstreams(test.upstreams, field.NewPath("upstreams"))
+		allErrs, resultUpstreamNames := validateTransportServerUpstreams(test.upstreams, field.NewPath("upstreams"), test.isPlus)
 		if len(allErrs) == 0 {
 			t.Errorf("validateTransportServerUpstreams() returned no errors for the case of %s", test.msg)
 		}
@@ -458,6 +476,70 @@ func TestValidateTSUpstreamHealthChecks(t *testing.T) {
 	}
 }
 
+func TestValidateTSUpstreamOutlierDetection(t *testing.T) {
+	tests := []struct {
+		outlierDetection *v1alpha1.OutlierDetection
+		msg              string
+	}{
+		{
+			outlierDetection: nil,
+			msg:              "nil outlier detection",
+		},
+		{
+			outlierDetection: &v1alpha1.OutlierDetection{
+				ConsecutiveErrors:  createPointerFromInt(3),
+				EjectionTime:       "1m",
+				MaxEjectionPercent: createPointerFromInt(50),
+			},
+			msg: "valid outlier detection",
+		},
+	}
+	for _, test := range tests {
+		allErrs := validateTSUpstreamOutlierDetection(test.outlierDetection, field.NewPath("outlierDetection"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateTSUpstreamOutlierDetection() returned errors %v for valid input for the case of %s", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateTSUpstreamOutlierDetectionFails(t *testing.T) {
+	tests := []struct {
+		outlierDetection *v1alpha1.OutlierDetection
+		msg              string
+	}{
+		{
+			outlierDetection: &v1alpha1.OutlierDetection{},
+			msg:              "missing consecutive errors",
+		},
+		{
+			outlierDetection: &v1alpha1.OutlierDetection{
+				ConsecutiveErrors: createPointerFromInt(0),
+			},
+			msg: "zero consecutive errors",
+		},
+		{
+			outlierDetection: &v1alpha1.OutlierDetection{
+				ConsecutiveErrors: createPointerFromInt(3),
+				EjectionTime:      "-1m",
+			},
+			msg: "negative ejection time",
+		},
+		{
+			outlierDetection: &v1alpha1.OutlierDetection{
+				ConsecutiveErrors:  createPointerFromInt(3),
+				MaxEjectionPercent: createPointerFromInt(101),
+			},
+			msg: "max ejection percent out of range",
+		},
+	}
+	for _, test := range tests {
+		allErrs := validateTSUpstreamOutlierDetection(test.outlierDetection, field.NewPath("outlierDetection"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateTSUpstreamOutlierDetection() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
 func TestValidateTSUpstreamHealthChecksFails(t *testing.T) {
 	tests := []struct {
 		healthCheck *v1alpha1.HealthCheck
@@ -5,9 +5,12 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/nginxinc/kubernetes-ingress/internal/configs"
+	"github.com/nginxinc/kubernetes-ingress/internal/metrics/collectors"
 	v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -43,6 +46,7 @@ func (vsv *VirtualServerValidator) validateVirtualServerSpec(spec *v1.VirtualSer
 	allErrs = append(allErrs, upstreamErrs...)
 
 	allErrs = append(allErrs, vsv.validateVirtualServerRoutes(spec.Routes, fieldPath.Child("routes"), upstreamNames, namespace)...)
+	allErrs = append(allErrs, validateGRPCUpstreamActions(spec.Routes, spec.Upstreams, fieldPath.Child("routes"))...)
 
 	return allErrs
 }
@@ -213,15 +217,113 @@ func validateUpstreamLBMethod(lBMethod string, fieldPath *field.Path, isPlus boo
 	return allErrs
 }
 
-func validateUpstreamHealthCheck(hc *v1.HealthCheck, fieldPath *field.Path) field.ErrorList {
+const grpcUpstreamType = "grpc"
+
+var validUpstreamTypes = map[string]bool{
+	"http":           true,
+	grpcUpstreamType: true,
+}
+
+func validateUpstreamType(upstreamType string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if upstreamType == "" {
+		return allErrs
+	}
+
+	if !validUpstreamTypes[upstreamType] {
+		allErrs = append(allErrs, field.Invalid(fieldPath, upstreamType, "must be one of `http` or `grpc`"))
+	}
+
+	return allErrs
+}
+
+// validateGRPCUpstream validates the fields of an Upstream that are not supported for gRPC.
+func validateGRPCUpstream(u v1.Upstream, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if u.Type != grpcUpstreamType {
+		return allErrs
+	}
+
+	if u.ProxyBuffering != nil {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("buffering"), "is not supported for grpc upstreams"))
+	}
+	if u.ProxyBuffers != nil {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("buffers"), "is not supported for grpc upstreams"))
+	}
+	if u.ProxyBufferSize != "" {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("buffer-size"), "is not supported for grpc upstreams"))
+	}
+
+	return allErrs
+}
+
+// validateGRPCUpstreamActions validates the proxy actions of the routes that pass the requests to grpc upstreams.
+// NGINX always passes the request headers to a grpc upstream, so the actions can't disable passing them.
+func validateGRPCUpstreamActions(routes []v1.Route, upstreams []v1.Upstream, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	grpcUpstreams := sets.String{}
+	for _, u := range upstreams {
+		if u.Type == grpcUpstreamType {
+			grpcUpstreams.Insert(u.Name)
+		}
+	}
+	if grpcUpstreams.Len() == 0 {
+		return allErrs
+	}
+
+	validateAction := func(action *v1.Action, actionPath *field.Path) {
+		if action == nil || action.Proxy == nil || !grpcUpstreams.Has(action.Proxy.Upstream) {
+			return
+		}
+		rh := action.Proxy.RequestHeaders
+		if rh != nil && rh.Pass != nil && !*rh.Pass {
+			allErrs = append(allErrs, field.Forbidden(actionPath.Child("proxy", "requestHeaders", "pass"), "can't be false for grpc upstreams"))
+		}
+	}
+
+	for i, r := range routes {
+		idxPath := fieldPath.Index(i)
+
+		validateAction(r.Action, idxPath.Child("action"))
+		for j, s := range r.Splits {
+			validateAction(s.Action, idxPath.Child("splits").Index(j).Child("action"))
+		}
+		for j, m := range r.Matches {
+			matchPath := idxPath.Child("matches").Index(j)
+			validateAction(m.Action, matchPath.Child("action"))
+			for k, s := range m.Splits {
+				validateAction(s.Action, matchPath.Child("splits").Index(k).Child("action"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateUpstreamHealthCheck(hc *v1.HealthCheck, upstreamType string, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
 	if hc == nil {
 		return allErrs
 	}
 
-	if hc.Path != "" {
-		allErrs = append(allErrs, validatePath(hc.Path, fieldPath.Child("path"))...)
+	if upstreamType == grpcUpstreamType {
+		allErrs = append(allErrs, validateGRPCHealthCheck(hc, fieldPath)...)
+	} else {
+		if hc.Path != "" {
+			allErrs = append(allErrs, validatePath(hc.Path, fieldPath.Child("path"))...)
+		}
+		allErrs = append(allErrs, validateStatusMatch(hc.StatusMatch, fieldPath.Child("statusMatch"))...)
+
+		if hc.GRPCStatus != nil {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("grpcStatus"), "is only supported for grpc upstreams"))
+		}
+		if hc.GRPCService != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("grpcService"), "is only supported for grpc upstreams"))
+		}
 	}
 
 	allErrs = append(allErrs, validateTime(hc.Interval, fieldPath.Child("interval"))...)
@@ -231,7 +333,6 @@ func validateUpstreamHealthCheck(hc *v1.HealthCheck, fieldPath *field.Path) fiel
 	allErrs = append(allErrs, validateTime(hc.ConnectTimeout, fieldPath.Child("connect-timeout"))...)
 	allErrs = append(allErrs, validateTime(hc.ReadTimeout, fieldPath.Child("read-timeout"))...)
 	allErrs = append(allErrs, validateTime(hc.SendTimeout, fieldPath.Child("send-timeout"))...)
-	allErrs = append(allErrs, validateStatusMatch(hc.StatusMatch, fieldPath.Child("statusMatch"))...)
 
 	for i, header := range hc.Headers {
 		idxPath := fieldPath.Child("headers").Index(i)
@@ -247,6 +348,34 @@ func validateUpstreamHealthCheck(hc *v1.HealthCheck, fieldPath *field.Path) fiel
 	return allErrs
 }
 
+const grpcServiceFmt = `[a-zA-Z0-9_.]*`
+
+const grpcServiceErrMsg = "must contain only alphanumeric characters, '_' or '.'"
+
+var grpcServiceRegexp = regexp.MustCompile("^" + grpcServiceFmt + "$")
+
+// validateGRPCHealthCheck validates the fields of a HealthCheck of a grpc upstream.
+// Such a health check uses the grpc.health.v1 protocol, so the HTTP specific fields are not supported.
+func validateGRPCHealthCheck(hc *v1.HealthCheck, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if hc.Path != "" {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("path"), "is not supported for grpc upstreams"))
+	}
+	if hc.StatusMatch != "" {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("statusMatch"), "is not supported for grpc upstreams"))
+	}
+
+	allErrs = append(allErrs, validatePositiveIntOrZeroFromPointer(hc.GRPCStatus, fieldPath.Child("grpcStatus"))...)
+
+	if !grpcServiceRegexp.MatchString(hc.GRPCService) {
+		msg := validation.RegexError(grpcServiceErrMsg, grpcServiceFmt, "helloworld.Greeter")
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("grpcService"), hc.GRPCService, msg))
+	}
+
+	return allErrs
+}
+
 func validateSessionCookie(sc *v1.SessionCookie, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -416,7 +545,15 @@ func (vsv *VirtualServerValidator) validateUpstreams(upstreams []v1.Upstream, fi
 			allErrs = append(allErrs, validateLabels(u.Subselector, idxPath.Child("subselector"))...)
 		}
 
-		allErrs = append(allErrs, validateServiceName(u.Service, idxPath.Child("service"))...)
+		if u.Host != "" {
+			allErrs = append(allErrs, validateUpstreamHost(u, idxPath)...)
+		} else {
+			allErrs = append(allErrs, validateServiceName(u.Service, idxPath.Child("service"))...)
+		}
+		allErrs = append(allErrs, validateUpstreamResolver(u.Resolver, idxPath.Child("resolver"))...)
+		allErrs = append(allErrs, validateUpstreamType(u.Type, idxPath.Child("type"))...)
+		allErrs = append(allErrs, validateGRPCUpstream(u, idxPath)...)
+		allErrs = append(allErrs, validateUpstreamWebSocket(u, idxPath)...)
 		allErrs = append(allErrs, validateTime(u.ProxyConnectTimeout, idxPath.Child("connect-timeout"))...)
 		allErrs = append(allErrs, validateTime(u.ProxyReadTimeout, idxPath.Child("read-timeout"))...)
 		allErrs = append(allErrs, validateTime(u.ProxySendTimeout, idxPath.Child("send-timeout"))...)
@@ -429,12 +566,16 @@ func (vsv *VirtualServerValidator) validateUpstreams(upstreams []v1.Upstream, fi
 		allErrs = append(allErrs, validatePositiveIntOrZeroFromPointer(u.Keepalive, idxPath.Child("keepalive"))...)
 		allErrs = append(allErrs, validatePositiveIntOrZeroFromPointer(u.MaxConns, idxPath.Child("max-conns"))...)
 		allErrs = append(allErrs, validateOffset(u.ClientMaxBodySize, idxPath.Child("client-max-body-size"))...)
-		allErrs = append(allErrs, validateUpstreamHealthCheck(u.HealthCheck, idxPath.Child("healthCheck"))...)
+		allErrs = append(allErrs, validateUpstreamHealthCheck(u.HealthCheck, u.Type, idxPath.Child("healthCheck"))...)
 		allErrs = append(allErrs, validateTime(u.SlowStart, idxPath.Child("slow-start"))...)
 		allErrs = append(allErrs, validateBuffer(u.ProxyBuffers, idxPath.Child("buffers"))...)
 		allErrs = append(allErrs, validateSize(u.ProxyBufferSize, idxPath.Child("buffer-size"))...)
 		allErrs = append(allErrs, validateQueue(u.Queue, idxPath.Child("queue"))...)
 		allErrs = append(allErrs, validateSessionCookie(u.SessionCookie, idxPath.Child("sessionCookie"))...)
+		allErrs = append(allErrs, validateOutlierDetection(u.OutlierDetection, idxPath.Child("outlierDetection"))...)
+		allErrs = append(allErrs, validateUpstreamSubsets(u.Subsets, u.UseClusterIP, idxPath.Child("subsets"))...)
+		allErrs = append(allErrs, validateTopologyAwareRouting(u.TopologyAwareRouting, u.UseClusterIP, idxPath.Child("topologyAwareRouting"))...)
+		allErrs = append(allErrs, validateDrainTimeout(u.DrainTimeout, u.UseClusterIP, idxPath.Child("drain-timeout"))...)
 
 		for _, msg := range validation.IsValidPortNum(int(u.Port)) {
 			allErrs = append(allErrs, field.Invalid(idxPath.Child("port"), u.Port, msg))
@@ -562,6 +703,28 @@ func (vsv *VirtualServerValidator) validateRoute(route v1.Route, fieldPath *fiel
 		allErrs = append(allErrs, vsv.validateErrorPage(e, fieldPath.Child("errorPages").Index(i))...)
 	}
 
+	if route.Canary != nil {
+		allErrs = append(allErrs, validateCanary(route.Canary, fieldPath.Child("canary"), upstreamNames)...)
+		if len(route.Matches) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("matches"), "is not allowed with `canary`"))
+		}
+		fieldCount++
+	}
+
+	if route.Rollback != nil {
+		allErrs = append(allErrs, validateRollback(route.Rollback, fieldPath.Child("rollback"))...)
+		if len(route.Splits) == 0 && route.Canary == nil {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("rollback"), "is only allowed with `splits` or `canary`"))
+		}
+	}
+
+	if route.Mirror != nil {
+		allErrs = append(allErrs, validateMirror(route.Mirror, fieldPath.Child("mirror"), upstreamNames)...)
+		if route.Route != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("mirror"), "is not allowed with `route`"))
+		}
+	}
+
 	if route.Route != "" {
 		if isRouteFieldForbidden {
 			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("route"), "is not allowed"))
@@ -572,9 +735,9 @@ func (vsv *VirtualServerValidator) validateRoute(route v1.Route, fieldPath *fiel
 	}
 
 	if fieldCount != 1 {
-		msg := "must specify exactly one of `action`, `splits` or `route`"
+		msg := "must specify exactly one of `action`, `splits`, `canary` or `route`"
 		if isRouteFieldForbidden || len(route.Matches) > 0 {
-			msg = "must specify exactly one of `action` or `splits`"
+			msg = "must specify exactly one of `action`, `splits` or `canary`"
 		}
 
 		allErrs = append(allErrs, field.Invalid(fieldPath, "", msg))
@@ -583,6 +746,181 @@ func (vsv *VirtualServerValidator) validateRoute(route v1.Route, fieldPath *fiel
 	return allErrs
 }
 
+func validateCanary(canary *v1.Canary, fieldPath *field.Path, upstreamNames sets.String) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	allErrs = append(allErrs, validateReferencedUpstream(canary.Stable, fieldPath.Child("stable"), upstreamNames)...)
+	allErrs = append(allErrs, validateReferencedUpstream(canary.Canary, fieldPath.Child("canary"), upstreamNames)...)
+
+	if canary.Stable != "" && canary.Stable == canary.Canary {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("canary"), canary.Canary, "must be different from `stable`"))
+	}
+
+	if canary.Override != nil {
+		allErrs = append(allErrs, validateCanaryOverride(canary.Override, fieldPath.Child("override"))...)
+	}
+
+	allErrs = append(allErrs, validateCanarySteps(canary.Steps, fieldPath.Child("steps"))...)
+
+	return allErrs
+}
+
+func validateCanaryOverride(override *v1.CanaryOverride, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	fieldCount := 0
+
+	if override.Header != "" {
+		for _, msg := range validation.IsHTTPHeaderName(override.Header) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("header"), override.Header, msg))
+		}
+		fieldCount++
+	}
+
+	if override.Cookie != "" {
+		for _, msg := range isCookieName(override.Cookie) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("cookie"), override.Cookie, msg))
+		}
+		fieldCount++
+	}
+
+	if fieldCount != 1 {
+		allErrs = append(allErrs, field.Invalid(fieldPath, "", "must specify exactly one of: `header` or `cookie`"))
+	}
+
+	if override.Value == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("value"), ""))
+	}
+
+	for _, msg := range isValidMatchValue(override.Value) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("value"), override.Value, msg))
+	}
+
+	return allErrs
+}
+
+func validateCanarySteps(steps []v1.CanaryStep, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(steps) == 0 {
+		return append(allErrs, field.Required(fieldPath, "must include at least 1 step"))
+	}
+
+	prevWeight := 0
+
+	for i, s := range steps {
+		idxPath := fieldPath.Index(i)
+
+		for _, msg := range validation.IsInRange(s.Weight, 0, 100) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("weight"), s.Weight, msg))
+		}
+
+		if s.Weight < prevWeight {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("weight"), s.Weight, "must not be less than the weight of the previous step"))
+		}
+		prevWeight = s.Weight
+
+		isLast := i == len(steps)-1
+
+		if s.Duration == "" {
+			if !isLast {
+				allErrs = append(allErrs, field.Required(idxPath.Child("duration"), "must be set for every step except the last one"))
+			}
+			continue
+		}
+
+		if isLast {
+			allErrs = append(allErrs, field.Forbidden(idxPath.Child("duration"), "is not allowed for the last step"))
+			continue
+		}
+
+		allErrs = append(allErrs, validatePositiveDuration(s.Duration, idxPath.Child("duration"))...)
+	}
+
+	return allErrs
+}
+
+func validateRollback(rollback *v1.Rollback, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if rollback.Window != "" {
+		allErrs = append(allErrs, validatePositiveDuration(rollback.Window, fieldPath.Child("window"))...)
+		if d, err := time.ParseDuration(rollback.Window); err == nil && d > collectors.MaxUpstreamStatsWindow {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("window"), rollback.Window, fmt.Sprintf("must not exceed %v", collectors.MaxUpstreamStatsWindow)))
+		}
+	}
+
+	allErrs = append(allErrs, validatePositiveIntOrZeroFromPointer(rollback.MinRequests, fieldPath.Child("minRequests"))...)
+
+	if rollback.MaxErrorRate == nil && rollback.MaxLatency == "" {
+		allErrs = append(allErrs, field.Required(fieldPath, "must specify at least one of `maxErrorRate` or `maxLatency`"))
+	}
+
+	if rollback.MaxErrorRate != nil {
+		for _, msg := range validation.IsInRange(*rollback.MaxErrorRate, 1, 100) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("maxErrorRate"), *rollback.MaxErrorRate, msg))
+		}
+	}
+
+	if rollback.MaxLatency != "" {
+		allErrs = append(allErrs, validatePositiveDuration(rollback.MaxLatency, fieldPath.Child("maxLatency"))...)
+	}
+
+	if rollback.LatencyPercentile != nil {
+		if rollback.MaxLatency == "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("latencyPercentile"), "is only allowed with `maxLatency`"))
+		}
+		for _, msg := range validation.IsInRange(*rollback.LatencyPercentile, 1, 100) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("latencyPercentile"), *rollback.LatencyPercentile, msg))
+		}
+	}
+
+	return allErrs
+}
+
+func validateMirror(mirror *v1.Mirror, fieldPath *field.Path, upstreamNames sets.String) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(mirror.Upstreams) == 0 {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("upstreams"), "must include at least one upstream"))
+	}
+
+	mirrored := sets.String{}
+
+	for i, u := range mirror.Upstreams {
+		idxPath := fieldPath.Child("upstreams").Index(i)
+
+		upstreamErrs := validateReferencedUpstream(u, idxPath, upstreamNames)
+		if len(upstreamErrs) > 0 {
+			allErrs = append(allErrs, upstreamErrs...)
+		} else if mirrored.Has(u) {
+			allErrs = append(allErrs, field.Duplicate(idxPath, u))
+		} else {
+			mirrored.Insert(u)
+		}
+	}
+
+	if mirror.Percentage != nil {
+		for _, msg := range validation.IsInRange(*mirror.Percentage, 1, 100) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("percentage"), *mirror.Percentage, msg))
+		}
+	}
+
+	return allErrs
+}
+
+func validatePositiveDuration(duration string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if d, err := time.ParseDuration(duration); err != nil {
+		allErrs = append(allErrs, field.Invalid(fieldPath, duration, "must be a valid duration, for example, 30s, 10m or 1h30m"))
+	} else if d <= 0 {
+		allErrs = append(allErrs, field.Invalid(fieldPath, duration, "must be positive"))
+	}
+
+	return allErrs
+}
+
 func errorPageHasRequiredFields(errorPage v1.ErrorPage) bool {
 	var count int
 
@@ -1335,6 +1673,7 @@ func (vsv *VirtualServerValidator) validateVirtualServerRouteSpec(spec *v1.Virtu
 	allErrs = append(allErrs, upstreamErrs...)
 
 	allErrs = append(allErrs, vsv.validateVirtualServerRouteSubroutes(spec.Subroutes, fieldPath.Child("subroutes"), upstreamNames, vsPath, namespace)...)
+	allErrs = append(allErrs, validateGRPCUpstreamActions(spec.Subroutes, spec.Upstreams, fieldPath.Child("subroutes"))...)
 
 	return allErrs
 }
@@ -1420,6 +1759,18 @@ func rejectPlusResourcesInOSS(upstream v1.Upstream, idxPath *field.Path, isPlus
 		allErrs = append(allErrs, field.Forbidden(idxPath.Child("queue"), "queue is only supported in NGINX Plus"))
 	}
 
+	if upstream.OutlierDetection != nil {
+		allErrs = append(allErrs, field.Forbidden(idxPath.Child("outlierDetection"), "outlier detection is only supported in NGINX Plus"))
+	}
+
+	if upstream.Host != "" {
+		allErrs = append(allErrs, field.Forbidden(idxPath.Child("host"), "host is only supported in NGINX Plus"))
+	}
+
+	if upstream.Resolver != nil {
+		allErrs = append(allErrs, field.Forbidden(idxPath.Child("resolver"), "resolver is only supported in NGINX Plus"))
+	}
+
 	return allErrs
 }
 
@@ -1438,6 +1789,190 @@ func validateQueue(queue *v1.UpstreamQueue, fieldPath *field.Path) field.ErrorLi
 	return allErrs
 }
 
+func validateOutlierDetection(outlierDetection *v1.OutlierDetection, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if outlierDetection == nil {
+		return allErrs
+	}
+
+	if outlierDetection.Consecutive5xx == nil && outlierDetection.ConsecutiveGatewayErrors == nil {
+		allErrs = append(allErrs, field.Required(fieldPath, "must specify at least one of `consecutive5xx` or `consecutiveGatewayErrors`"))
+	}
+
+	if outlierDetection.Consecutive5xx != nil {
+		allErrs = append(allErrs, validatePositiveInt(*outlierDetection.Consecutive5xx, fieldPath.Child("consecutive5xx"))...)
+	}
+
+	if outlierDetection.ConsecutiveGatewayErrors != nil {
+		allErrs = append(allErrs, validatePositiveInt(*outlierDetection.ConsecutiveGatewayErrors, fieldPath.Child("consecutiveGatewayErrors"))...)
+	}
+
+	allErrs = append(allErrs, validateEjection(outlierDetection.EjectionTime, outlierDetection.MaxEjectionPercent, fieldPath)...)
+
+	return allErrs
+}
+
+// validateEjection validates the ejection settings of the outlier detection of VirtualServer and TransportServer upstreams.
+func validateEjection(ejectionTime string, maxEjectionPercent *int, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if ejectionTime != "" {
+		allErrs = append(allErrs, validatePositiveDuration(ejectionTime, fieldPath.Child("ejectionTime"))...)
+	}
+
+	if maxEjectionPercent != nil {
+		for _, msg := range validation.IsInRange(*maxEjectionPercent, 1, 100) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("maxEjectionPercent"), *maxEjectionPercent, msg))
+		}
+	}
+
+	return allErrs
+}
+
+func validateUpstreamSubsets(subsets []v1.UpstreamSubset, useClusterIP bool, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(subsets) == 0 {
+		return allErrs
+	}
+
+	if useClusterIP {
+		return append(allErrs, field.Forbidden(fieldPath, "subsets can't be used with use-cluster-ip"))
+	}
+
+	subselectors := sets.String{}
+
+	for i, subset := range subsets {
+		idxPath := fieldPath.Index(i)
+
+		if len(subset.Subselector) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("subselector"), ""))
+		} else {
+			allErrs = append(allErrs, validateLabels(subset.Subselector, idxPath.Child("subselector"))...)
+
+			subselector := labels.Set(subset.Subselector).String()
+			if subselectors.Has(subselector) {
+				allErrs = append(allErrs, field.Duplicate(idxPath.Child("subselector"), subset.Subselector))
+			} else {
+				subselectors.Insert(subselector)
+			}
+		}
+
+		allErrs = append(allErrs, validatePositiveInt(subset.Weight, idxPath.Child("weight"))...)
+	}
+
+	return allErrs
+}
+
+func validateTopologyAwareRouting(topologyAwareRouting *v1.TopologyAwareRouting, useClusterIP bool, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if topologyAwareRouting == nil {
+		return allErrs
+	}
+
+	if useClusterIP {
+		return append(allErrs, field.Forbidden(fieldPath, "topologyAwareRouting can't be used with use-cluster-ip"))
+	}
+
+	if topologyAwareRouting.MinHealthyPercent != nil {
+		for _, msg := range validation.IsInRange(*topologyAwareRouting.MinHealthyPercent, 0, 100) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("minHealthyPercent"), *topologyAwareRouting.MinHealthyPercent, msg))
+		}
+	}
+
+	return allErrs
+}
+
+func validateDrainTimeout(drainTimeout string, useClusterIP bool, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if drainTimeout == "" {
+		return allErrs
+	}
+
+	if useClusterIP {
+		return append(allErrs, field.Forbidden(fieldPath, "drain-timeout can't be used with use-cluster-ip"))
+	}
+
+	return append(allErrs, validatePositiveDuration(drainTimeout, fieldPath)...)
+}
+
+// validateUpstreamHost validates the host of an upstream. The host replaces the service, so the fields that select
+// the endpoints of the service are forbidden.
+func validateUpstreamHost(upstream v1.Upstream, fieldPath *field.Path) field.ErrorList {
+	allErrs := validateHost(upstream.Host, fieldPath.Child("host"))
+
+	if upstream.Service != "" {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("service"), "service can't be used with host"))
+	}
+	if upstream.Subselector != nil {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("subselector"), "subselector can't be used with host"))
+	}
+	if upstream.Subsets != nil {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("subsets"), "subsets can't be used with host"))
+	}
+	if upstream.TopologyAwareRouting != nil {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("topologyAwareRouting"), "topologyAwareRouting can't be used with host"))
+	}
+	if upstream.UseClusterIP {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("use-cluster-ip"), "use-cluster-ip can't be used with host"))
+	}
+	if upstream.DrainTimeout != "" {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("drain-timeout"), "drain-timeout can't be used with host"))
+	}
+
+	return allErrs
+}
+
+func validateUpstreamResolver(resolver *v1.UpstreamResolver, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if resolver == nil {
+		return allErrs
+	}
+
+	allErrs = append(allErrs, validateTime(resolver.Valid, fieldPath.Child("valid"))...)
+	allErrs = append(allErrs, validateTime(resolver.Timeout, fieldPath.Child("timeout"))...)
+
+	return allErrs
+}
+
+// validateUpstreamWebSocket validates the websocket field of an Upstream. The idle timeout of the enabled WebSocket
+// support replaces the read and send timeouts of the upstream, so they can't be set together.
+func validateUpstreamWebSocket(u v1.Upstream, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	ws := u.WebSocket
+	if ws == nil {
+		return allErrs
+	}
+
+	wsPath := fieldPath.Child("websocket")
+
+	if u.Type == grpcUpstreamType {
+		return append(allErrs, field.Forbidden(wsPath, "is not supported for grpc upstreams"))
+	}
+
+	if ws.IdleTimeout != "" && !ws.Enable {
+		allErrs = append(allErrs, field.Forbidden(wsPath.Child("idle-timeout"), "requires websocket to be enabled"))
+	}
+
+	if ws.Enable {
+		if u.ProxyReadTimeout != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("read-timeout"), "can't be used with websocket enabled, use websocket.idle-timeout instead"))
+		}
+		if u.ProxySendTimeout != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("send-timeout"), "can't be used with websocket enabled, use websocket.idle-timeout instead"))
+		}
+	}
+
+	allErrs = append(allErrs, validateTime(ws.IdleTimeout, wsPath.Child("idle-timeout"))...)
+
+	return allErrs
+}
+
 // isValidLabelName checks if a label name is valid.
 // It performs the same validation as ValidateLabelName from k8s.io/apimachinery/pkg/apis/meta/v1/validation/validation.go.
 func isValidLabelName(labelName string, fieldPath *field.Path) field.ErrorList {
@@ -868,6 +868,88 @@ func TestValidateRouteFails(t *testing.T) {
 			isRouteFieldForbidden: false,
 			msg:                   "non-existing upstream in pass action",
 		},
+		{
+			route: v1.Route{
+				Path: "/",
+				Action: &v1.Action{
+					Pass: "test",
+				},
+				Canary: &v1.Canary{
+					Stable: "test",
+					Canary: "test-canary",
+					Steps: []v1.CanaryStep{
+						{Weight: 100},
+					},
+				},
+			},
+			upstreamNames: map[string]sets.Empty{
+				"test":        {},
+				"test-canary": {},
+			},
+			isRouteFieldForbidden: false,
+			msg:                   "both action and canary exist",
+		},
+		{
+			route: v1.Route{
+				Path: "/",
+				Matches: []v1.Match{
+					{
+						Conditions: []v1.Condition{
+							{
+								Header: "x-version",
+								Value:  "v2",
+							},
+						},
+						Action: &v1.Action{
+							Pass: "test-canary",
+						},
+					},
+				},
+				Canary: &v1.Canary{
+					Stable: "test",
+					Canary: "test-canary",
+					Steps: []v1.CanaryStep{
+						{Weight: 100},
+					},
+				},
+			},
+			upstreamNames: map[string]sets.Empty{
+				"test":        {},
+				"test-canary": {},
+			},
+			isRouteFieldForbidden: false,
+			msg:                   "both matches and canary exist",
+		},
+		{
+			route: v1.Route{
+				Path: "/",
+				Action: &v1.Action{
+					Pass: "test",
+				},
+				Rollback: &v1.Rollback{
+					MaxErrorRate: createPointerFromInt(5),
+				},
+			},
+			upstreamNames: map[string]sets.Empty{
+				"test": {},
+			},
+			isRouteFieldForbidden: false,
+			msg:                   "rollback without splits or canary",
+		},
+		{
+			route: v1.Route{
+				Path:  "/",
+				Route: "default/test",
+				Mirror: &v1.Mirror{
+					Upstreams: []string{"test"},
+				},
+			},
+			upstreamNames: map[string]sets.Empty{
+				"test": {},
+			},
+			isRouteFieldForbidden: false,
+			msg:                   "mirror with route",
+		},
 		{
 			route: v1.Route{
 				Path: "/",
@@ -1567,6 +1649,393 @@ func TestValidateSplitsFails(t *testing.T) {
 	}
 }
 
+func TestValidateCanary(t *testing.T) {
+	tests := []struct {
+		canary *v1.Canary
+		msg    string
+	}{
+		{
+			canary: &v1.Canary{
+				Stable: "test-1",
+				Canary: "test-2",
+				Steps: []v1.CanaryStep{
+					{Weight: 100},
+				},
+			},
+			msg: "single step",
+		},
+		{
+			canary: &v1.Canary{
+				Stable: "test-1",
+				Canary: "test-2",
+				Override: &v1.CanaryOverride{
+					Header: "x-canary",
+					Value:  "always",
+				},
+				Steps: []v1.CanaryStep{
+					{Weight: 0, Duration: "1h"},
+					{Weight: 10, Duration: "30m"},
+					{Weight: 10, Duration: "5m"},
+					{Weight: 100},
+				},
+			},
+			msg: "multiple steps with a header override",
+		},
+		{
+			canary: &v1.Canary{
+				Stable: "test-1",
+				Canary: "test-2",
+				Override: &v1.CanaryOverride{
+					Cookie: "canary",
+					Value:  "always",
+				},
+				Steps: []v1.CanaryStep{
+					{Weight: 20, Duration: "10m"},
+					{Weight: 50},
+				},
+			},
+			msg: "cookie override",
+		},
+	}
+
+	upstreamNames := map[string]sets.Empty{
+		"test-1": {},
+		"test-2": {},
+	}
+
+	for _, test := range tests {
+		allErrs := validateCanary(test.canary, field.NewPath("canary"), upstreamNames)
+		if len(allErrs) > 0 {
+			t.Errorf("validateCanary() returned errors %v for valid input for the case of %s", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateCanaryFails(t *testing.T) {
+	tests := []struct {
+		canary *v1.Canary
+		msg    string
+	}{
+		{
+			canary: &v1.Canary{
+				Stable: "test-1",
+				Canary: "some-upstream",
+				Steps: []v1.CanaryStep{
+					{Weight: 100},
+				},
+			},
+			msg: "non-existing canary upstream",
+		},
+		{
+			canary: &v1.Canary{
+				Stable: "test-1",
+				Canary: "test-1",
+				Steps: []v1.CanaryStep{
+					{Weight: 100},
+				},
+			},
+			msg: "same stable and canary upstreams",
+		},
+		{
+			canary: &v1.Canary{
+				Stable: "test-1",
+				Canary: "test-2",
+			},
+			msg: "no steps",
+		},
+		{
+			canary: &v1.Canary{
+				Stable: "test-1",
+				Canary: "test-2",
+				Steps: []v1.CanaryStep{
+					{Weight: 50, Duration: "10m"},
+					{Weight: 20},
+				},
+			},
+			msg: "decreasing weight",
+		},
+		{
+			canary: &v1.Canary{
+				Stable: "test-1",
+				Canary: "test-2",
+				Steps: []v1.CanaryStep{
+					{Weight: 10, Duration: "10m"},
+					{Weight: 120},
+				},
+			},
+			msg: "weight out of range",
+		},
+		{
+			canary: &v1.Canary{
+				Stable: "test-1",
+				Canary: "test-2",
+				Steps: []v1.CanaryStep{
+					{Weight: 10},
+					{Weight: 100},
+				},
+			},
+			msg: "missing duration",
+		},
+		{
+			canary: &v1.Canary{
+				Stable: "test-1",
+				Canary: "test-2",
+				Steps: []v1.CanaryStep{
+					{Weight: 10, Duration: "10 minutes"},
+					{Weight: 100},
+				},
+			},
+			msg: "invalid duration",
+		},
+		{
+			canary: &v1.Canary{
+				Stable: "test-1",
+				Canary: "test-2",
+				Steps: []v1.CanaryStep{
+					{Weight: 10, Duration: "-10m"},
+					{Weight: 100},
+				},
+			},
+			msg: "negative duration",
+		},
+		{
+			canary: &v1.Canary{
+				Stable: "test-1",
+				Canary: "test-2",
+				Steps: []v1.CanaryStep{
+					{Weight: 100, Duration: "10m"},
+				},
+			},
+			msg: "duration for the last step",
+		},
+		{
+			canary: &v1.Canary{
+				Stable: "test-1",
+				Canary: "test-2",
+				Override: &v1.CanaryOverride{
+					Header: "x-canary",
+					Cookie: "canary",
+					Value:  "always",
+				},
+				Steps: []v1.CanaryStep{
+					{Weight: 100},
+				},
+			},
+			msg: "override with both header and cookie",
+		},
+		{
+			canary: &v1.Canary{
+				Stable: "test-1",
+				Canary: "test-2",
+				Override: &v1.CanaryOverride{
+					Header: "x-canary",
+				},
+				Steps: []v1.CanaryStep{
+					{Weight: 100},
+				},
+			},
+			msg: "override without value",
+		},
+	}
+
+	upstreamNames := map[string]sets.Empty{
+		"test-1": {},
+		"test-2": {},
+	}
+
+	for _, test := range tests {
+		allErrs := validateCanary(test.canary, field.NewPath("canary"), upstreamNames)
+		if len(allErrs) == 0 {
+			t.Errorf("validateCanary() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateRollback(t *testing.T) {
+	tests := []struct {
+		rollback *v1.Rollback
+		msg      string
+	}{
+		{
+			rollback: &v1.Rollback{
+				MaxErrorRate: createPointerFromInt(5),
+			},
+			msg: "error rate only",
+		},
+		{
+			rollback: &v1.Rollback{
+				MaxLatency: "500ms",
+			},
+			msg: "latency only",
+		},
+		{
+			rollback: &v1.Rollback{
+				Window:            "5m",
+				MinRequests:       createPointerFromInt(100),
+				MaxErrorRate:      createPointerFromInt(1),
+				MaxLatency:        "1s",
+				LatencyPercentile: createPointerFromInt(95),
+			},
+			msg: "all fields",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateRollback(test.rollback, field.NewPath("rollback"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateRollback() returned errors %v for valid input for the case of %s", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateRollbackFails(t *testing.T) {
+	tests := []struct {
+		rollback *v1.Rollback
+		msg      string
+	}{
+		{
+			rollback: &v1.Rollback{},
+			msg:      "no thresholds",
+		},
+		{
+			rollback: &v1.Rollback{
+				MaxErrorRate: createPointerFromInt(0),
+			},
+			msg: "zero error rate",
+		},
+		{
+			rollback: &v1.Rollback{
+				MaxErrorRate: createPointerFromInt(101),
+			},
+			msg: "error rate out of range",
+		},
+		{
+			rollback: &v1.Rollback{
+				MaxLatency: "500",
+			},
+			msg: "invalid latency",
+		},
+		{
+			rollback: &v1.Rollback{
+				MaxErrorRate:      createPointerFromInt(5),
+				LatencyPercentile: createPointerFromInt(99),
+			},
+			msg: "percentile without latency",
+		},
+		{
+			rollback: &v1.Rollback{
+				MaxLatency:        "500ms",
+				LatencyPercentile: createPointerFromInt(0),
+			},
+			msg: "percentile out of range",
+		},
+		{
+			rollback: &v1.Rollback{
+				Window:       "1h",
+				MaxErrorRate: createPointerFromInt(5),
+			},
+			msg: "window too long",
+		},
+		{
+			rollback: &v1.Rollback{
+				MinRequests:  createPointerFromInt(-1),
+				MaxErrorRate: createPointerFromInt(5),
+			},
+			msg: "negative min requests",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateRollback(test.rollback, field.NewPath("rollback"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateRollback() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateMirror(t *testing.T) {
+	upstreamNames := map[string]sets.Empty{
+		"test-1": {},
+		"test-2": {},
+	}
+
+	tests := []struct {
+		mirror *v1.Mirror
+		msg    string
+	}{
+		{
+			mirror: &v1.Mirror{
+				Upstreams: []string{"test-1"},
+			},
+			msg: "single upstream",
+		},
+		{
+			mirror: &v1.Mirror{
+				Upstreams:   []string{"test-1", "test-2"},
+				Percentage:  createPointerFromInt(10),
+				RequestBody: createPointerFromBool(false),
+			},
+			msg: "all fields",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateMirror(test.mirror, field.NewPath("mirror"), upstreamNames)
+		if len(allErrs) > 0 {
+			t.Errorf("validateMirror() returned errors %v for valid input for the case of %s", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateMirrorFails(t *testing.T) {
+	upstreamNames := map[string]sets.Empty{
+		"test-1": {},
+	}
+
+	tests := []struct {
+		mirror *v1.Mirror
+		msg    string
+	}{
+		{
+			mirror: &v1.Mirror{},
+			msg:    "no upstreams",
+		},
+		{
+			mirror: &v1.Mirror{
+				Upstreams: []string{"test-2"},
+			},
+			msg: "non-existing upstream",
+		},
+		{
+			mirror: &v1.Mirror{
+				Upstreams: []string{"test-1", "test-1"},
+			},
+			msg: "duplicated upstream",
+		},
+		{
+			mirror: &v1.Mirror{
+				Upstreams:  []string{"test-1"},
+				Percentage: createPointerFromInt(0),
+			},
+			msg: "zero percentage",
+		},
+		{
+			mirror: &v1.Mirror{
+				Upstreams:  []string{"test-1"},
+				Percentage: createPointerFromInt(101),
+			},
+			msg: "percentage out of range",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateMirror(test.mirror, field.NewPath("mirror"), upstreamNames)
+		if len(allErrs) == 0 {
+			t.Errorf("validateMirror() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
 func TestValidateCondition(t *testing.T) {
 	tests := []struct {
 		condition v1.Condition
@@ -2328,16 +2797,35 @@ func TestValidateUpstreamHealthCheck(t *testing.T) {
 		StatusMatch: "! 500",
 	}
 
-	allErrs := validateUpstreamHealthCheck(hc, field.NewPath("healthCheck"))
+	allErrs := validateUpstreamHealthCheck(hc, "", field.NewPath("healthCheck"))
 
 	if len(allErrs) != 0 {
 		t.Errorf("validateUpstreamHealthCheck() returned errors for valid input %v", hc)
 	}
 }
 
-func TestValidateUpstreamHealthCheckFails(t *testing.T) {
-	tests := []struct {
-		hc *v1.HealthCheck
+func TestValidateUpstreamHealthCheckForGRPC(t *testing.T) {
+	hc := &v1.HealthCheck{
+		Enable:      true,
+		Interval:    "4s",
+		Fails:       3,
+		Passes:      2,
+		Port:        50051,
+		GRPCStatus:  createPointerFromInt(12),
+		GRPCService: "helloworld.Greeter",
+	}
+
+	allErrs := validateUpstreamHealthCheck(hc, "grpc", field.NewPath("healthCheck"))
+
+	if len(allErrs) != 0 {
+		t.Errorf("validateUpstreamHealthCheck() returned errors %v for valid input %v", allErrs, hc)
+	}
+}
+
+func TestValidateUpstreamHealthCheckFails(t *testing.T) {
+	tests := []struct {
+		hc           *v1.HealthCheck
+		upstreamType string
 	}{
 		{
 			hc: &v1.HealthCheck{
@@ -2351,10 +2839,51 @@ func TestValidateUpstreamHealthCheckFails(t *testing.T) {
 				Path:   "/healthz//;",
 			},
 		},
+		{
+			hc: &v1.HealthCheck{
+				Enable:      true,
+				GRPCService: "helloworld.Greeter",
+			},
+			upstreamType: "http",
+		},
+		{
+			hc: &v1.HealthCheck{
+				Enable:     true,
+				GRPCStatus: createPointerFromInt(12),
+			},
+		},
+		{
+			hc: &v1.HealthCheck{
+				Enable: true,
+				Path:   "/healthz",
+			},
+			upstreamType: "grpc",
+		},
+		{
+			hc: &v1.HealthCheck{
+				Enable:      true,
+				StatusMatch: "200",
+			},
+			upstreamType: "grpc",
+		},
+		{
+			hc: &v1.HealthCheck{
+				Enable:     true,
+				GRPCStatus: createPointerFromInt(-1),
+			},
+			upstreamType: "grpc",
+		},
+		{
+			hc: &v1.HealthCheck{
+				Enable:      true,
+				GRPCService: "helloworld Greeter;",
+			},
+			upstreamType: "grpc",
+		},
 	}
 
 	for _, test := range tests {
-		allErrs := validateUpstreamHealthCheck(test.hc, field.NewPath("healthCheck"))
+		allErrs := validateUpstreamHealthCheck(test.hc, test.upstreamType, field.NewPath("healthCheck"))
 
 		if len(allErrs) == 0 {
 			t.Errorf("validateUpstreamHealthCheck() returned no errors for invalid input %v", test.hc)
@@ -2547,6 +3076,85 @@ func TestValidateIntFromStringFails(t *testing.T) {
 	}
 }
 
+func TestValidateOutlierDetection(t *testing.T) {
+	tests := []struct {
+		outlierDetection *v1.OutlierDetection
+		msg              string
+	}{
+		{
+			outlierDetection: nil,
+			msg:              "nil outlier detection",
+		},
+		{
+			outlierDetection: &v1.OutlierDetection{
+				Consecutive5xx: createPointerFromInt(5),
+			},
+			msg: "consecutive 5xx only",
+		},
+		{
+			outlierDetection: &v1.OutlierDetection{
+				Consecutive5xx:           createPointerFromInt(5),
+				ConsecutiveGatewayErrors: createPointerFromInt(3),
+				EjectionTime:             "1m",
+				MaxEjectionPercent:       createPointerFromInt(50),
+			},
+			msg: "all fields",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateOutlierDetection(test.outlierDetection, field.NewPath("outlierDetection"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateOutlierDetection() returned errors %v for valid input for the case of %s", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateOutlierDetectionFails(t *testing.T) {
+	tests := []struct {
+		outlierDetection *v1.OutlierDetection
+		msg              string
+	}{
+		{
+			outlierDetection: &v1.OutlierDetection{},
+			msg:              "no thresholds",
+		},
+		{
+			outlierDetection: &v1.OutlierDetection{
+				Consecutive5xx: createPointerFromInt(0),
+			},
+			msg: "zero consecutive 5xx",
+		},
+		{
+			outlierDetection: &v1.OutlierDetection{
+				ConsecutiveGatewayErrors: createPointerFromInt(-1),
+			},
+			msg: "negative consecutive gateway errors",
+		},
+		{
+			outlierDetection: &v1.OutlierDetection{
+				Consecutive5xx: createPointerFromInt(5),
+				EjectionTime:   "30",
+			},
+			msg: "invalid ejection time",
+		},
+		{
+			outlierDetection: &v1.OutlierDetection{
+				Consecutive5xx:     createPointerFromInt(5),
+				MaxEjectionPercent: createPointerFromInt(0),
+			},
+			msg: "max ejection percent out of range",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateOutlierDetection(test.outlierDetection, field.NewPath("outlierDetection"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateOutlierDetection() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
 func TestRejectPlusResourcesInOSS(t *testing.T) {
 	tests := []struct {
 		upstream *v1.Upstream
@@ -2571,6 +3179,21 @@ func TestRejectPlusResourcesInOSS(t *testing.T) {
 				Queue: &v1.UpstreamQueue{},
 			},
 		},
+		{
+			upstream: &v1.Upstream{
+				OutlierDetection: &v1.OutlierDetection{},
+			},
+		},
+		{
+			upstream: &v1.Upstream{
+				Host: "api.example.com",
+			},
+		},
+		{
+			upstream: &v1.Upstream{
+				Resolver: &v1.UpstreamResolver{},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -3793,3 +4416,581 @@ func TestValidateErrorPageHeaderFails(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateUpstreamSubsets(t *testing.T) {
+	tests := []struct {
+		subsets []v1.UpstreamSubset
+		msg     string
+	}{
+		{
+			subsets: nil,
+			msg:     "no subsets",
+		},
+		{
+			subsets: []v1.UpstreamSubset{
+				{
+					Subselector: map[string]string{"version": "v1"},
+					Weight:      90,
+				},
+				{
+					Subselector: map[string]string{"version": "v2"},
+					Weight:      10,
+				},
+			},
+			msg: "two subsets",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateUpstreamSubsets(test.subsets, false, field.NewPath("subsets"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateUpstreamSubsets() returned errors %v for valid input for the case of %s", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateUpstreamSubsetsFails(t *testing.T) {
+	tests := []struct {
+		subsets      []v1.UpstreamSubset
+		useClusterIP bool
+		msg          string
+	}{
+		{
+			subsets: []v1.UpstreamSubset{
+				{
+					Subselector: map[string]string{"version": "v1"},
+					Weight:      1,
+				},
+			},
+			useClusterIP: true,
+			msg:          "subsets with use-cluster-ip",
+		},
+		{
+			subsets: []v1.UpstreamSubset{
+				{
+					Weight: 1,
+				},
+			},
+			msg: "missing subselector",
+		},
+		{
+			subsets: []v1.UpstreamSubset{
+				{
+					Subselector: map[string]string{"version": "v1!"},
+					Weight:      1,
+				},
+			},
+			msg: "invalid subselector",
+		},
+		{
+			subsets: []v1.UpstreamSubset{
+				{
+					Subselector: map[string]string{"version": "v1"},
+					Weight:      1,
+				},
+				{
+					Subselector: map[string]string{"version": "v1"},
+					Weight:      2,
+				},
+			},
+			msg: "duplicate subselectors",
+		},
+		{
+			subsets: []v1.UpstreamSubset{
+				{
+					Subselector: map[string]string{"version": "v1"},
+					Weight:      0,
+				},
+			},
+			msg: "zero weight",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateUpstreamSubsets(test.subsets, test.useClusterIP, field.NewPath("subsets"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateUpstreamSubsets() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateTopologyAwareRouting(t *testing.T) {
+	tests := []struct {
+		topologyAwareRouting *v1.TopologyAwareRouting
+		msg                  string
+	}{
+		{
+			topologyAwareRouting: nil,
+			msg:                  "nil topology aware routing",
+		},
+		{
+			topologyAwareRouting: &v1.TopologyAwareRouting{
+				Enable: true,
+			},
+			msg: "default min healthy percent",
+		},
+		{
+			topologyAwareRouting: &v1.TopologyAwareRouting{
+				Enable:            true,
+				MinHealthyPercent: createPointerFromInt(0),
+			},
+			msg: "zero min healthy percent",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateTopologyAwareRouting(test.topologyAwareRouting, false, field.NewPath("topologyAwareRouting"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateTopologyAwareRouting() returned errors %v for valid input for the case of %s", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateTopologyAwareRoutingFails(t *testing.T) {
+	tests := []struct {
+		topologyAwareRouting *v1.TopologyAwareRouting
+		useClusterIP         bool
+		msg                  string
+	}{
+		{
+			topologyAwareRouting: &v1.TopologyAwareRouting{
+				Enable: true,
+			},
+			useClusterIP: true,
+			msg:          "topology aware routing with use-cluster-ip",
+		},
+		{
+			topologyAwareRouting: &v1.TopologyAwareRouting{
+				Enable:            true,
+				MinHealthyPercent: createPointerFromInt(101),
+			},
+			msg: "min healthy percent out of range",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateTopologyAwareRouting(test.topologyAwareRouting, test.useClusterIP, field.NewPath("topologyAwareRouting"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateTopologyAwareRouting() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateDrainTimeout(t *testing.T) {
+	drainTimeouts := []string{"", "30s", "1m30s"}
+
+	for _, drainTimeout := range drainTimeouts {
+		allErrs := validateDrainTimeout(drainTimeout, false, field.NewPath("drain-timeout"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateDrainTimeout(%q) returned errors %v for valid input", drainTimeout, allErrs)
+		}
+	}
+}
+
+func TestValidateDrainTimeoutFails(t *testing.T) {
+	tests := []struct {
+		drainTimeout string
+		useClusterIP bool
+		msg          string
+	}{
+		{
+			drainTimeout: "30s",
+			useClusterIP: true,
+			msg:          "drain timeout with use-cluster-ip",
+		},
+		{
+			drainTimeout: "1d",
+			msg:          "invalid duration",
+		},
+		{
+			drainTimeout: "0s",
+			msg:          "zero duration",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateDrainTimeout(test.drainTimeout, test.useClusterIP, field.NewPath("drain-timeout"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateDrainTimeout() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateUpstreamHost(t *testing.T) {
+	upstream := v1.Upstream{
+		Name: "api",
+		Host: "api.example.com",
+		Port: 443,
+	}
+
+	allErrs := validateUpstreamHost(upstream, field.NewPath("upstreams").Index(0))
+	if len(allErrs) > 0 {
+		t.Errorf("validateUpstreamHost() returned errors %v for valid input", allErrs)
+	}
+}
+
+func TestValidateUpstreamHostFails(t *testing.T) {
+	tests := []struct {
+		upstream v1.Upstream
+		msg      string
+	}{
+		{
+			upstream: v1.Upstream{
+				Host: "api_example.com",
+			},
+			msg: "invalid host",
+		},
+		{
+			upstream: v1.Upstream{
+				Host:    "api.example.com",
+				Service: "api-svc",
+			},
+			msg: "host with service",
+		},
+		{
+			upstream: v1.Upstream{
+				Host:        "api.example.com",
+				Subselector: map[string]string{"version": "v1"},
+			},
+			msg: "host with subselector",
+		},
+		{
+			upstream: v1.Upstream{
+				Host:    "api.example.com",
+				Subsets: []v1.UpstreamSubset{{Subselector: map[string]string{"version": "v1"}, Weight: 100}},
+			},
+			msg: "host with subsets",
+		},
+		{
+			upstream: v1.Upstream{
+				Host:                 "api.example.com",
+				TopologyAwareRouting: &v1.TopologyAwareRouting{},
+			},
+			msg: "host with topology aware routing",
+		},
+		{
+			upstream: v1.Upstream{
+				Host:         "api.example.com",
+				UseClusterIP: true,
+			},
+			msg: "host with use-cluster-ip",
+		},
+		{
+			upstream: v1.Upstream{
+				Host:         "api.example.com",
+				DrainTimeout: "30s",
+			},
+			msg: "host with drain timeout",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateUpstreamHost(test.upstream, field.NewPath("upstreams").Index(0))
+		if len(allErrs) == 0 {
+			t.Errorf("validateUpstreamHost() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateUpstreamResolver(t *testing.T) {
+	tests := []*v1.UpstreamResolver{
+		nil,
+		{},
+		{
+			Valid:   "30s",
+			Timeout: "5s",
+			IPv6:    createPointerFromBool(false),
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateUpstreamResolver(test, field.NewPath("resolver"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateUpstreamResolver() returned errors %v for valid input %v", allErrs, test)
+		}
+	}
+}
+
+func TestValidateUpstreamResolverFails(t *testing.T) {
+	tests := []struct {
+		resolver *v1.UpstreamResolver
+		msg      string
+	}{
+		{
+			resolver: &v1.UpstreamResolver{Valid: "1x"},
+			msg:      "invalid valid",
+		},
+		{
+			resolver: &v1.UpstreamResolver{Timeout: "-5s"},
+			msg:      "invalid timeout",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateUpstreamResolver(test.resolver, field.NewPath("resolver"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateUpstreamResolver() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateUpstreamType(t *testing.T) {
+	upstreamTypes := []string{"", "http", "grpc"}
+
+	for _, upstreamType := range upstreamTypes {
+		allErrs := validateUpstreamType(upstreamType, field.NewPath("type"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateUpstreamType(%q) returned errors %v for valid input", upstreamType, allErrs)
+		}
+	}
+}
+
+func TestValidateUpstreamTypeFails(t *testing.T) {
+	upstreamTypes := []string{"https", "GRPC", "grpcs"}
+
+	for _, upstreamType := range upstreamTypes {
+		allErrs := validateUpstreamType(upstreamType, field.NewPath("type"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateUpstreamType(%q) returned no errors for invalid input", upstreamType)
+		}
+	}
+}
+
+func TestValidateGRPCUpstream(t *testing.T) {
+	upstreams := []v1.Upstream{
+		{
+			Type:           "http",
+			ProxyBuffering: createPointerFromBool(false),
+		},
+		{
+			Type:                "grpc",
+			ProxyReadTimeout:    "1m",
+			ProxyNextUpstream:   "error timeout",
+			ProxyConnectTimeout: "10s",
+		},
+	}
+
+	for _, u := range upstreams {
+		allErrs := validateGRPCUpstream(u, field.NewPath("upstreams").Index(0))
+		if len(allErrs) > 0 {
+			t.Errorf("validateGRPCUpstream() returned errors %v for valid input %v", allErrs, u)
+		}
+	}
+}
+
+func TestValidateGRPCUpstreamFails(t *testing.T) {
+	tests := []struct {
+		upstream v1.Upstream
+		msg      string
+	}{
+		{
+			upstream: v1.Upstream{
+				Type:           "grpc",
+				ProxyBuffering: createPointerFromBool(true),
+			},
+			msg: "buffering",
+		},
+		{
+			upstream: v1.Upstream{
+				Type: "grpc",
+				ProxyBuffers: &v1.UpstreamBuffers{
+					Number: 8,
+					Size:   "4k",
+				},
+			},
+			msg: "buffers",
+		},
+		{
+			upstream: v1.Upstream{
+				Type:            "grpc",
+				ProxyBufferSize: "4k",
+			},
+			msg: "buffer-size",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateGRPCUpstream(test.upstream, field.NewPath("upstreams").Index(0))
+		if len(allErrs) == 0 {
+			t.Errorf("validateGRPCUpstream() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateGRPCUpstreamActions(t *testing.T) {
+	upstreams := []v1.Upstream{
+		{Name: "grpc", Type: "grpc"},
+		{Name: "http", Type: "http"},
+	}
+	createProxyAction := func(upstream string, pass bool) *v1.Action {
+		return &v1.Action{
+			Proxy: &v1.ActionProxy{
+				Upstream: upstream,
+				RequestHeaders: &v1.ProxyRequestHeaders{
+					Pass: createPointerFromBool(pass),
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		routes         []v1.Route
+		expectedErrors int
+		msg            string
+	}{
+		{
+			routes: []v1.Route{
+				{Path: "/a", Action: createProxyAction("grpc", true)},
+				{Path: "/b", Action: createProxyAction("http", false)},
+				{Path: "/c", Action: &v1.Action{Pass: "grpc"}},
+			},
+			expectedErrors: 0,
+			msg:            "valid actions",
+		},
+		{
+			routes: []v1.Route{
+				{Path: "/a", Action: createProxyAction("grpc", false)},
+			},
+			expectedErrors: 1,
+			msg:            "action of the route",
+		},
+		{
+			routes: []v1.Route{
+				{
+					Path: "/a",
+					Splits: []v1.Split{
+						{Weight: 50, Action: createProxyAction("http", false)},
+						{Weight: 50, Action: createProxyAction("grpc", false)},
+					},
+					Matches: []v1.Match{
+						{
+							Action: createProxyAction("grpc", false),
+						},
+						{
+							Splits: []v1.Split{
+								{Weight: 100, Action: createProxyAction("grpc", false)},
+							},
+						},
+					},
+				},
+			},
+			expectedErrors: 3,
+			msg:            "actions of the splits and the matches",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateGRPCUpstreamActions(test.routes, upstreams, field.NewPath("routes"))
+		if len(allErrs) != test.expectedErrors {
+			t.Errorf("validateGRPCUpstreamActions() returned %v errors but expected %v for the case of %s: %v", len(allErrs), test.expectedErrors, test.msg, allErrs)
+		}
+	}
+}
+
+func TestValidateUpstreamWebSocket(t *testing.T) {
+	tests := []struct {
+		upstream v1.Upstream
+		msg      string
+	}{
+		{
+			upstream: v1.Upstream{
+				ProxyReadTimeout: "30s",
+			},
+			msg: "nil websocket",
+		},
+		{
+			upstream: v1.Upstream{
+				WebSocket: &v1.UpstreamWebSocket{
+					Enable: true,
+				},
+			},
+			msg: "enabled websocket",
+		},
+		{
+			upstream: v1.Upstream{
+				Type: "http",
+				WebSocket: &v1.UpstreamWebSocket{
+					Enable:      true,
+					IdleTimeout: "1h",
+				},
+			},
+			msg: "enabled websocket with idle timeout",
+		},
+		{
+			upstream: v1.Upstream{
+				ProxyReadTimeout: "30s",
+				ProxySendTimeout: "30s",
+				WebSocket: &v1.UpstreamWebSocket{
+					Enable: false,
+				},
+			},
+			msg: "disabled websocket with read and send timeouts",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateUpstreamWebSocket(test.upstream, field.NewPath("upstreams").Index(0))
+		if len(allErrs) > 0 {
+			t.Errorf("validateUpstreamWebSocket() returned errors %v for valid input for the case of %s", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateUpstreamWebSocketFails(t *testing.T) {
+	tests := []struct {
+		upstream v1.Upstream
+		msg      string
+	}{
+		{
+			upstream: v1.Upstream{
+				Type: "grpc",
+				WebSocket: &v1.UpstreamWebSocket{
+					Enable: true,
+				},
+			},
+			msg: "grpc upstream",
+		},
+		{
+			upstream: v1.Upstream{
+				WebSocket: &v1.UpstreamWebSocket{
+					IdleTimeout: "1h",
+				},
+			},
+			msg: "idle timeout of disabled websocket",
+		},
+		{
+			upstream: v1.Upstream{
+				WebSocket: &v1.UpstreamWebSocket{
+					Enable:      true,
+					IdleTimeout: "1 hour",
+				},
+			},
+			msg: "invalid idle timeout",
+		},
+		{
+			upstream: v1.Upstream{
+				ProxyReadTimeout: "30s",
+				WebSocket: &v1.UpstreamWebSocket{
+					Enable: true,
+				},
+			},
+			msg: "enabled websocket with read timeout",
+		},
+		{
+			upstream: v1.Upstream{
+				ProxySendTimeout: "30s",
+				WebSocket: &v1.UpstreamWebSocket{
+					Enable: true,
+				},
+			},
+			msg: "enabled websocket with send timeout",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateUpstreamWebSocket(test.upstream, field.NewPath("upstreams").Index(0))
+		if len(allErrs) == 0 {
+			t.Errorf("validateUpstreamWebSocket() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
@@ -39,8 +39,6 @@ class TestVirtualServerUpstreamOptions:
         assert "slow_start" not in config
 
         assert "keepalive" not in config
-        assert 'set $default_connection_header "";' not in config
-        assert 'set $default_connection_header close;' in config
         assert "proxy_set_header Upgrade $http_upgrade;" in config
         assert "proxy_set_header Connection $vs_connection_header;" in config
         assert "proxy_http_version 1.1;" in config
@@ -64,7 +62,7 @@ class TestVirtualServerUpstreamOptions:
           "buffering": True, "buffer-size": "2k", "buffers": {"number": 4, "size": "2k"}},
          ["least_conn;", "max_fails=8 ",
           "fail_timeout=13s ", "proxy_connect_timeout 55s;", "proxy_read_timeout 1s;",
-          "proxy_send_timeout 1h;", "keepalive 54;", 'set $default_connection_header "";', "max_conns=1048;",
+          "proxy_send_timeout 1h;", "keepalive 54;", "proxy_set_header Connection $vs_connection_header_keepalive;", "max_conns=1048;",
           "client_max_body_size 1048K;",
           "proxy_buffering on;", "proxy_buffer_size 2k;", "proxy_buffers 4 2k;"]),
         ({"lb-method": "ip_hash", "connect-timeout": "75", "read-timeout": "15", "send-timeout": "1h"},
@@ -110,7 +108,7 @@ class TestVirtualServerUpstreamOptions:
         (f"{TEST_DATA}/virtual-server-upstream-options/configmap-with-keys.yaml",
          ["max_fails=3 ", "fail_timeout=33s ", "max_conns=0;",
           "proxy_connect_timeout 44s;", "proxy_read_timeout 22s;", "proxy_send_timeout 55s;",
-          "keepalive 1024;", 'set $default_connection_header "";',
+          "keepalive 1024;", "proxy_set_header Connection $vs_connection_header_keepalive;",
           "client_max_body_size 3m;",
           "proxy_buffering off;", "proxy_buffer_size 1k;", "proxy_buffers 8 1k;"],
          ["ip_hash;", "least_conn;", "random ", "hash", "least_time ",
@@ -159,7 +157,7 @@ class TestVirtualServerUpstreamOptions:
           "buffering": True, "buffer-size": "2k", "buffers": {"number": 4, "size": "2k"}},
          ["least_conn;", "max_fails=12 ",
           "fail_timeout=1m ", "max_conns=0;", "proxy_connect_timeout 1m;", "proxy_read_timeout 77s;",
-          "proxy_send_timeout 23s;", "keepalive 48;", 'set $default_connection_header "";',
+          "proxy_send_timeout 23s;", "keepalive 48;", "proxy_set_header Connection $vs_connection_header_keepalive;",
           "client_max_body_size 0;",
           "proxy_buffering on;", "proxy_buffer_size 2k;", "proxy_buffers 4 2k;"],
          ["ip_hash;", "random ", "hash", "least_time ", "max_fails=1 ",